@@ -5,8 +5,8 @@
 package cmd
 
 import (
+	"compress/gzip"
 	"errors"
-	"fmt"
 	"github.com/elastic/elastic-integration-corpus-generator-tool/internal/corpus"
 	"github.com/elastic/elastic-integration-corpus-generator-tool/pkg/genlib/config"
 	"github.com/spf13/afero"
@@ -18,6 +18,8 @@ import (
 var integrationPackage string
 var dataStream string
 var packageVersion string
+var schemaVersionField string
+var updateRatio float64
 
 func GenerateCmd() *cobra.Command {
 	generateCmd := &cobra.Command{
@@ -69,6 +71,8 @@ func GenerateCmd() *cobra.Command {
 				return err
 			}
 
+			fc = withRolloverFromFlags(withGzipFromFlags(fc.WithOutput(outputTarget).WithSchemaVersionField(schemaVersionField).WithUpdateRatio(updateRatio).WithWarmup(warmup)))
+
 			timeNow, err := getTimeNowFromFlag(timeNowAsString)
 			if err != nil {
 				return err
@@ -79,7 +83,7 @@ func GenerateCmd() *cobra.Command {
 				return err
 			}
 
-			fmt.Println("File generated:", payloadFilename)
+			printGenerated(payloadFilename)
 
 			return nil
 		},
@@ -90,6 +94,14 @@ func GenerateCmd() *cobra.Command {
 	generateCmd.Flags().Uint64VarP(&totEvents, "tot-events", "t", 1, "total events of the corpus to generate")
 	generateCmd.Flags().StringVarP(&timeNowAsString, "now", "n", "", "time to use for generation based on now (`date` type)")
 	generateCmd.Flags().Int64VarP(&randSeed, "seed", "s", 1, "seed to set as source of rand")
+	generateCmd.Flags().Uint64Var(&warmup, "warmup", 0, warmupFlagUsage)
+	generateCmd.Flags().StringVarP(&outputTarget, "output", "o", "", outputFlagUsage)
+	generateCmd.Flags().BoolVar(&gzipOutput, "gzip", false, gzipFlagUsage)
+	generateCmd.Flags().IntVar(&gzipLevel, "gzip-level", gzip.DefaultCompression, gzipLevelFlagUsage)
+	generateCmd.Flags().Int64Var(&rolloverBytes, "rollover-bytes", 0, rolloverBytesFlagUsage)
+	generateCmd.Flags().StringVar(&chunkNaming, "chunk-naming", corpus.ChunkNamingSequence, chunkNamingFlagUsage)
+	generateCmd.Flags().Float64Var(&updateRatio, "update-ratio", 0, "fraction of the documents, between 0 and 1, emitted as update actions of previously created documents")
+	generateCmd.Flags().StringVar(&schemaVersionField, "schema-version-field", "", "prefix of the <prefix>.version and <prefix>.hash fields to stamp each document with the version of the fields schema")
 
 	return generateCmd
 }
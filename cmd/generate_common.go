@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/elastic/elastic-integration-corpus-generator-tool/internal/corpus"
 	"github.com/elastic/elastic-integration-corpus-generator-tool/pkg/genlib"
 )
 
@@ -12,6 +13,49 @@ var configFile string
 var totEvents uint64
 var timeNowAsString string
 var randSeed int64
+var outputTarget string
+var gzipOutput bool
+var gzipLevel int
+var rolloverBytes int64
+var chunkNaming string
+var warmup uint64
+
+const outputFlagUsage = "where to write the corpus: 'stdout', 'discard' or a file path (default a new file in the corpora location)"
+
+const gzipFlagUsage = "compress the corpus with gzip, the name of the file generated in the corpora location ends in '.gz'"
+const gzipLevelFlagUsage = "gzip compression level, between -2 (huffman only) and 9 (best compression)"
+
+// withGzipFromFlags returns fc compressing the corpus with gzip if requested by the flags
+func withGzipFromFlags(fc corpus.GeneratorCorpus) corpus.GeneratorCorpus {
+	if !gzipOutput {
+		return fc
+	}
+
+	return fc.WithGzip(gzipLevel)
+}
+
+const warmupFlagUsage = "number of documents generated and discarded before the ones written to the corpus, for fields that need some documents to stabilise"
+
+const rolloverBytesFlagUsage = "split the corpus into files of at least this size in the corpora location (default not split)"
+const chunkNamingFlagUsage = "naming of the files the corpus is split into: 'sequence' or 'content_hash'"
+
+// withRolloverFromFlags returns fc splitting the corpus into chunks if requested by the flags
+func withRolloverFromFlags(fc corpus.GeneratorCorpus) corpus.GeneratorCorpus {
+	if rolloverBytes == 0 {
+		return fc
+	}
+
+	return fc.WithRollover(rolloverBytes, chunkNaming)
+}
+
+// printGenerated reports where the corpus was written to, unless it was written to stdout or discarded
+func printGenerated(payloadFilename string) {
+	if outputTarget == corpus.OutputTargetStdout || outputTarget == corpus.OutputTargetDiscard {
+		return
+	}
+
+	fmt.Println("File generated:", payloadFilename)
+}
 
 func getTimeNowFromFlag(timeNowAsString string) (time.Time, error) {
 	if len(timeNowAsString) > 0 {
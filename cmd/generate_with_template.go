@@ -5,8 +5,8 @@
 package cmd
 
 import (
+	"compress/gzip"
 	"errors"
-	"fmt"
 	"github.com/elastic/elastic-integration-corpus-generator-tool/internal/corpus"
 	"github.com/elastic/elastic-integration-corpus-generator-tool/pkg/genlib/config"
 	"github.com/spf13/afero"
@@ -61,6 +61,8 @@ func GenerateWithTemplateCmd() *cobra.Command {
 				return err
 			}
 
+			fc = withRolloverFromFlags(withGzipFromFlags(fc.WithOutput(outputTarget).WithWarmup(warmup)))
+
 			timeNow, err := getTimeNowFromFlag(timeNowAsString)
 			if err != nil {
 				return err
@@ -71,7 +73,7 @@ func GenerateWithTemplateCmd() *cobra.Command {
 				return err
 			}
 
-			fmt.Println("File generated:", payloadFilename)
+			printGenerated(payloadFilename)
 
 			return nil
 		},
@@ -82,6 +84,12 @@ func GenerateWithTemplateCmd() *cobra.Command {
 	generateWithTemplateCmd.Flags().Uint64VarP(&totEvents, "tot-events", "t", 1, "total events of the corpus to generate")
 	generateWithTemplateCmd.Flags().StringVarP(&timeNowAsString, "now", "n", "", "time to use for generation based on now (`date` type)")
 	generateWithTemplateCmd.Flags().Int64VarP(&randSeed, "seed", "s", 1, "seed to set as source of rand")
+	generateWithTemplateCmd.Flags().Uint64Var(&warmup, "warmup", 0, warmupFlagUsage)
+	generateWithTemplateCmd.Flags().StringVarP(&outputTarget, "output", "o", "", outputFlagUsage)
+	generateWithTemplateCmd.Flags().BoolVar(&gzipOutput, "gzip", false, gzipFlagUsage)
+	generateWithTemplateCmd.Flags().IntVar(&gzipLevel, "gzip-level", gzip.DefaultCompression, gzipLevelFlagUsage)
+	generateWithTemplateCmd.Flags().Int64Var(&rolloverBytes, "rollover-bytes", 0, rolloverBytesFlagUsage)
+	generateWithTemplateCmd.Flags().StringVar(&chunkNaming, "chunk-naming", corpus.ChunkNamingSequence, chunkNamingFlagUsage)
 
 	return generateWithTemplateCmd
 }
@@ -80,6 +80,8 @@ func TemplateCmd() *cobra.Command {
 				return err
 			}
 
+			fc = fc.WithOutput(outputTarget)
+
 			timeNow, err := getTimeNowFromFlag(timeNowAsString)
 			if err != nil {
 				return err
@@ -90,7 +92,7 @@ func TemplateCmd() *cobra.Command {
 				return err
 			}
 
-			fmt.Println("File generated:", payloadFilename)
+			printGenerated(payloadFilename)
 
 			return nil
 		},
@@ -103,5 +105,6 @@ func TemplateCmd() *cobra.Command {
 	command.Flags().StringVarP(&timeNowAsString, "now", "n", "", "time to use for generation based on now (`date` type)")
 
 	command.Flags().Int64VarP(&randSeed, "seed", "s", 1, "seed to set as source of rand")
+	command.Flags().StringVarP(&outputTarget, "output", "o", "", outputFlagUsage)
 	return command
 }
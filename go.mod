@@ -7,7 +7,6 @@ require (
 	github.com/OpenPeeDeeP/xdg v1.0.0
 	github.com/Pallinder/go-randomdata v1.2.0
 	github.com/elastic/go-ucfg v0.8.6
-	github.com/lithammer/shortuuid/v3 v3.0.7
 	github.com/spf13/afero v1.11.0
 	github.com/spf13/cobra v1.8.0
 	github.com/spf13/viper v1.18.2
@@ -0,0 +1,70 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package corpus
+
+import (
+	"bytes"
+	"errors"
+	"math/rand"
+	"strconv"
+)
+
+var ErrNotValidUpdateRatio = errors.New("please, pass --update-ratio between 0 and 1")
+
+// bulkActions writes the action preceding each document of a bulk request corpus.
+// With no update ratio every document is created. Otherwise created documents get an `_id` and, with the update
+// ratio probability, a document is instead emitted as a partial update of a previously created `_id`.
+type bulkActions struct {
+	index       string
+	updateRatio float64
+	// rand is not shared with the generator, so that the documents don't change with the update ratio
+	rand *rand.Rand
+	// created is the number of created documents: their `_id` are the numbers from 0 to created - 1
+	created uint64
+	update  bool
+}
+
+func validUpdateRatio(updateRatio float64) error {
+	if updateRatio < 0 || updateRatio > 1 {
+		return ErrNotValidUpdateRatio
+	}
+
+	return nil
+}
+
+func newBulkActions(index string, updateRatio float64, randSeed int64) *bulkActions {
+	return &bulkActions{
+		index:       index,
+		updateRatio: updateRatio,
+		rand:        rand.New(rand.NewSource(randSeed)),
+	}
+}
+
+// writeAction writes the action of the next document, opening the partial document of updates
+func (b *bulkActions) writeAction(buf *bytes.Buffer) {
+	if b.updateRatio == 0 {
+		buf.WriteString(`{ "create" : { "_index": "` + b.index + `" } }` + "\n")
+		return
+	}
+
+	// the first document is always created, so that there is an `_id` to update
+	b.update = b.created > 0 && b.rand.Float64() < b.updateRatio
+	if b.update {
+		id := uint64(b.rand.Int63n(int64(b.created)))
+		buf.WriteString(`{ "update" : { "_index": "` + b.index + `", "_id": "` + strconv.FormatUint(id, 10) + `" } }` + "\n")
+		buf.WriteString(`{"doc":`)
+		return
+	}
+
+	buf.WriteString(`{ "create" : { "_index": "` + b.index + `", "_id": "` + strconv.FormatUint(b.created, 10) + `" } }` + "\n")
+	b.created += 1
+}
+
+// closeDocument closes the partial document of updates
+func (b *bulkActions) closeDocument(buf *bytes.Buffer) {
+	if b.update {
+		buf.WriteByte('}')
+	}
+}
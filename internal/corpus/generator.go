@@ -28,6 +28,8 @@ const (
 
 var ErrNotValidTemplate = errors.New("please, pass --template-type as one of 'placeholder' or 'gotext'")
 
+var ErrNotValidRecordSeparator = errors.New("bulk request corpora are newline delimited: `record_separator` must be `newline`")
+
 type Config = config.Config
 type Fields = fields.Fields
 
@@ -77,6 +79,20 @@ type GeneratorCorpus struct {
 	fs           afero.Fs
 	location     string
 	templateType int
+	// output is the output target, when empty a file in location
+	output string
+	// schemaVersionField is the prefix of the fields the version of the fields schema is injected as, when not empty
+	schemaVersionField string
+	// updateRatio is the fraction of documents emitted as updates in bulk request corpora
+	updateRatio float64
+	// gzip compresses the corpus at gzipLevel
+	gzip      bool
+	gzipLevel int
+	// rolloverBytes is the size of the chunks the corpus is split into in the location, named by chunkNaming
+	rolloverBytes int64
+	chunkNaming   string
+	// warmup is the number of documents generated and discarded before the ones written to the corpus
+	warmup uint64
 	// timestamp allow overriding value in tests
 	timestamp timestamp
 }
@@ -85,6 +101,116 @@ func (gc GeneratorCorpus) Location() string {
 	return gc.location
 }
 
+// WithOutput returns a copy of the GeneratorCorpus writing the corpus to the output target instead of to a file in the
+// location: see NewOutputWriter for the available targets.
+func (gc GeneratorCorpus) WithOutput(target string) GeneratorCorpus {
+	gc.output = target
+	return gc
+}
+
+// WithSchemaVersionField returns a copy of the GeneratorCorpus stamping each document generated by Generate with
+// the version of the loaded fields schema, as `<field>.version` and `<field>.hash`.
+func (gc GeneratorCorpus) WithSchemaVersionField(field string) GeneratorCorpus {
+	gc.schemaVersionField = field
+	return gc
+}
+
+// WithUpdateRatio returns a copy of the GeneratorCorpus emitting, in the bulk request corpus generated by Generate,
+// the updateRatio fraction of the documents as `update` actions of previously created documents instead of `create`
+// actions. It must be between 0 and 1.
+func (gc GeneratorCorpus) WithUpdateRatio(updateRatio float64) GeneratorCorpus {
+	gc.updateRatio = updateRatio
+	return gc
+}
+
+// WithGzip returns a copy of the GeneratorCorpus writing the corpus compressed with gzip at level, between
+// gzip.HuffmanOnly and gzip.BestCompression. The name of the file generated in the location ends in `.gz`.
+func (gc GeneratorCorpus) WithGzip(level int) GeneratorCorpus {
+	gc.gzip = true
+	gc.gzipLevel = level
+	return gc
+}
+
+// WithRollover returns a copy of the GeneratorCorpus splitting the corpus into chunk files of at least rolloverBytes
+// in the location, named according to chunkNaming: see RolloverWriter. It cannot be combined with an output target
+// nor with gzip.
+func (gc GeneratorCorpus) WithRollover(rolloverBytes int64, chunkNaming string) GeneratorCorpus {
+	gc.rolloverBytes = rolloverBytes
+	gc.chunkNaming = chunkNaming
+	return gc
+}
+
+// WithWarmup returns a copy of the GeneratorCorpus generating and discarding warmup documents before the ones
+// written to the corpus, so that fields needing some documents to stabilise (ie: with `fuzziness`) are written once
+// stable. The total number of documents written to the corpus is not affected.
+func (gc GeneratorCorpus) WithWarmup(warmup uint64) GeneratorCorpus {
+	gc.warmup = warmup
+	return gc
+}
+
+// schemaVersionFields returns the static fields to inject for the schemaVersion, if any
+func (gc GeneratorCorpus) schemaVersionFields(schemaVersion fields.SchemaVersion) map[string]any {
+	if len(gc.schemaVersionField) == 0 {
+		return nil
+	}
+
+	return map[string]any{
+		gc.schemaVersionField + ".version": schemaVersion.Version,
+		gc.schemaVersionField + ".hash":    schemaVersion.Hash,
+	}
+}
+
+// outputWriter returns the writer for the corpus and its target, defaulting to payloadFilename in the location.
+// With rollover the target is the location the chunks are written to.
+func (gc GeneratorCorpus) outputWriter(payloadFilename string) (io.WriteCloser, string, error) {
+	if gc.rolloverBytes != 0 || len(gc.chunkNaming) > 0 {
+		if len(gc.output) > 0 || gc.gzip {
+			return nil, "", ErrNotValidRollover
+		}
+
+		if err := gc.fs.MkdirAll(gc.location, corpusLocPerm); err != nil {
+			return nil, "", fmt.Errorf("cannot generate corpus location folder: %v", err)
+		}
+
+		ext := path.Ext(payloadFilename)
+		w, err := NewRolloverWriter(gc.fs, gc.location, strings.TrimSuffix(payloadFilename, ext), ext, gc.rolloverBytes, gc.chunkNaming)
+		if err != nil {
+			return nil, "", err
+		}
+
+		return w, gc.location, nil
+	}
+
+	target := gc.output
+	if len(target) == 0 {
+		if err := gc.fs.MkdirAll(gc.location, corpusLocPerm); err != nil {
+			return nil, "", fmt.Errorf("cannot generate corpus location folder: %v", err)
+		}
+
+		target = path.Join(gc.location, payloadFilename)
+		if gc.gzip {
+			target += ".gz"
+		}
+	}
+
+	w, err := NewOutputWriter(gc.fs, target)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if !gc.gzip {
+		return w, target, nil
+	}
+
+	zw, err := NewGzipWriter(w, gc.gzipLevel)
+	if err != nil {
+		_ = w.Close()
+		return nil, "", err
+	}
+
+	return zw, target, nil
+}
+
 // bulkPayloadFilename computes the bulkPayloadFilename for the corpus to be generated.
 // To provide unique names the provided slug is prepended with current timestamp.
 func (gc GeneratorCorpus) bulkPayloadFilename(integrationPackage, dataStream, packageVersion string) string {
@@ -106,10 +232,15 @@ func (gc GeneratorCorpus) bulkPayloadFilenameWithTemplate(templatePath string) s
 var corpusLocPerm = os.FileMode(0770)
 var corpusPerm = os.FileMode(0660)
 
-func (gc GeneratorCorpus) eventsPayloadFromFields(template []byte, fields Fields, totEvents uint64, timeNow time.Time, randSeed int64, createPayload []byte, f afero.File) error {
+func (gc GeneratorCorpus) eventsPayloadFromFields(template []byte, fields Fields, totEvents uint64, timeNow time.Time, randSeed int64, actions *bulkActions, staticFields map[string]any, f io.Writer) error {
 	genlib.InitGeneratorTimeNow(timeNow)
 	genlib.InitGeneratorRandSeed(randSeed)
 
+	// the warmup documents are discarded, on top of the total events of the corpus
+	if totEvents > 0 {
+		totEvents += gc.warmup
+	}
+
 	var evgen genlib.Generator
 	var err error
 	if len(template) == 0 {
@@ -123,28 +254,54 @@ func (gc GeneratorCorpus) eventsPayloadFromFields(template []byte, fields Fields
 			return ErrNotValidTemplate
 		}
 
+		// genlib.NewGenerator already caps the fields of the documents
+		if err == nil && gc.config.MaxFieldsPerDoc() > 0 {
+			evgen, err = genlib.NewGeneratorWithMaxFields(evgen, gc.config.MaxFieldsPerDoc())
+		}
+	}
+
+	// the static fields are added after capping the fields of the documents, so that they are never dropped
+	if err == nil && len(staticFields) > 0 {
+		evgen, err = genlib.NewGeneratorWithStaticFields(evgen, staticFields)
 	}
 
 	if err != nil {
 		return err
 	}
 
-	var buf *bytes.Buffer
-	if len(template) == 0 {
-		buf = bytes.NewBuffer(createPayload)
-	} else {
-		buf = bytes.NewBufferString("")
-	}
+	recordPrefix, recordSuffix := gc.config.RecordDelimiters()
+	buf := bytes.NewBufferString("")
 
 	defer func() {
 		_ = evgen.Close()
 	}()
 
+	for i := uint64(0); i < gc.warmup; i++ {
+		buf.Reset()
+		err := evgen.Emit(buf)
+		if err == io.EOF {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
 	for {
-		buf.Truncate(len(createPayload))
+		buf.Reset()
+		buf.Write(recordPrefix)
+		if actions != nil {
+			actions.writeAction(buf)
+		}
+
 		err := evgen.Emit(buf)
 		if err == nil {
-			buf.WriteByte('\n')
+			if actions != nil {
+				actions.closeDocument(buf)
+			}
+
+			buf.Write(recordSuffix)
 
 			if _, err = f.Write(buf.Bytes()); err != nil {
 				return err
@@ -161,27 +318,35 @@ func (gc GeneratorCorpus) eventsPayloadFromFields(template []byte, fields Fields
 	}
 }
 
-// Generate generates a bulk request corpus and persist it to file.
+// Generate generates a bulk request corpus and persist it to file, or to the output target if set.
+// It returns the target the corpus was written to.
 func (gc GeneratorCorpus) Generate(packageRegistryBaseURL, integrationPackage, dataStream, packageVersion string, totEvents uint64, timeNow time.Time, randSeed int64) (string, error) {
-	if err := gc.fs.MkdirAll(gc.location, corpusLocPerm); err != nil {
-		return "", fmt.Errorf("cannot generate corpus location folder: %v", err)
+	if err := validUpdateRatio(gc.updateRatio); err != nil {
+		return "", err
+	}
+
+	if separator := gc.config.RecordSeparator(); separator != "" && separator != config.RecordSeparatorNewline {
+		return "", ErrNotValidRecordSeparator
 	}
 
-	payloadFilename := path.Join(gc.location, gc.bulkPayloadFilename(integrationPackage, dataStream, packageVersion))
-	f, err := gc.fs.OpenFile(payloadFilename, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, corpusPerm)
+	f, payloadFilename, err := gc.outputWriter(gc.bulkPayloadFilename(integrationPackage, dataStream, packageVersion))
 	if err != nil {
 		return "", err
 	}
 
+	// on early termination the output is closed anyway, so that what was generated is flushed
+	defer func() {
+		_ = f.Close()
+	}()
+
 	ctx := context.Background()
-	flds, dataStreamType, err := fields.LoadFields(ctx, packageRegistryBaseURL, integrationPackage, dataStream, packageVersion)
+	flds, dataStreamType, schemaVersion, err := fields.LoadFieldsWithSchemaVersion(ctx, packageRegistryBaseURL, integrationPackage, dataStream, packageVersion)
 	if err != nil {
 		return "", err
 	}
 
-	createPayload := []byte(`{ "create" : { "_index": "` + dataStreamType + `-` + integrationPackage + `.` + dataStream + `-default" } }` + "\n")
-
-	err = gc.eventsPayloadFromFields(nil, flds, totEvents, timeNow, randSeed, createPayload, f)
+	actions := newBulkActions(dataStreamType+"-"+integrationPackage+"."+dataStream+"-default", gc.updateRatio, randSeed)
+	err = gc.eventsPayloadFromFields(nil, flds, totEvents, timeNow, randSeed, actions, gc.schemaVersionFields(schemaVersion), f)
 	if err != nil {
 		return "", err
 	}
@@ -193,18 +358,19 @@ func (gc GeneratorCorpus) Generate(packageRegistryBaseURL, integrationPackage, d
 	return payloadFilename, err
 }
 
-// GenerateWithTemplate generates a template based corpus and persist it to file.
+// GenerateWithTemplate generates a template based corpus and persist it to file, or to the output target if set.
+// It returns the target the corpus was written to.
 func (gc GeneratorCorpus) GenerateWithTemplate(templatePath, fieldsDefinitionPath string, totEvents uint64, timeNow time.Time, randSeed int64) (string, error) {
-	if err := gc.fs.MkdirAll(gc.location, corpusLocPerm); err != nil {
-		return "", fmt.Errorf("cannot generate corpus location folder: %v", err)
-	}
-
-	payloadFilename := path.Join(gc.location, gc.bulkPayloadFilenameWithTemplate(templatePath))
-	f, err := gc.fs.OpenFile(payloadFilename, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, corpusPerm)
+	f, payloadFilename, err := gc.outputWriter(gc.bulkPayloadFilenameWithTemplate(templatePath))
 	if err != nil {
 		return "", err
 	}
 
+	// on early termination the output is closed anyway, so that what was generated is flushed
+	defer func() {
+		_ = f.Close()
+	}()
+
 	template, err := os.ReadFile(templatePath)
 	if err != nil {
 		return "", err
@@ -220,7 +386,7 @@ func (gc GeneratorCorpus) GenerateWithTemplate(templatePath, fieldsDefinitionPat
 		return "", err
 	}
 
-	err = gc.eventsPayloadFromFields(template, flds, totEvents, timeNow, randSeed, nil, f)
+	err = gc.eventsPayloadFromFields(template, flds, totEvents, timeNow, randSeed, nil, nil, f)
 	if err != nil {
 		return "", err
 	}
@@ -5,9 +5,13 @@
 package corpus
 
 import (
+	"path/filepath"
 	"reflect"
 	"testing"
+	"time"
 
+	"github.com/elastic/elastic-integration-corpus-generator-tool/pkg/genlib/config"
+	"github.com/spf13/afero"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -40,3 +44,31 @@ func TestSanitizeFilename(t *testing.T) {
 		}
 	}
 }
+
+func TestGenerateWithTemplateBundledAssets(t *testing.T) {
+	templates, err := filepath.Glob(filepath.Join("..", "..", "assets", "templates", "*", "*", "*.tpl"))
+	assert.Nil(t, err)
+	assert.NotEmpty(t, templates)
+
+	for _, templatePath := range templates {
+		templatePath := templatePath
+		t.Run(templatePath, func(t *testing.T) {
+			datasetFolderPath := filepath.Dir(templatePath)
+			cfg, err := config.LoadConfig(afero.NewOsFs(), filepath.Join(datasetFolderPath, "configs.yml"))
+			assert.Nil(t, err)
+
+			templateType := "gotext"
+			if filepath.Base(templatePath) == "placeholder.tpl" {
+				templateType = "placeholder"
+			}
+
+			fc, err := NewGeneratorWithTemplate(cfg, afero.NewMemMapFs(), "testdata", templateType)
+			assert.Nil(t, err)
+
+			fc = fc.WithOutput(OutputTargetDiscard)
+			// the kubernetes templates split the timestamp on `:`, expecting a timezone offset
+			_, err = fc.GenerateWithTemplate(templatePath, filepath.Join(datasetFolderPath, "fields.yml"), 100, time.Now().In(time.FixedZone("CET", 3600)), 1)
+			assert.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,93 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package corpus
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+
+	"github.com/spf13/afero"
+)
+
+// Output targets not backed by a file
+const (
+	OutputTargetStdout  = "stdout"
+	OutputTargetDiscard = "discard"
+)
+
+// DiscardWriter is an io.WriteCloser discarding what is written to it, counting the bytes.
+// It is meant for benchmarking the generation without the cost of the output.
+type DiscardWriter struct {
+	written int64
+}
+
+func (w *DiscardWriter) Write(p []byte) (int, error) {
+	w.written += int64(len(p))
+	return len(p), nil
+}
+
+func (w *DiscardWriter) Close() error {
+	return nil
+}
+
+// Written returns the number of bytes written to the DiscardWriter
+func (w *DiscardWriter) Written() int64 {
+	return w.written
+}
+
+type nopCloser struct {
+	io.Writer
+}
+
+func (nopCloser) Close() error {
+	return nil
+}
+
+// NewOutputWriter returns the io.WriteCloser for the output target: `stdout`, `discard` or a file path in fs.
+// The file is created or truncated if it already exists.
+func NewOutputWriter(fs afero.Fs, target string) (io.WriteCloser, error) {
+	switch target {
+	case OutputTargetStdout:
+		return nopCloser{Writer: os.Stdout}, nil
+	case OutputTargetDiscard:
+		return &DiscardWriter{}, nil
+	default:
+		return fs.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, corpusPerm)
+	}
+}
+
+// gzipWriteCloser is an io.WriteCloser compressing what is written to it with gzip
+type gzipWriteCloser struct {
+	*gzip.Writer
+	w      io.WriteCloser
+	closed bool
+}
+
+// Close writes the gzip trailer and closes the underlying io.WriteCloser. Closing it more than once is a no-op.
+func (w *gzipWriteCloser) Close() error {
+	if w.closed {
+		return nil
+	}
+
+	w.closed = true
+	if err := w.Writer.Close(); err != nil {
+		_ = w.w.Close()
+		return err
+	}
+
+	return w.w.Close()
+}
+
+// NewGzipWriter returns an io.WriteCloser compressing what is written to it with gzip at level, between
+// gzip.HuffmanOnly and gzip.BestCompression, into w. Closing it writes the gzip trailer and closes w.
+func NewGzipWriter(w io.WriteCloser, level int) (io.WriteCloser, error) {
+	zw, err := gzip.NewWriterLevel(w, level)
+	if err != nil {
+		return nil, err
+	}
+
+	return &gzipWriteCloser{Writer: zw, w: w}, nil
+}
@@ -0,0 +1,362 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package corpus
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/elastic/elastic-integration-corpus-generator-tool/pkg/genlib/config"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewOutputWriterFile(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	target := "/corpus.ndjson"
+
+	err := afero.WriteFile(fs, target, []byte("previous content to truncate"), 0666)
+	assert.Nil(t, err)
+
+	w, err := NewOutputWriter(fs, target)
+	assert.Nil(t, err)
+
+	_, err = w.Write([]byte("{}\n"))
+	assert.Nil(t, err)
+	assert.Nil(t, w.Close())
+
+	data, err := afero.ReadFile(fs, target)
+	assert.Nil(t, err)
+	assert.Equal(t, "{}\n", string(data))
+}
+
+func TestNewOutputWriterStdout(t *testing.T) {
+	stdout := os.Stdout
+	r, pipe, err := os.Pipe()
+	assert.Nil(t, err)
+
+	os.Stdout = pipe
+	defer func() {
+		os.Stdout = stdout
+	}()
+
+	w, err := NewOutputWriter(afero.NewMemMapFs(), OutputTargetStdout)
+	assert.Nil(t, err)
+
+	_, err = w.Write([]byte("{}\n"))
+	assert.Nil(t, err)
+	assert.Nil(t, w.Close())
+	assert.Nil(t, pipe.Close())
+
+	data, err := io.ReadAll(r)
+	assert.Nil(t, err)
+	assert.Equal(t, "{}\n", string(data))
+}
+
+func TestNewOutputWriterDiscard(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	w, err := NewOutputWriter(fs, OutputTargetDiscard)
+	assert.Nil(t, err)
+
+	for i := 0; i < 10; i++ {
+		n, err := w.Write([]byte("{}\n"))
+		assert.Nil(t, err)
+		assert.Equal(t, 3, n)
+	}
+
+	assert.Nil(t, w.Close())
+	assert.Equal(t, int64(30), w.(*DiscardWriter).Written())
+
+	files, err := afero.ReadDir(fs, "/")
+	assert.Nil(t, err)
+	assert.Len(t, files, 0)
+}
+
+func TestGeneratorCorpusWithOutput(t *testing.T) {
+	fc := TestNewGenerator().WithOutput(OutputTargetDiscard)
+
+	template := "/template.tpl"
+	fieldsDefinition := "/fields.yml"
+	osFs := afero.NewOsFs()
+	dir := t.TempDir()
+	assert.Nil(t, afero.WriteFile(osFs, dir+template, []byte(`{"alpha":"{{.alpha}}"}`), 0666))
+	assert.Nil(t, afero.WriteFile(osFs, dir+fieldsDefinition, []byte("- name: alpha\n  type: keyword\n"), 0666))
+
+	target, err := fc.GenerateWithTemplate(dir+template, dir+fieldsDefinition, 10, time.Now(), 1)
+	assert.Nil(t, err)
+	assert.Equal(t, OutputTargetDiscard, target)
+
+	exists, err := afero.DirExists(fc.fs, fc.location)
+	assert.Nil(t, err)
+	assert.False(t, exists)
+}
+
+func TestGeneratorCorpusWithGzip(t *testing.T) {
+	template := "/template.tpl"
+	fieldsDefinition := "/fields.yml"
+	osFs := afero.NewOsFs()
+	dir := t.TempDir()
+	assert.Nil(t, afero.WriteFile(osFs, dir+template, []byte(`{"alpha":"{{.alpha}}"}`), 0666))
+	assert.Nil(t, afero.WriteFile(osFs, dir+fieldsDefinition, []byte("- name: alpha\n  type: keyword\n"), 0666))
+
+	timeNow := time.Now()
+	fc := TestNewGenerator()
+
+	_, err := fc.WithOutput("/corpus.ndjson").GenerateWithTemplate(dir+template, dir+fieldsDefinition, 100, timeNow, 1)
+	assert.Nil(t, err)
+
+	// without output target the corpus is written to a file in the location, its name ending in .gz
+	target, err := fc.WithGzip(gzip.BestCompression).GenerateWithTemplate(dir+template, dir+fieldsDefinition, 100, timeNow, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, "testdata/1647345675-template.tpl.gz", target)
+
+	expected, err := afero.ReadFile(fc.fs, "/corpus.ndjson")
+	assert.Nil(t, err)
+
+	f, err := fc.fs.Open(target)
+	assert.Nil(t, err)
+	defer f.Close()
+
+	zr, err := gzip.NewReader(f)
+	assert.Nil(t, err)
+
+	data, err := io.ReadAll(zr)
+	assert.Nil(t, err)
+	assert.Equal(t, string(expected), string(data))
+}
+
+func TestGeneratorCorpusWithNotValidGzipLevel(t *testing.T) {
+	fc := TestNewGenerator().WithOutput(OutputTargetDiscard).WithGzip(gzip.BestCompression + 1)
+
+	_, err := fc.GenerateWithTemplate("/template.tpl", "/fields.yml", 10, time.Now(), 1)
+	assert.NotNil(t, err)
+}
+
+// newTestPackageRegistry returns a package registry serving the data_stream data stream of the integration package
+// at version 1.2.3, with a single keyword field
+func newTestPackageRegistry(t *testing.T) *httptest.Server {
+	var archive bytes.Buffer
+	zw := zip.NewWriter(&archive)
+	for name, content := range map[string]string{
+		"integration-1.2.3/data_stream/data_stream/fields/fields.yml": "- name: alpha\n  type: keyword\n",
+		"integration-1.2.3/data_stream/data_stream/manifest.yml":      "type: logs\n",
+	} {
+		w, err := zw.Create(name)
+		assert.Nil(t, err)
+		_, err = w.Write([]byte(content))
+		assert.Nil(t, err)
+	}
+
+	assert.Nil(t, zw.Close())
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/package/integration/1.2.3", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"download":"/epr/integration-1.2.3.zip"}`))
+	})
+
+	mux.HandleFunc("/epr/integration-1.2.3.zip", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(archive.Bytes())
+	})
+
+	return httptest.NewServer(mux)
+}
+
+func TestGeneratorCorpusWithSchemaVersionField(t *testing.T) {
+	registry := newTestPackageRegistry(t)
+	defer registry.Close()
+
+	target := "/corpus.ndjson"
+	fc := TestNewGenerator().WithOutput(target).WithSchemaVersionField("schema")
+
+	_, err := fc.Generate(registry.URL, "integration", "data_stream", "1.2.3", 10, time.Now(), 1)
+	assert.Nil(t, err)
+
+	data, err := afero.ReadFile(fc.fs, target)
+	assert.Nil(t, err)
+
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	// each document is preceded by its bulk create action
+	assert.Len(t, lines, 20)
+	for i := 1; i < len(lines); i += 2 {
+		var document map[string]any
+		assert.Nil(t, json.Unmarshal([]byte(lines[i]), &document))
+		assert.Equal(t, "1.2.3", document["schema.version"])
+		assert.Len(t, document["schema.hash"], 64)
+		assert.Contains(t, document, "alpha")
+	}
+}
+
+func TestGeneratorCorpusWithUpdateRatio(t *testing.T) {
+	registry := newTestPackageRegistry(t)
+	defer registry.Close()
+
+	target := "/corpus.ndjson"
+	fc := TestNewGenerator().WithOutput(target).WithUpdateRatio(0.3)
+
+	_, err := fc.Generate(registry.URL, "integration", "data_stream", "1.2.3", 1000, time.Now(), 1)
+	assert.Nil(t, err)
+
+	data, err := afero.ReadFile(fc.fs, target)
+	assert.Nil(t, err)
+
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	assert.Len(t, lines, 2000)
+
+	created := make(map[string]struct{})
+	var updates int
+	for i := 0; i < len(lines); i += 2 {
+		var action map[string]struct {
+			Index string `json:"_index"`
+			ID    string `json:"_id"`
+		}
+
+		assert.Nil(t, json.Unmarshal([]byte(lines[i]), &action))
+		assert.Len(t, action, 1)
+
+		var document map[string]any
+		assert.Nil(t, json.Unmarshal([]byte(lines[i+1]), &document))
+
+		if create, ok := action["create"]; ok {
+			assert.Equal(t, "logs-integration.data_stream-default", create.Index)
+			assert.NotContains(t, created, create.ID)
+			assert.Contains(t, document, "alpha")
+			created[create.ID] = struct{}{}
+			continue
+		}
+
+		update, ok := action["update"]
+		assert.True(t, ok, "expected create or update action, got %s", lines[i])
+		assert.Contains(t, created, update.ID, "update of a not created document")
+		assert.Contains(t, document, "doc")
+		updates += 1
+	}
+
+	assert.InDelta(t, 300, updates, 60)
+}
+
+func TestGeneratorCorpusWithNotValidUpdateRatio(t *testing.T) {
+	fc := TestNewGenerator().WithOutput(OutputTargetDiscard).WithUpdateRatio(1.5)
+
+	_, err := fc.Generate("http://localhost", "integration", "data_stream", "1.2.3", 10, time.Now(), 1)
+	assert.ErrorIs(t, err, ErrNotValidUpdateRatio)
+}
+
+func TestGeneratorCorpusWithJSONSeqRecordSeparator(t *testing.T) {
+	template := "/template.tpl"
+	fieldsDefinition := "/fields.yml"
+	osFs := afero.NewOsFs()
+	dir := t.TempDir()
+	assert.Nil(t, afero.WriteFile(osFs, dir+template, []byte(`{"alpha":"{{.alpha}}"}`), 0666))
+	assert.Nil(t, afero.WriteFile(osFs, dir+fieldsDefinition, []byte("- name: alpha\n  type: keyword\n"), 0666))
+
+	cfg, err := config.LoadConfigFromYaml([]byte("record_separator: json_seq\nfields:\n  - name: alpha\n"))
+	assert.Nil(t, err)
+
+	target := "/corpus.json-seq"
+	fc := TestNewGenerator().WithOutput(target)
+	fc.config = cfg
+
+	_, err = fc.GenerateWithTemplate(dir+template, dir+fieldsDefinition, 10, time.Now(), 1)
+	assert.Nil(t, err)
+
+	data, err := afero.ReadFile(fc.fs, target)
+	assert.Nil(t, err)
+
+	// RFC 7464: each JSON text is preceded by a record separator and followed by a line feed
+	assert.True(t, bytes.HasPrefix(data, []byte{0x1e}))
+	texts := bytes.Split(data[1:], []byte{0x1e})
+	assert.Len(t, texts, 10)
+	for _, text := range texts {
+		assert.True(t, bytes.HasSuffix(text, []byte("\n")))
+		assert.True(t, json.Valid(bytes.TrimSuffix(text, []byte("\n"))), string(text))
+	}
+}
+
+func TestGeneratorCorpusWithCustomRecordSeparator(t *testing.T) {
+	template := "/template.tpl"
+	fieldsDefinition := "/fields.yml"
+	osFs := afero.NewOsFs()
+	dir := t.TempDir()
+	assert.Nil(t, afero.WriteFile(osFs, dir+template, []byte(`{"alpha":"{{.alpha}}"}`), 0666))
+	assert.Nil(t, afero.WriteFile(osFs, dir+fieldsDefinition, []byte("- name: alpha\n  type: keyword\n"), 0666))
+
+	cfg, err := config.LoadConfigFromYaml([]byte("record_separator: \"\\r\\n\"\nfields:\n  - name: alpha\n"))
+	assert.Nil(t, err)
+
+	target := "/corpus.json"
+	fc := TestNewGenerator().WithOutput(target)
+	fc.config = cfg
+
+	_, err = fc.GenerateWithTemplate(dir+template, dir+fieldsDefinition, 10, time.Now(), 1)
+	assert.Nil(t, err)
+
+	data, err := afero.ReadFile(fc.fs, target)
+	assert.Nil(t, err)
+
+	documents := strings.Split(string(data), "\r\n")
+	assert.Len(t, documents, 11)
+	assert.Empty(t, documents[10])
+	for _, document := range documents[:10] {
+		assert.True(t, json.Valid([]byte(document)), document)
+	}
+}
+
+func TestGeneratorCorpusWithNotValidRecordSeparator(t *testing.T) {
+	cfg, err := config.LoadConfigFromYaml([]byte("record_separator: json_seq\nfields:\n  - name: alpha\n"))
+	assert.Nil(t, err)
+
+	fc := TestNewGenerator().WithOutput(OutputTargetDiscard)
+	fc.config = cfg
+
+	_, err = fc.Generate("http://localhost", "integration", "data_stream", "1.2.3", 10, time.Now(), 1)
+	assert.ErrorIs(t, err, ErrNotValidRecordSeparator)
+}
+
+func TestGeneratorCorpusWithWarmup(t *testing.T) {
+	template := "/template.tpl"
+	fieldsDefinition := "/fields.yml"
+	osFs := afero.NewOsFs()
+	dir := t.TempDir()
+	assert.Nil(t, afero.WriteFile(osFs, dir+template, []byte(`{"timestamp":"{{.timestamp}}","alpha":{{.alpha}}}`), 0666))
+	assert.Nil(t, afero.WriteFile(osFs, dir+fieldsDefinition, []byte("- name: timestamp\n  type: date\n- name: alpha\n  type: long\n"), 0666))
+
+	cfg, err := config.LoadConfigFromYaml([]byte("fields:\n  - name: timestamp\n    mode: aligned\n    interval: 1m\n    base: \"2023-01-01T00:00:00-00:00\"\n  - name: alpha\n    fuzziness: 0.1\n    range:\n      min: 1\n      max: 1000\n"))
+	assert.Nil(t, err)
+
+	timeNow := time.Now()
+	fc := TestNewGenerator()
+	fc.config = cfg
+
+	_, err = fc.WithOutput("/corpus.ndjson").GenerateWithTemplate(dir+template, dir+fieldsDefinition, 15, timeNow, 1)
+	assert.Nil(t, err)
+
+	_, err = fc.WithOutput("/warmup.ndjson").WithWarmup(5).GenerateWithTemplate(dir+template, dir+fieldsDefinition, 10, timeNow, 1)
+	assert.Nil(t, err)
+
+	data, err := afero.ReadFile(fc.fs, "/corpus.ndjson")
+	assert.Nil(t, err)
+	documents := strings.Split(strings.TrimSpace(string(data)), "\n")
+
+	data, err = afero.ReadFile(fc.fs, "/warmup.ndjson")
+	assert.Nil(t, err)
+	warmupDocuments := strings.Split(strings.TrimSpace(string(data)), "\n")
+
+	// the first document written after the warmup is the 6th document generated
+	assert.Len(t, warmupDocuments, 10)
+	assert.Equal(t, documents[5:], warmupDocuments)
+
+	var document map[string]any
+	assert.Nil(t, json.Unmarshal([]byte(warmupDocuments[0]), &document))
+	assert.Equal(t, "2023-01-01T00:05:00Z", document["timestamp"])
+}
@@ -0,0 +1,142 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package corpus
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"os"
+	"path"
+
+	"github.com/spf13/afero"
+)
+
+// Naming strategies of the chunks written by RolloverWriter
+const (
+	// ChunkNamingSequence names the chunks `<prefix>-<n><ext>`, n starting from 1
+	ChunkNamingSequence = "sequence"
+	// ChunkNamingContentHash names the chunks `<sha256 of the content><ext>`, so that identical chunks have the same name
+	ChunkNamingContentHash = "content_hash"
+)
+
+var ErrNotValidRollover = errors.New("please, pass --rollover-bytes greater than 0, --chunk-naming as one of 'sequence' or 'content_hash', and no --output nor --gzip")
+
+// RolloverWriter is an io.WriteCloser writing to a new chunk file in a folder once the current one reaches maxBytes.
+// A chunk ends after the Write reaching maxBytes, so that chunks end at document boundaries if each Write is a
+// whole document.
+type RolloverWriter struct {
+	fs       afero.Fs
+	location string
+	prefix   string
+	ext      string
+	maxBytes int64
+	naming   string
+
+	current     afero.File
+	currentName string
+	written     int64
+	hash        hash.Hash
+	chunks      []string
+}
+
+// NewRolloverWriter returns a RolloverWriter writing chunks of at least maxBytes to location, named according to naming
+func NewRolloverWriter(fs afero.Fs, location, prefix, ext string, maxBytes int64, naming string) (*RolloverWriter, error) {
+	if maxBytes <= 0 || (naming != ChunkNamingSequence && naming != ChunkNamingContentHash) {
+		return nil, ErrNotValidRollover
+	}
+
+	return &RolloverWriter{
+		fs:       fs,
+		location: location,
+		prefix:   prefix,
+		ext:      ext,
+		maxBytes: maxBytes,
+		naming:   naming,
+		hash:     sha256.New(),
+	}, nil
+}
+
+func (w *RolloverWriter) Write(p []byte) (int, error) {
+	if w.current == nil {
+		if err := w.open(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := w.current.Write(p)
+	w.written += int64(n)
+	w.hash.Write(p[:n])
+	if err != nil {
+		return n, err
+	}
+
+	if w.written >= w.maxBytes {
+		return n, w.finalize()
+	}
+
+	return n, nil
+}
+
+// Close finalizes the current chunk, if any
+func (w *RolloverWriter) Close() error {
+	if w.current == nil {
+		return nil
+	}
+
+	return w.finalize()
+}
+
+// Chunks returns the paths of the finalized chunks, in the order they were written
+func (w *RolloverWriter) Chunks() []string {
+	return w.chunks
+}
+
+func (w *RolloverWriter) chunkName() string {
+	if w.naming == ChunkNamingContentHash {
+		return path.Join(w.location, hex.EncodeToString(w.hash.Sum(nil))+w.ext)
+	}
+
+	return path.Join(w.location, fmt.Sprintf("%s-%d%s", w.prefix, len(w.chunks)+1, w.ext))
+}
+
+func (w *RolloverWriter) open() error {
+	// the name of a chunk named by its content is known only once it is finalized
+	name := w.chunkName()
+	if w.naming == ChunkNamingContentHash {
+		name = path.Join(w.location, fmt.Sprintf("%s-%d%s.partial", w.prefix, len(w.chunks)+1, w.ext))
+	}
+
+	f, err := w.fs.OpenFile(name, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, corpusPerm)
+	if err != nil {
+		return err
+	}
+
+	w.current = f
+	w.currentName = name
+	w.written = 0
+	w.hash.Reset()
+	return nil
+}
+
+func (w *RolloverWriter) finalize() error {
+	f := w.current
+	w.current = nil
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	name := w.chunkName()
+	if name != w.currentName {
+		if err := w.fs.Rename(w.currentName, name); err != nil {
+			return err
+		}
+	}
+
+	w.chunks = append(w.chunks, name)
+	return nil
+}
@@ -0,0 +1,90 @@
+package corpus
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+)
+
+// generateChunks generates a template based corpus split into chunks in a new in memory fs, returning the fs and
+// the names of the chunks
+func generateChunks(t *testing.T, chunkNaming string, timeNow time.Time, randSeed int64) (afero.Fs, []string) {
+	template := "/template.tpl"
+	fieldsDefinition := "/fields.yml"
+	osFs := afero.NewOsFs()
+	dir := t.TempDir()
+	assert.Nil(t, afero.WriteFile(osFs, dir+template, []byte(`{"alpha":"{{.alpha}}"}`), 0666))
+	assert.Nil(t, afero.WriteFile(osFs, dir+fieldsDefinition, []byte("- name: alpha\n  type: keyword\n"), 0666))
+
+	fc := TestNewGenerator().WithRollover(256, chunkNaming)
+	target, err := fc.GenerateWithTemplate(dir+template, dir+fieldsDefinition, 100, timeNow, randSeed)
+	assert.Nil(t, err)
+	assert.Equal(t, fc.location, target)
+
+	infos, err := afero.ReadDir(fc.fs, fc.location)
+	assert.Nil(t, err)
+
+	names := make([]string, 0, len(infos))
+	for _, info := range infos {
+		names = append(names, info.Name())
+	}
+
+	sort.Strings(names)
+	return fc.fs, names
+}
+
+func TestRolloverWriterContentHashNaming(t *testing.T) {
+	timeNow := time.Now()
+
+	fs, names := generateChunks(t, ChunkNamingContentHash, timeNow, 1)
+	assert.Greater(t, len(names), 1)
+
+	for _, name := range names {
+		assert.Len(t, name, 64+len(".tpl"))
+		assert.True(t, strings.HasSuffix(name, ".tpl"))
+
+		// chunks end at document boundaries
+		data, err := afero.ReadFile(fs, "testdata/"+name)
+		assert.Nil(t, err)
+		assert.True(t, strings.HasSuffix(string(data), "}\n"))
+	}
+
+	_, sameNames := generateChunks(t, ChunkNamingContentHash, timeNow, 1)
+	assert.Equal(t, names, sameNames)
+
+	_, otherNames := generateChunks(t, ChunkNamingContentHash, timeNow, 2)
+	for _, name := range otherNames {
+		assert.NotContains(t, names, name)
+	}
+}
+
+func TestRolloverWriterSequenceNaming(t *testing.T) {
+	fs, names := generateChunks(t, ChunkNamingSequence, time.Now(), 1)
+	assert.Greater(t, len(names), 1)
+
+	var corpus []byte
+	for i := range names {
+		data, err := afero.ReadFile(fs, fmt.Sprintf("testdata/1647345675-template-%d.tpl", i+1))
+		assert.Nil(t, err)
+		corpus = append(corpus, data...)
+	}
+
+	assert.Len(t, strings.Split(strings.TrimSpace(string(corpus)), "\n"), 100)
+}
+
+func TestRolloverWriterNotValid(t *testing.T) {
+	for _, fc := range []GeneratorCorpus{
+		TestNewGenerator().WithRollover(0, ChunkNamingSequence),
+		TestNewGenerator().WithRollover(256, "random"),
+		TestNewGenerator().WithRollover(256, ChunkNamingSequence).WithOutput(OutputTargetDiscard),
+		TestNewGenerator().WithRollover(256, ChunkNamingSequence).WithGzip(1),
+	} {
+		_, err := fc.GenerateWithTemplate("/template.tpl", "/fields.yml", 10, time.Now(), 1)
+		assert.ErrorIs(t, err, ErrNotValidRollover)
+	}
+}
@@ -0,0 +1,103 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package genlib
+
+import (
+	"bytes"
+	"fmt"
+)
+
+func isArray(cfg Config, field Field) bool {
+	fieldCfg, ok := cfg.GetField(field.Name)
+	return ok && fieldCfg.Array != nil
+}
+
+func validArray(fieldCfg ConfigField, field Field) error {
+	if fieldCfg.Array.Min < 0 || fieldCfg.Array.Min > fieldCfg.Array.Max {
+		return fmt.Errorf("field %s array must have a min not negative and not greater than max", field.Name)
+	}
+
+	if isEnumArray(fieldCfg) {
+		return fmt.Errorf("field %s cannot have both array and array_size", field.Name)
+	}
+
+	return nil
+}
+
+// arrayLength returns the number of elements of an array field for the current event
+func arrayLength(state *genState, fieldCfg ConfigField) int {
+	return fieldCfg.Array.Min + state.rand.Intn(fieldCfg.Array.Max-fieldCfg.Array.Min+1)
+}
+
+// startArrayElement sets in the state the index of the element of the array being generated
+func startArrayElement(state *genState, fieldCfg ConfigField, element int) {
+	state.arrayMax = fieldCfg.Array.Max
+	state.arrayElement = element
+}
+
+// endArray resets in the state the element of the array being generated, once the array is generated
+func endArray(state *genState) {
+	state.arrayMax = 0
+	state.arrayElement = 0
+}
+
+// bindArray wraps the bound field so that it is generated as an array of between `array.min` and `array.max`
+// elements, each generated as the value of the field, so that its config applies to each of them. With a `min`
+// of 0 the array can be empty.
+func bindArray(fieldCfg ConfigField, field Field, fieldMap map[string]any) error {
+	if err := validArray(fieldCfg, field); err != nil {
+		return err
+	}
+
+	switch elementF := fieldMap[field.Name].(type) {
+	case emitFNotReturn:
+		elementWrap := fieldValueWrapByFieldConfig(fieldCfg, field)
+
+		var emitFNotReturn emitFNotReturn
+		emitFNotReturn = func(state *genState, buf *bytes.Buffer) error {
+			defer endArray(state)
+
+			buf.WriteByte('[')
+			length := arrayLength(state, fieldCfg)
+			for i := length; i > 0; i-- {
+				buf.WriteString(elementWrap)
+				startArrayElement(state, fieldCfg, length-i)
+				if err := elementF(state, buf); err != nil {
+					return err
+				}
+
+				buf.WriteString(elementWrap)
+				if i > 1 {
+					buf.WriteByte(',')
+				}
+			}
+
+			buf.WriteByte(']')
+			return nil
+		}
+
+		fieldMap[field.Name] = emitFNotReturn
+	case emitF:
+		var emitF emitF
+		emitF = func(state *genState) any {
+			defer endArray(state)
+
+			length := arrayLength(state, fieldCfg)
+			array := make(arrayValues, 0, length)
+			for i := 0; i < length; i++ {
+				startArrayElement(state, fieldCfg, i)
+				array = append(array, elementF(state))
+			}
+
+			return array
+		}
+
+		fieldMap[field.Name] = emitF
+	default:
+		return fmt.Errorf("field %s of type %s cannot be generated as an array", field.Name, field.Type)
+	}
+
+	return nil
+}
@@ -0,0 +1,45 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package genlib
+
+import (
+	"github.com/elastic/elastic-integration-corpus-generator-tool/pkg/genlib/config"
+)
+
+// defaultASNs are the autonomous systems chosen from when no `asns` is set for an `asn` field
+// NOTE: this list is not comprehensive
+var defaultASNs = []config.ASN{
+	{Number: 701, Organization: "Verizon Business"},
+	{Number: 3320, Organization: "Deutsche Telekom AG"},
+	{Number: 3356, Organization: "Level 3 Parent, LLC"},
+	{Number: 4134, Organization: "Chinanet"},
+	{Number: 7922, Organization: "Comcast Cable Communications, LLC"},
+	{Number: 8075, Organization: "Microsoft Corporation"},
+	{Number: 13335, Organization: "Cloudflare, Inc."},
+	{Number: 15169, Organization: "Google LLC"},
+	{Number: 16509, Organization: "Amazon.com, Inc."},
+	{Number: 32934, Organization: "Facebook, Inc."},
+}
+
+func asnTable(fieldCfg ConfigField) []config.ASN {
+	if len(fieldCfg.ASNs) == 0 {
+		return defaultASNs
+	}
+
+	return fieldCfg.ASNs
+}
+
+// asnForEvent returns the table row of an `asn` field for the current event,
+// so that number and organization name are consistent regardless of the order they are emitted.
+func asnForEvent(fieldName string, asns []config.ASN, state *genState) config.ASN {
+	if value, ok := state.eventValue(fieldName); ok {
+		return value.(config.ASN)
+	}
+
+	asn := asns[state.rand.Intn(len(asns))]
+	state.setEventValue(fieldName, asn)
+
+	return asn
+}
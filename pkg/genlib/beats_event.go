@@ -0,0 +1,46 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package genlib
+
+// Versions of the envelope of the Beats events when `agent.version` and `ecs.version` are neither in the fields
+// definition nor in the config
+const (
+	beatsDefaultAgentType    = "filebeat"
+	beatsDefaultAgentVersion = "8.11.0"
+	beatsDefaultECSVersion   = "8.11.0"
+)
+
+// beatsEventFields are the fields of the envelope of the events published by Beats (ie: Filebeat and Metricbeat)
+var beatsEventFields = Fields{
+	{Name: "@timestamp", Type: FieldTypeDate},
+	{Name: "agent.type", Type: FieldTypeKeyword, Value: beatsDefaultAgentType},
+	{Name: "agent.version", Type: FieldTypeKeyword, Value: beatsDefaultAgentVersion},
+	{Name: "ecs.version", Type: FieldTypeKeyword, Value: beatsDefaultECSVersion},
+	{Name: "host.name", Type: FieldTypeHostname},
+}
+
+// withBeatsEventFields returns flds preceded by the fields of the envelope of the Beats events missing from them.
+// The default value of an envelope field is not set if the field is in the config, so that it can be overridden.
+func withBeatsEventFields(cfg Config, flds Fields) Fields {
+	defined := make(map[string]struct{}, len(flds))
+	for _, field := range flds {
+		defined[field.Name] = struct{}{}
+	}
+
+	withEnvelope := make(Fields, 0, len(beatsEventFields)+len(flds))
+	for _, field := range beatsEventFields {
+		if _, ok := defined[field.Name]; ok {
+			continue
+		}
+
+		if _, ok := cfg.GetField(field.Name); ok {
+			field.Value = ""
+		}
+
+		withEnvelope = append(withEnvelope, field)
+	}
+
+	return append(withEnvelope, flds...)
+}
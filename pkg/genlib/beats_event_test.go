@@ -0,0 +1,94 @@
+package genlib
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/elastic/elastic-integration-corpus-generator-tool/pkg/genlib/config"
+)
+
+// sampleBeatsEvent is a document as published by Filebeat, trimmed to the fields the test generates
+const sampleBeatsEvent = `{
+  "@timestamp": "2023-10-16T22:00:00.000Z",
+  "agent": {"type": "filebeat", "version": "8.11.0"},
+  "ecs": {"version": "8.11.0"},
+  "host": {"name": "web-01"},
+  "event": {"dataset": "nginx.access", "duration": 1200},
+  "source": {"ip": "10.1.2.3", "port": 51234}
+}`
+
+// assertSameShape asserts that generated has the keys of sample, with values of the same JSON type and the same
+// nested objects
+func assertSameShape(t *testing.T, path string, sample, generated any) {
+	t.Helper()
+
+	sampleObject, ok := sample.(map[string]any)
+	if !ok {
+		if fmt.Sprintf("%T", sample) != fmt.Sprintf("%T", generated) {
+			t.Errorf("Expected %s to be %T, got %T", path, sample, generated)
+		}
+
+		return
+	}
+
+	generatedObject, ok := generated.(map[string]any)
+	if !ok {
+		t.Errorf("Expected %s to be an object, got %T", path, generated)
+		return
+	}
+
+	if len(generatedObject) != len(sampleObject) {
+		t.Errorf("Expected %s to have %d keys, got %v", path, len(sampleObject), generatedObject)
+	}
+
+	for key, value := range sampleObject {
+		assertSameShape(t, path+"/"+key, value, generatedObject[key])
+	}
+}
+
+func Test_GeneratorBeatsEvent(t *testing.T) {
+	flds := Fields{
+		{Name: "event.dataset", Type: FieldTypeConstantKeyword, Value: "nginx.access"},
+		{Name: "event.duration", Type: FieldTypeLong},
+		{Name: "source.ip", Type: FieldTypeIP},
+		{Name: "source.port", Type: FieldTypeLong},
+	}
+
+	cfg, err := config.LoadConfigFromYaml([]byte("output_mode: beats\nfields:\n  - name: agent.version\n    value: 8.12.0\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	g, err := NewGenerator(cfg, flds, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var sample map[string]any
+	if err := json.Unmarshal([]byte(sampleBeatsEvent), &sample); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 10; i++ {
+		var buf bytes.Buffer
+		if err := g.Emit(&buf); err != nil {
+			t.Fatal(err)
+		}
+
+		m := unmarshalJSONT[any](t, buf.Bytes())
+		assertSameShape(t, "", sample, map[string]any(m))
+
+		if _, err := time.Parse(FieldTypeTimeLayout, fmt.Sprint(m["@timestamp"])); err != nil {
+			t.Errorf("Expected a @timestamp, got %s", buf.String())
+		}
+
+		// the envelope defaults are overridden by the config
+		agent, _ := m["agent"].(map[string]any)
+		if agent["type"] != "filebeat" || agent["version"] != "8.12.0" {
+			t.Errorf("Expected filebeat 8.12.0 agent, got %s", buf.String())
+		}
+	}
+}
@@ -0,0 +1,81 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package genlib
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// cachedValue returns the value cached for the field if it was generated less than cacheFor events ago
+func (state *genState) cachedValue(fieldName string, cacheFor uint64) (any, bool) {
+	v, ok := state.cachedValues[fieldName]
+	if !ok || state.counter-v.counter >= cacheFor {
+		return nil, false
+	}
+
+	return v.value, true
+}
+
+func (state *genState) setCachedValue(fieldName string, value any) {
+	state.cachedValues[fieldName] = eventValue{counter: state.counter, value: value}
+}
+
+// bindCacheFor wraps the field so that its value is generated once every `cache_for` events, and the same value is
+// emitted in the events in between, like `agent.version` that rarely changes in a run
+func bindCacheFor(fieldCfg ConfigField, field Field, fieldMap map[string]any, withReturn bool) error {
+	if fieldCfg.CacheFor < 0 {
+		return fmt.Errorf("field %s cache_for must be positive", field.Name)
+	}
+
+	cacheFor := uint64(fieldCfg.CacheFor)
+
+	if withReturn {
+		boundF, ok := fieldMap[field.Name].(emitF)
+		if !ok {
+			return fmt.Errorf("cannot bind field %s with cache_for", field.Name)
+		}
+
+		var emitF emitF
+		emitF = func(state *genState) any {
+			if value, ok := state.cachedValue(field.Name, cacheFor); ok {
+				return value
+			}
+
+			value := boundF(state)
+			state.setCachedValue(field.Name, value)
+			return value
+		}
+
+		fieldMap[field.Name] = emitF
+		return nil
+	}
+
+	boundF, ok := fieldMap[field.Name].(emitFNotReturn)
+	if !ok {
+		return fmt.Errorf("cannot bind field %s with cache_for", field.Name)
+	}
+
+	var emitFNotReturn emitFNotReturn
+	emitFNotReturn = func(state *genState, buf *bytes.Buffer) error {
+		if value, ok := state.cachedValue(field.Name, cacheFor); ok {
+			buf.Write(value.([]byte))
+			return nil
+		}
+
+		start := buf.Len()
+		if err := boundF(state, buf); err != nil {
+			return err
+		}
+
+		value := make([]byte, buf.Len()-start)
+		copy(value, buf.Bytes()[start:])
+		state.setCachedValue(field.Name, value)
+		return nil
+	}
+
+	fieldMap[field.Name] = emitFNotReturn
+	return nil
+}
@@ -0,0 +1,92 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package genlib
+
+import (
+	"fmt"
+	"math/rand"
+	"net"
+
+	"github.com/elastic/elastic-integration-corpus-generator-tool/pkg/genlib/config"
+)
+
+// default prefix length bounds of generated CIDRs, when no `range` is set
+const (
+	cidrDefaultMinPrefixV4 = 8
+	cidrDefaultMaxPrefixV4 = 32
+	cidrDefaultMinPrefixV6 = 16
+	cidrDefaultMaxPrefixV6 = 128
+)
+
+type cidrPrefixRange struct {
+	bits      int
+	minPrefix int
+	maxPrefix int
+}
+
+func makeCIDRPrefixRange(fieldCfg ConfigField, bits, defaultMinPrefix, defaultMaxPrefix int) (cidrPrefixRange, error) {
+	prefixRange := cidrPrefixRange{bits: bits, minPrefix: defaultMinPrefix, maxPrefix: defaultMaxPrefix}
+	if minPrefix, err := fieldCfg.Range.MinAsInt64(); err == nil {
+		prefixRange.minPrefix = int(minPrefix)
+	}
+
+	if maxPrefix, err := fieldCfg.Range.MaxAsInt64(); err == nil {
+		prefixRange.maxPrefix = int(maxPrefix)
+	}
+
+	if prefixRange.minPrefix < 0 || prefixRange.maxPrefix > bits || prefixRange.minPrefix > prefixRange.maxPrefix {
+		return cidrPrefixRange{}, fmt.Errorf("invalid CIDR prefix length range [%d, %d] for %d bits addresses", prefixRange.minPrefix, prefixRange.maxPrefix, bits)
+	}
+
+	return prefixRange, nil
+}
+
+// makeCIDRFunc returns a function generating CIDRs in canonical network address form (ie: `10.1.2.0/24`),
+// with prefix length within `range` and of the IP version set by `ip_version`
+func makeCIDRFunc(fieldCfg ConfigField) (func(r *rand.Rand) string, error) {
+	var prefixRanges []cidrPrefixRange
+
+	switch fieldCfg.IPVersion {
+	case "", config.IPVersion4, config.IPVersion6, config.IPVersionBoth:
+	default:
+		return nil, fmt.Errorf("invalid ip_version: %s", fieldCfg.IPVersion)
+	}
+
+	if fieldCfg.IPVersion != config.IPVersion6 {
+		prefixRange, err := makeCIDRPrefixRange(fieldCfg, net.IPv4len*8, cidrDefaultMinPrefixV4, cidrDefaultMaxPrefixV4)
+		if err != nil {
+			return nil, err
+		}
+
+		prefixRanges = append(prefixRanges, prefixRange)
+	}
+
+	if fieldCfg.IPVersion == config.IPVersion6 || fieldCfg.IPVersion == config.IPVersionBoth {
+		prefixRange, err := makeCIDRPrefixRange(fieldCfg, net.IPv6len*8, cidrDefaultMinPrefixV6, cidrDefaultMaxPrefixV6)
+		if err != nil {
+			return nil, err
+		}
+
+		prefixRanges = append(prefixRanges, prefixRange)
+	}
+
+	return func(r *rand.Rand) string {
+		prefixRange := prefixRanges[r.Intn(len(prefixRanges))]
+		return randCIDR(r, prefixRange)
+	}, nil
+}
+
+func randCIDR(r *rand.Rand, prefixRange cidrPrefixRange) string {
+	ip := make(net.IP, prefixRange.bits/8)
+	_, _ = r.Read(ip)
+
+	prefix := prefixRange.minPrefix + r.Intn(prefixRange.maxPrefix-prefixRange.minPrefix+1)
+	ipNet := net.IPNet{
+		IP:   ip.Mask(net.CIDRMask(prefix, prefixRange.bits)),
+		Mask: net.CIDRMask(prefix, prefixRange.bits),
+	}
+
+	return ipNet.String()
+}
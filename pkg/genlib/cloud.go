@@ -0,0 +1,92 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package genlib
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+// cloudRegion is a region of a cloud provider with its availability zones
+type cloudRegion struct {
+	name              string
+	availabilityZones []string
+}
+
+// cloudProvider is a cloud provider with its regions
+type cloudProvider struct {
+	name    string
+	regions []cloudRegion
+	// accountID returns a random account id in the format of the provider
+	accountID func(r *rand.Rand) string
+}
+
+// cloudTable are the cloud providers, regions and availability zones chosen from for a `cloud` field
+// NOTE: this list is not comprehensive
+var cloudTable = []cloudProvider{
+	{
+		name: "aws",
+		regions: []cloudRegion{
+			{name: "us-east-1", availabilityZones: []string{"us-east-1a", "us-east-1b", "us-east-1c"}},
+			{name: "us-west-2", availabilityZones: []string{"us-west-2a", "us-west-2b", "us-west-2c"}},
+			{name: "eu-west-1", availabilityZones: []string{"eu-west-1a", "eu-west-1b", "eu-west-1c"}},
+			{name: "ap-southeast-1", availabilityZones: []string{"ap-southeast-1a", "ap-southeast-1b"}},
+		},
+		accountID: func(r *rand.Rand) string {
+			return fmt.Sprintf("%012d", r.Int63n(1000000000000))
+		},
+	},
+	{
+		name: "gcp",
+		regions: []cloudRegion{
+			{name: "us-central1", availabilityZones: []string{"us-central1-a", "us-central1-b", "us-central1-c", "us-central1-f"}},
+			{name: "europe-west1", availabilityZones: []string{"europe-west1-b", "europe-west1-c", "europe-west1-d"}},
+			{name: "asia-east1", availabilityZones: []string{"asia-east1-a", "asia-east1-b", "asia-east1-c"}},
+		},
+		accountID: func(r *rand.Rand) string {
+			return fmt.Sprintf("elastic-project-%06d", r.Intn(1000000))
+		},
+	},
+	{
+		name: "azure",
+		regions: []cloudRegion{
+			{name: "eastus", availabilityZones: []string{"eastus-1", "eastus-2", "eastus-3"}},
+			{name: "westeurope", availabilityZones: []string{"westeurope-1", "westeurope-2", "westeurope-3"}},
+			{name: "southeastasia", availabilityZones: []string{"southeastasia-1", "southeastasia-2", "southeastasia-3"}},
+		},
+		accountID: func(r *rand.Rand) string {
+			return fmt.Sprintf("%08x-%04x-%04x-%04x-%012x", r.Uint32(), r.Intn(0x10000), r.Intn(0x10000), r.Intn(0x10000), r.Int63n(0x1000000000000))
+		},
+	},
+}
+
+// cloud is the generated value of a `cloud` field
+type cloud struct {
+	provider         string
+	accountID        string
+	region           string
+	availabilityZone string
+}
+
+// cloudForEvent returns the cloud metadata of a `cloud` field for the current event, so that the availability zone
+// belongs to the region and the region to the provider regardless of the order they are emitted.
+func cloudForEvent(fieldName string, state *genState) cloud {
+	if value, ok := state.eventValue(fieldName); ok {
+		return value.(cloud)
+	}
+
+	provider := cloudTable[state.rand.Intn(len(cloudTable))]
+	region := provider.regions[state.rand.Intn(len(provider.regions))]
+	c := cloud{
+		provider:         provider.name,
+		accountID:        provider.accountID(state.rand),
+		region:           region.name,
+		availabilityZone: region.availabilityZones[state.rand.Intn(len(region.availabilityZones))],
+	}
+
+	state.setEventValue(fieldName, c)
+
+	return c
+}
@@ -2,10 +2,12 @@ package config
 
 import (
 	"errors"
+	"fmt"
 	"time"
 
 	"math"
 	"os"
+	"strings"
 
 	"github.com/elastic/go-ucfg/yaml"
 	"github.com/spf13/afero"
@@ -14,6 +16,110 @@ import (
 var rangeBoundNotSet = errors.New("range bound not set")
 var rangeTimeNotSet = errors.New("range time not set")
 var rangeInvalidConfig = errors.New("range defining both `period` and `from`/`to`")
+var alignedInvalidConfig = errors.New("`aligned` mode requires a positive `interval` and no `period` nor `from`/`to`")
+var monotonicInvalidConfig = errors.New("`monotonic` mode requires a not negative `max_delta` and no `period`, `interval` nor `from`/`to`, and `max_delta` requires `monotonic` mode")
+var jitterInvalidConfig = errors.New("`jitter` requires `aligned` mode, a positive duration and a `jitter_distribution` of `uniform` or `normal`")
+var formatInvalidConfig = errors.New("`format` must be one of `apache_clf`, `nginx`, `syslog_bsd`, `epoch_millis`, `epoch_second`, a named golang layout (ie: `RFC3339`) or a golang layout")
+var distributionInvalidConfig = errors.New("`distribution` must be `uniform` or `zipf`, the latter requiring an `enum` without weights and a `zipf_s` greater than 1 and a `zipf_v` not lower than 1 when set")
+var enumWeightsInvalidConfig = errors.New("`enum` values must be either all plain values or all `value` and `weight` pairs, with weights not negative and not all zero")
+var keyStyleInvalidConfig = errors.New("`key_style` must be one of `dotted`, `snake`, `camel` or `nested`")
+var maxFieldsPerDocInvalidConfig = errors.New("`max_fields_per_doc` must not be negative")
+var outputModeInvalidConfig = errors.New("`output_mode` must be `json` or `beats`, the latter requiring a `key_style` of `dotted` or `nested`")
+var ErrRangeMinMaxInvalidConfig = errors.New("`range` `min` must not be greater than `max`")
+var minMaxInvalidConfig = errors.New("`min` must not be greater than `max`")
+var geoBoundsInvalidConfig = errors.New("`geo_bounds` requires `lat` between -90 and 90 and `lon` between -180 and 180 for both `top_left` and `bottom_right`, with `top_left` north-west of `bottom_right`")
+var activeSubsetInvalidConfig = errors.New("`active_subset` requires an `enum` and must be between 1 and the number of its values")
+var cardinalityGroupInvalidConfig = errors.New("`cardinality_group` requires a positive `cardinality`")
+var nullProbabilityInvalidConfig = errors.New("`null_probability` must be between 0 and 1, and `null_mode` `omit` or `null`")
+
+// Modes of date fields
+const (
+	// DateModeAligned generates the date of the n-th event as `base + n*interval`
+	DateModeAligned = "aligned"
+	// DateModeMonotonic generates the date of each event as the one of the previous event plus a random delta up to `max_delta`
+	DateModeMonotonic = "monotonic"
+)
+
+// Distributions of the `jitter` applied to `aligned` dates
+const (
+	JitterDistributionUniform = "uniform"
+	JitterDistributionNormal  = "normal"
+)
+
+// Date format presets for log timestamps not in ISO 8601 format
+const (
+	DateFormatApacheCLF = "apache_clf"
+	DateFormatNginx     = "nginx"
+	DateFormatSyslogBSD = "syslog_bsd"
+)
+
+// Date formats of epoch timestamps, emitted as integers
+const (
+	DateFormatEpochMillis = "epoch_millis"
+	DateFormatEpochSecond = "epoch_second"
+)
+
+// Date formats named after the golang layout constants of the time package
+const (
+	DateFormatRFC3339     = "RFC3339"
+	DateFormatRFC3339Nano = "RFC3339Nano"
+	DateFormatRFC1123     = "RFC1123"
+	DateFormatRFC1123Z    = "RFC1123Z"
+	DateFormatRFC822      = "RFC822"
+	DateFormatRFC822Z     = "RFC822Z"
+	DateFormatANSIC       = "ANSIC"
+	DateFormatUnixDate    = "UnixDate"
+)
+
+// Distributions of the depth of generated paths
+const (
+	DepthDistributionUniform   = "uniform"
+	DepthDistributionGeometric = "geometric"
+)
+
+// Distributions of the values chosen from an `enum`
+const (
+	DistributionUniform = "uniform"
+	DistributionZipf    = "zipf"
+)
+
+// GeoFormatGeohash emits `geo_point` values as geohash strings instead of `lat,lon`
+const GeoFormatGeohash = "geohash"
+
+const (
+	IPVersion4    = "v4"
+	IPVersion6    = "v6"
+	IPVersionBoth = "both"
+)
+
+const (
+	KeyStyleDotted = "dotted"
+	KeyStyleSnake  = "snake"
+	KeyStyleCamel  = "camel"
+	KeyStyleNested = "nested"
+)
+
+const (
+	OutputModeJSON  = "json"
+	OutputModeBeats = "beats"
+)
+
+// Presets of the `record_separator` written between documents
+const (
+	// RecordSeparatorNewline terminates each document with a line feed, as in newline delimited JSON
+	RecordSeparatorNewline = "newline"
+	// RecordSeparatorJSONSeq starts each document with an ASCII record separator (0x1e) and terminates it with a
+	// line feed, as in JSON text sequences (RFC 7464)
+	RecordSeparatorJSONSeq = "json_seq"
+)
+
+// Modes of the fields missing with `null_probability`
+const (
+	// NullModeOmit omits the field from the document
+	NullModeOmit = "omit"
+	// NullModeNull emits the field with a JSON `null` value
+	NullModeNull = "null"
+)
 
 type TimeRange struct {
 	time.Time
@@ -33,19 +139,232 @@ type Range struct {
 	To   *TimeRange `config:"to"`
 }
 
+// ArrayLength is the range of the number of elements of a field generated as an array
+type ArrayLength struct {
+	Min int `config:"min"`
+	Max int `config:"max"`
+}
+
+// WordCount is the range of the number of words of the values of a `text` field
+type WordCount struct {
+	Min int `config:"min"`
+	Max int `config:"max"`
+}
+
+// ASN is a row of the table autonomous system numbers and organizations are chosen from
+type ASN struct {
+	Number       int64  `config:"number"`
+	Organization string `config:"organization"`
+}
+
+// IPPool is a CIDR IP addresses are drawn from, with the weight of the CIDR among the pools of the field
+type IPPool struct {
+	CIDR   string  `config:"cidr"`
+	Weight float64 `config:"weight"`
+}
+
+// GeoPosition is a coordinate of a `geo_bounds` corner
+type GeoPosition struct {
+	Lat *float64 `config:"lat"`
+	Lon *float64 `config:"lon"`
+}
+
+// GeoBounds is the bounding box `geo_point` values are generated within
+type GeoBounds struct {
+	TopLeft     GeoPosition `config:"top_left"`
+	BottomRight GeoPosition `config:"bottom_right"`
+}
+
+// valid returns whether both corners are set and within bounds, and the top left corner is north-west of the bottom
+// right one: boxes crossing the antimeridian are not supported
+func (gb GeoBounds) valid() bool {
+	for _, position := range []GeoPosition{gb.TopLeft, gb.BottomRight} {
+		if position.Lat == nil || position.Lon == nil || *position.Lat < -90 || *position.Lat > 90 || *position.Lon < -180 || *position.Lon > 180 {
+			return false
+		}
+	}
+
+	return *gb.TopLeft.Lat > *gb.BottomRight.Lat && *gb.TopLeft.Lon < *gb.BottomRight.Lon
+}
+
+// EnumValue is a value of `enum`: either a plain value or a `value` and `weight` pair (ie: `{value: ACCEPT, weight: 95}`)
+type EnumValue struct {
+	Value  string
+	Weight *float64
+}
+
+// Unpack unpacks the plain values as they were unpacked to string, and the `value` and `weight` pairs
+func (e *EnumValue) Unpack(v interface{}) error {
+	pair, ok := v.(map[string]interface{})
+	if !ok {
+		e.Value = fmt.Sprint(v)
+		return nil
+	}
+
+	value, ok := pair["value"]
+	if !ok {
+		return enumWeightsInvalidConfig
+	}
+
+	e.Value = fmt.Sprint(value)
+
+	var weight float64
+	switch w := pair["weight"].(type) {
+	case int64:
+		weight = float64(w)
+	case uint64:
+		weight = float64(w)
+	case float64:
+		weight = w
+	default:
+		return enumWeightsInvalidConfig
+	}
+
+	e.Weight = &weight
+	return nil
+}
+
+// enumOfValues returns the values of the enum and their weights, nil if the values are not weighted
+func enumOfValues(enumValues []EnumValue) ([]string, []float64, error) {
+	if len(enumValues) == 0 {
+		return nil, nil, nil
+	}
+
+	values := make([]string, 0, len(enumValues))
+	weighted := enumValues[0].Weight != nil
+	var weights []float64
+	var totWeight float64
+	for _, enumValue := range enumValues {
+		if (enumValue.Weight != nil) != weighted {
+			return nil, nil, enumWeightsInvalidConfig
+		}
+
+		values = append(values, enumValue.Value)
+		if !weighted {
+			continue
+		}
+
+		if *enumValue.Weight < 0 {
+			return nil, nil, enumWeightsInvalidConfig
+		}
+
+		weights = append(weights, *enumValue.Weight)
+		totWeight += *enumValue.Weight
+	}
+
+	if weighted && totWeight <= 0 {
+		return nil, nil, enumWeightsInvalidConfig
+	}
+
+	return values, weights, nil
+}
+
+// SQLTable is a table, with its columns, SQL statements are generated for
+type SQLTable struct {
+	Name    string   `config:"name"`
+	Columns []string `config:"columns"`
+}
+
 type Config struct {
-	m map[string]ConfigField
+	m               map[string]ConfigField
+	keyStyle        string
+	maxFieldsPerDoc int
+	outputMode      string
+	recordSeparator string
 }
 
 type ConfigField struct {
-	Name        string        `config:"name"`
-	Fuzziness   float64       `config:"fuzziness"`
-	Range       Range         `config:"range"`
-	Cardinality int           `config:"cardinality"`
-	Period      time.Duration `config:"period"`
-	Enum        []string      `config:"enum"`
-	ObjectKeys  []string      `config:"object_keys"`
-	Value       any           `config:"value"`
+	Name                string              `config:"name"`
+	Fuzziness           float64             `config:"fuzziness"`
+	Range               Range               `config:"range"`
+	Min                 *float64            `config:"min"`
+	Max                 *float64            `config:"max"`
+	Cardinality         int                 `config:"cardinality"`
+	Period              time.Duration       `config:"period"`
+	Enum                []string            `config:",ignore"`
+	EnumWeights         []float64           `config:",ignore"`
+	EnumValues          []EnumValue         `config:"enum"`
+	ActiveSubset        int                 `config:"active_subset"`
+	ObjectKeys          []string            `config:"object_keys"`
+	Value               any                 `config:"value"`
+	KeywordMultiField   bool                `config:"keyword_multi_field"`
+	RawJSON             string              `config:"raw_json"`
+	Unique              bool                `config:"unique"`
+	Mode                string              `config:"mode"`
+	Interval            time.Duration       `config:"interval"`
+	Base                *TimeRange          `config:"base"`
+	DependsOn           string              `config:"depends_on"`
+	EnumByValue         map[string][]string `config:"enum_by_value"`
+	FirstOnly           bool                `config:"first_only"`
+	NullProbability     float64             `config:"null_probability"`
+	NullMode            string              `config:"null_mode"`
+	CacheFor            int                 `config:"cache_for"`
+	IPVersion           string              `config:"ip_version"`
+	IPPools             []IPPool            `config:"ip_pools"`
+	GeoFormat           string              `config:"geo_format"`
+	GeohashPrecision    int                 `config:"geohash_precision"`
+	GeoBounds           *GeoBounds          `config:"geo_bounds"`
+	GeometryTypes       []string            `config:"geometry_types"`
+	Format              string              `config:"format"`
+	RelatedFields       []string            `config:"related_fields"`
+	ArraySize           int                 `config:"array_size"`
+	Array               *ArrayLength        `config:"array"`
+	UniqueWithinDoc     bool                `config:"unique_within_doc"`
+	OmitIntegerDecimals bool                `config:"omit_integer_decimals"`
+	NumericAsString     bool                `config:"numeric_as_string"`
+	DurationOf          []string            `config:"duration_of"`
+	Jitter              time.Duration       `config:"jitter"`
+	JitterDistribution  string              `config:"jitter_distribution"`
+	ASNs                []ASN               `config:"asns"`
+	Locale              string              `config:"locale"`
+	DepthDistribution   string              `config:"depth_distribution"`
+	DepthProbability    float64             `config:"depth_probability"`
+	Hives               []string            `config:"hives"`
+	Entity              string              `config:"entity"`
+	EntityCacheSize     int                 `config:"entity_cache_size"`
+	Dimension           bool                `config:"dimension"`
+	CardinalityGroup    string              `config:"cardinality_group"`
+	SessionLength       int                 `config:"session_length"`
+	UserPoolSize        int                 `config:"user_pool_size"`
+	Length              int                 `config:"length"`
+	UUIDVersion         int                 `config:"uuid_version"`
+	Pattern             string              `config:"pattern"`
+	ValueFromTemplate   string              `config:"value_from_template"`
+	Words               *WordCount          `config:"words"`
+	Punctuation         bool                `config:"punctuation"`
+	Distribution        string              `config:"distribution"`
+	ZipfS               float64             `config:"zipf_s"`
+	ZipfV               float64             `config:"zipf_v"`
+	LagOf               string              `config:"lag_of"`
+	MaxLag              time.Duration       `config:"max_lag"`
+	MaxDelta            time.Duration       `config:"max_delta"`
+	RatioOf             string              `config:"ratio_of"`
+	Ratio               float64             `config:"ratio"`
+	RatioNoise          float64             `config:"ratio_noise"`
+	DirectionOf         []string            `config:"direction_of"`
+	InternalNetworks    []string            `config:"internal_networks"`
+	SQLTables           []SQLTable          `config:"sql_tables"`
+	SQLStatementWeights map[string]float64  `config:"sql_statement_weights"`
+	Polymorphic         map[string]float64  `config:"polymorphic"`
+	Sequence            []any               `config:"sequence"`
+	Validity            time.Duration       `config:"validity"`
+	Seed                *int64              `config:"seed"`
+}
+
+// Validate is called once the field is unpacked: it splits the values of `enum` into Enum and their weights into
+// EnumWeights, if any
+func (cf *ConfigField) Validate() error {
+	if len(cf.EnumValues) == 0 {
+		return nil
+	}
+
+	enum, enumWeights, err := enumOfValues(cf.EnumValues)
+	if err != nil {
+		return fmt.Errorf("%w: field %s", err, cf.Name)
+	}
+
+	cf.Enum, cf.EnumWeights, cf.EnumValues = enum, enumWeights, nil
+	return nil
 }
 
 func (cf ConfigField) ValidForDateField() error {
@@ -53,6 +372,57 @@ func (cf ConfigField) ValidForDateField() error {
 		return rangeInvalidConfig
 	}
 
+	if cf.Mode == DateModeAligned && (cf.Interval <= 0 || cf.Period.Abs() > 0 || cf.Range.From != nil || cf.Range.To != nil) {
+		return alignedInvalidConfig
+	}
+
+	if cf.MaxDelta != 0 && cf.Mode != DateModeMonotonic {
+		return monotonicInvalidConfig
+	}
+
+	if cf.Mode == DateModeMonotonic && (cf.MaxDelta < 0 || cf.Interval != 0 || cf.Period.Abs() > 0 || cf.Range.From != nil || cf.Range.To != nil) {
+		return monotonicInvalidConfig
+	}
+
+	if cf.Jitter != 0 || len(cf.JitterDistribution) > 0 {
+		if cf.Mode != DateModeAligned || cf.Jitter <= 0 {
+			return jitterInvalidConfig
+		}
+
+		switch cf.JitterDistribution {
+		case "", JitterDistributionUniform, JitterDistributionNormal:
+		default:
+			return jitterInvalidConfig
+		}
+	}
+
+	switch cf.Format {
+	case "", DateFormatApacheCLF, DateFormatNginx, DateFormatSyslogBSD, DateFormatEpochMillis, DateFormatEpochSecond:
+	case DateFormatRFC3339, DateFormatRFC3339Nano, DateFormatRFC1123, DateFormatRFC1123Z, DateFormatRFC822, DateFormatRFC822Z, DateFormatANSIC, DateFormatUnixDate:
+	default:
+		// any other format must be a golang layout: formatting a time with it must not return the format itself
+		if time.Unix(0, 0).UTC().Format(cf.Format) == cf.Format {
+			return formatInvalidConfig
+		}
+	}
+
+	return nil
+}
+
+func (cf ConfigField) ValidForDistribution() error {
+	switch cf.Distribution {
+	case "", DistributionUniform:
+		if cf.ZipfS != 0 || cf.ZipfV != 0 {
+			return distributionInvalidConfig
+		}
+	case DistributionZipf:
+		if len(cf.Enum) == 0 || len(cf.EnumWeights) > 0 || (cf.ZipfS != 0 && cf.ZipfS <= 1) || (cf.ZipfV != 0 && cf.ZipfV < 1) {
+			return distributionInvalidConfig
+		}
+	default:
+		return distributionInvalidConfig
+	}
+
 	return nil
 }
 
@@ -88,6 +458,38 @@ func (r Range) MaxAsInt64() (int64, error) {
 	return int64(*r.Max), nil
 }
 
+// MinAsUint64 returns the min of the range as uint64, clamped to the bounds of uint64
+func (r Range) MinAsUint64() (uint64, error) {
+	if r.Min == nil {
+		return 0, rangeBoundNotSet
+	}
+
+	return uint64OfFloat64(*r.Min), nil
+}
+
+// MaxAsUint64 returns the max of the range as uint64, clamped to the bounds of uint64
+func (r Range) MaxAsUint64() (uint64, error) {
+	if r.Max == nil {
+		return math.MaxUint64, rangeBoundNotSet
+	}
+
+	return uint64OfFloat64(*r.Max), nil
+}
+
+// uint64OfFloat64 converts f to uint64, clamping it to the bounds of uint64: float64(math.MaxUint64) is 2^64,
+// that would overflow
+func uint64OfFloat64(f float64) uint64 {
+	if f <= 0 {
+		return 0
+	}
+
+	if f >= math.MaxUint64 {
+		return math.MaxUint64
+	}
+
+	return uint64(f)
+}
+
 func (r Range) MinAsFloat64() (float64, error) {
 	if r.Min == nil {
 		return 0, rangeBoundNotSet
@@ -105,7 +507,11 @@ func (r Range) MaxAsFloat64() (float64, error) {
 }
 
 type ConfigFile struct {
-	Fields []ConfigField `config:"fields"`
+	KeyStyle        string        `config:"key_style"`
+	MaxFieldsPerDoc int           `config:"max_fields_per_doc"`
+	OutputMode      string        `config:"output_mode"`
+	RecordSeparator string        `config:"record_separator"`
+	Fields          []ConfigField `config:"fields"`
 }
 
 func LoadConfig(fs afero.Fs, configFile string) (Config, error) {
@@ -139,11 +545,75 @@ func LoadConfigFromYaml(c []byte) (Config, error) {
 		return Config{}, err
 	}
 
+	return newConfig(cfgfile)
+}
+
+// NewConfig returns the Config of the fields, validated as they were loaded from a config file
+func NewConfig(fields []ConfigField) (Config, error) {
+	for i := range fields {
+		if err := fields[i].Validate(); err != nil {
+			return Config{}, err
+		}
+	}
+
+	return newConfig(ConfigFile{Fields: fields})
+}
+
+func newConfig(cfgfile ConfigFile) (Config, error) {
+	switch cfgfile.KeyStyle {
+	case "", KeyStyleDotted, KeyStyleSnake, KeyStyleCamel, KeyStyleNested:
+	default:
+		return Config{}, keyStyleInvalidConfig
+	}
+
+	if cfgfile.MaxFieldsPerDoc < 0 {
+		return Config{}, maxFieldsPerDocInvalidConfig
+	}
+
+	switch cfgfile.OutputMode {
+	case "", OutputModeJSON:
+	case OutputModeBeats:
+		// beats events are nested objects
+		if cfgfile.KeyStyle != "" && cfgfile.KeyStyle != KeyStyleDotted && cfgfile.KeyStyle != KeyStyleNested {
+			return Config{}, outputModeInvalidConfig
+		}
+	default:
+		return Config{}, outputModeInvalidConfig
+	}
+
 	outCfg := Config{
-		m: make(map[string]ConfigField),
+		m:               make(map[string]ConfigField),
+		keyStyle:        cfgfile.KeyStyle,
+		maxFieldsPerDoc: cfgfile.MaxFieldsPerDoc,
+		outputMode:      cfgfile.OutputMode,
+		recordSeparator: cfgfile.RecordSeparator,
 	}
 
 	for _, c := range cfgfile.Fields {
+		if c.Range.Min != nil && c.Range.Max != nil && *c.Range.Min > *c.Range.Max {
+			return Config{}, fmt.Errorf("%w: field %s has min %v and max %v", ErrRangeMinMaxInvalidConfig, c.Name, *c.Range.Min, *c.Range.Max)
+		}
+
+		if c.Min != nil && c.Max != nil && *c.Min > *c.Max {
+			return Config{}, fmt.Errorf("%w: field %s has min %v and max %v", minMaxInvalidConfig, c.Name, *c.Min, *c.Max)
+		}
+
+		if c.ActiveSubset != 0 && (c.ActiveSubset < 0 || c.ActiveSubset > len(c.Enum)) {
+			return Config{}, fmt.Errorf("%w: field %s", activeSubsetInvalidConfig, c.Name)
+		}
+
+		if c.CardinalityGroup != "" && c.Cardinality <= 0 {
+			return Config{}, fmt.Errorf("%w: field %s", cardinalityGroupInvalidConfig, c.Name)
+		}
+
+		if c.GeoBounds != nil && !c.GeoBounds.valid() {
+			return Config{}, fmt.Errorf("%w: field %s", geoBoundsInvalidConfig, c.Name)
+		}
+
+		if c.NullProbability < 0 || c.NullProbability > 1 || (c.NullMode != "" && c.NullMode != NullModeOmit && c.NullMode != NullModeNull) {
+			return Config{}, fmt.Errorf("%w: field %s", nullProbabilityInvalidConfig, c.Name)
+		}
+
 		outCfg.m[c.Name] = c
 	}
 
@@ -159,3 +629,66 @@ func (c Config) SetField(fieldName string, configField ConfigField) {
 	configField.Name = fieldName
 	c.m[fieldName] = configField
 }
+
+// CardinalityGroupSize returns the highest `cardinality` of the fields in the `cardinality_group`, 0 for no fields
+func (c Config) CardinalityGroupSize(group string) int {
+	var size int
+	for _, f := range c.m {
+		if f.CardinalityGroup == group && f.Cardinality > size {
+			size = f.Cardinality
+		}
+	}
+
+	return size
+}
+
+func (c Config) KeyStyle() string {
+	return c.keyStyle
+}
+
+// MaxFieldsPerDoc returns the maximum number of fields emitted per document, 0 when not capped
+func (c Config) MaxFieldsPerDoc() int {
+	return c.maxFieldsPerDoc
+}
+
+// OutputMode returns the configured `output_mode`, empty for the default `json`
+func (c Config) OutputMode() string {
+	return c.outputMode
+}
+
+// RecordDelimiters returns the bytes written before and after each document according to the configured
+// `record_separator`: either one of its presets, or the bytes written after each document
+func (c Config) RecordDelimiters() (prefix, suffix []byte) {
+	switch c.recordSeparator {
+	case "", RecordSeparatorNewline:
+		return nil, []byte("\n")
+	case RecordSeparatorJSONSeq:
+		return []byte("\x1e"), []byte("\n")
+	default:
+		return nil, []byte(c.recordSeparator)
+	}
+}
+
+// RecordSeparator returns the configured `record_separator`, empty for the default `newline`
+func (c Config) RecordSeparator() string {
+	return c.recordSeparator
+}
+
+// FieldKey returns the key emitted for fieldName according to the configured `key_style`
+func (c Config) FieldKey(fieldName string) string {
+	switch c.keyStyle {
+	case KeyStyleSnake:
+		return strings.ReplaceAll(fieldName, ".", "_")
+	case KeyStyleCamel:
+		parts := strings.FieldsFunc(fieldName, func(r rune) bool {
+			return r == '.' || r == '_'
+		})
+		for i := 1; i < len(parts); i++ {
+			parts[i] = strings.ToUpper(parts[i][:1]) + parts[i][1:]
+		}
+
+		return strings.Join(parts, "")
+	default:
+		return fieldName
+	}
+}
@@ -48,6 +48,190 @@ func TestSetField(t *testing.T) {
 	assert.Equal(t, "foobaz", f.Value.(string))
 }
 
+func TestKeyStyle(t *testing.T) {
+	testCases := []struct {
+		keyStyle string
+		expected string
+		hasError bool
+	}{
+		{keyStyle: "", expected: "source.ip_address"},
+		{keyStyle: KeyStyleDotted, expected: "source.ip_address"},
+		{keyStyle: KeyStyleSnake, expected: "source_ip_address"},
+		{keyStyle: KeyStyleCamel, expected: "sourceIpAddress"},
+		{keyStyle: KeyStyleNested, expected: "source.ip_address"},
+		{keyStyle: "kebab", hasError: true},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.keyStyle, func(t *testing.T) {
+			cfg, err := LoadConfigFromYaml([]byte("key_style: \"" + testCase.keyStyle + "\"\nfields:\n  - name: field\n"))
+			if testCase.hasError {
+				assert.Equal(t, keyStyleInvalidConfig, err)
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.Equal(t, testCase.keyStyle, cfg.KeyStyle())
+			assert.Equal(t, testCase.expected, cfg.FieldKey("source.ip_address"))
+		})
+	}
+}
+
+func TestMaxFieldsPerDoc(t *testing.T) {
+	cfg, err := LoadConfigFromYaml([]byte("max_fields_per_doc: 10\nfields:\n  - name: field\n"))
+	assert.Nil(t, err)
+	assert.Equal(t, 10, cfg.MaxFieldsPerDoc())
+
+	_, err = LoadConfigFromYaml([]byte("max_fields_per_doc: -1\nfields:\n  - name: field\n"))
+	assert.Equal(t, maxFieldsPerDocInvalidConfig, err)
+}
+
+func TestOutputMode(t *testing.T) {
+	testCases := []struct {
+		config   string
+		expected string
+		hasError bool
+	}{
+		{config: "fields:\n  - name: field\n", expected: ""},
+		{config: "output_mode: json\nfields:\n  - name: field\n", expected: OutputModeJSON},
+		{config: "output_mode: beats\nfields:\n  - name: field\n", expected: OutputModeBeats},
+		{config: "output_mode: beats\nkey_style: nested\nfields:\n  - name: field\n", expected: OutputModeBeats},
+		{config: "output_mode: beats\nkey_style: snake\nfields:\n  - name: field\n", hasError: true},
+		{config: "output_mode: xml\nfields:\n  - name: field\n", hasError: true},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.config, func(t *testing.T) {
+			cfg, err := LoadConfigFromYaml([]byte(testCase.config))
+			if testCase.hasError {
+				assert.Equal(t, outputModeInvalidConfig, err)
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.Equal(t, testCase.expected, cfg.OutputMode())
+		})
+	}
+}
+
+func TestEnumWeights(t *testing.T) {
+	cfg, err := LoadConfigFromYaml([]byte("fields:\n  - name: plain\n    enum: [1.0, b]\n  - name: weighted\n    enum: [{value: ACCEPT, weight: 95}, {value: REJECT, weight: 0.5}]\n"))
+	assert.Nil(t, err)
+
+	plain, _ := cfg.GetField("plain")
+	assert.Equal(t, []string{"1", "b"}, plain.Enum)
+	assert.Nil(t, plain.EnumWeights)
+
+	weighted, _ := cfg.GetField("weighted")
+	assert.Equal(t, []string{"ACCEPT", "REJECT"}, weighted.Enum)
+	assert.Equal(t, []float64{95, 0.5}, weighted.EnumWeights)
+
+	for _, enum := range []string{
+		"[{value: ACCEPT, weight: 95}, REJECT]",
+		"[{value: ACCEPT, weight: -1}, {value: REJECT, weight: 5}]",
+		"[{value: ACCEPT, weight: 0}, {value: REJECT, weight: 0}]",
+		"[{value: ACCEPT}]",
+		"[{weight: 1}]",
+	} {
+		_, err := LoadConfigFromYaml([]byte("fields:\n  - name: weighted\n    enum: " + enum + "\n"))
+		assert.ErrorContains(t, err, enumWeightsInvalidConfig.Error(), enum)
+	}
+
+	// the weights already set the distribution of the values
+	cfg, err = LoadConfigFromYaml([]byte("fields:\n  - name: weighted\n    distribution: zipf\n    enum: [{value: ACCEPT, weight: 95}]\n"))
+	assert.Nil(t, err)
+
+	weighted, _ = cfg.GetField("weighted")
+	assert.Equal(t, distributionInvalidConfig, weighted.ValidForDistribution())
+}
+
+func TestActiveSubset(t *testing.T) {
+	cfg, err := LoadConfigFromYaml([]byte("fields:\n  - name: region\n    enum: [a, b, c]\n    active_subset: 2\n"))
+	assert.Nil(t, err)
+
+	fieldCfg, _ := cfg.GetField("region")
+	assert.Equal(t, 2, fieldCfg.ActiveSubset)
+
+	for _, field := range []string{
+		"active_subset: 2",
+		"enum: [a, b, c]\n    active_subset: -1",
+		"enum: [a, b, c]\n    active_subset: 4",
+	} {
+		_, err := LoadConfigFromYaml([]byte("fields:\n  - name: region\n    " + field + "\n"))
+		assert.ErrorContains(t, err, activeSubsetInvalidConfig.Error(), field)
+	}
+}
+
+func TestCardinalityGroup(t *testing.T) {
+	cfg, err := LoadConfigFromYaml([]byte("fields:\n  - name: source.ip\n    cardinality: 10\n    cardinality_group: host\n  - name: source.port\n    cardinality: 25\n    cardinality_group: host\n  - name: destination.ip\n    cardinality: 100\n"))
+	assert.Nil(t, err)
+
+	assert.Equal(t, 25, cfg.CardinalityGroupSize("host"))
+	assert.Equal(t, 0, cfg.CardinalityGroupSize("other"))
+
+	_, err = LoadConfigFromYaml([]byte("fields:\n  - name: source.ip\n    cardinality_group: host\n"))
+	assert.ErrorContains(t, err, cardinalityGroupInvalidConfig.Error())
+}
+
+func TestGeoBounds(t *testing.T) {
+	cfg, err := LoadConfigFromYaml([]byte("fields:\n  - name: location\n    geo_bounds:\n      top_left: {lat: 47.1, lon: 6.6}\n      bottom_right: {lat: 36.6, lon: 18.5}\n"))
+	assert.Nil(t, err)
+
+	fieldCfg, _ := cfg.GetField("location")
+	assert.Equal(t, 47.1, *fieldCfg.GeoBounds.TopLeft.Lat)
+	assert.Equal(t, 18.5, *fieldCfg.GeoBounds.BottomRight.Lon)
+
+	for _, bounds := range []string{
+		"top_left: {lat: 47.1, lon: 6.6}",
+		"top_left: {lat: 91, lon: 6.6}\n      bottom_right: {lat: 36.6, lon: 18.5}",
+		"top_left: {lat: 47.1, lon: -181}\n      bottom_right: {lat: 36.6, lon: 18.5}",
+		"top_left: {lat: 36.6, lon: 6.6}\n      bottom_right: {lat: 47.1, lon: 18.5}",
+		"top_left: {lat: 47.1, lon: 18.5}\n      bottom_right: {lat: 36.6, lon: 6.6}",
+	} {
+		_, err := LoadConfigFromYaml([]byte("fields:\n  - name: location\n    geo_bounds:\n      " + bounds + "\n"))
+		assert.ErrorContains(t, err, geoBoundsInvalidConfig.Error(), bounds)
+	}
+}
+
+func TestNullProbability(t *testing.T) {
+	cfg, err := LoadConfigFromYaml([]byte("fields:\n  - name: alpha\n    null_probability: 0.25\n    null_mode: \"null\"\n"))
+	assert.Nil(t, err)
+
+	fieldCfg, _ := cfg.GetField("alpha")
+	assert.Equal(t, 0.25, fieldCfg.NullProbability)
+	assert.Equal(t, NullModeNull, fieldCfg.NullMode)
+
+	for _, field := range []string{
+		"null_probability: -0.1",
+		"null_probability: 1.5",
+		"null_probability: 0.5\n    null_mode: missing",
+	} {
+		_, err := LoadConfigFromYaml([]byte("fields:\n  - name: alpha\n    " + field + "\n"))
+		assert.ErrorContains(t, err, nullProbabilityInvalidConfig.Error(), field)
+	}
+}
+
+func TestRangeMinMax(t *testing.T) {
+	cfg, err := LoadConfigFromYaml([]byte("fields:\n  - name: port\n    range:\n      min: 1024\n      max: 65535\n"))
+	assert.Nil(t, err)
+
+	fieldCfg, ok := cfg.GetField("port")
+	assert.True(t, ok)
+	assert.Equal(t, float64(1024), *fieldCfg.Range.Min)
+	assert.Equal(t, float64(65535), *fieldCfg.Range.Max)
+
+	_, err = LoadConfigFromYaml([]byte("fields:\n  - name: port\n    range:\n      min: 10\n      max: 10\n"))
+	assert.Nil(t, err)
+
+	_, err = LoadConfigFromYaml([]byte("fields:\n  - name: port\n    range:\n      min: 65535\n      max: 1024\n"))
+	assert.ErrorIs(t, err, ErrRangeMinMaxInvalidConfig)
+	assert.ErrorContains(t, err, "port")
+
+	_, err = LoadConfigFromYaml([]byte("fields:\n  - name: port\n    min: 65535\n    max: 1024\n"))
+	assert.ErrorIs(t, err, minMaxInvalidConfig)
+	assert.ErrorContains(t, err, "port")
+}
+
 func TestIsValidForDateField(t *testing.T) {
 	testCases := []struct {
 		scenario string
@@ -160,6 +344,281 @@ func TestIsValidForDateField(t *testing.T) {
 	}
 }
 
+func TestIsValidForDateFieldAligned(t *testing.T) {
+	testCases := []struct {
+		scenario string
+		config   string
+		hasError bool
+	}{
+		{
+			scenario: "aligned with interval",
+			config:   "name: field\nmode: aligned\ninterval: 1s",
+			hasError: false,
+		},
+		{
+			scenario: "aligned with interval and base",
+			config:   "name: field\nmode: aligned\ninterval: 1s\nbase: \"2006-01-02T15:04:05-07:00\"",
+			hasError: false,
+		},
+		{
+			scenario: "aligned without interval",
+			config:   "name: field\nmode: aligned",
+			hasError: true,
+		},
+		{
+			scenario: "aligned with negative interval",
+			config:   "name: field\nmode: aligned\ninterval: -1s",
+			hasError: true,
+		},
+		{
+			scenario: "aligned with period",
+			config:   "name: field\nmode: aligned\ninterval: 1s\nperiod: 1h",
+			hasError: true,
+		},
+		{
+			scenario: "aligned with from",
+			config:   "name: field\nmode: aligned\ninterval: 1s\nrange:\n  from: \"2006-01-02T15:04:05-07:00\"",
+			hasError: true,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.scenario, func(t *testing.T) {
+			cfg, err := yaml.NewConfig([]byte(testCase.config))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var config ConfigField
+			err = cfg.Unpack(&config)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = config.ValidForDateField()
+			if testCase.hasError {
+				assert.Equal(t, alignedInvalidConfig, err)
+			} else {
+				assert.Nil(t, err)
+			}
+		})
+	}
+}
+
+func TestIsValidForDateFieldMonotonic(t *testing.T) {
+	testCases := []struct {
+		scenario string
+		config   string
+		hasError bool
+	}{
+		{
+			scenario: "default max_delta",
+			config:   "name: field\nmode: monotonic",
+		},
+		{
+			scenario: "max_delta and base",
+			config:   "name: field\nmode: monotonic\nmax_delta: 1s\nbase: \"2023-01-01T00:00:00+00:00\"",
+		},
+		{
+			scenario: "negative max_delta",
+			config:   "name: field\nmode: monotonic\nmax_delta: -1s",
+			hasError: true,
+		},
+		{
+			scenario: "max_delta without monotonic",
+			config:   "name: field\nmax_delta: 1s",
+			hasError: true,
+		},
+		{
+			scenario: "period",
+			config:   "name: field\nmode: monotonic\nperiod: 1h",
+			hasError: true,
+		},
+		{
+			scenario: "interval",
+			config:   "name: field\nmode: monotonic\ninterval: 1s",
+			hasError: true,
+		},
+		{
+			scenario: "from",
+			config:   "name: field\nmode: monotonic\nrange:\n  from: \"2023-01-01T00:00:00+00:00\"",
+			hasError: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.scenario, func(t *testing.T) {
+			cfg, err := yaml.NewConfig([]byte(testCase.config))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var fieldCfg ConfigField
+			if err := cfg.Unpack(&fieldCfg); err != nil {
+				t.Fatal(err)
+			}
+
+			err = fieldCfg.ValidForDateField()
+			if testCase.hasError {
+				assert.Equal(t, monotonicInvalidConfig, err)
+			} else {
+				assert.Nil(t, err)
+			}
+		})
+	}
+}
+
+func TestIsValidForDateFieldJitter(t *testing.T) {
+	testCases := []struct {
+		scenario string
+		config   string
+		hasError bool
+	}{
+		{
+			scenario: "uniform",
+			config:   "name: field\nmode: aligned\ninterval: 1m\njitter: 1s",
+		},
+		{
+			scenario: "normal",
+			config:   "name: field\nmode: aligned\ninterval: 1m\njitter: 1s\njitter_distribution: normal",
+		},
+		{
+			scenario: "not aligned",
+			config:   "name: field\njitter: 1s",
+			hasError: true,
+		},
+		{
+			scenario: "negative jitter",
+			config:   "name: field\nmode: aligned\ninterval: 1m\njitter: -1s",
+			hasError: true,
+		},
+		{
+			scenario: "distribution without jitter",
+			config:   "name: field\nmode: aligned\ninterval: 1m\njitter_distribution: normal",
+			hasError: true,
+		},
+		{
+			scenario: "unknown distribution",
+			config:   "name: field\nmode: aligned\ninterval: 1m\njitter: 1s\njitter_distribution: poisson",
+			hasError: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.scenario, func(t *testing.T) {
+			cfg, err := yaml.NewConfig([]byte(testCase.config))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var fieldCfg ConfigField
+			if err := cfg.Unpack(&fieldCfg); err != nil {
+				t.Fatal(err)
+			}
+
+			err = fieldCfg.ValidForDateField()
+			if testCase.hasError {
+				assert.Equal(t, jitterInvalidConfig, err)
+			} else {
+				assert.Nil(t, err)
+			}
+		})
+	}
+}
+
+func TestIsValidForDateFieldFormat(t *testing.T) {
+	testCases := []struct {
+		format   string
+		expected error
+	}{
+		{format: "", expected: nil},
+		{format: DateFormatApacheCLF, expected: nil},
+		{format: DateFormatNginx, expected: nil},
+		{format: DateFormatSyslogBSD, expected: nil},
+		{format: DateFormatEpochMillis, expected: nil},
+		{format: DateFormatEpochSecond, expected: nil},
+		{format: DateFormatRFC3339, expected: nil},
+		{format: "2006-01-02 15:04:05", expected: nil},
+		{format: "unknown", expected: formatInvalidConfig},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.format, func(t *testing.T) {
+			assert.Equal(t, testCase.expected, ConfigField{Format: testCase.format}.ValidForDateField())
+		})
+	}
+}
+
+func TestIsValidForDistribution(t *testing.T) {
+	testCases := []struct {
+		scenario string
+		config   string
+		hasError bool
+	}{
+		{
+			scenario: "default",
+			config:   "name: field",
+		},
+		{
+			scenario: "uniform",
+			config:   "name: field\ndistribution: uniform\nenum: [\"a\", \"b\"]",
+		},
+		{
+			scenario: "zipf",
+			config:   "name: field\ndistribution: zipf\nenum: [\"a\", \"b\"]",
+		},
+		{
+			scenario: "zipf with parameters",
+			config:   "name: field\ndistribution: zipf\nzipf_s: 2\nzipf_v: 1\nenum: [\"a\", \"b\"]",
+		},
+		{
+			scenario: "zipf without enum",
+			config:   "name: field\ndistribution: zipf",
+			hasError: true,
+		},
+		{
+			scenario: "zipf with s not greater than 1",
+			config:   "name: field\ndistribution: zipf\nzipf_s: 1\nenum: [\"a\", \"b\"]",
+			hasError: true,
+		},
+		{
+			scenario: "zipf with v lower than 1",
+			config:   "name: field\ndistribution: zipf\nzipf_v: 0.5\nenum: [\"a\", \"b\"]",
+			hasError: true,
+		},
+		{
+			scenario: "zipf parameters without zipf",
+			config:   "name: field\nzipf_s: 2\nenum: [\"a\", \"b\"]",
+			hasError: true,
+		},
+		{
+			scenario: "unknown",
+			config:   "name: field\ndistribution: pareto\nenum: [\"a\", \"b\"]",
+			hasError: true,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.scenario, func(t *testing.T) {
+			cfg, err := yaml.NewConfig([]byte(testCase.config))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var config ConfigField
+			err = cfg.Unpack(&config)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = config.ValidForDistribution()
+			if testCase.hasError {
+				assert.Equal(t, distributionInvalidConfig, err)
+			} else {
+				assert.Nil(t, err)
+			}
+		})
+	}
+}
+
 func TestRange_MaxAsFloat64(t *testing.T) {
 	testCases := []struct {
 		scenario  string
@@ -552,3 +1011,27 @@ func TestPeriod(t *testing.T) {
 		})
 	}
 }
+
+func TestRecordDelimiters(t *testing.T) {
+	testCases := []struct {
+		config string
+		prefix []byte
+		suffix []byte
+	}{
+		{config: "fields:\n  - name: field\n", suffix: []byte("\n")},
+		{config: "record_separator: newline\nfields:\n  - name: field\n", suffix: []byte("\n")},
+		{config: "record_separator: json_seq\nfields:\n  - name: field\n", prefix: []byte{0x1e}, suffix: []byte("\n")},
+		{config: "record_separator: \"\\r\\n\"\nfields:\n  - name: field\n", suffix: []byte("\r\n")},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.config, func(t *testing.T) {
+			cfg, err := LoadConfigFromYaml([]byte(testCase.config))
+			assert.Nil(t, err)
+
+			prefix, suffix := cfg.RecordDelimiters()
+			assert.Equal(t, testCase.prefix, prefix)
+			assert.Equal(t, testCase.suffix, suffix)
+		})
+	}
+}
@@ -0,0 +1,85 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package genlib
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Fields identifying the data stream a document is indexed into, following the Fleet data stream naming scheme
+const (
+	DataStreamTypeField      = "data_stream.type"
+	DataStreamDatasetField   = "data_stream.dataset"
+	DataStreamNamespaceField = "data_stream.namespace"
+	EventDatasetField        = "event.dataset"
+)
+
+// dataStreamTypes are the valid data stream types
+var dataStreamTypes = []string{"logs", "metrics", "traces", "synthetics", "profiling"}
+
+// dataStreamInvalidChars are the characters not allowed in a data stream dataset and namespace
+const dataStreamInvalidChars = `\/*?"<>| ,#-`
+
+// dataStreamMaxLength is the maximum length of a data stream dataset and namespace
+const dataStreamMaxLength = 100
+
+var dataStreamInvalid = errors.New("invalid data stream")
+
+// DataStreamFields returns the `data_stream.type`, `data_stream.dataset` and `data_stream.namespace` fields of the
+// data stream, and the matching `event.dataset`, to be added to each document with NewGeneratorWithStaticFields.
+// An error is returned if the data stream doesn't follow the Fleet data stream naming scheme.
+func DataStreamFields(dataStreamType, dataset, namespace string) (map[string]any, error) {
+	validType := false
+	for _, t := range dataStreamTypes {
+		validType = validType || t == dataStreamType
+	}
+
+	if !validType {
+		return nil, fmt.Errorf("%w: type must be one of %s, got %s", dataStreamInvalid, strings.Join(dataStreamTypes, ", "), dataStreamType)
+	}
+
+	for _, part := range []struct{ name, value string }{{name: "dataset", value: dataset}, {name: "namespace", value: namespace}} {
+		name, value := part.name, part.value
+		if len(value) == 0 || len(value) > dataStreamMaxLength || strings.ToLower(value) != value || strings.ContainsAny(value, dataStreamInvalidChars) {
+			return nil, fmt.Errorf("%w: %s must be lowercase, not longer than %d characters and without any of %s, got %s", dataStreamInvalid, name, dataStreamMaxLength, dataStreamInvalidChars, value)
+		}
+	}
+
+	return map[string]any{
+		DataStreamTypeField:      dataStreamType,
+		DataStreamDatasetField:   dataset,
+		DataStreamNamespaceField: namespace,
+		EventDatasetField:        dataset,
+	}, nil
+}
+
+// NewGeneratorWithDataStream returns a Generator emitting the documents of gen with the fields of the data stream
+// and the matching `event.dataset`, see DataStreamFields. gen must not generate the fields itself: the fields of
+// an integration package can be removed with WithoutDataStreamFields.
+func NewGeneratorWithDataStream(gen Generator, dataStreamType, dataset, namespace string) (*GeneratorWithStaticFields, error) {
+	staticFields, err := DataStreamFields(dataStreamType, dataset, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewGeneratorWithStaticFields(gen, staticFields)
+}
+
+// WithoutDataStreamFields returns flds without the fields added by NewGeneratorWithDataStream
+func WithoutDataStreamFields(flds Fields) Fields {
+	withoutFields := make(Fields, 0, len(flds))
+	for _, field := range flds {
+		switch field.Name {
+		case DataStreamTypeField, DataStreamDatasetField, DataStreamNamespaceField, EventDatasetField:
+			continue
+		}
+
+		withoutFields = append(withoutFields, field)
+	}
+
+	return withoutFields
+}
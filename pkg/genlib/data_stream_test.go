@@ -0,0 +1,77 @@
+package genlib
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/elastic/elastic-integration-corpus-generator-tool/pkg/genlib/config"
+)
+
+func Test_GeneratorWithDataStream(t *testing.T) {
+	flds := Fields{
+		{Name: "alpha", Type: FieldTypeKeyword},
+		{Name: "data_stream.type", Type: FieldTypeConstantKeyword},
+		{Name: "data_stream.dataset", Type: FieldTypeConstantKeyword},
+		{Name: "data_stream.namespace", Type: FieldTypeConstantKeyword},
+		{Name: "event.dataset", Type: FieldTypeConstantKeyword},
+	}
+
+	flds = WithoutDataStreamFields(flds)
+	if len(flds) != 1 {
+		t.Fatalf("Expected data stream fields removed, got %v", flds)
+	}
+
+	gen, err := NewGenerator(config.Config{}, flds, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	g, err := NewGeneratorWithDataStream(gen, "logs", "nginx.access", "default")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 10; i++ {
+		var buf bytes.Buffer
+		if err := g.Emit(&buf); err != nil {
+			t.Fatal(err)
+		}
+
+		m := unmarshalJSONT[string](t, buf.Bytes())
+		if m["data_stream.type"] != "logs" || m["data_stream.dataset"] != "nginx.access" || m["data_stream.namespace"] != "default" {
+			t.Errorf("Expected the data stream fields of the configured data stream, got %s", buf.String())
+		}
+
+		if m["event.dataset"] != m["data_stream.dataset"] {
+			t.Errorf("Expected event.dataset to match data_stream.dataset, got %s", buf.String())
+		}
+
+		if len(m) != 5 {
+			t.Errorf("Expected alpha and the data stream fields, got %s", buf.String())
+		}
+	}
+}
+
+func Test_DataStreamFieldsInvalid(t *testing.T) {
+	testCases := []struct {
+		scenario       string
+		dataStreamType string
+		dataset        string
+		namespace      string
+	}{
+		{scenario: "unknown type", dataStreamType: "events", dataset: "nginx.access", namespace: "default"},
+		{scenario: "empty dataset", dataStreamType: "logs", dataset: "", namespace: "default"},
+		{scenario: "dash in dataset", dataStreamType: "logs", dataset: "nginx-access", namespace: "default"},
+		{scenario: "uppercase namespace", dataStreamType: "logs", dataset: "nginx.access", namespace: "Default"},
+		{scenario: "dash in namespace", dataStreamType: "logs", dataset: "nginx.access", namespace: "prod-eu"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.scenario, func(t *testing.T) {
+			if _, err := DataStreamFields(testCase.dataStreamType, testCase.dataset, testCase.namespace); !errors.Is(err, dataStreamInvalid) {
+				t.Errorf("Expected dataStreamInvalid error, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package genlib
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/elastic/elastic-integration-corpus-generator-tool/pkg/genlib/config"
+)
+
+// dateFormatLayouts maps the `format` presets of date fields to their golang layout
+var dateFormatLayouts = map[string]string{
+	// 10/Oct/2000:13:55:36 -0700
+	config.DateFormatApacheCLF: "02/Jan/2006:15:04:05 -0700",
+	// nginx $time_local is in Common Log Format
+	config.DateFormatNginx: "02/Jan/2006:15:04:05 -0700",
+	// Oct 10 13:55:36, as defined in RFC 3164
+	config.DateFormatSyslogBSD: time.Stamp,
+	// the golang layouts by the name of their constant
+	config.DateFormatRFC3339:     time.RFC3339,
+	config.DateFormatRFC3339Nano: time.RFC3339Nano,
+	config.DateFormatRFC1123:     time.RFC1123,
+	config.DateFormatRFC1123Z:    time.RFC1123Z,
+	config.DateFormatRFC822:      time.RFC822,
+	config.DateFormatRFC822Z:     time.RFC822Z,
+	config.DateFormatANSIC:       time.ANSIC,
+	config.DateFormatUnixDate:    time.UnixDate,
+}
+
+// hasDateFormat returns true if the value of the date field is emitted in a `format` preset instead of as a time
+func hasDateFormat(cfg Config, field Field) bool {
+	fieldCfg, ok := cfg.GetField(field.Name)
+	return ok && len(fieldCfg.Format) > 0
+}
+
+// isEpochDateFormat returns true if the date is emitted as an epoch timestamp, that is an integer
+func isEpochDateFormat(format string) bool {
+	return format == config.DateFormatEpochMillis || format == config.DateFormatEpochSecond
+}
+
+// dateLayout returns the layout the date field is emitted with, defaulting to FieldTypeTimeLayout.
+// The epoch formats are returned as they are, see formatDate and parseDate.
+func dateLayout(fieldCfg ConfigField) string {
+	if layout, ok := dateFormatLayouts[fieldCfg.Format]; ok {
+		return layout
+	}
+
+	if len(fieldCfg.Format) > 0 {
+		return fieldCfg.Format
+	}
+
+	return FieldTypeTimeLayout
+}
+
+// formatDate returns t formatted with layout, or the epoch timestamp of t for the epoch formats
+func formatDate(t time.Time, layout string) string {
+	switch layout {
+	case config.DateFormatEpochMillis:
+		return strconv.FormatInt(t.UnixMilli(), 10)
+	case config.DateFormatEpochSecond:
+		return strconv.FormatInt(t.Unix(), 10)
+	default:
+		return t.Format(layout)
+	}
+}
+
+// formattedDate returns the value of a date field with a `format` for templates: the epoch timestamp of t as an
+// integer for the epoch formats, so that it is emitted unquoted, t formatted with layout otherwise
+func formattedDate(t time.Time, layout string) any {
+	switch layout {
+	case config.DateFormatEpochMillis:
+		return t.UnixMilli()
+	case config.DateFormatEpochSecond:
+		return t.Unix()
+	default:
+		return t.Format(layout)
+	}
+}
+
+// parseDate parses value formatted with formatDate
+func parseDate(layout, value string) (time.Time, error) {
+	switch layout {
+	case config.DateFormatEpochMillis:
+		millis, err := strconv.ParseInt(value, 10, 64)
+		return time.UnixMilli(millis).UTC(), err
+	case config.DateFormatEpochSecond:
+		seconds, err := strconv.ParseInt(value, 10, 64)
+		return time.Unix(seconds, 0).UTC(), err
+	default:
+		return time.Parse(layout, value)
+	}
+}
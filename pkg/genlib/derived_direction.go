@@ -0,0 +1,183 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package genlib
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+)
+
+const (
+	networkDirectionInbound  = "inbound"
+	networkDirectionOutbound = "outbound"
+	networkDirectionInternal = "internal"
+	networkDirectionExternal = "external"
+)
+
+// defaultInternalNetworks are the private networks the source and destination addresses are classified against
+// when `internal_networks` is not set
+var defaultInternalNetworks = []string{"10.0.0.0/8", "172.16.0.0/12", "192.168.0.0/16", "fc00::/7"}
+
+// bindDerivedDirection binds the keyword fields whose value is the network direction of a source and a destination
+// ip field in the same event, like ECS `network.direction` of `source.ip` and `destination.ip`: `internal` if both
+// addresses belong to the `internal_networks`, `outbound` if only the source does, `inbound` if only the destination
+// does and `external` if none does.
+// The ip fields are wrapped, so that their value is generated once per event regardless the order the fields are emitted.
+func bindDerivedDirection(cfg Config, fields Fields, fieldMap map[string]any, withReturn bool) error {
+	wrapped := make(map[string]struct{})
+	for _, field := range fields {
+		fieldCfg, _ := cfg.GetField(field.Name)
+		if len(fieldCfg.DirectionOf) == 0 {
+			if len(fieldCfg.InternalNetworks) > 0 {
+				return fmt.Errorf("field %s internal_networks requires direction_of", field.Name)
+			}
+
+			continue
+		}
+
+		if len(fieldCfg.DirectionOf) != 2 {
+			return fmt.Errorf("field %s direction_of must have a source and a destination ip field", field.Name)
+		}
+
+		internalNetworks, err := parseInternalNetworks(fieldCfg)
+		if err != nil {
+			return fmt.Errorf("field %s: %w", field.Name, err)
+		}
+
+		for _, ipFieldName := range fieldCfg.DirectionOf {
+			if _, ok := fieldMap[ipFieldName]; !ok {
+				return fmt.Errorf("field %s is the direction of field %s that is not defined", field.Name, ipFieldName)
+			}
+
+			if _, ok := wrapped[ipFieldName]; ok {
+				continue
+			}
+
+			if err := wrapEventValue(ipFieldName, fieldMap, withReturn); err != nil {
+				return err
+			}
+
+			wrapped[ipFieldName] = struct{}{}
+		}
+
+		if withReturn {
+			bindDerivedDirectionWithReturn(fieldCfg, field, internalNetworks, fieldMap)
+		} else {
+			bindDerivedDirectionNotReturn(fieldCfg, field, internalNetworks, fieldMap)
+		}
+	}
+
+	return nil
+}
+
+func parseInternalNetworks(fieldCfg ConfigField) ([]*net.IPNet, error) {
+	cidrs := fieldCfg.InternalNetworks
+	if len(cidrs) == 0 {
+		cidrs = defaultInternalNetworks
+	}
+
+	internalNetworks := make([]*net.IPNet, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		_, ipNet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid internal_networks cidr %s: %w", cidr, err)
+		}
+
+		internalNetworks = append(internalNetworks, ipNet)
+	}
+
+	return internalNetworks, nil
+}
+
+func isInternalIP(value string, internalNetworks []*net.IPNet) (bool, error) {
+	ip := net.ParseIP(value)
+	if ip == nil {
+		return false, fmt.Errorf("value %s is not an ip address", value)
+	}
+
+	for _, ipNet := range internalNetworks {
+		if ipNet.Contains(ip) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// networkDirection returns the direction of the traffic from source to destination
+func networkDirection(source, destination string, internalNetworks []*net.IPNet) (string, error) {
+	sourceInternal, err := isInternalIP(source, internalNetworks)
+	if err != nil {
+		return "", err
+	}
+
+	destinationInternal, err := isInternalIP(destination, internalNetworks)
+	if err != nil {
+		return "", err
+	}
+
+	switch {
+	case sourceInternal && destinationInternal:
+		return networkDirectionInternal, nil
+	case sourceInternal:
+		return networkDirectionOutbound, nil
+	case destinationInternal:
+		return networkDirectionInbound, nil
+	default:
+		return networkDirectionExternal, nil
+	}
+}
+
+func bindDerivedDirectionNotReturn(fieldCfg ConfigField, field Field, internalNetworks []*net.IPNet, fieldMap map[string]any) {
+	sourceF := fieldMap[fieldCfg.DirectionOf[0]].(emitFNotReturn)
+	destinationF := fieldMap[fieldCfg.DirectionOf[1]].(emitFNotReturn)
+
+	var emitFNotReturn emitFNotReturn
+	emitFNotReturn = func(state *genState, buf *bytes.Buffer) error {
+		var source, destination bytes.Buffer
+		if err := sourceF(state, &source); err != nil {
+			return err
+		}
+
+		if err := destinationF(state, &destination); err != nil {
+			return err
+		}
+
+		direction, err := networkDirection(source.String(), destination.String(), internalNetworks)
+		if err != nil {
+			return err
+		}
+
+		buf.WriteString(direction)
+		return nil
+	}
+
+	fieldMap[field.Name] = emitFNotReturn
+}
+
+func bindDerivedDirectionWithReturn(fieldCfg ConfigField, field Field, internalNetworks []*net.IPNet, fieldMap map[string]any) {
+	sourceF := fieldMap[fieldCfg.DirectionOf[0]].(emitF)
+	destinationF := fieldMap[fieldCfg.DirectionOf[1]].(emitF)
+
+	var emitF emitF
+	emitF = func(state *genState) any {
+		source, destination := sourceF(state), destinationF(state)
+		for _, value := range []any{source, destination} {
+			if err, ok := value.(error); ok {
+				return err
+			}
+		}
+
+		direction, err := networkDirection(fmt.Sprint(source), fmt.Sprint(destination), internalNetworks)
+		if err != nil {
+			return err
+		}
+
+		return direction
+	}
+
+	fieldMap[field.Name] = emitF
+}
@@ -0,0 +1,128 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package genlib
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// bindDerivedDuration binds the fields whose value is the duration in nanoseconds between two date fields in the
+// same event, like ECS `event.duration` from `event.start` and `event.end`.
+// The date fields are wrapped, so that their value is generated once per event regardless the order the fields are emitted.
+func bindDerivedDuration(cfg Config, fields Fields, fieldMap map[string]any, withReturn bool) error {
+	wrapped := make(map[string]struct{})
+	for _, field := range fields {
+		fieldCfg, _ := cfg.GetField(field.Name)
+		if len(fieldCfg.DurationOf) == 0 {
+			continue
+		}
+
+		if len(fieldCfg.DurationOf) != 2 {
+			return fmt.Errorf("field %s duration_of must have a start and an end date field", field.Name)
+		}
+
+		layouts := make([]string, 0, len(fieldCfg.DurationOf))
+		for _, dateFieldName := range fieldCfg.DurationOf {
+			if _, ok := fieldMap[dateFieldName]; !ok {
+				return fmt.Errorf("field %s is the duration of field %s that is not defined", field.Name, dateFieldName)
+			}
+
+			dateFieldCfg, _ := cfg.GetField(dateFieldName)
+			layouts = append(layouts, dateLayout(dateFieldCfg))
+
+			if _, ok := wrapped[dateFieldName]; ok {
+				continue
+			}
+
+			if err := wrapEventValue(dateFieldName, fieldMap, withReturn); err != nil {
+				return err
+			}
+
+			wrapped[dateFieldName] = struct{}{}
+		}
+
+		if withReturn {
+			bindDerivedDurationWithReturn(fieldCfg, field, layouts, fieldMap)
+		} else {
+			bindDerivedDurationNotReturn(fieldCfg, field, layouts, fieldMap)
+		}
+	}
+
+	return nil
+}
+
+func bindDerivedDurationNotReturn(fieldCfg ConfigField, field Field, layouts []string, fieldMap map[string]any) {
+	startF := fieldMap[fieldCfg.DurationOf[0]].(emitFNotReturn)
+	endF := fieldMap[fieldCfg.DurationOf[1]].(emitFNotReturn)
+
+	var emitFNotReturn emitFNotReturn
+	emitFNotReturn = func(state *genState, buf *bytes.Buffer) error {
+		var start, end bytes.Buffer
+		if err := startF(state, &start); err != nil {
+			return err
+		}
+
+		if err := endF(state, &end); err != nil {
+			return err
+		}
+
+		startTime, err := parseDate(layouts[0], start.String())
+		if err != nil {
+			return err
+		}
+
+		endTime, err := parseDate(layouts[1], end.String())
+		if err != nil {
+			return err
+		}
+
+		buf.WriteString(strconv.FormatInt(endTime.Sub(startTime).Nanoseconds(), 10))
+		return nil
+	}
+
+	fieldMap[field.Name] = emitFNotReturn
+}
+
+func bindDerivedDurationWithReturn(fieldCfg ConfigField, field Field, layouts []string, fieldMap map[string]any) {
+	startF := fieldMap[fieldCfg.DurationOf[0]].(emitF)
+	endF := fieldMap[fieldCfg.DurationOf[1]].(emitF)
+
+	var emitF emitF
+	emitF = func(state *genState) any {
+		startTime, err := timeOfValue(startF(state), layouts[0])
+		if err != nil {
+			return err
+		}
+
+		endTime, err := timeOfValue(endF(state), layouts[1])
+		if err != nil {
+			return err
+		}
+
+		return endTime.Sub(startTime).Nanoseconds()
+	}
+
+	fieldMap[field.Name] = emitF
+}
+
+// timeOfValue returns the time of the value of a date field, parsing it with layout when already formatted
+func timeOfValue(value any, layout string) (time.Time, error) {
+	switch v := value.(type) {
+	case time.Time:
+		return v, nil
+	case string:
+		return parseDate(layout, v)
+	case int64:
+		// the epoch formats are emitted as integers
+		return parseDate(layout, strconv.FormatInt(v, 10))
+	case error:
+		return time.Time{}, v
+	default:
+		return time.Time{}, fmt.Errorf("value %v is not a date", value)
+	}
+}
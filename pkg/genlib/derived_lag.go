@@ -0,0 +1,110 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package genlib
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+)
+
+// defaultMaxLag is the upper bound of the lag of a date field from the one it lags when `max_lag` is not set
+const defaultMaxLag = 5 * time.Second
+
+// bindDerivedLag binds the date fields whose value is the value of another date field in the same event plus a
+// random positive lag, like ECS `event.ingested` lagging `@timestamp`.
+// The lagged date field is wrapped, so that its value is generated once per event regardless the order the fields are emitted.
+func bindDerivedLag(cfg Config, fields Fields, fieldMap map[string]any, withReturn bool) error {
+	wrapped := make(map[string]struct{})
+	for _, field := range fields {
+		fieldCfg, _ := cfg.GetField(field.Name)
+		if len(fieldCfg.LagOf) == 0 {
+			if fieldCfg.MaxLag != 0 {
+				return fmt.Errorf("field %s max_lag requires lag_of", field.Name)
+			}
+
+			continue
+		}
+
+		if fieldCfg.MaxLag < 0 {
+			return fmt.Errorf("field %s max_lag must be positive", field.Name)
+		}
+
+		if _, ok := fieldMap[fieldCfg.LagOf]; !ok {
+			return fmt.Errorf("field %s lags field %s that is not defined", field.Name, fieldCfg.LagOf)
+		}
+
+		if _, ok := wrapped[fieldCfg.LagOf]; !ok {
+			if err := wrapEventValue(fieldCfg.LagOf, fieldMap, withReturn); err != nil {
+				return err
+			}
+
+			wrapped[fieldCfg.LagOf] = struct{}{}
+		}
+
+		lagOfCfg, _ := cfg.GetField(fieldCfg.LagOf)
+		if withReturn {
+			bindDerivedLagWithReturn(fieldCfg, field, dateLayout(lagOfCfg), fieldMap)
+		} else {
+			bindDerivedLagNotReturn(fieldCfg, field, dateLayout(lagOfCfg), fieldMap)
+		}
+	}
+
+	return nil
+}
+
+// lag returns a random lag between 0 and `max_lag`, defaulting to defaultMaxLag
+func lag(state *genState, fieldCfg ConfigField) time.Duration {
+	maxLag := fieldCfg.MaxLag
+	if maxLag == 0 {
+		maxLag = defaultMaxLag
+	}
+
+	return time.Duration(state.rand.Int63n(int64(maxLag) + 1))
+}
+
+func bindDerivedLagNotReturn(fieldCfg ConfigField, field Field, lagOfLayout string, fieldMap map[string]any) {
+	lagOfF := fieldMap[fieldCfg.LagOf].(emitFNotReturn)
+	layout := dateLayout(fieldCfg)
+
+	var emitFNotReturn emitFNotReturn
+	emitFNotReturn = func(state *genState, buf *bytes.Buffer) error {
+		var lagOf bytes.Buffer
+		if err := lagOfF(state, &lagOf); err != nil {
+			return err
+		}
+
+		lagOfTime, err := parseDate(lagOfLayout, lagOf.String())
+		if err != nil {
+			return err
+		}
+
+		buf.WriteString(formatDate(lagOfTime.Add(lag(state, fieldCfg)), layout))
+		return nil
+	}
+
+	fieldMap[field.Name] = emitFNotReturn
+}
+
+func bindDerivedLagWithReturn(fieldCfg ConfigField, field Field, lagOfLayout string, fieldMap map[string]any) {
+	lagOfF := fieldMap[fieldCfg.LagOf].(emitF)
+
+	var emitF emitF
+	emitF = func(state *genState) any {
+		lagOfTime, err := timeOfValue(lagOfF(state), lagOfLayout)
+		if err != nil {
+			return err
+		}
+
+		// with a `format` preset the value is emitted as already formatted string
+		if len(fieldCfg.Format) > 0 {
+			return formattedDate(lagOfTime.Add(lag(state, fieldCfg)), dateLayout(fieldCfg))
+		}
+
+		return lagOfTime.Add(lag(state, fieldCfg))
+	}
+
+	fieldMap[field.Name] = emitF
+}
@@ -0,0 +1,153 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package genlib
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// defaultRatioNoise is the relative noise of a field derived from another one by `ratio` when `ratio_noise` is not set
+const defaultRatioNoise = 0.1
+
+// bindDerivedRatio binds the numeric fields whose value is the value of another numeric field in the same event
+// multiplied by `ratio`, plus a relative noise of at most `ratio_noise`, like flows `bytes` being about `packets`
+// times the average packet size.
+// The field the ratio is of is wrapped, so that its value is generated once per event regardless the order the fields are emitted.
+func bindDerivedRatio(cfg Config, fields Fields, fieldMap map[string]any, withReturn bool) error {
+	wrapped := make(map[string]struct{})
+	for _, field := range fields {
+		fieldCfg, _ := cfg.GetField(field.Name)
+		if len(fieldCfg.RatioOf) == 0 {
+			if fieldCfg.Ratio != 0 || fieldCfg.RatioNoise != 0 {
+				return fmt.Errorf("field %s ratio and ratio_noise require ratio_of", field.Name)
+			}
+
+			continue
+		}
+
+		if fieldCfg.Ratio <= 0 {
+			return fmt.Errorf("field %s ratio must be positive", field.Name)
+		}
+
+		if fieldCfg.RatioNoise < 0 || fieldCfg.RatioNoise >= 1 {
+			return fmt.Errorf("field %s ratio_noise must be between 0 and 1", field.Name)
+		}
+
+		if _, ok := fieldMap[fieldCfg.RatioOf]; !ok {
+			return fmt.Errorf("field %s is a ratio of field %s that is not defined", field.Name, fieldCfg.RatioOf)
+		}
+
+		if _, ok := wrapped[fieldCfg.RatioOf]; !ok {
+			if err := wrapEventValue(fieldCfg.RatioOf, fieldMap, withReturn); err != nil {
+				return err
+			}
+
+			wrapped[fieldCfg.RatioOf] = struct{}{}
+		}
+
+		if withReturn {
+			bindDerivedRatioWithReturn(fieldCfg, field, fieldMap)
+		} else {
+			bindDerivedRatioNotReturn(fieldCfg, field, fieldMap)
+		}
+	}
+
+	return nil
+}
+
+// ratio returns value multiplied by `ratio` with a random relative noise of at most `ratio_noise`,
+// rounded for the integer field types
+func ratio(state *genState, fieldCfg ConfigField, field Field, value float64) any {
+	noise := fieldCfg.RatioNoise
+	if noise == 0 {
+		noise = defaultRatioNoise
+	}
+
+	derived := value * fieldCfg.Ratio * (1 + noise*(2*state.rand.Float64()-1))
+
+	switch field.Type {
+	case FieldTypeByte, FieldTypeShort, FieldTypeInteger, FieldTypeLong:
+		return int64(math.Round(derived))
+	case FieldTypeUnsignedLong:
+		// negative values are clamped to 0, and the ones beyond the bounds of uint64 to math.MaxUint64
+		if derived <= 0 {
+			return uint64(0)
+		}
+
+		if derived >= math.MaxUint64 {
+			return uint64(math.MaxUint64)
+		}
+
+		return uint64(math.Round(derived))
+	default:
+		return derived
+	}
+}
+
+func numberOfValue(value any) (float64, error) {
+	switch v := value.(type) {
+	case int64:
+		return float64(v), nil
+	case uint64:
+		return float64(v), nil
+	case float64:
+		return v, nil
+	case int:
+		return float64(v), nil
+	case error:
+		return 0, v
+	default:
+		return 0, fmt.Errorf("value %v is not a number", value)
+	}
+}
+
+func bindDerivedRatioNotReturn(fieldCfg ConfigField, field Field, fieldMap map[string]any) {
+	ratioOfF := fieldMap[fieldCfg.RatioOf].(emitFNotReturn)
+
+	var emitFNotReturn emitFNotReturn
+	emitFNotReturn = func(state *genState, buf *bytes.Buffer) error {
+		var ratioOf bytes.Buffer
+		if err := ratioOfF(state, &ratioOf); err != nil {
+			return err
+		}
+
+		value, err := strconv.ParseFloat(ratioOf.String(), 64)
+		if err != nil {
+			return err
+		}
+
+		switch v := ratio(state, fieldCfg, field, value).(type) {
+		case int64:
+			buf.Write(strconv.AppendInt(make([]byte, 0, 32), v, 10))
+		case uint64:
+			buf.Write(strconv.AppendUint(make([]byte, 0, 32), v, 10))
+		case float64:
+			buf.Write(appendDouble(make([]byte, 0, 32), v, fieldCfg.OmitIntegerDecimals))
+		}
+
+		return nil
+	}
+
+	fieldMap[field.Name] = emitFNotReturn
+}
+
+func bindDerivedRatioWithReturn(fieldCfg ConfigField, field Field, fieldMap map[string]any) {
+	ratioOfF := fieldMap[fieldCfg.RatioOf].(emitF)
+
+	var emitF emitF
+	emitF = func(state *genState) any {
+		value, err := numberOfValue(ratioOfF(state))
+		if err != nil {
+			return err
+		}
+
+		return ratio(state, fieldCfg, field, value)
+	}
+
+	fieldMap[field.Name] = emitF
+}
@@ -0,0 +1,131 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package genlib
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+)
+
+var diffDocumentNotObject = errors.New("diffed document is not a JSON object")
+
+// ValueChange is the value of a field in the n-th document of two corpora, empty when the field is missing
+type ValueChange struct {
+	Document int
+	Before   string
+	After    string
+}
+
+// FieldDiff holds the changes of the values of a field between two corpora
+type FieldDiff struct {
+	Field   string
+	Changes []ValueChange
+}
+
+// CorpusDiff holds the fields whose values changed between two corpora, sorted by field name
+type CorpusDiff struct {
+	Documents int
+	Fields    []FieldDiff
+}
+
+// DiffCorpora emits up to n documents from the generators built by before and after, both built and emitting with
+// the random generator seeded with randSeed, and returns for each top level field the documents where its value
+// changed. The generators are built by the factories after seeding, so that the random values drawn when binding
+// the fields (ie: the values of an `active_subset`) are the same for both.
+// NOTE: the random generator is shared by all the fields: a config change altering how many random values a field
+// draws (ie: the length of a generated keyword) changes the values of the fields emitted after it as well.
+func DiffCorpora(before, after func() (Generator, error), n int, randSeed int64) (CorpusDiff, error) {
+	beforeDocs, err := emitDocuments(before, n, randSeed)
+	if err != nil {
+		return CorpusDiff{}, err
+	}
+
+	afterDocs, err := emitDocuments(after, n, randSeed)
+	if err != nil {
+		return CorpusDiff{}, err
+	}
+
+	documents := len(beforeDocs)
+	if len(afterDocs) < documents {
+		documents = len(afterDocs)
+	}
+
+	changesByField := make(map[string][]ValueChange)
+	for i := 0; i < documents; i++ {
+		fieldNames := make(map[string]struct{})
+		for fieldName := range beforeDocs[i] {
+			fieldNames[fieldName] = struct{}{}
+		}
+
+		for fieldName := range afterDocs[i] {
+			fieldNames[fieldName] = struct{}{}
+		}
+
+		for fieldName := range fieldNames {
+			beforeValue, afterValue := beforeDocs[i][fieldName], afterDocs[i][fieldName]
+			if bytes.Equal(beforeValue, afterValue) {
+				continue
+			}
+
+			changesByField[fieldName] = append(changesByField[fieldName], ValueChange{
+				Document: i,
+				Before:   string(beforeValue),
+				After:    string(afterValue),
+			})
+		}
+	}
+
+	diff := CorpusDiff{Documents: documents}
+	for fieldName, changes := range changesByField {
+		diff.Fields = append(diff.Fields, FieldDiff{Field: fieldName, Changes: changes})
+	}
+
+	sort.Slice(diff.Fields, func(i, j int) bool {
+		return diff.Fields[i].Field < diff.Fields[j].Field
+	})
+
+	return diff, nil
+}
+
+// emitDocuments builds a generator with newGen and emits up to n documents from it, with the random generator
+// seeded with randSeed
+func emitDocuments(newGen func() (Generator, error), n int, randSeed int64) ([]map[string]json.RawMessage, error) {
+	InitGeneratorRandSeed(randSeed)
+
+	gen, err := newGen()
+	if err != nil {
+		return nil, err
+	}
+
+	defer gen.Close()
+
+	docs := make([]map[string]json.RawMessage, 0, n)
+
+	var buf bytes.Buffer
+	for i := 0; i < n; i++ {
+		buf.Reset()
+		err := gen.Emit(&buf)
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		var doc map[string]json.RawMessage
+		if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+			return nil, fmt.Errorf("%w: %s", diffDocumentNotObject, err)
+		}
+
+		docs = append(docs, doc)
+	}
+
+	return docs, nil
+}
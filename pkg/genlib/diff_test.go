@@ -0,0 +1,107 @@
+package genlib
+
+import (
+	"testing"
+
+	"github.com/elastic/elastic-integration-corpus-generator-tool/pkg/genlib/config"
+)
+
+func makeDiffGenerator(t *testing.T, configYaml string) func() (Generator, error) {
+	flds := Fields{
+		{Name: "alpha", Type: FieldTypeKeyword},
+		{Name: "beta", Type: FieldTypeLong},
+		{Name: "gamma", Type: FieldTypeKeyword},
+	}
+
+	cfg, err := config.LoadConfigFromYaml([]byte(configYaml))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := []byte(`{"alpha":"{{.alpha}}","beta":{{.beta}},"gamma":"{{.gamma}}"}`)
+	return func() (Generator, error) {
+		return NewGeneratorWithCustomTemplate(template, cfg, flds, 0)
+	}
+}
+
+func Test_DiffCorporaChangedField(t *testing.T) {
+	before := makeDiffGenerator(t, `fields:
+  - name: beta
+    range:
+      min: 0
+      max: 100
+  - name: gamma
+    enum: ["one", "two", "three"]
+`)
+
+	after := makeDiffGenerator(t, `fields:
+  - name: beta
+    range:
+      min: 1000
+      max: 1100
+  - name: gamma
+    enum: ["one", "two", "three"]
+`)
+
+	nDocs := 100
+	diff, err := DiffCorpora(before, after, nDocs, 42)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if diff.Documents != nDocs {
+		t.Errorf("Expected %d documents diffed, got %d", nDocs, diff.Documents)
+	}
+
+	if len(diff.Fields) != 1 || diff.Fields[0].Field != "beta" {
+		t.Fatalf("Expected diffs for beta only, got %+v", diff.Fields)
+	}
+
+	if len(diff.Fields[0].Changes) != nDocs {
+		t.Errorf("Expected beta to change in all documents, got %d", len(diff.Fields[0].Changes))
+	}
+}
+
+func Test_DiffCorporaSameConfig(t *testing.T) {
+	testCases := []struct {
+		name       string
+		configYaml string
+	}{
+		{
+			name: "enum",
+			configYaml: `fields:
+  - name: gamma
+    enum: ["one", "two", "three"]
+`,
+		},
+		{
+			name: "active_subset",
+			configYaml: `fields:
+  - name: gamma
+    enum: ["one", "two", "three", "four", "five"]
+    active_subset: 2
+`,
+		},
+		{
+			name: "zipf",
+			configYaml: `fields:
+  - name: gamma
+    enum: ["one", "two", "three", "four", "five"]
+    distribution: zipf
+`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			diff, err := DiffCorpora(makeDiffGenerator(t, testCase.configYaml), makeDiffGenerator(t, testCase.configYaml), 100, 42)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(diff.Fields) != 0 {
+				t.Errorf("Expected no diffs, got %+v", diff.Fields)
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package genlib
+
+import "math/rand"
+
+const (
+	dnsTypeA     = "A"
+	dnsTypeAAAA  = "AAAA"
+	dnsTypeCNAME = "CNAME"
+)
+
+// dnsQuestionTypes are the question types chosen from for a `dns` field
+var dnsQuestionTypes = []string{dnsTypeA, dnsTypeAAAA, dnsTypeCNAME}
+
+// dns is the generated value of a `dns` field
+type dns struct {
+	questionName string
+	questionType string
+	answerName   string
+	answerType   string
+	answerData   string
+}
+
+// dnsAnswerData returns the data of an answer record of questionType:
+// an IPv4 address for `A`, an IPv6 address for `AAAA` and a domain for `CNAME`
+func dnsAnswerData(r *rand.Rand, questionType string) string {
+	switch questionType {
+	case dnsTypeA:
+		return randIPv4(r)
+	case dnsTypeAAAA:
+		return randIPv6(r)
+	default:
+		return randHostname(r)
+	}
+}
+
+// dnsForEvent returns the question and answer of a `dns` field for the current event,
+// so that the answer matches the question type regardless of the order they are emitted.
+func dnsForEvent(fieldName string, state *genState) dns {
+	if value, ok := state.eventValue(fieldName); ok {
+		return value.(dns)
+	}
+
+	questionName := randHostname(state.rand)
+	questionType := dnsQuestionTypes[state.rand.Intn(len(dnsQuestionTypes))]
+	d := dns{
+		questionName: questionName,
+		questionType: questionType,
+		answerName:   questionName,
+		answerType:   questionType,
+		answerData:   dnsAnswerData(state.rand, questionType),
+	}
+
+	state.setEventValue(fieldName, d)
+
+	return d
+}
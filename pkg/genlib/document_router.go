@@ -0,0 +1,82 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package genlib
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io"
+)
+
+var documentRouterNoWriters = errors.New("document router needs at least one writer")
+var documentRouterDocumentNotObject = errors.New("routed document is not a JSON object")
+
+// DocumentRouter routes documents to the writer of their shard, chosen by the hash of the value of a field,
+// so that documents with the same value always go to the same writer (ie: for sharded sinks)
+type DocumentRouter struct {
+	fieldName string
+	writers   []io.Writer
+	buf       bytes.Buffer
+}
+
+// NewDocumentRouter returns a DocumentRouter routing documents by the value of fieldName to writers,
+// the writer of each shard: the shard of a document is the FNV-1a hash of the value modulo the number of writers.
+func NewDocumentRouter(fieldName string, writers []io.Writer) (*DocumentRouter, error) {
+	if len(writers) == 0 {
+		return nil, documentRouterNoWriters
+	}
+
+	return &DocumentRouter{
+		fieldName: fieldName,
+		writers:   writers,
+	}, nil
+}
+
+// Shard returns the shard of doc. The value of the field is hashed in its compact JSON encoding, so that the
+// formatting of the document doesn't matter. Documents without the field are routed as if its value was null.
+func (r *DocumentRouter) Shard(doc []byte) (int, error) {
+	var m map[string]json.RawMessage
+	if err := json.Unmarshal(doc, &m); err != nil || m == nil {
+		return 0, fmt.Errorf("%w: %s", documentRouterDocumentNotObject, doc)
+	}
+
+	value, ok := m[r.fieldName]
+	if !ok {
+		value = json.RawMessage("null")
+	}
+
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, value); err != nil {
+		return 0, err
+	}
+
+	h := fnv.New32a()
+	_, _ = h.Write(compact.Bytes())
+	return int(h.Sum32() % uint32(len(r.writers))), nil
+}
+
+// Route emits a document of gen and writes it, followed by a newline, to the writer of its shard.
+// It returns the shard the document was written to, and io.EOF once gen is exhausted.
+func (r *DocumentRouter) Route(gen Generator) (int, error) {
+	r.buf.Reset()
+	if err := gen.Emit(&r.buf); err != nil {
+		return 0, err
+	}
+
+	shard, err := r.Shard(r.buf.Bytes())
+	if err != nil {
+		return 0, err
+	}
+
+	r.buf.WriteByte('\n')
+	if _, err := r.writers[shard].Write(r.buf.Bytes()); err != nil {
+		return shard, err
+	}
+
+	return shard, nil
+}
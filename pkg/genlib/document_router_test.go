@@ -0,0 +1,100 @@
+package genlib
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/elastic/elastic-integration-corpus-generator-tool/pkg/genlib/config"
+)
+
+func Test_DocumentRouter(t *testing.T) {
+	flds := Fields{
+		{Name: "host.name", Type: FieldTypeKeyword},
+		{Name: "alpha", Type: FieldTypeLong},
+	}
+
+	cfg, err := config.LoadConfigFromYaml([]byte("fields:\n  - name: host.name\n    cardinality: 20"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := []byte(`{ "host.name": "{{.host.name}}", "alpha": {{.alpha}} }`)
+	g := makeGeneratorWithCustomTemplate(t, cfg, flds, template, 0)
+
+	shards := make([]bytes.Buffer, 4)
+	writers := make([]io.Writer, len(shards))
+	for i := range shards {
+		writers[i] = &shards[i]
+	}
+
+	router, err := NewDocumentRouter("host.name", writers)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	nSpins := 1000
+	for i := 0; i < nSpins; i++ {
+		if _, err := router.Route(g); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// documents with the same host name always go to the same shard
+	shardOfHost := make(map[string]int)
+	var routed int
+	for i := range shards {
+		lines := bytes.Split(bytes.TrimSuffix(shards[i].Bytes(), []byte("\n")), []byte("\n"))
+		if len(lines) == 0 || len(lines[0]) == 0 {
+			t.Errorf("Expected documents in shard %d", i)
+			continue
+		}
+
+		for _, line := range lines {
+			routed += 1
+			host := unmarshalJSONT[any](t, line)["host.name"].(string)
+			if shard, ok := shardOfHost[host]; ok && shard != i {
+				t.Errorf("Expected host %s in shard %d, got it in shard %d too", host, shard, i)
+			}
+
+			shardOfHost[host] = i
+		}
+	}
+
+	if routed != nSpins {
+		t.Errorf("Expected %d documents routed, got %d", nSpins, routed)
+	}
+}
+
+func Test_DocumentRouterShard(t *testing.T) {
+	router, err := NewDocumentRouter("alpha", []io.Writer{io.Discard, io.Discard, io.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	shard, err := router.Shard([]byte(`{"alpha": "a", "beta": 1}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the formatting of the document and the other fields don't matter
+	if other, _ := router.Shard([]byte(`{"beta":2,"alpha":"a"}`)); other != shard {
+		t.Errorf("Expected shard %d for the same value, got %d", shard, other)
+	}
+
+	// a missing field is routed as null
+	missing, _ := router.Shard([]byte(`{"beta": 1}`))
+	null, _ := router.Shard([]byte(`{"alpha": null}`))
+	if missing != null {
+		t.Errorf("Expected missing field routed as null to shard %d, got %d", null, missing)
+	}
+
+	if _, err := router.Shard([]byte(`[1]`)); !errors.Is(err, documentRouterDocumentNotObject) {
+		t.Errorf("Expected documentRouterDocumentNotObject error, got %v", err)
+	}
+
+	if _, err := NewDocumentRouter("alpha", nil); !errors.Is(err, documentRouterNoWriters) {
+		t.Errorf("Expected documentRouterNoWriters error, got %v", err)
+	}
+}
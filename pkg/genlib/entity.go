@@ -0,0 +1,176 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package genlib
+
+import (
+	"bytes"
+	"container/list"
+	"fmt"
+)
+
+// defaultEntityCacheSize is the number of entities whose attributes are remembered when `entity_cache_size` is not set
+const defaultEntityCacheSize = 10000
+
+// entityAttributes is the cache of the attributes of the entities identified by the values of a field.
+// When more than size entities are cached the least recently seen one is evicted.
+type entityAttributes struct {
+	size  int
+	order *list.List
+	byID  map[string]*list.Element
+}
+
+// entity is an element of entityAttributes order
+type entity struct {
+	id         string
+	attributes map[string]any
+}
+
+func newEntityAttributes(size int) *entityAttributes {
+	return &entityAttributes{
+		size:  size,
+		order: list.New(),
+		byID:  make(map[string]*list.Element),
+	}
+}
+
+// attributes returns the attributes of the entity identified by id, adding it to the cache if missing
+func (ea *entityAttributes) attributes(id string) map[string]any {
+	if e, ok := ea.byID[id]; ok {
+		ea.order.MoveToFront(e)
+		return e.Value.(*entity).attributes
+	}
+
+	if ea.order.Len() >= ea.size {
+		oldest := ea.order.Back()
+		ea.order.Remove(oldest)
+		delete(ea.byID, oldest.Value.(*entity).id)
+	}
+
+	e := &entity{id: id, attributes: make(map[string]any)}
+	ea.byID[id] = ea.order.PushFront(e)
+
+	return e.attributes
+}
+
+// entityAttributes returns the attributes of the entity identified by id for the entities of entityField
+func (state *genState) entityAttributes(entityField string, size int, id string) map[string]any {
+	ea, ok := state.entityCache[entityField]
+	if !ok {
+		ea = newEntityAttributes(size)
+		state.entityCache[entityField] = ea
+	}
+
+	return ea.attributes(id)
+}
+
+// bindEntityAttributes binds the fields that are attributes of the entity identified by the value of another field.
+// The same value is emitted for the attribute whenever the same entity id appears in the corpus, as long as
+// the entity is not evicted from the cache. The entity id field is wrapped, so that its value is generated
+// once per event regardless the order the fields are emitted.
+func bindEntityAttributes(cfg Config, fields Fields, fieldMap map[string]any, withReturn bool) error {
+	wrapped := make(map[string]struct{})
+	for _, field := range fields {
+		fieldCfg, _ := cfg.GetField(field.Name)
+		if len(fieldCfg.Entity) == 0 {
+			continue
+		}
+
+		if _, ok := fieldMap[fieldCfg.Entity]; !ok {
+			return fmt.Errorf("field %s is an attribute of entity field %s that is not defined", field.Name, fieldCfg.Entity)
+		}
+
+		entityCfg, _ := cfg.GetField(fieldCfg.Entity)
+		if entityCfg.EntityCacheSize < 0 {
+			return fmt.Errorf("field %s has a negative entity_cache_size", fieldCfg.Entity)
+		}
+
+		size := entityCfg.EntityCacheSize
+		if size == 0 {
+			size = defaultEntityCacheSize
+		}
+
+		if _, ok := wrapped[fieldCfg.Entity]; !ok {
+			if err := wrapEventValue(fieldCfg.Entity, fieldMap, withReturn); err != nil {
+				return err
+			}
+
+			wrapped[fieldCfg.Entity] = struct{}{}
+		}
+
+		var err error
+		if withReturn {
+			err = bindEntityAttributeWithReturn(fieldCfg, field, size, fieldMap)
+		} else {
+			err = bindEntityAttributeNotReturn(fieldCfg, field, size, fieldMap)
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func bindEntityAttributeNotReturn(fieldCfg ConfigField, field Field, size int, fieldMap map[string]any) error {
+	entityF := fieldMap[fieldCfg.Entity].(emitFNotReturn)
+	boundF, ok := fieldMap[field.Name].(emitFNotReturn)
+	if !ok {
+		return fmt.Errorf("cannot bind field %s as attribute of entity field %s", field.Name, fieldCfg.Entity)
+	}
+
+	var emitFNotReturn emitFNotReturn
+	emitFNotReturn = func(state *genState, buf *bytes.Buffer) error {
+		var id bytes.Buffer
+		if err := entityF(state, &id); err != nil {
+			return err
+		}
+
+		attributes := state.entityAttributes(fieldCfg.Entity, size, id.String())
+		if value, ok := attributes[field.Name]; ok {
+			buf.Write(value.([]byte))
+			return nil
+		}
+
+		start := buf.Len()
+		if err := boundF(state, buf); err != nil {
+			return err
+		}
+
+		value := make([]byte, buf.Len()-start)
+		copy(value, buf.Bytes()[start:])
+		attributes[field.Name] = value
+		return nil
+	}
+
+	fieldMap[field.Name] = emitFNotReturn
+	return nil
+}
+
+func bindEntityAttributeWithReturn(fieldCfg ConfigField, field Field, size int, fieldMap map[string]any) error {
+	entityF := fieldMap[fieldCfg.Entity].(emitF)
+	boundF, ok := fieldMap[field.Name].(emitF)
+	if !ok {
+		return fmt.Errorf("cannot bind field %s as attribute of entity field %s", field.Name, fieldCfg.Entity)
+	}
+
+	var emitF emitF
+	emitF = func(state *genState) any {
+		attributes := state.entityAttributes(fieldCfg.Entity, size, fmt.Sprint(entityF(state)))
+		if value, ok := attributes[field.Name]; ok {
+			return value
+		}
+
+		value := boundF(state)
+		if _, ok := value.(error); !ok {
+			attributes[field.Name] = value
+		}
+
+		return value
+	}
+
+	fieldMap[field.Name] = emitF
+	return nil
+}
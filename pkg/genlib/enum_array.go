@@ -0,0 +1,94 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package genlib
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+)
+
+// isEnumArray returns true if the field is generated as an array of `array_size` values drawn from `enum`
+func isEnumArray(fieldCfg ConfigField) bool {
+	return fieldCfg.ArraySize > 0 && len(fieldCfg.Enum) > 0
+}
+
+func validEnumArray(fieldCfg ConfigField, field Field) error {
+	if fieldCfg.UniqueWithinDoc && fieldCfg.ArraySize > len(fieldCfg.Enum) {
+		return fmt.Errorf("field %s cannot have %d distinct values from an enum of %d values", field.Name, fieldCfg.ArraySize, len(fieldCfg.Enum))
+	}
+
+	return nil
+}
+
+// randEnumArray draws `array_size` values from `enum`, without repeats if `unique_within_doc` is set
+func randEnumArray(r *rand.Rand, fieldCfg ConfigField) []string {
+	values := make([]string, fieldCfg.ArraySize)
+	if !fieldCfg.UniqueWithinDoc {
+		for i := range values {
+			values[i] = fieldCfg.Enum[r.Intn(len(fieldCfg.Enum))]
+		}
+
+		return values
+	}
+
+	// partial Fisher-Yates shuffle of the enum indexes
+	idxs := make([]int, len(fieldCfg.Enum))
+	for i := range idxs {
+		idxs[i] = i
+	}
+
+	for i := range values {
+		j := i + r.Intn(len(idxs)-i)
+		idxs[i], idxs[j] = idxs[j], idxs[i]
+		values[i] = fieldCfg.Enum[idxs[i]]
+	}
+
+	return values
+}
+
+func bindEnumArray(fieldCfg ConfigField, field Field, fieldMap map[string]any) error {
+	fieldCfg = withActiveEnumSubset(fieldCfg)
+	if err := validEnumArray(fieldCfg, field); err != nil {
+		return err
+	}
+
+	var emitFNotReturn emitFNotReturn
+	emitFNotReturn = func(state *genState, buf *bytes.Buffer) error {
+		b, err := json.Marshal(randEnumArray(state.rand, fieldCfg))
+		if err != nil {
+			return err
+		}
+
+		buf.Write(b)
+		return nil
+	}
+
+	fieldMap[field.Name] = emitFNotReturn
+	return nil
+}
+
+func bindEnumArrayWithReturn(fieldCfg ConfigField, field Field, fieldMap map[string]any) error {
+	fieldCfg = withActiveEnumSubset(fieldCfg)
+	if err := validEnumArray(fieldCfg, field); err != nil {
+		return err
+	}
+
+	var emitF emitF
+	emitF = func(state *genState) any {
+		values := randEnumArray(state.rand, fieldCfg)
+
+		array := make(arrayValues, 0, len(values))
+		for _, value := range values {
+			array = append(array, value)
+		}
+
+		return array
+	}
+
+	fieldMap[field.Name] = emitF
+	return nil
+}
@@ -0,0 +1,141 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package genlib
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// bindEnumByValue binds the fields whose enum depends on the value of another field in the same event.
+// The other field is wrapped, so that its value is generated once per event regardless the order the fields are emitted.
+func bindEnumByValue(cfg Config, fields Fields, fieldMap map[string]any, withReturn bool) error {
+	wrapped := make(map[string]struct{})
+	for _, field := range fields {
+		fieldCfg, _ := cfg.GetField(field.Name)
+		if len(fieldCfg.DependsOn) == 0 {
+			continue
+		}
+
+		if _, ok := fieldMap[fieldCfg.DependsOn]; !ok {
+			return fmt.Errorf("field %s depends on field %s that is not defined", field.Name, fieldCfg.DependsOn)
+		}
+
+		if _, ok := wrapped[fieldCfg.DependsOn]; !ok {
+			if err := wrapEventValue(fieldCfg.DependsOn, fieldMap, withReturn); err != nil {
+				return err
+			}
+
+			wrapped[fieldCfg.DependsOn] = struct{}{}
+		}
+
+		if withReturn {
+			bindEnumByValueWithReturn(fieldCfg, field, fieldMap)
+		} else {
+			bindEnumByValueNotReturn(fieldCfg, field, fieldMap)
+		}
+	}
+
+	return nil
+}
+
+func wrapEventValue(fieldName string, fieldMap map[string]any, withReturn bool) error {
+	if withReturn {
+		boundF, ok := fieldMap[fieldName].(emitF)
+		if !ok {
+			return fmt.Errorf("cannot bind field %s once per event", fieldName)
+		}
+
+		var emitF emitF
+		emitF = func(state *genState) any {
+			if value, ok := state.eventValue(fieldName); ok {
+				return value
+			}
+
+			value := boundF(state)
+			state.setEventValue(fieldName, value)
+			return value
+		}
+
+		fieldMap[fieldName] = emitF
+		return nil
+	}
+
+	boundF, ok := fieldMap[fieldName].(emitFNotReturn)
+	if !ok {
+		return fmt.Errorf("cannot bind field %s once per event", fieldName)
+	}
+
+	var emitFNotReturn emitFNotReturn
+	emitFNotReturn = func(state *genState, buf *bytes.Buffer) error {
+		if value, ok := state.eventValue(fieldName); ok {
+			buf.Write(value.([]byte))
+			return nil
+		}
+
+		start := buf.Len()
+		if err := boundF(state, buf); err != nil {
+			return err
+		}
+
+		value := make([]byte, buf.Len()-start)
+		copy(value, buf.Bytes()[start:])
+		state.setEventValue(fieldName, value)
+		return nil
+	}
+
+	fieldMap[fieldName] = emitFNotReturn
+	return nil
+}
+
+// enumForValue returns the enum to chose from given the value of the field depended on, defaulting to `enum`
+func enumForValue(fieldCfg ConfigField, value string) []string {
+	if enum, ok := fieldCfg.EnumByValue[value]; ok {
+		return enum
+	}
+
+	return fieldCfg.Enum
+}
+
+func bindEnumByValueNotReturn(fieldCfg ConfigField, field Field, fieldMap map[string]any) {
+	dependsOnF := fieldMap[fieldCfg.DependsOn].(emitFNotReturn)
+
+	var emitFNotReturn emitFNotReturn
+	emitFNotReturn = func(state *genState, buf *bytes.Buffer) error {
+		var tmp bytes.Buffer
+		if err := dependsOnF(state, &tmp); err != nil {
+			return err
+		}
+
+		enum := enumForValue(fieldCfg, tmp.String())
+		if len(enum) == 0 {
+			return fmt.Errorf("no enum for field %s with %s value %s", field.Name, fieldCfg.DependsOn, tmp.String())
+		}
+
+		buf.WriteString(enum[state.rand.Intn(len(enum))])
+		return nil
+	}
+
+	fieldMap[field.Name] = emitFNotReturn
+}
+
+func bindEnumByValueWithReturn(fieldCfg ConfigField, field Field, fieldMap map[string]any) {
+	dependsOnF := fieldMap[fieldCfg.DependsOn].(emitF)
+
+	var emitF emitF
+	emitF = func(state *genState) any {
+		value := fmt.Sprint(dependsOnF(state))
+
+		enum := enumForValue(fieldCfg, value)
+		if len(enum) == 0 {
+			// the error is returned as value and reported by the template engine
+			return fmt.Errorf("no enum for field %s with %s value %s", field.Name, fieldCfg.DependsOn, value)
+		}
+
+		return enum[state.rand.Intn(len(enum))]
+	}
+
+	fieldMap[field.Name] = emitF
+}
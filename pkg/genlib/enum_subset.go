@@ -0,0 +1,47 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package genlib
+
+import "sort"
+
+// withActiveEnumSubset returns the field config with its `enum` restricted to `active_subset` values, randomly
+// chosen when the field is bound: the subset is the same across runs with the same seed. The chosen values keep
+// their order in the enum, and their weights if any, so that the distribution of the values applies to the subset.
+// Values with a zero weight are never emitted, thus never chosen.
+func withActiveEnumSubset(fieldCfg ConfigField) ConfigField {
+	if fieldCfg.ActiveSubset <= 0 {
+		return fieldCfg
+	}
+
+	candidates := make([]int, 0, len(fieldCfg.Enum))
+	for idx := range fieldCfg.Enum {
+		if len(fieldCfg.EnumWeights) == 0 || fieldCfg.EnumWeights[idx] > 0 {
+			candidates = append(candidates, idx)
+		}
+	}
+
+	if fieldCfg.ActiveSubset >= len(candidates) {
+		return fieldCfg
+	}
+
+	idxs := make([]int, 0, fieldCfg.ActiveSubset)
+	for _, i := range customRand.Perm(len(candidates))[:fieldCfg.ActiveSubset] {
+		idxs = append(idxs, candidates[i])
+	}
+
+	sort.Ints(idxs)
+
+	enum := make([]string, 0, len(idxs))
+	var enumWeights []float64
+	for _, idx := range idxs {
+		enum = append(enum, fieldCfg.Enum[idx])
+		if len(fieldCfg.EnumWeights) > 0 {
+			enumWeights = append(enumWeights, fieldCfg.EnumWeights[idx])
+		}
+	}
+
+	fieldCfg.Enum, fieldCfg.EnumWeights = enum, enumWeights
+	return fieldCfg
+}
@@ -0,0 +1,66 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package genlib
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"sort"
+)
+
+var noDocumentToEstimate = errors.New("no document emitted to estimate size from")
+var invalidSamplesToEstimate = errors.New("samples to estimate size from must be greater than 0")
+
+// DocSizeEstimate holds statistics about the size in bytes of emitted documents
+type DocSizeEstimate struct {
+	Samples int
+	Mean    float64
+	Median  int
+	P95     int
+}
+
+// EstimateDocSize emits up to samples documents from gen and returns statistics about their size.
+// The sampled documents are consumed from gen: use a dedicated generator to estimate before a full run.
+func EstimateDocSize(gen Generator, samples int) (DocSizeEstimate, error) {
+	if samples <= 0 {
+		return DocSizeEstimate{}, invalidSamplesToEstimate
+	}
+
+	sizes := make([]int, 0, samples)
+
+	var buf bytes.Buffer
+	for i := 0; i < samples; i++ {
+		buf.Reset()
+		err := gen.Emit(&buf)
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return DocSizeEstimate{}, err
+		}
+
+		sizes = append(sizes, buf.Len())
+	}
+
+	if len(sizes) == 0 {
+		return DocSizeEstimate{}, noDocumentToEstimate
+	}
+
+	sort.Ints(sizes)
+
+	var total int
+	for _, size := range sizes {
+		total += size
+	}
+
+	return DocSizeEstimate{
+		Samples: len(sizes),
+		Mean:    float64(total) / float64(len(sizes)),
+		Median:  sizes[len(sizes)/2],
+		P95:     sizes[(len(sizes)*95-1)/100],
+	}, nil
+}
@@ -0,0 +1,121 @@
+package genlib
+
+import (
+	"bytes"
+	"math"
+	"testing"
+
+	"github.com/elastic/elastic-integration-corpus-generator-tool/pkg/genlib/config"
+)
+
+func Test_EstimateDocSizeFixedSize(t *testing.T) {
+	fld := Field{
+		Name: "alpha",
+		Type: FieldTypeKeyword,
+	}
+
+	cfg, err := config.LoadConfigFromYaml([]byte("fields:\n  - name: alpha\n    value: beta"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := []byte(`{"alpha":{{.alpha}}}`)
+	g := makeGeneratorWithCustomTemplate(t, cfg, Fields{fld}, template, 0)
+
+	estimate, err := EstimateDocSize(g, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedSize := len(`{"alpha":"beta"}`)
+	if estimate.Samples != 10 || estimate.Mean != float64(expectedSize) || estimate.Median != expectedSize || estimate.P95 != expectedSize {
+		t.Errorf("Expected all sizes to be %d, got %+v", expectedSize, estimate)
+	}
+}
+
+func Test_EstimateDocSizeCloseToObserved(t *testing.T) {
+	flds := Fields{
+		{
+			Name: "alpha",
+			Type: FieldTypeKeyword,
+		},
+		{
+			Name: "beta",
+			Type: FieldTypeLong,
+		},
+		{
+			Name: "gamma",
+			Type: FieldTypeIP,
+		},
+	}
+
+	template, _ := generateCustomTemplateFromField(Config{}, flds)
+
+	estimate, err := EstimateDocSize(makeGeneratorWithCustomTemplate(t, Config{}, flds, template, 0), 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	g := makeGeneratorWithCustomTemplate(t, Config{}, flds, template, 0)
+	nSpins := 10000
+	var total int
+	var buf bytes.Buffer
+	for i := 0; i < nSpins; i++ {
+		buf.Reset()
+		if err := g.Emit(&buf); err != nil {
+			t.Fatal(err)
+		}
+
+		total += buf.Len()
+	}
+
+	observedMean := float64(total) / float64(nSpins)
+	if math.Abs(estimate.Mean-observedMean)/observedMean > 0.1 {
+		t.Errorf("Expected estimated mean %f to be within 10%% of observed mean %f", estimate.Mean, observedMean)
+	}
+
+	if estimate.Median > estimate.P95 {
+		t.Errorf("Expected median %d to be lower or equal than p95 %d", estimate.Median, estimate.P95)
+	}
+}
+
+func Test_EstimateDocSizeTotEvents(t *testing.T) {
+	fld := Field{
+		Name: "alpha",
+		Type: FieldTypeKeyword,
+	}
+
+	template := []byte(`{"alpha":"{{.alpha}}"}`)
+
+	estimate, err := EstimateDocSize(makeGeneratorWithCustomTemplate(t, Config{}, Fields{fld}, template, 5), 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if estimate.Samples != 5 {
+		t.Errorf("Expected 5 samples, got %d", estimate.Samples)
+	}
+
+	g := makeGeneratorWithCustomTemplate(t, Config{}, Fields{fld}, template, 1)
+	var buf bytes.Buffer
+	_ = g.Emit(&buf)
+	if _, err = EstimateDocSize(g, 10); err != noDocumentToEstimate {
+		t.Errorf("Expected noDocumentToEstimate error, got %v", err)
+	}
+}
+
+func Test_EstimateDocSizeInvalidSamples(t *testing.T) {
+	fld := Field{
+		Name: "alpha",
+		Type: FieldTypeKeyword,
+	}
+
+	template := []byte(`{"alpha":"{{.alpha}}"}`)
+
+	for _, samples := range []int{0, -1} {
+		g := makeGeneratorWithCustomTemplate(t, Config{}, Fields{fld}, template, 0)
+		if _, err := EstimateDocSize(g, samples); err != invalidSamplesToEstimate {
+			t.Errorf("Expected invalidSamplesToEstimate error with %d samples, got %v", samples, err)
+		}
+	}
+}
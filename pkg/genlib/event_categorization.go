@@ -0,0 +1,202 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package genlib
+
+import (
+	"fmt"
+	"sort"
+)
+
+// ecsEventTypes are, for each ECS `event.category`, the `event.type` values allowed with it
+// (see https://www.elastic.co/guide/en/ecs/current/ecs-allowed-values-event-category.html)
+var ecsEventTypes = map[string][]string{
+	"authentication":      {"start", "end", "info"},
+	"configuration":       {"access", "change", "creation", "deletion", "info"},
+	"database":            {"access", "change", "info", "error"},
+	"driver":              {"change", "end", "info", "start"},
+	"email":               {"info"},
+	"file":                {"access", "change", "creation", "deletion", "info"},
+	"host":                {"access", "change", "end", "info", "start"},
+	"iam":                 {"admin", "change", "creation", "deletion", "group", "info", "user"},
+	"intrusion_detection": {"allowed", "denied", "info"},
+	"library":             {"start"},
+	"malware":             {"info"},
+	"network":             {"access", "allowed", "connection", "denied", "end", "info", "protocol", "start"},
+	"package":             {"access", "change", "deletion", "info", "installation", "start"},
+	"process":             {"access", "change", "end", "info", "start"},
+	"registry":            {"access", "change", "creation", "deletion"},
+	"session":             {"start", "end", "info"},
+	"threat":              {"indicator"},
+	"vulnerability":       {"info"},
+	"web":                 {"access", "error", "info"},
+}
+
+// ecsEventActions are, for each ECS `event.category` and one of its allowed `event.type`, the `event.action` values
+// generated with them: ECS does not restrict `event.action`, every allowed combination has a plausible action though
+var ecsEventActions = map[string]map[string][]string{
+	"authentication": {
+		"start": {"logged-in", "user-login"},
+		"end":   {"logged-out", "user-logout"},
+		"info":  {"authentication-failure", "credentials-validated"},
+	},
+	"configuration": {
+		"access":   {"config-read"},
+		"change":   {"config-changed", "policy-updated"},
+		"creation": {"config-created"},
+		"deletion": {"config-deleted"},
+		"info":     {"config-loaded"},
+	},
+	"database": {
+		"access": {"query", "select"},
+		"change": {"insert", "update", "delete"},
+		"info":   {"connection-stats"},
+		"error":  {"query-failed"},
+	},
+	"driver": {
+		"change": {"driver-updated"},
+		"end":    {"driver-unloaded"},
+		"info":   {"driver-info"},
+		"start":  {"driver-loaded"},
+	},
+	"email": {
+		"info": {"email-sent", "email-received"},
+	},
+	"file": {
+		"access":   {"opened", "read"},
+		"change":   {"modified", "renamed", "attributes-modified"},
+		"creation": {"created"},
+		"deletion": {"deleted"},
+		"info":     {"scanned"},
+	},
+	"host": {
+		"access": {"host-accessed"},
+		"change": {"hostname-changed"},
+		"end":    {"shutdown"},
+		"info":   {"host-info"},
+		"start":  {"boot"},
+	},
+	"iam": {
+		"admin":    {"privilege-granted"},
+		"change":   {"password-changed", "user-modified"},
+		"creation": {"user-created", "group-created"},
+		"deletion": {"user-deleted", "group-deleted"},
+		"group":    {"added-user-to-group", "removed-user-from-group"},
+		"info":     {"user-listed"},
+		"user":     {"user-enabled", "user-disabled"},
+	},
+	"intrusion_detection": {
+		"allowed": {"alert"},
+		"denied":  {"blocked"},
+		"info":    {"signature-matched"},
+	},
+	"library": {
+		"start": {"loaded-library"},
+	},
+	"malware": {
+		"info": {"malware-detected", "quarantined"},
+	},
+	"network": {
+		"access":     {"network-access"},
+		"allowed":    {"accept", "allow"},
+		"connection": {"connection-attempted", "connection-accepted"},
+		"denied":     {"deny", "drop"},
+		"end":        {"connection-closed", "flow-ended"},
+		"info":       {"network-flow"},
+		"protocol":   {"dns-query", "http-request"},
+		"start":      {"connection-opened", "flow-started"},
+	},
+	"package": {
+		"access":       {"package-queried"},
+		"change":       {"package-upgraded"},
+		"deletion":     {"package-removed"},
+		"info":         {"package-listed"},
+		"installation": {"package-installed"},
+		"start":        {"package-install-started"},
+	},
+	"process": {
+		"access": {"process-accessed"},
+		"change": {"process-modified"},
+		"end":    {"exec-ended", "process-stopped"},
+		"info":   {"process-info"},
+		"start":  {"exec", "process-started"},
+	},
+	"registry": {
+		"access":   {"registry-value-queried"},
+		"change":   {"registry-value-modified"},
+		"creation": {"registry-key-created"},
+		"deletion": {"registry-key-deleted"},
+	},
+	"session": {
+		"start": {"session-started"},
+		"end":   {"session-ended"},
+		"info":  {"session-info"},
+	},
+	"threat": {
+		"indicator": {"indicator-matched"},
+	},
+	"vulnerability": {
+		"info": {"vulnerability-detected"},
+	},
+	"web": {
+		"access": {"http-request", "page-view"},
+		"error":  {"http-error"},
+		"info":   {"web-info"},
+	},
+}
+
+// defaultEventCategories are the ECS `event.category` values chosen from when no `enum` is set
+var defaultEventCategories = func() []string {
+	categories := make([]string, 0, len(ecsEventTypes))
+	for category := range ecsEventTypes {
+		categories = append(categories, category)
+	}
+
+	// map iteration order is random, the categories must be sorted for the generation to be reproducible
+	sort.Strings(categories)
+	return categories
+}()
+
+// eventCategorization is the generated value of an `event_categorization` field
+type eventCategorization struct {
+	category  string
+	eventType string
+	action    string
+}
+
+func eventCategories(fieldCfg ConfigField) ([]string, error) {
+	if len(fieldCfg.Enum) == 0 {
+		return defaultEventCategories, nil
+	}
+
+	for _, category := range fieldCfg.Enum {
+		if _, ok := ecsEventTypes[category]; !ok {
+			return nil, fmt.Errorf("unknown ECS event category: %s", category)
+		}
+	}
+
+	return fieldCfg.Enum, nil
+}
+
+// eventCategorizationForEvent returns the category, type and action of an `event_categorization` field for the
+// current event, so that they are an allowed ECS combination regardless of the order they are emitted.
+func eventCategorizationForEvent(fieldName string, categories []string, state *genState) eventCategorization {
+	if value, ok := state.eventValue(fieldName); ok {
+		return value.(eventCategorization)
+	}
+
+	category := categories[state.rand.Intn(len(categories))]
+	eventTypes := ecsEventTypes[category]
+	eventType := eventTypes[state.rand.Intn(len(eventTypes))]
+	actions := ecsEventActions[category][eventType]
+	e := eventCategorization{
+		category:  category,
+		eventType: eventType,
+		action:    actions[state.rand.Intn(len(actions))],
+	}
+
+	state.setEventValue(fieldName, e)
+
+	return e
+}
@@ -0,0 +1,145 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package genlib
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+)
+
+var errCustomFieldNotBound = errors.New("custom field type binder did not bind the field")
+
+// EventState is the state of the event being generated, passed to a FieldEmitter so that the values of a custom
+// field type can be correlated with the event
+type EventState struct {
+	// Counter is the number of events generated before the current one
+	Counter uint64
+	// TotEvents is the total number of events to generate, 0 when unbounded
+	TotEvents uint64
+}
+
+// FieldEmitter returns the value of a custom field type for the current event.
+// Strings and time.Time values are emitted as JSON strings, any other value as its JSON encoding. Whether the values
+// are quoted in the templates generated from the fields is decided by the kind of the first value.
+type FieldEmitter func(event EventState) any
+
+// FieldBinder binds a FieldEmitter for the given field in fieldMap
+type FieldBinder func(fieldCfg ConfigField, field Field, fieldMap map[string]FieldEmitter) error
+
+var (
+	fieldTypeRegistryMu sync.RWMutex
+	fieldTypeRegistry   = make(map[string]FieldBinder)
+)
+
+// RegisterFieldType registers a binder for a custom field type.
+// The binder is used for fields whose type is not natively supported by the generator.
+func RegisterFieldType(name string, binder FieldBinder) {
+	fieldTypeRegistryMu.Lock()
+	defer fieldTypeRegistryMu.Unlock()
+
+	fieldTypeRegistry[name] = binder
+}
+
+func registeredFieldBinder(fieldType string) (FieldBinder, bool) {
+	fieldTypeRegistryMu.RLock()
+	defer fieldTypeRegistryMu.RUnlock()
+
+	binder, ok := fieldTypeRegistry[fieldType]
+	return binder, ok
+}
+
+// isRegisteredType returns true if a binder is registered for the field type
+func isRegisteredType(fieldType string) bool {
+	_, ok := registeredFieldBinder(fieldType)
+	return ok
+}
+
+// registeredTypeValueWrap returns the wrapping of the values of a custom field type: the first value of an emitter
+// bound for the field is probed, strings and time.Time values are quoted, any other value is emitted as its JSON encoding
+func registeredTypeValueWrap(fieldCfg ConfigField, field Field) string {
+	binder, ok := registeredFieldBinder(field.Type)
+	if !ok {
+		return "\""
+	}
+
+	// the binding error, if any, is returned when the field is bound for generating
+	fieldEmitter, err := bindRegistered(binder, fieldCfg, field)
+	if err != nil {
+		return "\""
+	}
+
+	switch fieldEmitter(EventState{}).(type) {
+	case string, time.Time:
+		return "\""
+	default:
+		return ""
+	}
+}
+
+func eventStateOf(state *genState) EventState {
+	return EventState{Counter: state.counter, TotEvents: state.totEvents}
+}
+
+func bindRegistered(binder FieldBinder, fieldCfg ConfigField, field Field) (FieldEmitter, error) {
+	emitterMap := make(map[string]FieldEmitter)
+	if err := binder(fieldCfg, field, emitterMap); err != nil {
+		return nil, err
+	}
+
+	fieldEmitter, ok := emitterMap[field.Name]
+	if !ok || fieldEmitter == nil {
+		return nil, errCustomFieldNotBound
+	}
+
+	return fieldEmitter, nil
+}
+
+func bindRegisteredType(binder FieldBinder, fieldCfg ConfigField, field Field, fieldMap map[string]any) error {
+	fieldEmitter, err := bindRegistered(binder, fieldCfg, field)
+	if err != nil {
+		return err
+	}
+
+	var emitFNotReturn emitFNotReturn
+	emitFNotReturn = func(state *genState, buf *bytes.Buffer) error {
+		// strings are written raw, like the values of the other field types, and quoted by the template
+		switch v := fieldEmitter(eventStateOf(state)).(type) {
+		case string:
+			buf.WriteString(v)
+		case time.Time:
+			buf.WriteString(v.Format(FieldTypeTimeLayout))
+		default:
+			vstr, err := json.Marshal(v)
+			if err != nil {
+				return err
+			}
+
+			buf.Write(vstr)
+		}
+
+		return nil
+	}
+
+	fieldMap[field.Name] = emitFNotReturn
+	return nil
+}
+
+func bindRegisteredTypeWithReturn(binder FieldBinder, fieldCfg ConfigField, field Field, fieldMap map[string]any) error {
+	fieldEmitter, err := bindRegistered(binder, fieldCfg, field)
+	if err != nil {
+		return err
+	}
+
+	var emitF emitF
+	emitF = func(state *genState) any {
+		return fieldEmitter(eventStateOf(state))
+	}
+
+	fieldMap[field.Name] = emitF
+	return nil
+}
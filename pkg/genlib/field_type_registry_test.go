@@ -0,0 +1,208 @@
+package genlib
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+const fieldTypeSequence = "test_sequence"
+
+func registerSequenceFieldType() {
+	RegisterFieldType(fieldTypeSequence, func(fieldCfg ConfigField, field Field, fieldMap map[string]FieldEmitter) error {
+		fieldMap[field.Name] = func(event EventState) any {
+			return fmt.Sprintf("seq-%d", event.Counter+1)
+		}
+
+		return nil
+	})
+}
+
+func Test_RegisteredFieldTypeWithCustomTemplate(t *testing.T) {
+	registerSequenceFieldType()
+
+	fld := Field{
+		Name: "alpha",
+		Type: fieldTypeSequence,
+	}
+
+	template, objectKeysField := generateCustomTemplateFromField(Config{}, Fields{fld})
+	flds := append(Fields{fld}, objectKeysField...)
+	t.Logf("with template: %s", string(template))
+	g := makeGeneratorWithCustomTemplate(t, Config{}, flds, template, 3)
+
+	for i := 1; i <= 3; i++ {
+		var buf bytes.Buffer
+		if err := g.Emit(&buf); err != nil {
+			t.Fatal(err)
+		}
+
+		m := unmarshalJSONT[string](t, buf.Bytes())
+		if m[fld.Name] != fmt.Sprintf("seq-%d", i) {
+			t.Errorf("Expected seq-%d, got %s", i, m[fld.Name])
+		}
+	}
+}
+
+func Test_RegisteredFieldTypeWithTextTemplate(t *testing.T) {
+	registerSequenceFieldType()
+
+	fld := Field{
+		Name: "alpha",
+		Type: fieldTypeSequence,
+	}
+
+	template := []byte(`{"alpha":"{{generate "alpha"}}"}`)
+	t.Logf("with template: %s", string(template))
+	g := makeGeneratorWithTextTemplate(t, Config{}, Fields{fld}, template, 3)
+
+	for i := 1; i <= 3; i++ {
+		var buf bytes.Buffer
+		if err := g.Emit(&buf); err != nil {
+			t.Fatal(err)
+		}
+
+		m := unmarshalJSONT[string](t, buf.Bytes())
+		if m[fld.Name] != fmt.Sprintf("seq-%d", i) {
+			t.Errorf("Expected seq-%d, got %s", i, m[fld.Name])
+		}
+	}
+}
+
+func Test_RegisteredFieldTypeSameJSONInBothEngines(t *testing.T) {
+	registerSequenceFieldType()
+
+	flds := Fields{{Name: "alpha", Type: fieldTypeSequence}}
+
+	customTemplate, _ := generateCustomTemplateFromField(Config{}, flds)
+	textTemplate, _ := generateTextTemplateFromField(Config{}, flds)
+
+	testCases := []struct {
+		scenario       string
+		customTemplate []byte
+		textTemplate   []byte
+	}{
+		{
+			scenario:       "generated templates",
+			customTemplate: customTemplate,
+			textTemplate:   textTemplate,
+		},
+		{
+			scenario:       "quoted in the templates",
+			customTemplate: []byte(`{"alpha":"{{.alpha}}"}`),
+			textTemplate:   []byte(`{"alpha":"{{generate "alpha"}}"}`),
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.scenario, func(t *testing.T) {
+			customG := makeGeneratorWithCustomTemplate(t, Config{}, flds, testCase.customTemplate, 3)
+			textG := makeGeneratorWithTextTemplate(t, Config{}, flds, testCase.textTemplate, 3)
+
+			for i := 1; i <= 3; i++ {
+				var customBuf, textBuf bytes.Buffer
+				if err := customG.Emit(&customBuf); err != nil {
+					t.Fatal(err)
+				}
+
+				if err := textG.Emit(&textBuf); err != nil {
+					t.Fatal(err)
+				}
+
+				if customBuf.String() != textBuf.String() {
+					t.Errorf("Expected identical JSON, got %s with custom template and %s with text template", customBuf.String(), textBuf.String())
+				}
+
+				m := unmarshalJSONT[string](t, customBuf.Bytes())
+				if m["alpha"] != fmt.Sprintf("seq-%d", i) {
+					t.Errorf("Expected seq-%d, got %s", i, m["alpha"])
+				}
+			}
+		})
+	}
+}
+
+const fieldTypeMeasure = "test_measure"
+
+func registerMeasureFieldType() {
+	RegisterFieldType(fieldTypeMeasure, func(fieldCfg ConfigField, field Field, fieldMap map[string]FieldEmitter) error {
+		fieldMap[field.Name] = func(event EventState) any {
+			return map[string]any{"value": event.Counter * 10, "unit": "ms"}
+		}
+
+		return nil
+	})
+}
+
+func Test_RegisteredFieldTypeNotStringWithCustomTemplate(t *testing.T) {
+	registerMeasureFieldType()
+	registerSequenceFieldType()
+
+	flds := Fields{{Name: "alpha", Type: fieldTypeMeasure}, {Name: "beta", Type: fieldTypeSequence}}
+
+	template, _ := generateCustomTemplateFromField(Config{}, flds)
+	t.Logf("with template: %s", string(template))
+	g := makeGeneratorWithCustomTemplate(t, Config{}, flds, template, 3)
+
+	for i := 0; i < 3; i++ {
+		var buf bytes.Buffer
+		if err := g.Emit(&buf); err != nil {
+			t.Fatal(err)
+		}
+
+		m := unmarshalJSONT[any](t, buf.Bytes())
+		expected := map[string]any{"value": float64(i * 10), "unit": "ms"}
+		if !reflect.DeepEqual(expected, m["alpha"]) {
+			t.Errorf("Expected %v, got %s", expected, buf.String())
+		}
+
+		if m["beta"] != fmt.Sprintf("seq-%d", i+1) {
+			t.Errorf("Expected seq-%d, got %s", i+1, buf.String())
+		}
+	}
+}
+
+func Test_RegisteredFieldTypeNotStringWithTextTemplate(t *testing.T) {
+	registerMeasureFieldType()
+	registerSequenceFieldType()
+
+	flds := Fields{{Name: "alpha", Type: fieldTypeMeasure}, {Name: "beta", Type: fieldTypeSequence}}
+
+	template, _ := generateTextTemplateFromField(Config{}, flds)
+	t.Logf("with template: %s", string(template))
+	g := makeGeneratorWithTextTemplate(t, Config{}, flds, template, 3)
+
+	for i := 0; i < 3; i++ {
+		var buf bytes.Buffer
+		if err := g.Emit(&buf); err != nil {
+			t.Fatal(err)
+		}
+
+		m := unmarshalJSONT[any](t, buf.Bytes())
+		expected := map[string]any{"value": float64(i * 10), "unit": "ms"}
+		if !reflect.DeepEqual(expected, m["alpha"]) {
+			t.Errorf("Expected %v, got %s", expected, buf.String())
+		}
+
+		if m["beta"] != fmt.Sprintf("seq-%d", i+1) {
+			t.Errorf("Expected seq-%d, got %s", i+1, buf.String())
+		}
+	}
+}
+
+func Test_RegisteredFieldTypeNotBound(t *testing.T) {
+	RegisterFieldType("test_unbound", func(fieldCfg ConfigField, field Field, fieldMap map[string]FieldEmitter) error {
+		return nil
+	})
+
+	fld := Field{
+		Name: "alpha",
+		Type: "test_unbound",
+	}
+
+	_, err := NewGeneratorWithCustomTemplate([]byte(`{"alpha":"{{.alpha}}"}`), Config{}, Fields{fld}, 1)
+	if err != errCustomFieldNotBound {
+		t.Errorf("Expected errCustomFieldNotBound, got %v", err)
+	}
+}
@@ -17,6 +17,13 @@ type tuple struct {
 	version     string
 }
 
+// PackageDataStream identifies the data stream of an integration package to load fields from
+type PackageDataStream struct {
+	Integration string
+	DataStream  string
+	Version     string
+}
+
 type Manifest struct {
 	Title   string `config:"title"`
 	Type    string `config:"type"`
@@ -96,3 +103,19 @@ func (f *Cache) LoadFields(ctx context.Context, integration, stream, version str
 
 	return flds, nil
 }
+
+// LoadFieldsFromPackages loads the fields of several integration data streams and merges them.
+// On name conflicts fields from later packages override fields from earlier ones.
+func (f *Cache) LoadFieldsFromPackages(ctx context.Context, packages ...PackageDataStream) (Fields, error) {
+	fieldsSets := make([]Fields, 0, len(packages))
+	for _, p := range packages {
+		flds, err := f.LoadFields(ctx, p.Integration, p.DataStream, p.Version)
+		if err != nil {
+			return nil, err
+		}
+
+		fieldsSets = append(fieldsSets, flds)
+	}
+
+	return MergeFields(fieldsSets...)
+}
@@ -13,11 +13,12 @@ func (f Fields) Less(i, j int) bool { return f[i].Name < f[j].Name }
 func (f Fields) Swap(i, j int)      { f[i], f[j] = f[j], f[i] }
 
 type Field struct {
-	Name       string
-	Type       string
-	ObjectType string
-	Example    string
-	Value      string
+	Name          string
+	Type          string
+	ObjectType    string
+	Example       string
+	Value         string
+	ScalingFactor float64
 }
 
 func (fields Fields) merge(fieldsToMerge ...Field) Fields {
@@ -83,3 +84,23 @@ func normaliseFields(fields Fields) (Fields, error) {
 	sort.Sort(normalisedFields)
 	return normalisedFields, nil
 }
+
+// MergeFields merges several sets of fields into one.
+// On name conflicts fields from later sets override fields from earlier ones.
+func MergeFields(fieldsSets ...Fields) (Fields, error) {
+	indexByName := make(map[string]int)
+	merged := make(Fields, 0)
+	for _, fields := range fieldsSets {
+		for _, field := range fields {
+			if idx, ok := indexByName[field.Name]; ok {
+				merged[idx] = field
+				continue
+			}
+
+			indexByName[field.Name] = len(merged)
+			merged = append(merged, field)
+		}
+	}
+
+	return normaliseFields(merged)
+}
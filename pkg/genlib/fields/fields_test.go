@@ -0,0 +1,58 @@
+package fields
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMergeFields(t *testing.T) {
+	ecsFields := Fields{
+		{Name: "host.name", Type: "keyword"},
+		{Name: "event.duration", Type: "long"},
+		{Name: "message", Type: "text"},
+	}
+
+	integrationFields := Fields{
+		{Name: "aws.cloudwatch.namespace", Type: "keyword"},
+		{Name: "event.duration", Type: "double", Example: "1.5"},
+	}
+
+	merged, err := MergeFields(ecsFields, integrationFields)
+	assert.Nil(t, err)
+
+	expected := Fields{
+		{Name: "aws.cloudwatch.namespace", Type: "keyword"},
+		{Name: "event.duration", Type: "double", Example: "1.5"},
+		{Name: "host.name", Type: "keyword"},
+		{Name: "message", Type: "text"},
+	}
+	assert.Equal(t, expected, merged)
+}
+
+func TestMergeFieldsOverridePrecedence(t *testing.T) {
+	first := Fields{{Name: "field", Type: "keyword", Value: "first"}}
+	second := Fields{{Name: "field", Type: "keyword", Value: "second"}}
+	third := Fields{{Name: "field", Type: "long"}}
+
+	merged, err := MergeFields(first, second)
+	assert.Nil(t, err)
+	assert.Len(t, merged, 1)
+	assert.Equal(t, "second", merged[0].Value)
+
+	merged, err = MergeFields(second, first)
+	assert.Nil(t, err)
+	assert.Len(t, merged, 1)
+	assert.Equal(t, "first", merged[0].Value)
+
+	merged, err = MergeFields(first, second, third)
+	assert.Nil(t, err)
+	assert.Len(t, merged, 1)
+	assert.Equal(t, Field{Name: "field", Type: "long"}, merged[0])
+}
+
+func TestMergeFieldsEmpty(t *testing.T) {
+	merged, err := MergeFields()
+	assert.Nil(t, err)
+	assert.Len(t, merged, 0)
+}
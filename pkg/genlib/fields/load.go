@@ -4,6 +4,8 @@ import (
 	"archive/zip"
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -33,26 +35,46 @@ type yamlManifest struct {
 	Type string `config:"type"`
 }
 
+// SchemaVersion identifies the fields schema loaded by LoadFieldsWithSchemaVersion, for provenance of the corpus
+type SchemaVersion struct {
+	// Version is the version of the integration package the fields are loaded from
+	Version string
+	// Hash is the hex encoded SHA-256 of the content of the fields files
+	Hash string
+}
+
 func LoadFields(ctx context.Context, baseURL, integration, dataStream, version string) (Fields, string, error) {
+	fields, dataStreamType, _, err := LoadFieldsWithSchemaVersion(ctx, baseURL, integration, dataStream, version)
+	return fields, dataStreamType, err
+}
+
+// LoadFieldsWithSchemaVersion is like LoadFields, returning as well the version of the loaded fields schema
+func LoadFieldsWithSchemaVersion(ctx context.Context, baseURL, integration, dataStream, version string) (Fields, string, SchemaVersion, error) {
 
 	fieldsContent, dataStreamType, err := getFieldsFilesAndDataStreamType(ctx, baseURL, integration, dataStream, version)
 	if err != nil {
-		return nil, dataStreamType, err
+		return nil, dataStreamType, SchemaVersion{}, err
 	}
 
 	if len(fieldsContent) == 0 {
-		return nil, dataStreamType, ErrNotFound
+		return nil, dataStreamType, SchemaVersion{}, ErrNotFound
+	}
+
+	hash := sha256.Sum256(fieldsContent)
+	schemaVersion := SchemaVersion{
+		Version: version,
+		Hash:    hex.EncodeToString(hash[:]),
 	}
 
 	fieldsFromYaml, err := loadFieldsFromYaml(fieldsContent)
 	if err != nil {
-		return nil, dataStreamType, err
+		return nil, dataStreamType, SchemaVersion{}, err
 	}
 
 	fields := collectFields(fieldsFromYaml, "")
 
 	fields, err = normaliseFields(fields)
-	return fields, dataStreamType, err
+	return fields, dataStreamType, schemaVersion, err
 }
 
 func LoadFieldsWithTemplateFromString(ctx context.Context, fieldsContent string) (Fields, error) {
@@ -0,0 +1,70 @@
+package fields
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testFieldsContent = "- name: alpha\n  type: keyword\n"
+
+// newTestPackageRegistry returns a package registry serving the integration package at version
+func newTestPackageRegistry(t *testing.T, integration, dataStream, version string) *httptest.Server {
+	var archive bytes.Buffer
+	zw := zip.NewWriter(&archive)
+	files := map[string]string{
+		integration + "-" + version + "/data_stream/" + dataStream + "/fields/fields.yml": testFieldsContent,
+		integration + "-" + version + "/data_stream/" + dataStream + "/manifest.yml":      "type: logs\n",
+	}
+
+	for name, content := range files {
+		w, err := zw.Create(name)
+		assert.Nil(t, err)
+		_, err = w.Write([]byte(content))
+		assert.Nil(t, err)
+	}
+
+	assert.Nil(t, zw.Close())
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/package/"+integration+"/"+version, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"download":"/epr/` + integration + `-` + version + `.zip"}`))
+	})
+
+	mux.HandleFunc("/epr/"+integration+"-"+version+".zip", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(archive.Bytes())
+	})
+
+	return httptest.NewServer(mux)
+}
+
+func TestLoadFieldsWithSchemaVersion(t *testing.T) {
+	registry := newTestPackageRegistry(t, "integration", "data_stream", "1.2.3")
+	defer registry.Close()
+
+	flds, dataStreamType, schemaVersion, err := LoadFieldsWithSchemaVersion(context.Background(), registry.URL, "integration", "data_stream", "1.2.3")
+	assert.Nil(t, err)
+	assert.Equal(t, "logs", dataStreamType)
+	assert.Len(t, flds, 1)
+	assert.Equal(t, "1.2.3", schemaVersion.Version)
+
+	assert.Len(t, schemaVersion.Hash, 64)
+
+	_, _, otherSchemaVersion, err := LoadFieldsWithSchemaVersion(context.Background(), registry.URL, "integration", "data_stream", "1.2.3")
+	assert.Nil(t, err)
+	assert.Equal(t, schemaVersion, otherSchemaVersion)
+}
+
+func TestLoadFieldsWithTemplateFromStringScalingFactor(t *testing.T) {
+	flds, err := LoadFieldsWithTemplateFromString(context.Background(), "- name: system.cpu.total.pct\n  type: scaled_float\n  scaling_factor: 1000\n- name: alpha\n  type: double")
+	assert.Nil(t, err)
+	assert.Equal(t, Fields{
+		{Name: "alpha", Type: "double"},
+		{Name: "system.cpu.total.pct", Type: "scaled_float", ScalingFactor: 1000},
+	}, flds)
+}
@@ -7,12 +7,13 @@ import (
 type yamlFields []yamlField
 
 type yamlField struct {
-	Name       string     `config:"name"`
-	Type       string     `config:"type"`
-	ObjectType string     `config:"object_type"`
-	Value      string     `config:"value"`
-	Example    string     `config:"example"`
-	Fields     yamlFields `config:"fields"`
+	Name          string     `config:"name"`
+	Type          string     `config:"type"`
+	ObjectType    string     `config:"object_type"`
+	Value         string     `config:"value"`
+	Example       string     `config:"example"`
+	Fields        yamlFields `config:"fields"`
+	ScalingFactor float64    `config:"scaling_factor"`
 }
 
 func loadFieldsFromYaml(f []byte) (yamlFields, error) {
@@ -38,10 +39,11 @@ func collectFields(fieldsFromYaml yamlFields, namePrefix string) Fields {
 	fields := make(Fields, 0, len(fieldsFromYaml))
 	for _, fieldFromYaml := range fieldsFromYaml {
 		field := Field{
-			Type:       fieldFromYaml.Type,
-			ObjectType: fieldFromYaml.ObjectType,
-			Example:    fieldFromYaml.Example,
-			Value:      fieldFromYaml.Value,
+			Type:          fieldFromYaml.Type,
+			ObjectType:    fieldFromYaml.ObjectType,
+			Example:       fieldFromYaml.Example,
+			Value:         fieldFromYaml.Value,
+			ScalingFactor: fieldFromYaml.ScalingFactor,
 		}
 
 		if len(namePrefix) == 0 {
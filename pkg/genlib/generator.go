@@ -8,10 +8,12 @@ import (
 	"bytes"
 	"fmt"
 	"github.com/Pallinder/go-randomdata"
-	"github.com/lithammer/shortuuid/v3"
 	"math/rand"
+	"strconv"
 	"strings"
 	"time"
+
+	"github.com/elastic/elastic-integration-corpus-generator-tool/pkg/genlib/config"
 )
 
 var customRand *rand.Rand
@@ -31,7 +33,7 @@ func fieldValueWrapByType(field Field) string {
 		return "\""
 	case FieldTypeDouble, FieldTypeFloat, FieldTypeHalfFloat, FieldTypeScaledFloat:
 		return ""
-	case FieldTypeInteger, FieldTypeLong, FieldTypeUnsignedLong:
+	case FieldTypeByte, FieldTypeShort, FieldTypeInteger, FieldTypeLong, FieldTypeUnsignedLong:
 		return ""
 	case FieldTypeConstantKeyword:
 		return "\""
@@ -39,6 +41,9 @@ func fieldValueWrapByType(field Field) string {
 		return "\""
 	case FieldTypeBool:
 		return ""
+	case FieldTypeGeoShape, FieldTypePoint:
+		// GeoJSON geometries and cartesian points are JSON objects
+		return ""
 	case FieldTypeObject, FieldTypeNested, FieldTypeFlattened:
 		if len(field.ObjectType) > 0 {
 			field.Type = field.ObjectType
@@ -46,13 +51,174 @@ func fieldValueWrapByType(field Field) string {
 			field.Type = FieldTypeKeyword
 		}
 		return fieldValueWrapByType(field)
-	case FieldTypeGeoPoint:
+	case FieldTypeGeoPoint, FieldTypeHostname, FieldTypeCIDR, FieldTypePersonName, FieldTypePath, FieldTypeRegistryPath, FieldTypeULID, FieldTypeUUID, FieldTypeHexToken:
 		return "\""
 	default:
 		return "\""
 	}
 }
 
+// isNumericType returns whether the values of the field type are emitted as JSON numbers
+func isNumericType(fieldType string) bool {
+	switch fieldType {
+	case FieldTypeDouble, FieldTypeFloat, FieldTypeHalfFloat, FieldTypeScaledFloat:
+		return true
+	case FieldTypeByte, FieldTypeShort, FieldTypeInteger, FieldTypeLong, FieldTypeUnsignedLong:
+		return true
+	default:
+		return false
+	}
+}
+
+// fieldValueWrapByConfig returns the wrapping of the value of the field, taking in account the config overrides
+func fieldValueWrapByConfig(cfg Config, field Field) string {
+	fieldCfg, _ := cfg.GetField(field.Name)
+	// arrays are emitted as JSON, the wrapping applies to their elements
+	if fieldCfg.Array != nil {
+		return ""
+	}
+
+	return fieldValueWrapByFieldConfig(fieldCfg, field)
+}
+
+// fieldValueWrapByFieldConfig returns the wrapping of a single value of the field, taking in account the config overrides
+func fieldValueWrapByFieldConfig(fieldCfg ConfigField, field Field) string {
+	if fieldCfg.Value != nil || len(fieldCfg.RawJSON) > 0 || len(fieldCfg.Sequence) > 0 || len(fieldCfg.RelatedFields) > 0 || len(fieldCfg.Polymorphic) > 0 || isEnumArray(fieldCfg) {
+		return ""
+	}
+
+	// numbers emitted as strings are not parsed as doubles, that lose the precision of large values
+	if fieldCfg.NumericAsString && isNumericType(field.Type) {
+		return "\""
+	}
+
+	if (field.Type == FieldTypeDate || field.Type == FieldTypeValidity) && isEpochDateFormat(fieldCfg.Format) {
+		return ""
+	}
+
+	if isRegisteredType(field.Type) {
+		return registeredTypeValueWrap(fieldCfg, field)
+	}
+
+	return fieldValueWrapByType(field)
+}
+
+func isDynamicObjectField(field Field) bool {
+	return strings.HasSuffix(field.Name, ".*") || field.Type == FieldTypeObject || field.Type == FieldTypeNested || field.Type == FieldTypeFlattened
+}
+
+// splitFirstOnlyFields splits the fields emitted only in the first event from the others
+func splitFirstOnlyFields(cfg Config, fields Fields) (Fields, Fields) {
+	firstOnlyFields := make(Fields, 0)
+	otherFields := make(Fields, 0, len(fields))
+	for _, field := range fields {
+		if fieldCfg, ok := cfg.GetField(field.Name); ok && fieldCfg.FirstOnly && !isDynamicObjectField(field) {
+			firstOnlyFields = append(firstOnlyFields, field)
+			continue
+		}
+
+		otherFields = append(otherFields, field)
+	}
+
+	return firstOnlyFields, otherFields
+}
+
+// isFormattedDouble returns true if the value of the field is a single double, rendered with formatDouble in text templates
+func isFormattedDouble(cfg Config, field Field) bool {
+	return isDoubleField(field) && !isPolymorphic(cfg, field) && !isArray(cfg, field)
+}
+
+// firstOnlyFieldTemplate returns the template of a field emitted only in the first event.
+// The whole key/value pair, with its trailing comma, is emitted only in the first event, so that the following events stay valid.
+func firstOnlyFieldTemplate(cfg Config, field Field, fieldKey, fieldWrap string, isLast bool, templateEngine int) string {
+	fieldTrailer := " "
+	separator := ", "
+	if isLast {
+		fieldTrailer = " }"
+		separator = ""
+	}
+
+	if templateEngine == customTemplateEngine {
+		// the emitter of the field writes the key/value pair and the separator
+		return fmt.Sprintf(`{{.%s}}%s`, field.Name, fieldTrailer)
+	}
+
+	fieldVariableName := fieldNormalizerRegex.ReplaceAllString(field.Name, "") + "Var"
+	fieldValue := fmt.Sprintf(`{{$%s}}`, fieldVariableName)
+	if field.Type == FieldTypeDate && !hasDateFormat(cfg, field) {
+		fieldValue = fmt.Sprintf(`{{$%s.Format "2006-01-02T15:04:05.999999999Z07:00"}}`, fieldVariableName)
+	}
+
+	if isFormattedDouble(cfg, field) {
+		fieldValue = fmt.Sprintf(`{{formatDouble "%s" $%s}}`, field.Name, fieldVariableName)
+	}
+
+	if isRegisteredType(field.Type) && !isArray(cfg, field) {
+		// the value is quoted by toJson
+		fieldValue = fmt.Sprintf(`{{toJson $%s}}`, fieldVariableName)
+		fieldWrap = ""
+	}
+
+	return fmt.Sprintf(`{{ $%s := generate "%s" }}{{ if not (kindIs "invalid" $%s) }}"%s": %s%s%s%s{{ end }}%s`, fieldVariableName, field.Name, fieldVariableName, fieldKey, fieldWrap, fieldValue, fieldWrap, separator, fieldTrailer)
+}
+
+// emittedFieldNames returns the names of the fields emitted for a field that is not a dynamic object:
+// the field itself, or the group of fields its type generates, and its `.keyword` multi-field if any
+func emittedFieldNames(cfg Config, field Field) []string {
+	var fieldNames []string
+	switch field.Type {
+	case FieldTypeMoney:
+		// money fields are emitted as a group of currency and amount
+		fieldNames = []string{field.Name + moneyCurrencySuffix, field.Name + moneyAmountSuffix}
+	case FieldTypeASN:
+		// asn fields are emitted as a group of number and organization name
+		fieldNames = []string{field.Name + asnNumberSuffix, field.Name + asnOrganizationNameSuffix}
+	case FieldTypeOS:
+		// os fields are emitted as a group of name, version and family
+		fieldNames = []string{field.Name + osNameSuffix, field.Name + osVersionSuffix, field.Name + osFamilySuffix}
+	case FieldTypeProcess:
+		// process fields are emitted as a group of pid and name, for the process and its parent
+		fieldNames = []string{field.Name + processPIDSuffix, field.Name + processNameSuffix, field.Name + processParentPIDSuffix, field.Name + processParentNameSuffix}
+	case FieldTypeCloud:
+		// cloud fields are emitted as a group of provider, account id, region and availability zone
+		fieldNames = []string{field.Name + cloudProviderSuffix, field.Name + cloudAccountIDSuffix, field.Name + cloudRegionSuffix, field.Name + cloudAvailabilityZoneSuffix}
+	case FieldTypeValidity:
+		// validity fields are emitted as a group of not before and not after dates
+		fieldNames = []string{field.Name + validityNotBeforeSuffix, field.Name + validityNotAfterSuffix}
+	case FieldTypeKubernetes:
+		// kubernetes fields are emitted as a group of namespace, deployment and pod name, with their container as sibling
+		containerPrefix := kubernetesContainerPrefix(field.Name)
+		fieldNames = []string{field.Name + kubernetesNamespaceSuffix, field.Name + kubernetesDeploymentNameSuffix, field.Name + kubernetesPodNameSuffix, containerPrefix + containerIDField, containerPrefix + containerImageNameField}
+	case FieldTypeDNS:
+		// dns fields are emitted as a group of question name and type, and the name, type and data of the answer
+		fieldNames = []string{field.Name + dnsQuestionNameSuffix, field.Name + dnsQuestionTypeSuffix, field.Name + dnsAnswersNameSuffix, field.Name + dnsAnswersTypeSuffix, field.Name + dnsAnswersDataSuffix}
+	case FieldTypeSession:
+		// session fields are emitted as a group of id, step and user name
+		fieldNames = []string{field.Name + sessionIDSuffix, field.Name + sessionStepSuffix, field.Name + sessionUserNameSuffix}
+	case FieldTypeTLS:
+		// tls fields are emitted as a group of version, cipher and the common name and validity dates of the server certificate
+		fieldNames = []string{field.Name + tlsVersionSuffix, field.Name + tlsVersionProtocolSuffix, field.Name + tlsCipherSuffix, field.Name + tlsServerCommonNameSuffix, field.Name + tlsServerX509Suffix + validityNotBeforeSuffix, field.Name + tlsServerX509Suffix + validityNotAfterSuffix}
+	case FieldTypeEventCategorization:
+		// event_categorization fields are emitted as a group of category, type and action
+		fieldNames = []string{field.Name + eventCategorySuffix, field.Name + eventTypeSuffix, field.Name + eventActionSuffix}
+	case FieldTypeUser:
+		// user fields are emitted as a group of id, name and group name
+		fieldNames = []string{field.Name + userIDSuffix, field.Name + userNameSuffix, field.Name + userGroupNameSuffix}
+	case FieldTypeWebTransaction:
+		// web_transaction fields are emitted as a group of request method and bytes, response status code and bytes,
+		// with the duration of the transaction as sibling
+		fieldNames = []string{field.Name + webRequestMethodSuffix, field.Name + webRequestBytesSuffix, field.Name + webResponseStatusSuffix, field.Name + webResponseBytesSuffix, webTransactionEventPrefix(field.Name) + webTransactionDurationField}
+	default:
+		fieldNames = []string{field.Name}
+	}
+
+	if fieldCfg, ok := cfg.GetField(field.Name); ok && fieldCfg.KeywordMultiField {
+		fieldNames = append(fieldNames, field.Name+keywordMultiFieldSuffix)
+	}
+
+	return fieldNames
+}
+
 func generateCustomTemplateFromField(cfg Config, fields Fields) ([]byte, []Field) {
 	return generateTemplateFromField(cfg, fields, customTemplateEngine)
 }
@@ -69,28 +235,37 @@ func generateTemplateFromField(cfg Config, fields Fields, templateEngine int) ([
 	dupes := make(map[string]struct{})
 	objectKeysField := make([]Field, 0, len(fields))
 
+	// first only fields go first: they carry their own trailing comma
+	firstOnlyFields, otherFields := splitFirstOnlyFields(cfg, fields)
+	fields = append(firstOnlyFields, otherFields...)
+
 	templatePrefix := "{ "
 	templateBuffer := bytes.NewBufferString(templatePrefix)
 	for i, field := range fields {
-		fieldWrap := fieldValueWrapByType(field)
-		if fieldCfg, ok := cfg.GetField(field.Name); ok {
-			if fieldCfg.Value != nil {
-				fieldWrap = ""
-			}
-		}
+		fieldWrap := fieldValueWrapByConfig(cfg, field)
 
 		fieldTrailer := []byte(",")
 		if i == len(fields)-1 {
 			fieldTrailer = []byte(" }")
 		}
 
-		if strings.HasSuffix(field.Name, ".*") || field.Type == FieldTypeObject || field.Type == FieldTypeNested || field.Type == FieldTypeFlattened {
+		if i < len(firstOnlyFields) {
+			templateBuffer.WriteString(firstOnlyFieldTemplate(cfg, field, cfg.FieldKey(field.Name), fieldWrap, i == len(fields)-1, templateEngine))
+			continue
+		}
+
+		if isNullableField(cfg, field) {
+			templateBuffer.WriteString(nullableFieldTemplate(cfg, field, cfg.FieldKey(field.Name), fieldWrap, i == len(fields)-1, templateEngine))
+			continue
+		}
+
+		if isDynamicObjectField(field) {
 			// This is a special case.  We are randomly generating keys on the fly
 			// Will set the json field name as "field.Name.N"
 			N := 5
 			for ii := 0; ii < N; ii++ {
 				// Fire or skip
-				if rand.Int()%2 == 0 {
+				if customRand.Int()%2 == 0 {
 					continue
 				}
 
@@ -107,10 +282,10 @@ func generateTemplateFromField(cfg Config, fields Fields, templateEngine int) ([
 					_, ok = dupes[rNoun]
 				}
 
-				// If all else fails, use a shortuuid.
-				// Try to avoid this as it is alloc expensive
+				// If all else fails, suffix the noun with the number of keys generated so far:
+				// nouns have no digits, so the key is unique and still reproducible under a seed
 				if try >= maxTries {
-					rNoun = shortuuid.New()
+					rNoun += strconv.Itoa(len(dupes))
 				}
 
 				dupes[rNoun] = struct{}{}
@@ -119,17 +294,18 @@ func generateTemplateFromField(cfg Config, fields Fields, templateEngine int) ([
 				fieldNameRoot := replacer.Replace(field.Name)
 				fieldVariableName := fieldNormalizerRegex.ReplaceAllString(fmt.Sprintf("%s%s", fieldNameRoot, rNoun), "")
 				fieldVariableName += "Var"
+				fieldKey := cfg.FieldKey(fieldNameRoot + "." + rNoun)
 				if field.Type == FieldTypeDate {
 					if templateEngine == textTemplateEngine {
-						fieldTemplate = fmt.Sprintf(`{{ $%s := generate "%s.%s" }}"%s.%s": %s{{$%s.Format "2006-01-02T15:04:05.999999999Z07:00"}}%s%s`, fieldVariableName, fieldNameRoot, rNoun, fieldNameRoot, rNoun, fieldWrap, fieldVariableName, fieldWrap, fieldTrailer)
+						fieldTemplate = fmt.Sprintf(`{{ $%s := generate "%s.%s" }}"%s": %s{{$%s.Format "2006-01-02T15:04:05.999999999Z07:00"}}%s%s`, fieldVariableName, fieldNameRoot, rNoun, fieldKey, fieldWrap, fieldVariableName, fieldWrap, fieldTrailer)
 					} else if templateEngine == customTemplateEngine {
-						fieldTemplate = fmt.Sprintf(`"%s.%s": %s{{.%s.%s}}%s%s`, fieldNameRoot, rNoun, fieldWrap, fieldNameRoot, rNoun, fieldWrap, fieldTrailer)
+						fieldTemplate = fmt.Sprintf(`"%s": %s{{.%s.%s}}%s%s`, fieldKey, fieldWrap, fieldNameRoot, rNoun, fieldWrap, fieldTrailer)
 					}
 				} else {
 					if templateEngine == textTemplateEngine {
-						fieldTemplate = fmt.Sprintf(`"%s.%s": %s{{generate "%s.%s"}}%s%s`, fieldNameRoot, rNoun, fieldWrap, fieldNameRoot, rNoun, fieldWrap, fieldTrailer)
+						fieldTemplate = fmt.Sprintf(`"%s": %s{{generate "%s.%s"}}%s%s`, fieldKey, fieldWrap, fieldNameRoot, rNoun, fieldWrap, fieldTrailer)
 					} else if templateEngine == customTemplateEngine {
-						fieldTemplate = fmt.Sprintf(`"%s.%s": %s{{.%s.%s}}%s%s`, fieldNameRoot, rNoun, fieldWrap, fieldNameRoot, rNoun, fieldWrap, fieldTrailer)
+						fieldTemplate = fmt.Sprintf(`"%s": %s{{.%s.%s}}%s%s`, fieldKey, fieldWrap, fieldNameRoot, rNoun, fieldWrap, fieldTrailer)
 					}
 				}
 
@@ -141,24 +317,70 @@ func generateTemplateFromField(cfg Config, fields Fields, templateEngine int) ([
 				templateBuffer.WriteString(fieldTemplate)
 			}
 		} else {
-			var fieldTemplate string
-			fieldVariableName := fieldNormalizerRegex.ReplaceAllString(field.Name, "")
-			fieldVariableName += "Var"
-			if field.Type == FieldTypeDate {
-				if templateEngine == textTemplateEngine {
-					fieldTemplate = fmt.Sprintf(`{{ $%s := generate "%s" }}"%s": %s{{$%s.Format "2006-01-02T15:04:05.999999999Z07:00"}}%s%s`, fieldVariableName, field.Name, field.Name, fieldWrap, fieldVariableName, fieldWrap, fieldTrailer)
-				} else if templateEngine == customTemplateEngine {
-					fieldTemplate = fmt.Sprintf(`"%s": %s{{.%s}}%s%s`, field.Name, fieldWrap, field.Name, fieldWrap, fieldTrailer)
+			fieldNames := emittedFieldNames(cfg, field)
+			for ii, fieldName := range fieldNames {
+				fieldNameTrailer := fieldTrailer
+				if ii < len(fieldNames)-1 {
+					fieldNameTrailer = []byte(",")
 				}
-			} else {
-				if templateEngine == textTemplateEngine {
-					fieldTemplate = fmt.Sprintf(`"%s": %s{{generate "%s"}}%s%s`, field.Name, fieldWrap, field.Name, fieldWrap, fieldTrailer)
-				} else if templateEngine == customTemplateEngine {
-					fieldTemplate = fmt.Sprintf(`"%s": %s{{.%s}}%s%s`, field.Name, fieldWrap, field.Name, fieldWrap, fieldTrailer)
+
+				switch field.Type {
+				case FieldTypeMoney:
+					fieldWrap = "\""
+					if strings.HasSuffix(fieldName, moneyAmountSuffix) {
+						fieldWrap = ""
+					}
+				case FieldTypeASN:
+					fieldWrap = "\""
+					if strings.HasSuffix(fieldName, asnNumberSuffix) {
+						fieldWrap = ""
+					}
+				case FieldTypeProcess:
+					fieldWrap = "\""
+					if strings.HasSuffix(fieldName, processPIDSuffix) {
+						fieldWrap = ""
+					}
+				case FieldTypeSession:
+					fieldWrap = "\""
+					if strings.HasSuffix(fieldName, sessionStepSuffix) {
+						fieldWrap = ""
+					}
+				case FieldTypeWebTransaction:
+					fieldWrap = ""
+					if strings.HasSuffix(fieldName, webRequestMethodSuffix) {
+						fieldWrap = "\""
+					}
+				case FieldTypeTLS:
+					fieldWrap = "\""
+					if fieldCfg, _ := cfg.GetField(field.Name); isTLSDateField(field, fieldName) && isEpochDateFormat(fieldCfg.Format) {
+						fieldWrap = ""
+					}
+				}
+
+				var fieldTemplate string
+				fieldVariableName := fieldNormalizerRegex.ReplaceAllString(fieldName, "")
+				fieldVariableName += "Var"
+				fieldKey := cfg.FieldKey(fieldName)
+				if (field.Type == FieldTypeDate || field.Type == FieldTypeValidity || isTLSDateField(field, fieldName)) && !hasDateFormat(cfg, field) && !isPolymorphic(cfg, field) && !isArray(cfg, field) {
+					if templateEngine == textTemplateEngine {
+						fieldTemplate = fmt.Sprintf(`{{ $%s := generate "%s" }}"%s": %s{{$%s.Format "2006-01-02T15:04:05.999999999Z07:00"}}%s%s`, fieldVariableName, fieldName, fieldKey, fieldWrap, fieldVariableName, fieldWrap, fieldNameTrailer)
+					} else if templateEngine == customTemplateEngine {
+						fieldTemplate = fmt.Sprintf(`"%s": %s{{.%s}}%s%s`, fieldKey, fieldWrap, fieldName, fieldWrap, fieldNameTrailer)
+					}
+				} else {
+					if templateEngine == textTemplateEngine && fieldName == field.Name && isRegisteredType(field.Type) && !isArray(cfg, field) {
+						fieldTemplate = fmt.Sprintf(`"%s": {{generate "%s" | toJson}}%s`, fieldKey, fieldName, fieldNameTrailer)
+					} else if templateEngine == textTemplateEngine && fieldName == field.Name && isFormattedDouble(cfg, field) {
+						fieldTemplate = fmt.Sprintf(`"%s": %s{{formatDouble "%s" (generate "%s")}}%s%s`, fieldKey, fieldWrap, fieldName, fieldName, fieldWrap, fieldNameTrailer)
+					} else if templateEngine == textTemplateEngine {
+						fieldTemplate = fmt.Sprintf(`"%s": %s{{generate "%s"}}%s%s`, fieldKey, fieldWrap, fieldName, fieldWrap, fieldNameTrailer)
+					} else if templateEngine == customTemplateEngine {
+						fieldTemplate = fmt.Sprintf(`"%s": %s{{.%s}}%s%s`, fieldKey, fieldWrap, fieldName, fieldWrap, fieldNameTrailer)
+					}
 				}
-			}
 
-			templateBuffer.WriteString(fieldTemplate)
+				templateBuffer.WriteString(fieldTemplate)
+			}
 		}
 	}
 
@@ -166,18 +388,56 @@ func generateTemplateFromField(cfg Config, fields Fields, templateEngine int) ([
 }
 
 func NewGenerator(cfg Config, flds Fields, totEvents uint64) (Generator, error) {
+	beatsEvent := cfg.OutputMode() == config.OutputModeBeats
+	if beatsEvent {
+		flds = withBeatsEventFields(cfg, flds)
+	}
+
 	template, objectKeysField := generateCustomTemplateFromField(cfg, flds)
 	flds = append(flds, objectKeysField...)
 
-	return NewGeneratorWithCustomTemplate(template, cfg, flds, totEvents)
+	var gen Generator
+	gen, err := NewGeneratorWithCustomTemplate(template, cfg, flds, totEvents)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.MaxFieldsPerDoc() > 0 {
+		if gen, err = NewGeneratorWithMaxFields(gen, cfg.MaxFieldsPerDoc()); err != nil {
+			return nil, err
+		}
+	}
+
+	// beats events are always nested objects
+	if cfg.KeyStyle() != config.KeyStyleNested && !beatsEvent {
+		return gen, nil
+	}
+
+	// the fields are nested after capping them, so that the dotted keys are counted
+	var fieldNames []string
+	for _, field := range flds {
+		if !isDynamicObjectField(field) {
+			fieldNames = append(fieldNames, emittedFieldNames(cfg, field)...)
+		}
+	}
+
+	return NewGeneratorWithNestedOutput(gen, fieldNames)
 }
 
-// InitGeneratorTimeNow sets base timeNow for `date` field
+// InitGeneratorTimeNow sets base timeNow for `date` field: with the same timeNow and rand seed the dates generated
+// are the same across runs. If it is not called, the wall clock at the time the first generator is created is used.
 func InitGeneratorTimeNow(timeNow time.Time) {
 	// set timeNowToBind to --now flag (already parsed or now)
 	timeNowToBind = timeNow
 }
 
+// initGeneratorTimeNowIfUnset defaults base timeNow for `date` field to the wall clock
+func initGeneratorTimeNowIfUnset() {
+	if timeNowToBind.IsZero() {
+		timeNowToBind = time.Now()
+	}
+}
+
 // InitGeneratorRandSeed sets rand seed
 func InitGeneratorRandSeed(randSeed int64) {
 	// set rand and randomdata seed to --seed flag (custom or 1)
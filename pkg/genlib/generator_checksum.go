@@ -0,0 +1,120 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package genlib
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"hash/crc32"
+)
+
+// ChecksumAlgorithm is the algorithm the checksum of a document is computed with
+type ChecksumAlgorithm string
+
+const (
+	ChecksumCRC32  ChecksumAlgorithm = "crc32"
+	ChecksumSHA256 ChecksumAlgorithm = "sha256"
+)
+
+var checksumUnknownAlgorithm = errors.New("unknown checksum algorithm")
+var checksumDocumentNotObject = errors.New("checksum document is not a JSON object")
+
+// GeneratorWithChecksum wraps a Generator adding to each document a field with the checksum of the rest of the document
+type GeneratorWithChecksum struct {
+	gen       Generator
+	fieldName string
+	algorithm ChecksumAlgorithm
+	tmp       bytes.Buffer
+}
+
+// NewGeneratorWithChecksum returns a Generator emitting the documents of gen with an additional fieldName field,
+// holding the hex encoded checksum computed with algorithm over the document without the field.
+// See DocumentChecksum for how the checksum is computed.
+func NewGeneratorWithChecksum(gen Generator, fieldName string, algorithm ChecksumAlgorithm) (*GeneratorWithChecksum, error) {
+	switch algorithm {
+	case ChecksumCRC32, ChecksumSHA256:
+	default:
+		return nil, fmt.Errorf("%w: %s", checksumUnknownAlgorithm, algorithm)
+	}
+
+	return &GeneratorWithChecksum{
+		gen:       gen,
+		fieldName: fieldName,
+		algorithm: algorithm,
+	}, nil
+}
+
+func (gen *GeneratorWithChecksum) Close() error {
+	return gen.gen.Close()
+}
+
+func (gen *GeneratorWithChecksum) Emit(buf *bytes.Buffer) error {
+	gen.tmp.Reset()
+	if err := gen.gen.Emit(&gen.tmp); err != nil {
+		return err
+	}
+
+	checksum, err := DocumentChecksum(gen.tmp.Bytes(), gen.fieldName, gen.algorithm)
+	if err != nil {
+		return err
+	}
+
+	// inject the checksum field before the closing brace, leaving the rest of the document untouched
+	doc := bytes.TrimRight(gen.tmp.Bytes(), " \t\r\n")
+	closingBrace := len(doc) - 1
+	buf.Write(doc[:closingBrace])
+	if len(bytes.TrimSpace(doc[1:closingBrace])) > 0 {
+		buf.WriteByte(',')
+	}
+
+	key, _ := json.Marshal(gen.fieldName)
+	buf.Write(key)
+	buf.WriteString(`:"`)
+	buf.WriteString(checksum)
+	buf.WriteString(`"}`)
+	return nil
+}
+
+// DocumentChecksum returns the hex encoded checksum of doc computed with algorithm, ignoring the fieldName field.
+// The checksum is computed over the compact JSON encoding of the document with its keys sorted, so that
+// it can be recomputed by the receiver regardless of the formatting of the document.
+func DocumentChecksum(doc []byte, fieldName string, algorithm ChecksumAlgorithm) (string, error) {
+	var m map[string]json.RawMessage
+	if err := json.Unmarshal(doc, &m); err != nil || m == nil {
+		return "", fmt.Errorf("%w: %s", checksumDocumentNotObject, doc)
+	}
+
+	delete(m, fieldName)
+
+	canonical, err := json.Marshal(m)
+	if err != nil {
+		return "", err
+	}
+
+	h, err := newChecksumHash(algorithm)
+	if err != nil {
+		return "", err
+	}
+
+	_, _ = h.Write(canonical)
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// newChecksumHash returns the hash computing checksums with algorithm
+func newChecksumHash(algorithm ChecksumAlgorithm) (hash.Hash, error) {
+	switch algorithm {
+	case ChecksumCRC32:
+		return crc32.NewIEEE(), nil
+	case ChecksumSHA256:
+		return sha256.New(), nil
+	default:
+		return nil, fmt.Errorf("%w: %s", checksumUnknownAlgorithm, algorithm)
+	}
+}
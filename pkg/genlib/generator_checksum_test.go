@@ -0,0 +1,91 @@
+package genlib
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/elastic/elastic-integration-corpus-generator-tool/pkg/genlib/config"
+)
+
+func Test_GeneratorWithChecksum(t *testing.T) {
+	flds := Fields{
+		{Name: "alpha", Type: FieldTypeLong},
+		{Name: "beta", Type: FieldTypeKeyword},
+		{Name: "gamma", Type: FieldTypeDouble},
+	}
+
+	template := []byte(`{ "alpha": {{.alpha}}, "beta": "{{.beta}}", "gamma": {{.gamma}} }`)
+
+	for _, algorithm := range []ChecksumAlgorithm{ChecksumCRC32, ChecksumSHA256} {
+		t.Run(string(algorithm), func(t *testing.T) {
+			g, err := NewGeneratorWithChecksum(makeGeneratorWithCustomTemplate(t, config.Config{}, flds, template, 0), "checksum", algorithm)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			nSpins := 100
+			for i := 0; i < nSpins; i++ {
+				var buf bytes.Buffer
+				if err := g.Emit(&buf); err != nil {
+					t.Fatal(err)
+				}
+
+				m := unmarshalJSONT[any](t, buf.Bytes())
+				if len(m) != len(flds)+1 {
+					t.Fatalf("Expected fields and checksum, got %s", buf.String())
+				}
+
+				// the receiver recomputes the checksum over the received document
+				var received bytes.Buffer
+				if err := json.Indent(&received, buf.Bytes(), "", "  "); err != nil {
+					t.Fatal(err)
+				}
+
+				checksum, err := DocumentChecksum(received.Bytes(), "checksum", algorithm)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if m["checksum"] != checksum {
+					t.Errorf("Expected checksum %s, got %v", checksum, m["checksum"])
+				}
+			}
+		})
+	}
+}
+
+func Test_GeneratorWithChecksumKnownDocument(t *testing.T) {
+	fld := Field{
+		Name: "alpha",
+		Type: FieldTypeLong,
+	}
+
+	cfg, err := config.LoadConfigFromYaml([]byte("fields:\n  - name: alpha\n    value: 1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := []byte(`{ "alpha": {{.alpha}} }`)
+	g, err := NewGeneratorWithChecksum(makeGeneratorWithCustomTemplate(t, cfg, Fields{fld}, template, 0), "checksum", ChecksumCRC32)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := g.Emit(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	// crc32 of `{"alpha":1}`
+	if buf.String() != `{ "alpha": 1 ,"checksum":"56c39984"}` {
+		t.Errorf("Unexpected document %s", buf.String())
+	}
+}
+
+func Test_GeneratorWithChecksumUnknownAlgorithm(t *testing.T) {
+	if _, err := NewGeneratorWithChecksum(nil, "checksum", "md4"); !errors.Is(err, checksumUnknownAlgorithm) {
+		t.Errorf("Expected checksumUnknownAlgorithm error, got %v", err)
+	}
+}
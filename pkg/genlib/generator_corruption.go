@@ -0,0 +1,175 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package genlib
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sort"
+	"time"
+)
+
+// CorruptionKind is the way a document is made malformed
+type CorruptionKind string
+
+const (
+	// CorruptionBadType replaces the value of a random field with a value of a different type
+	CorruptionBadType CorruptionKind = "bad_type"
+	// CorruptionInvalidDate replaces the value of a random date field with an unparsable date
+	CorruptionInvalidDate CorruptionKind = "invalid_date"
+	// CorruptionBrokenJSON truncates the document so that it is not valid JSON anymore
+	CorruptionBrokenJSON CorruptionKind = "broken_json"
+)
+
+const corruptedDate = "2006-13-32T25:61:61Z"
+
+var corruptionInvalidProbability = errors.New("corruption probability must be between 0 and 1")
+var corruptionUnknownKind = errors.New("unknown corruption kind")
+
+// GeneratorWithCorruption wraps a Generator so that a percentage of the emitted documents are malformed
+type GeneratorWithCorruption struct {
+	gen         Generator
+	probability float64
+	kinds       []CorruptionKind
+	tmp         bytes.Buffer
+	rand        *rand.Rand
+}
+
+// NewGeneratorWithCorruption returns a Generator emitting the documents of gen, corrupting each of them with the
+// given probability using one of kinds, chosen randomly. All kinds are used when none is given.
+// When the chosen kind cannot be applied to a document (ie: no date field in it) the document is truncated instead.
+func NewGeneratorWithCorruption(gen Generator, probability float64, kinds ...CorruptionKind) (*GeneratorWithCorruption, error) {
+	if probability < 0 || probability > 1 {
+		return nil, corruptionInvalidProbability
+	}
+
+	if len(kinds) == 0 {
+		kinds = []CorruptionKind{CorruptionBadType, CorruptionInvalidDate, CorruptionBrokenJSON}
+	}
+
+	for _, kind := range kinds {
+		switch kind {
+		case CorruptionBadType, CorruptionInvalidDate, CorruptionBrokenJSON:
+		default:
+			return nil, fmt.Errorf("%w: %s", corruptionUnknownKind, kind)
+		}
+	}
+
+	return &GeneratorWithCorruption{
+		gen:         gen,
+		probability: probability,
+		kinds:       kinds,
+		rand:        newGeneratorRand(),
+	}, nil
+}
+
+func (gen *GeneratorWithCorruption) Close() error {
+	return gen.gen.Close()
+}
+
+func (gen *GeneratorWithCorruption) Emit(buf *bytes.Buffer) error {
+	gen.tmp.Reset()
+	if err := gen.gen.Emit(&gen.tmp); err != nil {
+		return err
+	}
+
+	if gen.rand.Float64() >= gen.probability {
+		buf.Write(gen.tmp.Bytes())
+		return nil
+	}
+
+	kind := gen.kinds[gen.rand.Intn(len(gen.kinds))]
+	buf.Write(corruptDocument(gen.rand, gen.tmp.Bytes(), kind))
+	return nil
+}
+
+func corruptDocument(r *rand.Rand, doc []byte, kind CorruptionKind) []byte {
+	switch kind {
+	case CorruptionBadType, CorruptionInvalidDate:
+		if corrupted, ok := corruptDocumentValue(r, doc, kind); ok {
+			return corrupted
+		}
+	}
+
+	return truncateDocument(r, doc)
+}
+
+// corruptDocumentValue replaces the value of a random root level field of doc, returning false if no field
+// can be corrupted with kind
+func corruptDocumentValue(r *rand.Rand, doc []byte, kind CorruptionKind) ([]byte, bool) {
+	var m map[string]any
+	d := json.NewDecoder(bytes.NewReader(doc))
+	d.UseNumber()
+	if err := d.Decode(&m); err != nil {
+		return nil, false
+	}
+
+	// sort keys to keep the corruption reproducible under a seed
+	keys := make([]string, 0, len(m))
+	for k, v := range m {
+		if kind == CorruptionInvalidDate && !isDateValue(v) {
+			continue
+		}
+
+		keys = append(keys, k)
+	}
+
+	if len(keys) == 0 {
+		return nil, false
+	}
+
+	sort.Strings(keys)
+	k := keys[r.Intn(len(keys))]
+
+	if kind == CorruptionInvalidDate {
+		m[k] = corruptedDate
+	} else {
+		m[k] = badTypeValue(r, m[k])
+	}
+
+	corrupted, err := json.Marshal(m)
+	if err != nil {
+		return nil, false
+	}
+
+	return corrupted, true
+}
+
+func isDateValue(v any) bool {
+	s, ok := v.(string)
+	if !ok {
+		return false
+	}
+
+	_, err := time.Parse(time.RFC3339Nano, s)
+	return err == nil
+}
+
+// badTypeValue returns a value of a different JSON type than v
+func badTypeValue(r *rand.Rand, v any) any {
+	switch v.(type) {
+	case string:
+		return r.Int63()
+	case json.Number:
+		return "not a number"
+	case bool:
+		return "not a boolean"
+	default:
+		return true
+	}
+}
+
+// truncateDocument cuts doc at a random position, leaving out at least its last non blank byte
+func truncateDocument(r *rand.Rand, doc []byte) []byte {
+	doc = bytes.TrimSpace(doc)
+	if len(doc) < 2 {
+		return []byte("{")
+	}
+
+	return doc[:1+r.Intn(len(doc)-1)]
+}
@@ -0,0 +1,129 @@
+package genlib
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/elastic/elastic-integration-corpus-generator-tool/pkg/genlib/config"
+)
+
+func makeGeneratorWithCorruption(t *testing.T, probability float64, kinds ...CorruptionKind) Generator {
+	flds := Fields{
+		{Name: "alpha", Type: FieldTypeLong},
+		{Name: "beta", Type: FieldTypeDate},
+		{Name: "gamma", Type: FieldTypeKeyword},
+	}
+
+	template := []byte(`{"alpha":{{.alpha}},"beta":"{{.beta}}","gamma":"{{.gamma}}"}`)
+	g, err := NewGeneratorWithCorruption(makeGeneratorWithCustomTemplate(t, config.Config{}, flds, template, 0), probability, kinds...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return g
+}
+
+func Test_GeneratorWithCorruptionRate(t *testing.T) {
+	testCases := []struct {
+		kind        CorruptionKind
+		isCorrupted func(doc []byte) bool
+	}{
+		{
+			kind: CorruptionBrokenJSON,
+			isCorrupted: func(doc []byte) bool {
+				return !json.Valid(doc)
+			},
+		},
+		{
+			kind: CorruptionBadType,
+			isCorrupted: func(doc []byte) bool {
+				var m map[string]any
+				if err := json.Unmarshal(doc, &m); err != nil {
+					return true
+				}
+
+				_, alphaOk := m["alpha"].(float64)
+				_, betaOk := m["beta"].(string)
+				_, gammaOk := m["gamma"].(string)
+				return !alphaOk || !betaOk || !gammaOk
+			},
+		},
+		{
+			kind: CorruptionInvalidDate,
+			isCorrupted: func(doc []byte) bool {
+				var m map[string]any
+				if err := json.Unmarshal(doc, &m); err != nil {
+					return true
+				}
+
+				_, err := time.Parse(time.RFC3339Nano, m["beta"].(string))
+				return err != nil
+			},
+		},
+	}
+
+	probability := 0.2
+	for _, testCase := range testCases {
+		t.Run(string(testCase.kind), func(t *testing.T) {
+			g := makeGeneratorWithCorruption(t, probability, testCase.kind)
+
+			nSpins := 10000
+			var totCorrupted int
+			for i := 0; i < nSpins; i++ {
+				var buf bytes.Buffer
+				if err := g.Emit(&buf); err != nil {
+					t.Fatal(err)
+				}
+
+				if testCase.isCorrupted(buf.Bytes()) {
+					totCorrupted++
+					continue
+				}
+
+				if !json.Valid(buf.Bytes()) {
+					t.Errorf("Expected valid JSON document, got %s", buf.String())
+				}
+			}
+
+			rate := float64(totCorrupted) / float64(nSpins)
+			if math.Abs(rate-probability) > 0.03 {
+				t.Errorf("Expected corruption rate around %f, got %f", probability, rate)
+			}
+		})
+	}
+}
+
+func Test_GeneratorWithCorruptionNone(t *testing.T) {
+	g := makeGeneratorWithCorruption(t, 0)
+
+	nSpins := 1000
+	for i := 0; i < nSpins; i++ {
+		var buf bytes.Buffer
+		if err := g.Emit(&buf); err != nil {
+			t.Fatal(err)
+		}
+
+		m := unmarshalJSONT[any](t, buf.Bytes())
+		if _, ok := m["alpha"].(float64); !ok {
+			t.Errorf("Expected number for alpha, got %s", buf.String())
+		}
+
+		if _, err := time.Parse(time.RFC3339Nano, m["beta"].(string)); err != nil {
+			t.Errorf("Expected valid date for beta, got %s", buf.String())
+		}
+	}
+}
+
+func Test_GeneratorWithCorruptionInvalidConfig(t *testing.T) {
+	if _, err := NewGeneratorWithCorruption(nil, 1.5); !errors.Is(err, corruptionInvalidProbability) {
+		t.Errorf("Expected corruptionInvalidProbability error, got %v", err)
+	}
+
+	if _, err := NewGeneratorWithCorruption(nil, 0.5, "unknown"); !errors.Is(err, corruptionUnknownKind) {
+		t.Errorf("Expected corruptionUnknownKind error, got %v", err)
+	}
+}
@@ -0,0 +1,231 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package genlib
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+var csvInvalidDelimiter = errors.New("invalid csv delimiter")
+
+// CSVOptions are the options of the rows emitted by GeneratorCSV
+type CSVOptions struct {
+	// Delimiter separates the values of a row, defaulting to a comma
+	Delimiter rune
+	// Header emits the names of the fields as first row
+	Header bool
+}
+
+// GeneratorCSV is resolved at construction to a slice of emit functions, whose values are emitted as a row of
+// comma-separated values
+type GeneratorCSV struct {
+	totEvents uint64
+	emitFuncs []emitF
+	delimiter rune
+	header    []byte
+	state     *genState
+}
+
+// NewGeneratorCSV returns a Generator emitting a row of the values of fields, in their order, for each event.
+// The values containing the delimiter, quotes or newlines are quoted as per RFC 4180. The fields are bound as for
+// the text templates: a `first_only` field has an empty value after the first row.
+func NewGeneratorCSV(cfg Config, fields Fields, totEvents uint64, opts CSVOptions) (*GeneratorCSV, error) {
+	if opts.Delimiter == 0 {
+		opts.Delimiter = ','
+	}
+
+	if opts.Delimiter == '"' || opts.Delimiter == '\r' || opts.Delimiter == '\n' || !utf8.ValidRune(opts.Delimiter) {
+		return nil, fmt.Errorf("%w: %q", csvInvalidDelimiter, opts.Delimiter)
+	}
+
+	initGeneratorTimeNowIfUnset()
+
+	// Preprocess the fields, generating appropriate emit functions
+	state := newGenState()
+	// the fields are bound with the random generator of the state, the one they are generated with
+	defer useRand(state.rand)()
+
+	fieldMap := make(map[string]any)
+	for _, field := range fields {
+		if err := bindField(cfg, field, fieldMap, true); err != nil {
+			return nil, err
+		}
+
+		state.prevCacheForDup[field.Name] = make(map[any]struct{})
+		state.prevCacheCardinality[field.Name] = make([]any, 0)
+	}
+
+	if err := bindSeries(cfg, fields, fieldMap, true); err != nil {
+		return nil, err
+	}
+
+	state.seriesLength = seriesLength(cfg, fields)
+
+	if err := bindEnumByValue(cfg, fields, fieldMap, true); err != nil {
+		return nil, err
+	}
+
+	if err := bindDerivedLag(cfg, fields, fieldMap, true); err != nil {
+		return nil, err
+	}
+
+	if err := bindDerivedDuration(cfg, fields, fieldMap, true); err != nil {
+		return nil, err
+	}
+
+	if err := bindDerivedRatio(cfg, fields, fieldMap, true); err != nil {
+		return nil, err
+	}
+
+	if err := bindDerivedDirection(cfg, fields, fieldMap, true); err != nil {
+		return nil, err
+	}
+
+	if err := bindValueFromTemplate(cfg, fields, fieldMap, true); err != nil {
+		return nil, err
+	}
+
+	if err := bindRelatedFields(cfg, fields, fieldMap, true); err != nil {
+		return nil, err
+	}
+
+	if err := bindEntityAttributes(cfg, fields, fieldMap, true); err != nil {
+		return nil, err
+	}
+
+	if err := bindFirstOnly(cfg, fields, fieldMap, true); err != nil {
+		return nil, err
+	}
+
+	if err := bindNullProbability(cfg, fields, fieldMap, true); err != nil {
+		return nil, err
+	}
+
+	// Roll into slice of emit functions, a column for each of the fields emitted, grouped types emitting many of them
+	var fieldNames []string
+	for _, field := range fields {
+		if isDynamicObjectField(field) {
+			fieldNames = append(fieldNames, field.Name)
+			continue
+		}
+
+		fieldNames = append(fieldNames, emittedFieldNames(cfg, field)...)
+	}
+
+	emitFuncs := make([]emitF, 0, len(fieldNames))
+	var header bytes.Buffer
+	for i, fieldName := range fieldNames {
+		emitFunc, ok := fieldMap[fieldName].(emitF)
+		if !ok {
+			return nil, fmt.Errorf("cannot bind field %s to csv", fieldName)
+		}
+
+		emitFuncs = append(emitFuncs, emitFunc)
+
+		if i > 0 {
+			header.WriteRune(opts.Delimiter)
+		}
+
+		writeCSVValue(&header, fieldName, opts.Delimiter)
+	}
+
+	gen := &GeneratorCSV{emitFuncs: emitFuncs, delimiter: opts.Delimiter, totEvents: totEvents, state: state}
+	if opts.Header {
+		header.WriteByte('\n')
+		gen.header = header.Bytes()
+	}
+
+	state.totEvents = totEvents
+
+	return gen, nil
+}
+
+// csvValue returns the generated value as string: dates in FieldTypeTimeLayout, arrays and objects as JSON
+func csvValue(value any) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "", nil
+	case error:
+		return "", v
+	case string:
+		return v, nil
+	case time.Time:
+		return v.Format(FieldTypeTimeLayout), nil
+	case int64:
+		return strconv.FormatInt(v, 10), nil
+	case uint64:
+		return strconv.FormatUint(v, 10), nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case []any, map[string]any:
+		encoded, err := json.Marshal(v)
+		return string(encoded), err
+	default:
+		return fmt.Sprint(v), nil
+	}
+}
+
+// writeCSVValue writes value to buf, quoted if it contains the delimiter, quotes or newlines, with its quotes doubled
+func writeCSVValue(buf *bytes.Buffer, value string, delimiter rune) {
+	if !strings.ContainsRune(value, delimiter) && !strings.ContainsAny(value, "\"\r\n") {
+		buf.WriteString(value)
+		return
+	}
+
+	buf.WriteByte('"')
+	buf.WriteString(strings.ReplaceAll(value, `"`, `""`))
+	buf.WriteByte('"')
+}
+
+func (gen *GeneratorCSV) Close() error {
+	return nil
+}
+
+func (gen *GeneratorCSV) Emit(buf *bytes.Buffer) error {
+	gen.state.useRandomdata()
+	if err := gen.emit(buf); err != nil {
+		return err
+	}
+
+	gen.state.counter += 1
+
+	return nil
+}
+
+func (gen *GeneratorCSV) emit(buf *bytes.Buffer) error {
+	if gen.totEvents > 0 && gen.state.counter >= gen.totEvents {
+		return io.EOF
+	}
+
+	// the header precedes the first row
+	if gen.state.counter == 0 {
+		buf.Write(gen.header)
+	}
+
+	for i, emitFunc := range gen.emitFuncs {
+		if i > 0 {
+			buf.WriteRune(gen.delimiter)
+		}
+
+		value, err := csvValue(emitFunc(gen.state))
+		if err != nil {
+			return err
+		}
+
+		writeCSVValue(buf, value, gen.delimiter)
+	}
+
+	return nil
+}
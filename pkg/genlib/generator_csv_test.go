@@ -0,0 +1,153 @@
+package genlib
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"io"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/elastic/elastic-integration-corpus-generator-tool/pkg/genlib/config"
+)
+
+func Test_GeneratorCSV(t *testing.T) {
+	flds := Fields{
+		{Name: "message", Type: FieldTypeKeyword},
+		{Name: "event.duration", Type: FieldTypeLong},
+		{Name: "source.ip", Type: FieldTypeIP},
+		{Name: "@timestamp", Type: FieldTypeDate},
+	}
+
+	cfg, err := config.LoadConfigFromYaml([]byte("fields:\n  - name: message\n    value: \"a \\\"quoted\\\", multi\\nline\"\n  - name: event.duration\n    range:\n      min: 1\n      max: 100\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	nEvents := 10
+	g, err := NewGeneratorCSV(cfg, flds, uint64(nEvents), CSVOptions{Header: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	for i := 0; i < nEvents; i++ {
+		if err := g.Emit(&buf); err != nil {
+			t.Fatal(err)
+		}
+
+		buf.WriteByte('\n')
+	}
+
+	if err := g.Emit(&buf); !errors.Is(err, io.EOF) {
+		t.Errorf("Expected io.EOF after %d events, got %v", nEvents, err)
+	}
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("Expected valid csv, got %v: %s", err, buf.String())
+	}
+
+	if len(records) != nEvents+1 {
+		t.Fatalf("Expected a header and %d rows, got %d", nEvents, len(records))
+	}
+
+	if records[0][0] != "message" || records[0][1] != "event.duration" || records[0][2] != "source.ip" {
+		t.Errorf("Expected the field names as header, got %v", records[0])
+	}
+
+	for _, record := range records[1:] {
+		if record[0] != "a \"quoted\", multi\nline" {
+			t.Errorf("Expected the quoted value unescaped, got %q", record[0])
+		}
+
+		if duration, err := strconv.Atoi(record[1]); err != nil || duration < 1 || duration > 100 {
+			t.Errorf("Expected a duration between 1 and 100, got %s", record[1])
+		}
+
+		if net.ParseIP(record[2]) == nil {
+			t.Errorf("Expected a source ip, got %s", record[2])
+		}
+
+		if _, err := time.Parse(FieldTypeTimeLayout, record[3]); err != nil {
+			t.Errorf("Expected a timestamp, got %s", record[3])
+		}
+	}
+}
+
+func Test_GeneratorCSVDelimiter(t *testing.T) {
+	flds := Fields{
+		{Name: "alpha", Type: FieldTypeKeyword},
+		{Name: "beta", Type: FieldTypeKeyword},
+	}
+
+	cfg, err := config.LoadConfigFromYaml([]byte("fields:\n  - name: alpha\n    value: \"a;b\"\n  - name: beta\n    value: \"c,d\"\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	g, err := NewGeneratorCSV(cfg, flds, 0, CSVOptions{Delimiter: ';'})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := g.Emit(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	// without header the first row is the values, and only the values containing the delimiter are quoted
+	if buf.String() != `"a;b";c,d` {
+		t.Errorf("Expected values separated by semicolon, got %s", buf.String())
+	}
+}
+
+func Test_GeneratorCSVInvalidDelimiter(t *testing.T) {
+	for _, delimiter := range []rune{'"', '\n', '\r'} {
+		if _, err := NewGeneratorCSV(Config{}, Fields{{Name: "alpha", Type: FieldTypeKeyword}}, 0, CSVOptions{Delimiter: delimiter}); !errors.Is(err, csvInvalidDelimiter) {
+			t.Errorf("Expected invalid delimiter error for %q, got %v", delimiter, err)
+		}
+	}
+}
+
+func Test_GeneratorCSVGroupedType(t *testing.T) {
+	flds := Fields{
+		{Name: "transaction", Type: FieldTypeMoney},
+		{Name: "message", Type: FieldTypeKeyword},
+	}
+
+	cfg, err := config.LoadConfigFromYaml([]byte("fields:\n  - name: transaction\n    enum: [\"EUR\"]\n    range:\n      min: 1\n      max: 100\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	g, err := NewGeneratorCSV(cfg, flds, 1, CSVOptions{Header: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := g.Emit(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("Expected valid csv, got %v: %s", err, buf.String())
+	}
+
+	// the fields of the group are a column each
+	if len(records) != 2 || len(records[0]) != 3 || records[0][0] != "transaction.currency" || records[0][1] != "transaction.amount" || records[0][2] != "message" {
+		t.Fatalf("Expected a column for currency, amount and message, got %v", records)
+	}
+
+	if records[1][0] != "EUR" {
+		t.Errorf("Expected EUR currency, got %s", records[1][0])
+	}
+
+	if amount, err := strconv.ParseFloat(records[1][1], 64); err != nil || amount < 1 || amount > 100 {
+		t.Errorf("Expected an amount between 1 and 100, got %s", records[1][1])
+	}
+}
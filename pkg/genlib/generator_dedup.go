@@ -0,0 +1,59 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package genlib
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"hash/fnv"
+)
+
+var documentNotUnique = errors.New("cannot generate a unique document")
+
+// GeneratorWithDedup wraps a Generator so that no two emitted documents are byte-identical
+type GeneratorWithDedup struct {
+	gen        Generator
+	maxRetries int
+	hashes     map[uint64]struct{}
+	tmp        bytes.Buffer
+}
+
+// NewGeneratorWithDedup returns a Generator emitting the documents of gen, regenerating a document up to maxRetries
+// times when it was already emitted. Each regeneration consumes an event from gen.
+func NewGeneratorWithDedup(gen Generator, maxRetries int) *GeneratorWithDedup {
+	return &GeneratorWithDedup{
+		gen:        gen,
+		maxRetries: maxRetries,
+		hashes:     make(map[uint64]struct{}),
+	}
+}
+
+func (gen *GeneratorWithDedup) Close() error {
+	return gen.gen.Close()
+}
+
+func (gen *GeneratorWithDedup) Emit(buf *bytes.Buffer) error {
+	for i := 0; i <= gen.maxRetries; i++ {
+		gen.tmp.Reset()
+		if err := gen.gen.Emit(&gen.tmp); err != nil {
+			return err
+		}
+
+		h := fnv.New64a()
+		_, _ = h.Write(gen.tmp.Bytes())
+		sum := h.Sum64()
+
+		if _, ok := gen.hashes[sum]; ok {
+			continue
+		}
+
+		gen.hashes[sum] = struct{}{}
+		buf.Write(gen.tmp.Bytes())
+		return nil
+	}
+
+	return fmt.Errorf("%w after %d retries", documentNotUnique, gen.maxRetries)
+}
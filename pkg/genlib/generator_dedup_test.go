@@ -0,0 +1,71 @@
+package genlib
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/elastic/elastic-integration-corpus-generator-tool/pkg/genlib/config"
+)
+
+func Test_GeneratorWithDedup(t *testing.T) {
+	fld := Field{
+		Name: "alpha",
+		Type: FieldTypeLong,
+	}
+
+	cfg, err := config.LoadConfigFromYaml([]byte("fields:\n  - name: alpha\n    range:\n      min: 0\n      max: 1000"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := []byte(`{"alpha":{{.alpha}}}`)
+	g := NewGeneratorWithDedup(makeGeneratorWithCustomTemplate(t, cfg, Fields{fld}, template, 0), 1000)
+	defer func() {
+		_ = g.Close()
+	}()
+
+	nSpins := 500
+	docs := make(map[string]struct{})
+	for i := 0; i < nSpins; i++ {
+		var buf bytes.Buffer
+		if err := g.Emit(&buf); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, ok := docs[buf.String()]; ok {
+			t.Errorf("Document %s emitted more than once", buf.String())
+		}
+
+		docs[buf.String()] = struct{}{}
+	}
+}
+
+func Test_GeneratorWithDedupRetriesExhausted(t *testing.T) {
+	fld := Field{
+		Name: "alpha",
+		Type: FieldTypeKeyword,
+	}
+
+	cfg, err := config.LoadConfigFromYaml([]byte("fields:\n  - name: alpha\n    value: beta"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := []byte(`{"alpha":{{.alpha}}}`)
+	g := NewGeneratorWithDedup(makeGeneratorWithCustomTemplate(t, cfg, Fields{fld}, template, 0), 5)
+
+	var buf bytes.Buffer
+	if err := g.Emit(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	buf.Reset()
+	if err := g.Emit(&buf); !errors.Is(err, documentNotUnique) {
+		t.Errorf("Expected documentNotUnique error, got %v", err)
+	}
+
+	if buf.Len() != 0 {
+		t.Errorf("Expected no document emitted, got %s", buf.String())
+	}
+}
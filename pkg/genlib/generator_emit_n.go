@@ -0,0 +1,134 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package genlib
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+)
+
+// emitNFlushSize is the size EmitN buffers the documents up to before writing them
+const emitNFlushSize = 64 * 1024
+
+// EmitN emits up to n documents of gen to w as NDJSON, each document followed by a newline, until gen is exhausted.
+// n of 0 emits documents until gen is exhausted. The documents are buffered in a single reused buffer, written to w
+// whenever it exceeds 64KiB, so that the memory used doesn't depend on n.
+// It returns the number of documents emitted, only the ones written to w when writing to w fails.
+func EmitN(gen Generator, w io.Writer, n uint64) (uint64, error) {
+	return EmitNWithProgress(gen, w, n, 0, nil)
+}
+
+// EmitNWithProgress emits the documents of gen to w like EmitN, calling progress with the number of documents
+// emitted so far and n every `every` documents, and once more with the final count when the generation ends
+// without error, unless it was just called with it. progress is not called if it is nil or every is 0.
+func EmitNWithProgress(gen Generator, w io.Writer, n uint64, every uint64, progress func(done, total uint64)) (uint64, error) {
+	buf := bytes.NewBuffer(make([]byte, 0, 2*emitNFlushSize))
+
+	if progress == nil {
+		every = 0
+	}
+
+	// written counts the documents emitted to buf, flushed the ones actually written to w: the documents left in buf
+	// when writing to w fails are not returned as emitted
+	var written, flushed uint64
+	flush := func() error {
+		nBytes, err := w.Write(buf.Bytes())
+		if err != nil {
+			flushed += uint64(bytes.Count(buf.Bytes()[:nBytes], []byte{'\n'}))
+			return err
+		}
+
+		flushed = written
+		buf.Reset()
+		return nil
+	}
+
+	// nextProgress is compared instead of taking the modulo of written, to keep the loop cheap
+	nextProgress := every
+	for n == 0 || written < n {
+		docStart := buf.Len()
+		err := gen.Emit(buf)
+		if err == io.EOF {
+			buf.Truncate(docStart)
+			break
+		}
+
+		if err != nil {
+			buf.Truncate(docStart)
+			if flushErr := flush(); flushErr != nil {
+				return flushed, flushErr
+			}
+
+			return written, err
+		}
+
+		buf.WriteByte('\n')
+		written += 1
+
+		if buf.Len() >= emitNFlushSize {
+			if err := flush(); err != nil {
+				return flushed, err
+			}
+		}
+
+		if written == nextProgress {
+			progress(written, n)
+			nextProgress += every
+		}
+	}
+
+	if err := flush(); err != nil {
+		return flushed, err
+	}
+
+	if every > 0 && written != nextProgress-every {
+		progress(written, n)
+	}
+
+	return written, nil
+}
+
+// BatchFooter is the summary of a batch of documents written by EmitNWithFooter after them
+type BatchFooter struct {
+	// Count is the number of documents of the batch
+	Count uint64 `json:"count"`
+	// Checksum is the hex encoded checksum of the documents of the batch as written, newlines included
+	Checksum          string            `json:"checksum"`
+	ChecksumAlgorithm ChecksumAlgorithm `json:"checksum_algorithm"`
+}
+
+// EmitNWithFooter emits the documents of gen to w like EmitN, followed by a footer line with the BatchFooter of the
+// documents under the `batch_footer` key (ie: `{"batch_footer":{"count":2,"checksum":"...","checksum_algorithm":"crc32"}}`),
+// the checksum computed with algorithm. The footer is written only if all the documents are emitted without error.
+func EmitNWithFooter(gen Generator, w io.Writer, n uint64, algorithm ChecksumAlgorithm) (uint64, error) {
+	h, err := newChecksumHash(algorithm)
+	if err != nil {
+		return 0, err
+	}
+
+	written, err := EmitN(gen, io.MultiWriter(w, h), n)
+	if err != nil {
+		return written, err
+	}
+
+	footer, err := json.Marshal(map[string]BatchFooter{
+		"batch_footer": {
+			Count:             written,
+			Checksum:          hex.EncodeToString(h.Sum(nil)),
+			ChecksumAlgorithm: algorithm,
+		},
+	})
+	if err != nil {
+		return written, err
+	}
+
+	if _, err := w.Write(append(footer, '\n')); err != nil {
+		return written, err
+	}
+
+	return written, nil
+}
@@ -0,0 +1,273 @@
+package genlib
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+	"testing"
+
+	"github.com/elastic/elastic-integration-corpus-generator-tool/pkg/genlib/config"
+)
+
+// countingWriter records the size of each write
+type countingWriter struct {
+	bytes.Buffer
+	writes []int
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	w.writes = append(w.writes, len(p))
+	return w.Buffer.Write(p)
+}
+
+// limitedWriter writes up to limit bytes, failing the write exceeding it
+type limitedWriter struct {
+	bytes.Buffer
+	limit int
+}
+
+var errWriterLimit = errors.New("writer limit exceeded")
+
+func (w *limitedWriter) Write(p []byte) (int, error) {
+	if w.Len()+len(p) <= w.limit {
+		return w.Buffer.Write(p)
+	}
+
+	n, _ := w.Buffer.Write(p[:w.limit-w.Len()])
+	return n, errWriterLimit
+}
+
+func Test_EmitN(t *testing.T) {
+	flds := Fields{
+		{Name: "alpha", Type: FieldTypeLong},
+		{Name: "beta", Type: FieldTypeKeyword},
+	}
+
+	template := []byte(`{ "alpha": {{.alpha}}, "beta": "{{.beta}}" }`)
+
+	nDocs := uint64(10000)
+	g := makeGeneratorWithCustomTemplate(t, config.Config{}, flds, template, 0)
+
+	var w countingWriter
+	written, err := EmitN(g, &w, nDocs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if written != nDocs {
+		t.Errorf("Expected %d documents written, got %d", nDocs, written)
+	}
+
+	lines := bytes.Split(bytes.TrimSuffix(w.Bytes(), []byte("\n")), []byte("\n"))
+	if uint64(len(lines)) != nDocs {
+		t.Fatalf("Expected %d lines, got %d", nDocs, len(lines))
+	}
+
+	for _, line := range lines {
+		m := unmarshalJSONT[any](t, line)
+		if len(m) != len(flds) {
+			t.Fatalf("Expected document with %d fields, got %s", len(flds), line)
+		}
+	}
+
+	// the documents are flushed periodically rather than in a single write
+	if len(w.writes) < 2 {
+		t.Errorf("Expected several writes, got %d", len(w.writes))
+	}
+
+	for _, size := range w.writes {
+		if size > 2*emitNFlushSize {
+			t.Errorf("Expected writes not larger than %d bytes, got %d", 2*emitNFlushSize, size)
+		}
+	}
+}
+
+func Test_EmitNWriteFailure(t *testing.T) {
+	flds := Fields{{Name: "alpha", Type: FieldTypeLong}}
+	template := []byte(`{ "alpha": {{.alpha}} }`)
+
+	// the write fails after the first flush, and in the middle of it
+	for _, limit := range []int{emitNFlushSize + 1000, emitNFlushSize / 2} {
+		t.Run(fmt.Sprintf("limit=%d", limit), func(t *testing.T) {
+			g := makeGeneratorWithCustomTemplate(t, config.Config{}, flds, template, 0)
+
+			w := limitedWriter{limit: limit}
+			written, err := EmitN(g, &w, 100000)
+			if !errors.Is(err, errWriterLimit) {
+				t.Fatalf("Expected errWriterLimit error, got %v", err)
+			}
+
+			if flushed := uint64(bytes.Count(w.Bytes(), []byte("\n"))); written != flushed {
+				t.Errorf("Expected %d documents written, got %d", flushed, written)
+			}
+		})
+	}
+}
+
+func Test_EmitNExhausted(t *testing.T) {
+	flds := Fields{{Name: "alpha", Type: FieldTypeLong}}
+	template := []byte(`{ "alpha": {{.alpha}} }`)
+
+	for _, n := range []uint64{0, 100} {
+		t.Run(fmt.Sprintf("n=%d", n), func(t *testing.T) {
+			g := makeGeneratorWithCustomTemplate(t, config.Config{}, flds, template, 10)
+
+			var buf bytes.Buffer
+			written, err := EmitN(g, &buf, n)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if written != 10 || bytes.Count(buf.Bytes(), []byte("\n")) != 10 {
+				t.Errorf("Expected the 10 documents of the generator, got %d: %s", written, buf.String())
+			}
+		})
+	}
+}
+
+func Test_EmitNWithProgress(t *testing.T) {
+	flds := Fields{{Name: "alpha", Type: FieldTypeLong}}
+	template := []byte(`{ "alpha": {{.alpha}} }`)
+
+	testCases := []struct {
+		n        uint64
+		every    uint64
+		expected []uint64
+	}{
+		{n: 1000, every: 250, expected: []uint64{250, 500, 750, 1000}},
+		{n: 1000, every: 300, expected: []uint64{300, 600, 900, 1000}},
+		{n: 1000, every: 0},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(fmt.Sprintf("n=%d,every=%d", testCase.n, testCase.every), func(t *testing.T) {
+			g := makeGeneratorWithCustomTemplate(t, config.Config{}, flds, template, 0)
+
+			var calls []uint64
+			written, err := EmitNWithProgress(g, io.Discard, testCase.n, testCase.every, func(done, total uint64) {
+				if total != testCase.n {
+					t.Errorf("Expected total %d, got %d", testCase.n, total)
+				}
+
+				calls = append(calls, done)
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if written != testCase.n {
+				t.Errorf("Expected %d documents written, got %d", testCase.n, written)
+			}
+
+			if fmt.Sprint(calls) != fmt.Sprint(testCase.expected) {
+				t.Errorf("Expected progress %v, got %v", testCase.expected, calls)
+			}
+		})
+	}
+}
+
+func Test_EmitNWithProgressExhausted(t *testing.T) {
+	flds := Fields{{Name: "alpha", Type: FieldTypeLong}}
+	template := []byte(`{ "alpha": {{.alpha}} }`)
+	g := makeGeneratorWithCustomTemplate(t, config.Config{}, flds, template, 25)
+
+	var calls []uint64
+	if _, err := EmitNWithProgress(g, io.Discard, 0, 10, func(done, total uint64) {
+		calls = append(calls, done)
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// the final call reports the documents emitted once the generator is exhausted
+	if fmt.Sprint(calls) != fmt.Sprint([]uint64{10, 20, 25}) {
+		t.Errorf("Expected progress [10 20 25], got %v", calls)
+	}
+}
+
+func Test_EmitNWithFooter(t *testing.T) {
+	flds := Fields{{Name: "alpha", Type: FieldTypeLong}}
+	template := []byte(`{ "alpha": {{.alpha}} }`)
+
+	for _, algorithm := range []ChecksumAlgorithm{ChecksumCRC32, ChecksumSHA256} {
+		for _, totEvents := range []uint64{0, 7} {
+			t.Run(fmt.Sprintf("%s,totEvents=%d", algorithm, totEvents), func(t *testing.T) {
+				g := makeGeneratorWithCustomTemplate(t, config.Config{}, flds, template, totEvents)
+
+				var buf bytes.Buffer
+				written, err := EmitNWithFooter(g, &buf, 100, algorithm)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				lines := bytes.Split(bytes.TrimSuffix(buf.Bytes(), []byte("\n")), []byte("\n"))
+				documents := lines[:len(lines)-1]
+
+				var footer map[string]BatchFooter
+				if err := json.Unmarshal(lines[len(lines)-1], &footer); err != nil {
+					t.Fatal(err)
+				}
+
+				batchFooter := footer["batch_footer"]
+				if batchFooter.Count != written || batchFooter.Count != uint64(len(documents)) {
+					t.Errorf("Expected footer count %d, got %d", len(documents), batchFooter.Count)
+				}
+
+				h, _ := newChecksumHash(algorithm)
+				_, _ = h.Write(buf.Bytes()[:buf.Len()-len(lines[len(lines)-1])-1])
+				if checksum := hex.EncodeToString(h.Sum(nil)); batchFooter.Checksum != checksum || batchFooter.ChecksumAlgorithm != algorithm {
+					t.Errorf("Expected footer checksum %s, got %+v", checksum, batchFooter)
+				}
+			})
+		}
+	}
+}
+
+func Test_EmitNWithFooterUnknownAlgorithm(t *testing.T) {
+	var buf bytes.Buffer
+	if _, err := EmitNWithFooter(nil, &buf, 1, "md5"); !errors.Is(err, checksumUnknownAlgorithm) {
+		t.Errorf("Expected checksumUnknownAlgorithm error, got %v", err)
+	}
+}
+
+func Benchmark_EmitN(b *testing.B) {
+	flds := Fields{
+		{Name: "alpha", Type: FieldTypeLong},
+		{Name: "beta", Type: FieldTypeKeyword},
+		{Name: "gamma", Type: FieldTypeIP},
+	}
+
+	template := []byte(`{ "alpha": {{.alpha}}, "beta": "{{.beta}}", "gamma": "{{.gamma}}" }`)
+
+	// the memory allocated per document doesn't grow with the number of documents: the buffer is reused
+	for _, n := range []uint64{1000, 10000, 100000} {
+		b.Run(fmt.Sprintf("n=%d", n), func(b *testing.B) {
+			g, err := NewGeneratorWithCustomTemplate(template, config.Config{}, flds, 0)
+			if err != nil {
+				b.Fatal(err)
+			}
+
+			defer func() {
+				_ = g.Close()
+			}()
+
+			var before, after runtime.MemStats
+			runtime.GC()
+			runtime.ReadMemStats(&before)
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := EmitN(g, io.Discard, n); err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.StopTimer()
+
+			runtime.ReadMemStats(&after)
+			b.ReportMetric(float64(after.TotalAlloc-before.TotalAlloc)/float64(uint64(b.N)*n), "B/doc")
+		})
+	}
+}
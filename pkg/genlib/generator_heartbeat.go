@@ -0,0 +1,55 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package genlib
+
+import (
+	"bytes"
+	"errors"
+	"math/rand"
+)
+
+var heartbeatInvalidWeight = errors.New("heartbeat weight must be between 0 and 1")
+
+// GeneratorWithHeartbeats emits a mixture of minimal heartbeat documents and full events, like monitoring data
+type GeneratorWithHeartbeats struct {
+	heartbeat Generator
+	full      Generator
+	weight    float64
+	rand      *rand.Rand
+}
+
+// NewGeneratorWithHeartbeats returns a Generator emitting, for each document, a document of heartbeat with the given
+// weight, or a document of full otherwise. heartbeat is expected to be a template with a minimal subset of the fields
+// of the full template. The Generator stops as soon as the chosen Generator does.
+func NewGeneratorWithHeartbeats(heartbeat, full Generator, weight float64) (*GeneratorWithHeartbeats, error) {
+	if weight < 0 || weight > 1 {
+		return nil, heartbeatInvalidWeight
+	}
+
+	return &GeneratorWithHeartbeats{
+		heartbeat: heartbeat,
+		full:      full,
+		weight:    weight,
+		rand:      newGeneratorRand(),
+	}, nil
+}
+
+func (gen *GeneratorWithHeartbeats) Close() error {
+	heartbeatErr := gen.heartbeat.Close()
+	fullErr := gen.full.Close()
+	if heartbeatErr != nil {
+		return heartbeatErr
+	}
+
+	return fullErr
+}
+
+func (gen *GeneratorWithHeartbeats) Emit(buf *bytes.Buffer) error {
+	if gen.rand.Float64() < gen.weight {
+		return gen.heartbeat.Emit(buf)
+	}
+
+	return gen.full.Emit(buf)
+}
@@ -0,0 +1,65 @@
+package genlib
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/elastic/elastic-integration-corpus-generator-tool/pkg/genlib/config"
+)
+
+func Test_GeneratorWithHeartbeats(t *testing.T) {
+	flds := Fields{
+		{Name: "host.name", Type: FieldTypeKeyword},
+		{Name: "message", Type: FieldTypeKeyword},
+		{Name: "event.duration", Type: FieldTypeLong},
+	}
+
+	heartbeatTemplate := []byte(`{"host.name":"{{.host.name}}"}`)
+	fullTemplate := []byte(`{"host.name":"{{.host.name}}","message":"{{.message}}","event.duration":{{.event.duration}}}`)
+
+	for _, weight := range []float64{0, 0.2, 0.9, 1} {
+		g, err := NewGeneratorWithHeartbeats(
+			makeGeneratorWithCustomTemplate(t, config.Config{}, flds, heartbeatTemplate, 0),
+			makeGeneratorWithCustomTemplate(t, config.Config{}, flds, fullTemplate, 0),
+			weight,
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		nSpins := 10000
+		var heartbeats int
+		for i := 0; i < nSpins; i++ {
+			var buf bytes.Buffer
+			if err := g.Emit(&buf); err != nil {
+				t.Fatal(err)
+			}
+
+			m := unmarshalJSONT[any](t, buf.Bytes())
+			switch len(m) {
+			case 1:
+				heartbeats++
+			case len(flds):
+			default:
+				t.Errorf("Expected either a heartbeat or a full document, got %s", buf.String())
+			}
+		}
+
+		rate := float64(heartbeats) / float64(nSpins)
+		if math.Abs(rate-weight) > 0.03 {
+			t.Errorf("Expected heartbeats rate %f, got %f", weight, rate)
+		}
+
+		_ = g.Close()
+	}
+}
+
+func Test_GeneratorWithHeartbeatsInvalidWeight(t *testing.T) {
+	for _, weight := range []float64{-0.1, 1.1} {
+		if _, err := NewGeneratorWithHeartbeats(nil, nil, weight); !errors.Is(err, heartbeatInvalidWeight) {
+			t.Errorf("Expected heartbeatInvalidWeight error for weight %f, got %v", weight, err)
+		}
+	}
+}
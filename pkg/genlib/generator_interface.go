@@ -13,6 +13,7 @@ import (
 	"github.com/elastic/elastic-integration-corpus-generator-tool/pkg/genlib/config"
 	"github.com/elastic/elastic-integration-corpus-generator-tool/pkg/genlib/fields"
 	"math"
+	"math/rand"
 	"regexp"
 	"strconv"
 	"strings"
@@ -23,6 +24,17 @@ import (
 
 var timeNowToBind time.Time
 
+// defaultMonotonicMaxDelta is the upper bound of the delta between the dates of consecutive events of a `monotonic`
+// date field when `max_delta` is not set
+const defaultMonotonicMaxDelta = 500 * time.Millisecond
+
+var rawJSONNotValid = errors.New("raw_json is not valid JSON")
+var uniqueValuesExhausted = errors.New("cannot generate a unique value")
+var illegalConfigCombination = errors.New("illegal combination of config options")
+
+// uniqueMaxTries is the number of attempts to generate a value not generated before for a `unique` field
+const uniqueMaxTries = 1000
+
 type (
 	Fields      = fields.Fields
 	Field       = fields.Field
@@ -31,31 +43,125 @@ type (
 )
 
 const (
-	FieldTypeBool            = "boolean"
-	FieldTypeKeyword         = "keyword"
-	FieldTypeConstantKeyword = "constant_keyword"
-	FieldTypeDate            = "date"
-	FieldTypeIP              = "ip"
-	FieldTypeDouble          = "double"
-	FieldTypeFloat           = "float"
-	FieldTypeHalfFloat       = "half_float"
-	FieldTypeScaledFloat     = "scaled_float"
-	FieldTypeInteger         = "integer"
-	FieldTypeLong            = "long"
-	FieldTypeUnsignedLong    = "unsigned_long"
-	FieldTypeObject          = "object"
-	FieldTypeNested          = "nested"
-	FieldTypeFlattened       = "flattened"
-	FieldTypeGeoPoint        = "geo_point"
+	FieldTypeBool                = "boolean"
+	FieldTypeKeyword             = "keyword"
+	FieldTypeText                = "text"
+	FieldTypeConstantKeyword     = "constant_keyword"
+	FieldTypeDate                = "date"
+	FieldTypeIP                  = "ip"
+	FieldTypeDouble              = "double"
+	FieldTypeFloat               = "float"
+	FieldTypeHalfFloat           = "half_float"
+	FieldTypeScaledFloat         = "scaled_float"
+	FieldTypeByte                = "byte"
+	FieldTypeShort               = "short"
+	FieldTypeInteger             = "integer"
+	FieldTypeLong                = "long"
+	FieldTypeUnsignedLong        = "unsigned_long"
+	FieldTypeObject              = "object"
+	FieldTypeNested              = "nested"
+	FieldTypeFlattened           = "flattened"
+	FieldTypeGeoPoint            = "geo_point"
+	FieldTypeGeoShape            = "geo_shape"
+	FieldTypePoint               = "point"
+	FieldTypeHostname            = "hostname"
+	FieldTypeMoney               = "money"
+	FieldTypeCIDR                = "cidr"
+	FieldTypeASN                 = "asn"
+	FieldTypePersonName          = "person_name"
+	FieldTypePath                = "path"
+	FieldTypeOS                  = "os"
+	FieldTypeProcess             = "process"
+	FieldTypeSQLStatement        = "sql_statement"
+	FieldTypeCloud               = "cloud"
+	FieldTypeValidity            = "validity"
+	FieldTypeKubernetes          = "kubernetes"
+	FieldTypeDNS                 = "dns"
+	FieldTypeSession             = "session"
+	FieldTypeULID                = "ulid"
+	FieldTypeUUID                = "uuid"
+	FieldTypeHexToken            = "hex_token"
+	FieldTypeTLS                 = "tls"
+	FieldTypeRegistryPath        = "registry_path"
+	FieldTypeEventCategorization = "event_categorization"
+	FieldTypeUser                = "user"
+	FieldTypeWebTransaction      = "web_transaction"
 
 	FieldTypeDurationSpan = 1000 // milliseconds
 	FieldTypeTimeLayout   = "2006-01-02T15:04:05.999999Z07:00"
+
+	keywordMultiFieldSuffix = ".keyword"
+	moneyAmountSuffix       = ".amount"
+	moneyCurrencySuffix     = ".currency"
+
+	asnNumberSuffix           = ".number"
+	asnOrganizationNameSuffix = ".organization.name"
+
+	osNameSuffix    = ".name"
+	osVersionSuffix = ".version"
+	osFamilySuffix  = ".family"
+
+	processPIDSuffix        = ".pid"
+	processNameSuffix       = ".name"
+	processParentPIDSuffix  = ".parent.pid"
+	processParentNameSuffix = ".parent.name"
+
+	cloudProviderSuffix         = ".provider"
+	cloudAccountIDSuffix        = ".account.id"
+	cloudRegionSuffix           = ".region"
+	cloudAvailabilityZoneSuffix = ".availability_zone"
+
+	validityNotBeforeSuffix = ".not_before"
+	validityNotAfterSuffix  = ".not_after"
+
+	kubernetesNamespaceSuffix      = ".namespace"
+	kubernetesDeploymentNameSuffix = ".deployment.name"
+	kubernetesPodNameSuffix        = ".pod.name"
+	containerIDField               = "container.id"
+	containerImageNameField        = "container.image.name"
+
+	dnsQuestionNameSuffix = ".question.name"
+	dnsQuestionTypeSuffix = ".question.type"
+	dnsAnswersNameSuffix  = ".answers.name"
+	dnsAnswersTypeSuffix  = ".answers.type"
+	dnsAnswersDataSuffix  = ".answers.data"
+
+	sessionIDSuffix       = ".id"
+	sessionStepSuffix     = ".step"
+	sessionUserNameSuffix = ".user.name"
+
+	tlsVersionSuffix          = ".version"
+	tlsVersionProtocolSuffix  = ".version_protocol"
+	tlsCipherSuffix           = ".cipher"
+	tlsServerX509Suffix       = ".server.x509"
+	tlsServerCommonNameSuffix = ".server.x509.subject.common_name"
+
+	eventCategorySuffix = ".category"
+	eventTypeSuffix     = ".type"
+	eventActionSuffix   = ".action"
+
+	userIDSuffix        = ".id"
+	userNameSuffix      = ".name"
+	userGroupNameSuffix = ".group.name"
+
+	webRequestMethodSuffix      = ".request.method"
+	webRequestBytesSuffix       = ".request.bytes"
+	webResponseStatusSuffix     = ".response.status_code"
+	webResponseBytesSuffix      = ".response.bytes"
+	webTransactionDurationField = "event.duration"
+)
+
+// RFC 1123 limits: a label can be at most 63 characters, a hostname at most 253
+const (
+	hostnameMaxLabelLength = 63
+	hostnameMaxLength      = 253
 )
 
 var (
 	replacer             = strings.NewReplacer(".*", "")
 	fieldNormalizerRegex = regexp.MustCompile("[^a-zA-Z0-9]")
 	keywordRegex         = regexp.MustCompile("(\\.|-|_|\\s){1,1}")
+	hostnameLabelRegex   = regexp.MustCompile("[^a-z0-9-]")
 )
 
 // This is the emit function for the custom template engine where we stream content directly to the output buffer and no need a return value
@@ -64,6 +170,70 @@ type emitFNotReturn func(state *genState, buf *bytes.Buffer) error
 // emitF Typedef of the internal emit function
 type emitF func(state *genState) any
 
+// isDoubleField returns true if the field is bound to a floating point value
+func isDoubleField(field Field) bool {
+	switch field.Type {
+	case FieldTypeDouble, FieldTypeFloat, FieldTypeHalfFloat, FieldTypeScaledFloat:
+		return true
+	default:
+		return false
+	}
+}
+
+// formatDouble returns the rendering of the value of a double field in text templates, like appendFieldDouble renders
+// it in placeholder templates: values of other types (ie: nil for a missing value) are returned as they are
+func formatDouble(cfg Config, field Field, value any) any {
+	v, ok := value.(float64)
+	if !ok {
+		return value
+	}
+
+	fieldCfg, _ := cfg.GetField(field.Name)
+	return string(appendFieldDouble(make([]byte, 0, 32), field, v, fieldCfg.OmitIntegerDecimals))
+}
+
+// appendDouble appends the rendering of a double value to dst, with 6 decimals and without scientific notation.
+// Integer values are rendered without decimal point if omitIntegerDecimals is true.
+func appendDouble(dst []byte, v float64, omitIntegerDecimals bool) []byte {
+	if omitIntegerDecimals && v == math.Trunc(v) {
+		return strconv.AppendFloat(dst, v, 'f', 0, 64)
+	}
+
+	return strconv.AppendFloat(dst, v, 'f', 6, 64)
+}
+
+// scaledFloat rounds v to the precision of the `scaling_factor` of a `scaled_float` field, that is the precision
+// the value is stored with (ie: to 2 decimals for a scaling factor of 100). Other fields are returned unchanged.
+func scaledFloat(field Field, v float64) float64 {
+	if field.Type != FieldTypeScaledFloat || field.ScalingFactor <= 0 {
+		return v
+	}
+
+	return math.Round(v*field.ScalingFactor) / field.ScalingFactor
+}
+
+// appendFieldDouble appends the rendering of a double value of field to dst: as appendDouble, but values of
+// `scaled_float` fields with a `scaling_factor` are rendered with no more decimals than their precision.
+func appendFieldDouble(dst []byte, field Field, v float64, omitIntegerDecimals bool) []byte {
+	if field.Type == FieldTypeScaledFloat && field.ScalingFactor > 0 {
+		return strconv.AppendFloat(dst, v, 'f', -1, 64)
+	}
+
+	return appendDouble(dst, v, omitIntegerDecimals)
+}
+
+// arrayValues is the value of a field generating an array: it is printed as a JSON array in text templates
+type arrayValues []any
+
+func (a arrayValues) String() string {
+	b, err := json.Marshal([]any(a))
+	if err != nil {
+		return "[]"
+	}
+
+	return string(b)
+}
+
 type Generator interface {
 	Emit(buf *bytes.Buffer) error
 	Close() error
@@ -80,6 +250,22 @@ type genState struct {
 	prevCacheForDup map[string]map[any]struct{}
 	// previous cardinality value cache; necessary for cardinality
 	prevCacheCardinality map[string][]any
+	// current event value cache; necessary for fields depending on other fields
+	eventCache map[string]eventValue
+	// entity attributes cache by entity id field; necessary for entity attributes
+	entityCache map[string]*entityAttributes
+	// cached value by field, with the event it was generated in; necessary for cache_for
+	cachedValues map[string]eventValue
+	// number of series, the combinations of the values of the dimension fields; necessary for multi-series
+	seriesLength uint64
+	// previous value cache by field and series; necessary for fuzziness of multi-series
+	seriesPrevCache map[string]map[uint64]any
+	// index of the element of the array field being generated, and the max length of its arrays; necessary for
+	// cardinality of arrays
+	arrayElement int
+	arrayMax     int
+	// random generator the fields are generated with
+	rand *rand.Rand
 	// internal buffer pool to decrease load on GC
 	pool sync.Pool
 }
@@ -89,6 +275,11 @@ func newGenState() *genState {
 		prevCache:            make(map[string]any),
 		prevCacheForDup:      make(map[string]map[any]struct{}),
 		prevCacheCardinality: make(map[string][]any, 0),
+		eventCache:           make(map[string]eventValue),
+		entityCache:          make(map[string]*entityAttributes),
+		cachedValues:         make(map[string]eventValue),
+		seriesPrevCache:      make(map[string]map[uint64]any),
+		rand:                 newGeneratorRand(),
 		pool: sync.Pool{
 			New: func() any {
 				return new(bytes.Buffer)
@@ -97,6 +288,25 @@ func newGenState() *genState {
 	}
 }
 
+// eventValue is the value generated for a field in the event identified by counter
+type eventValue struct {
+	counter uint64
+	value   any
+}
+
+func (state *genState) eventValue(fieldName string) (any, bool) {
+	v, ok := state.eventCache[fieldName]
+	if !ok || v.counter != state.counter {
+		return nil, false
+	}
+
+	return v.value, true
+}
+
+func (state *genState) setEventValue(fieldName string, value any) {
+	state.eventCache[fieldName] = eventValue{counter: state.counter, value: value}
+}
+
 func bindField(cfg Config, field Field, fieldMap map[string]any, withReturn bool) error {
 
 	// Check for hardcoded field value
@@ -110,6 +320,38 @@ func bindField(cfg Config, field Field, fieldMap map[string]any, withReturn bool
 
 	// Check config override of value
 	fieldCfg, _ := cfg.GetField(field.Name)
+	if err := validConfigCombination(fieldCfg, field); err != nil {
+		return err
+	}
+
+	bind := func() error {
+		if err := bindFieldByConfig(cfg, fieldCfg, field, fieldMap, withReturn); err != nil {
+			return err
+		}
+
+		// the field is generated as an array of the values it is bound to generate
+		if fieldCfg.Array != nil {
+			if err := bindArray(fieldCfg, field, fieldMap); err != nil {
+				return err
+			}
+		}
+
+		if fieldCfg.CacheFor != 0 {
+			return bindCacheFor(fieldCfg, field, fieldMap, withReturn)
+		}
+
+		return nil
+	}
+
+	if fieldCfg.Seed != nil {
+		return bindWithSeed(*fieldCfg.Seed, fieldMap, bind)
+	}
+
+	return bind()
+}
+
+// bindFieldByConfig binds the field according to its config
+func bindFieldByConfig(cfg Config, fieldCfg ConfigField, field Field, fieldMap map[string]any, withReturn bool) error {
 	if fieldCfg.Value != nil {
 		if withReturn {
 			return bindStaticWithReturn(field, fieldCfg.Value, fieldMap)
@@ -118,6 +360,44 @@ func bindField(cfg Config, field Field, fieldMap map[string]any, withReturn bool
 		}
 	}
 
+	// Check config override of value with a fixed sequence of values
+	if len(fieldCfg.Sequence) > 0 {
+		if withReturn {
+			return bindSequenceWithReturn(fieldCfg, field, fieldMap)
+		} else {
+			return bindSequence(fieldCfg, field, fieldMap)
+		}
+	}
+
+	// Check config override of value with a pre-serialized JSON fragment
+	if len(fieldCfg.RawJSON) > 0 {
+		if withReturn {
+			return bindRawJSONWithReturn(field, fieldCfg.RawJSON, fieldMap)
+		} else {
+			return bindRawJSON(field, fieldCfg.RawJSON, fieldMap)
+		}
+	}
+
+	if fieldCfg.ArraySize > 0 && len(fieldCfg.Enum) > 0 {
+		if withReturn {
+			return bindEnumArrayWithReturn(fieldCfg, field, fieldMap)
+		} else {
+			return bindEnumArray(fieldCfg, field, fieldMap)
+		}
+	}
+
+	if len(fieldCfg.Polymorphic) > 0 {
+		return bindPolymorphic(cfg, fieldCfg, field, fieldMap, withReturn)
+	}
+
+	if fieldCfg.Unique {
+		if withReturn {
+			return bindUniqueWithReturn(cfg, field, fieldMap)
+		} else {
+			return bindUnique(cfg, field, fieldMap)
+		}
+	}
+
 	if fieldCfg.Cardinality > 0 {
 		if withReturn {
 			return bindCardinalityWithReturn(cfg, field, fieldMap)
@@ -133,6 +413,195 @@ func bindField(cfg Config, field Field, fieldMap map[string]any, withReturn bool
 	}
 }
 
+// validConfigCombination checks that the config options of the field don't have ambiguous semantics when combined
+func validConfigCombination(fieldCfg ConfigField, field Field) error {
+	// `min` and `max` bound the generated values like `range` does
+	hasRange := fieldCfg.Min != nil || fieldCfg.Max != nil || fieldCfg.Range.Min != nil || fieldCfg.Range.Max != nil || fieldCfg.Range.From != nil || fieldCfg.Range.To != nil
+
+	options := []struct {
+		name string
+		set  bool
+	}{
+		{name: "value", set: fieldCfg.Value != nil},
+		{name: "raw_json", set: len(fieldCfg.RawJSON) > 0},
+		{name: "sequence", set: len(fieldCfg.Sequence) > 0},
+		{name: "enum", set: len(fieldCfg.Enum) > 0},
+		{name: "range", set: hasRange},
+		{name: "cardinality", set: fieldCfg.Cardinality > 0},
+		{name: "unique", set: fieldCfg.Unique},
+		{name: "fuzziness", set: fieldCfg.Fuzziness > 0},
+		{name: "polymorphic", set: len(fieldCfg.Polymorphic) > 0},
+		{name: "array", set: fieldCfg.Array != nil},
+		{name: "pattern", set: len(fieldCfg.Pattern) > 0},
+		{name: "value_from_template", set: len(fieldCfg.ValueFromTemplate) > 0},
+		{name: "mode", set: fieldCfg.Mode == config.DateModeAligned || fieldCfg.Mode == config.DateModeMonotonic},
+	}
+
+	isSet := make(map[string]bool, len(options))
+	for _, option := range options {
+		isSet[option.name] = option.set
+	}
+
+	illegal := map[string][]string{
+		// a hardcoded value excludes any option about generating it
+		"value":       {"raw_json", "sequence", "enum", "range", "cardinality", "unique", "fuzziness", "polymorphic", "array", "pattern"},
+		"raw_json":    {"sequence", "enum", "range", "cardinality", "unique", "fuzziness", "polymorphic", "array", "pattern"},
+		"sequence":    {"enum", "range", "cardinality", "unique", "fuzziness", "polymorphic", "array", "pattern"},
+		"unique":      {"cardinality"},
+		"polymorphic": {"cardinality", "unique", "fuzziness"},
+		"pattern":     {"enum"},
+		// the values of `cardinality` are generated up front, before the `aligned` or `monotonic` date of any event
+		"mode": {"cardinality"},
+		// a value built from other fields excludes any option about generating it
+		"value_from_template": {"value", "raw_json", "sequence", "enum", "range", "cardinality", "unique", "fuzziness", "polymorphic", "array", "pattern"},
+	}
+
+	// `money` fields chose the currency from `enum` and the amount in `range`,
+	// `tls` fields the version from `enum` and the validity dates of the certificate in `range`
+	if field.Type != FieldTypeMoney && field.Type != FieldTypeTLS {
+		illegal["enum"] = []string{"range"}
+	}
+
+	for _, option := range options {
+		if !option.set {
+			continue
+		}
+
+		for _, other := range illegal[option.name] {
+			if isSet[other] {
+				return fmt.Errorf("%w for field %s: `%s` and `%s`", illegalConfigCombination, field.Name, option.name, other)
+			}
+		}
+	}
+
+	return nil
+}
+
+// bindKeywordMultiField mirrors the value of a field bound in fieldMap to its `.keyword` multi-field.
+// The value generated for the field is cached in the state and emitted as is by the multi-field.
+func bindKeywordMultiField(cfg Config, field Field, fieldMap map[string]any, withReturn bool) error {
+	fieldCfg, _ := cfg.GetField(field.Name)
+	if !fieldCfg.KeywordMultiField {
+		return nil
+	}
+
+	keywordFieldName := field.Name + keywordMultiFieldSuffix
+
+	if withReturn {
+		boundF, ok := fieldMap[field.Name].(emitF)
+		if !ok {
+			return errors.New("cannot bind keyword multi-field")
+		}
+
+		var emitF emitF
+		emitF = func(state *genState) any {
+			value := boundF(state)
+			state.prevCache[keywordFieldName] = value
+			return value
+		}
+
+		fieldMap[field.Name] = emitF
+		fieldMap[keywordFieldName] = makeKeywordMultiFieldStubWithReturn(keywordFieldName)
+
+		return nil
+	}
+
+	boundF, ok := fieldMap[field.Name].(emitFNotReturn)
+	if !ok {
+		return errors.New("cannot bind keyword multi-field")
+	}
+
+	var emitFNotReturn emitFNotReturn
+	emitFNotReturn = func(state *genState, buf *bytes.Buffer) error {
+		start := buf.Len()
+		if err := boundF(state, buf); err != nil {
+			return err
+		}
+
+		value := make([]byte, buf.Len()-start)
+		copy(value, buf.Bytes()[start:])
+		state.prevCache[keywordFieldName] = value
+		return nil
+	}
+
+	fieldMap[field.Name] = emitFNotReturn
+	fieldMap[keywordFieldName] = makeKeywordMultiFieldStub(keywordFieldName)
+
+	return nil
+}
+
+func makeKeywordMultiFieldStub(keywordFieldName string) emitFNotReturn {
+	return func(state *genState, buf *bytes.Buffer) error {
+		value, _ := state.prevCache[keywordFieldName].([]byte)
+		buf.Write(value)
+		return nil
+	}
+}
+
+func makeKeywordMultiFieldStubWithReturn(keywordFieldName string) emitF {
+	return func(state *genState) any {
+		return state.prevCache[keywordFieldName]
+	}
+}
+
+// bindFirstOnly wraps the fields to emit only in the first event.
+// In custom templates the emitter writes the whole key/value pair with its trailing comma,
+// so that the following events stay valid: the field must be referenced before the other fields.
+func bindFirstOnly(cfg Config, fields Fields, fieldMap map[string]any, withReturn bool) error {
+	firstOnlyFields, otherFields := splitFirstOnlyFields(cfg, fields)
+	for i, field := range firstOnlyFields {
+		if withReturn {
+			boundF, ok := fieldMap[field.Name].(emitF)
+			if !ok {
+				return errors.New("cannot bind first only")
+			}
+
+			var emitF emitF
+			emitF = func(state *genState) any {
+				if state.counter > 0 {
+					return nil
+				}
+
+				return boundF(state)
+			}
+
+			fieldMap[field.Name] = emitF
+			continue
+		}
+
+		boundF, ok := fieldMap[field.Name].(emitFNotReturn)
+		if !ok {
+			return errors.New("cannot bind first only")
+		}
+
+		fieldWrap := fieldValueWrapByConfig(cfg, field)
+		fieldPrefix := []byte(`"` + cfg.FieldKey(field.Name) + `": ` + fieldWrap)
+		fieldTrailer := []byte(fieldWrap + ", ")
+		if len(otherFields) == 0 && i == len(firstOnlyFields)-1 {
+			fieldTrailer = []byte(fieldWrap)
+		}
+
+		var emitFNotReturn emitFNotReturn
+		emitFNotReturn = func(state *genState, buf *bytes.Buffer) error {
+			if state.counter > 0 {
+				return nil
+			}
+
+			buf.Write(fieldPrefix)
+			if err := boundF(state, buf); err != nil {
+				return err
+			}
+
+			buf.Write(fieldTrailer)
+			return nil
+		}
+
+		fieldMap[field.Name] = emitFNotReturn
+	}
+
+	return nil
+}
+
 // Check for dupes O(n)
 func isDupeByteSlice(va []bytes.Buffer, dst []byte) bool {
 	var dupe bool
@@ -170,11 +639,13 @@ func bindByType(cfg Config, field Field, fieldMap map[string]any) (err error) {
 	case FieldTypeDate:
 		err = bindNearTime(fieldCfg, field, fieldMap)
 	case FieldTypeIP:
-		err = bindIP(field, fieldMap)
+		err = bindIP(fieldCfg, field, fieldMap)
 	case FieldTypeDouble, FieldTypeFloat, FieldTypeHalfFloat, FieldTypeScaledFloat:
 		err = bindDouble(fieldCfg, field, fieldMap)
-	case FieldTypeInteger, FieldTypeLong, FieldTypeUnsignedLong: // TODO: generate > 63 bit values for unsigned_long
+	case FieldTypeByte, FieldTypeShort, FieldTypeInteger, FieldTypeLong:
 		err = bindLong(fieldCfg, field, fieldMap)
+	case FieldTypeUnsignedLong:
+		err = bindUnsignedLong(fieldCfg, field, fieldMap)
 	case FieldTypeConstantKeyword:
 		err = bindConstantKeyword(field, fieldMap)
 	case FieldTypeKeyword:
@@ -184,9 +655,63 @@ func bindByType(cfg Config, field Field, fieldMap map[string]any) (err error) {
 	case FieldTypeObject, FieldTypeNested, FieldTypeFlattened:
 		err = bindObject(cfg, fieldCfg, field, fieldMap)
 	case FieldTypeGeoPoint:
-		err = bindGeoPoint(field, fieldMap)
+		err = bindGeoPoint(fieldCfg, field, fieldMap)
+	case FieldTypeText:
+		err = bindText(fieldCfg, field, fieldMap)
+	case FieldTypeGeoShape:
+		err = bindGeoShape(fieldCfg, field, fieldMap)
+	case FieldTypePoint:
+		err = bindPoint(fieldCfg, field, fieldMap)
+	case FieldTypeHostname:
+		err = bindHostname(field, fieldMap)
+	case FieldTypeMoney:
+		err = bindMoney(fieldCfg, field, fieldMap)
+	case FieldTypeCIDR:
+		err = bindCIDR(fieldCfg, field, fieldMap)
+	case FieldTypeULID:
+		err = bindULID(field, fieldMap)
+	case FieldTypeUUID:
+		err = bindUUID(fieldCfg, field, fieldMap)
+	case FieldTypeHexToken:
+		err = bindHexToken(fieldCfg, field, fieldMap)
+	case FieldTypeASN:
+		err = bindASN(fieldCfg, field, fieldMap)
+	case FieldTypePersonName:
+		err = bindPersonName(fieldCfg, field, fieldMap)
+	case FieldTypePath:
+		err = bindPath(fieldCfg, field, fieldMap)
+	case FieldTypeRegistryPath:
+		err = bindRegistryPath(fieldCfg, field, fieldMap)
+	case FieldTypeSQLStatement:
+		err = bindSQLStatement(fieldCfg, field, fieldMap)
+	case FieldTypeOS:
+		err = bindOS(field, fieldMap)
+	case FieldTypeProcess:
+		err = bindProcess(field, fieldMap)
+	case FieldTypeCloud:
+		err = bindCloud(field, fieldMap)
+	case FieldTypeValidity:
+		err = bindValidity(fieldCfg, field, fieldMap)
+	case FieldTypeKubernetes:
+		err = bindKubernetes(field, fieldMap)
+	case FieldTypeDNS:
+		err = bindDNS(field, fieldMap)
+	case FieldTypeSession:
+		err = bindSession(fieldCfg, field, fieldMap)
+	case FieldTypeTLS:
+		err = bindTLS(fieldCfg, field, fieldMap)
+	case FieldTypeEventCategorization:
+		err = bindEventCategorization(fieldCfg, field, fieldMap)
+	case FieldTypeUser:
+		err = bindUser(fieldCfg, field, fieldMap)
+	case FieldTypeWebTransaction:
+		err = bindWebTransaction(field, fieldMap)
 	default:
-		err = bindWordN(field, 25, fieldMap)
+		if binder, ok := registeredFieldBinder(field.Type); ok {
+			err = bindRegisteredType(binder, fieldCfg, field, fieldMap)
+		} else {
+			err = bindWordN(field, 25, fieldMap)
+		}
 	}
 
 	return
@@ -200,11 +725,13 @@ func bindByTypeWithReturn(cfg Config, field Field, fieldMap map[string]any) (err
 	case FieldTypeDate:
 		err = bindNearTimeWithReturn(fieldCfg, field, fieldMap)
 	case FieldTypeIP:
-		err = bindIPWithReturn(field, fieldMap)
+		err = bindIPWithReturn(fieldCfg, field, fieldMap)
 	case FieldTypeDouble, FieldTypeFloat, FieldTypeHalfFloat, FieldTypeScaledFloat:
 		err = bindDoubleWithReturn(fieldCfg, field, fieldMap)
-	case FieldTypeInteger, FieldTypeLong, FieldTypeUnsignedLong: // TODO: generate > 63 bit values for unsigned_long
+	case FieldTypeByte, FieldTypeShort, FieldTypeInteger, FieldTypeLong:
 		err = bindLongWithReturn(fieldCfg, field, fieldMap)
+	case FieldTypeUnsignedLong:
+		err = bindUnsignedLongWithReturn(fieldCfg, field, fieldMap)
 	case FieldTypeConstantKeyword:
 		err = bindConstantKeywordWithReturn(field, fieldMap)
 	case FieldTypeKeyword:
@@ -214,41 +741,144 @@ func bindByTypeWithReturn(cfg Config, field Field, fieldMap map[string]any) (err
 	case FieldTypeObject, FieldTypeNested, FieldTypeFlattened:
 		err = bindObjectWithReturn(cfg, fieldCfg, field, fieldMap)
 	case FieldTypeGeoPoint:
-		err = bindGeoPointWithReturn(field, fieldMap)
+		err = bindGeoPointWithReturn(fieldCfg, field, fieldMap)
+	case FieldTypeText:
+		err = bindTextWithReturn(fieldCfg, field, fieldMap)
+	case FieldTypeGeoShape:
+		err = bindGeoShapeWithReturn(fieldCfg, field, fieldMap)
+	case FieldTypePoint:
+		err = bindPointWithReturn(fieldCfg, field, fieldMap)
+	case FieldTypeHostname:
+		err = bindHostnameWithReturn(field, fieldMap)
+	case FieldTypeMoney:
+		err = bindMoneyWithReturn(fieldCfg, field, fieldMap)
+	case FieldTypeCIDR:
+		err = bindCIDRWithReturn(fieldCfg, field, fieldMap)
+	case FieldTypeULID:
+		err = bindULIDWithReturn(field, fieldMap)
+	case FieldTypeUUID:
+		err = bindUUIDWithReturn(fieldCfg, field, fieldMap)
+	case FieldTypeHexToken:
+		err = bindHexTokenWithReturn(fieldCfg, field, fieldMap)
+	case FieldTypeASN:
+		err = bindASNWithReturn(fieldCfg, field, fieldMap)
+	case FieldTypePersonName:
+		err = bindPersonNameWithReturn(fieldCfg, field, fieldMap)
+	case FieldTypePath:
+		err = bindPathWithReturn(fieldCfg, field, fieldMap)
+	case FieldTypeRegistryPath:
+		err = bindRegistryPathWithReturn(fieldCfg, field, fieldMap)
+	case FieldTypeSQLStatement:
+		err = bindSQLStatementWithReturn(fieldCfg, field, fieldMap)
+	case FieldTypeOS:
+		err = bindOSWithReturn(field, fieldMap)
+	case FieldTypeProcess:
+		err = bindProcessWithReturn(field, fieldMap)
+	case FieldTypeCloud:
+		err = bindCloudWithReturn(field, fieldMap)
+	case FieldTypeValidity:
+		err = bindValidityWithReturn(fieldCfg, field, fieldMap)
+	case FieldTypeKubernetes:
+		err = bindKubernetesWithReturn(field, fieldMap)
+	case FieldTypeDNS:
+		err = bindDNSWithReturn(field, fieldMap)
+	case FieldTypeSession:
+		err = bindSessionWithReturn(fieldCfg, field, fieldMap)
+	case FieldTypeTLS:
+		err = bindTLSWithReturn(fieldCfg, field, fieldMap)
+	case FieldTypeEventCategorization:
+		err = bindEventCategorizationWithReturn(fieldCfg, field, fieldMap)
+	case FieldTypeUser:
+		err = bindUserWithReturn(fieldCfg, field, fieldMap)
+	case FieldTypeWebTransaction:
+		err = bindWebTransactionWithReturn(field, fieldMap)
 	default:
-		err = bindWordNWithReturn(field, 25, fieldMap)
+		if binder, ok := registeredFieldBinder(field.Type); ok {
+			err = bindRegisteredTypeWithReturn(binder, fieldCfg, field, fieldMap)
+		} else {
+			err = bindWordNWithReturn(field, 25, fieldMap)
+		}
 	}
 
 	return
 }
 
-func makeFloatFunc(fieldCfg ConfigField, field Field) func() float64 {
-	minValue, _ := fieldCfg.Range.MinAsFloat64()
-	maxValue, err := fieldCfg.Range.MaxAsFloat64()
-	// maxValue not set, let's set it to 0 for the sake of the switch above
+// minMaxAsFloat64 returns the inclusive `min` and `max` of the field: a missing max defaults to highest, and a missing
+// min to 0, or to lowest when max is negative
+func minMaxAsFloat64(fieldCfg ConfigField, lowest, highest float64) (float64, float64) {
+	max := highest
+	if fieldCfg.Max != nil {
+		max = *fieldCfg.Max
+	}
+
+	var min float64
+	if max < 0 {
+		min = lowest
+	}
+
+	if fieldCfg.Min != nil {
+		min = *fieldCfg.Min
+	}
+
+	return min, max
+}
+
+// numericBounds returns the bounds the fuzzy values of the field are kept within: its `min` and `max` if any is set,
+// otherwise its `range`, a missing max defaulting to highest
+func numericBounds(fieldCfg ConfigField, lowest, highest float64) (float64, float64) {
+	if fieldCfg.Min != nil || fieldCfg.Max != nil {
+		return minMaxAsFloat64(fieldCfg, lowest, highest)
+	}
+
+	min, _ := fieldCfg.Range.MinAsFloat64()
+	max, err := fieldCfg.Range.MaxAsFloat64()
 	if err != nil {
-		maxValue = 0
+		max = highest
 	}
 
-	var dummyFunc func() float64
+	return min, max
+}
+
+func makeFloatFunc(fieldCfg ConfigField, field Field) func(r *rand.Rand) float64 {
+	minValue, errMin := fieldCfg.Range.MinAsFloat64()
+	maxValue, errMax := fieldCfg.Range.MaxAsFloat64()
+
+	var dummyFunc func(r *rand.Rand) float64
 
 	switch {
-	case maxValue > 0:
-		dummyFunc = func() float64 { return minValue + customRand.Float64()*(maxValue-minValue) }
+	case fieldCfg.Min != nil || fieldCfg.Max != nil:
+		minValue, maxValue = minMaxAsFloat64(fieldCfg, -math.MaxFloat64, math.MaxFloat64)
+		dummyFunc = func(r *rand.Rand) float64 {
+			t := r.Float64()
+			return minValue*(1-t) + maxValue*t
+		}
+	case errMin == nil || errMax == nil:
+		// a missing max defaults to the largest float64, and a missing min to 0, or to the lowest float64 for a
+		// negative max
+		if errMin != nil && maxValue < 0 {
+			minValue = -math.MaxFloat64
+		}
+
+		// the value is drawn uniformly between min and max, that can be negative: weighting the bounds
+		// instead of scaling max - min avoids overflowing to Inf for ranges wider than the largest float64
+		dummyFunc = func(r *rand.Rand) float64 {
+			t := r.Float64()
+			return minValue*(1-t) + maxValue*t
+		}
 	case len(field.Example) == 0:
-		dummyFunc = func() float64 { return customRand.Float64() * 10 }
+		dummyFunc = func(r *rand.Rand) float64 { return r.Float64() * 10 }
 	default:
 		totDigit := len(field.Example)
 		max := math.Pow10(totDigit)
-		dummyFunc = func() float64 {
-			return customRand.Float64() * max
+		dummyFunc = func(r *rand.Rand) float64 {
+			return r.Float64() * max
 		}
 	}
 
 	return dummyFunc
 }
 
-func makeIntFunc(fieldCfg ConfigField, field Field) func() int64 {
+func makeIntFunc(fieldCfg ConfigField, field Field) func(r *rand.Rand) int64 {
 	minValue, _ := fieldCfg.Range.MinAsInt64()
 	maxValue, err := fieldCfg.Range.MaxAsInt64()
 	// maxValue not set, let's set it to 0 for the sake of the switch above
@@ -256,18 +886,28 @@ func makeIntFunc(fieldCfg ConfigField, field Field) func() int64 {
 		maxValue = 0
 	}
 
-	var dummyFunc func() int64
+	var dummyFunc func(r *rand.Rand) int64
 
 	switch {
+	case fieldCfg.Min != nil || fieldCfg.Max != nil:
+		minFloat, maxFloat := minMaxAsFloat64(fieldCfg, float64(integerTypeMin(field.Type)), float64(integerTypeMax(field.Type)))
+		minInt, maxInt := int64OfFloat64(minFloat), int64OfFloat64(maxFloat)
+		dummyFunc = func(r *rand.Rand) int64 {
+			return minInt + int64(randUint64Inclusive(r, uint64(maxInt)-uint64(minInt)))
+		}
 	case maxValue > 0:
-		dummyFunc = func() int64 { return customRand.Int63n(maxValue-minValue) + minValue }
+		dummyFunc = func(r *rand.Rand) int64 { return r.Int63n(maxValue-minValue) + minValue }
 	case len(field.Example) == 0:
-		dummyFunc = func() int64 { return customRand.Int63n(10) }
+		dummyFunc = func(r *rand.Rand) int64 { return r.Int63n(10) }
 	default:
 		totDigit := len(field.Example)
 		max := int64(math.Pow10(totDigit))
-		dummyFunc = func() int64 {
-			return customRand.Int63n(max)
+		// the values are kept within the range of the type of the field
+		if typeMax := integerTypeMax(field.Type); max <= 0 || max > typeMax {
+			max = typeMax
+		}
+		dummyFunc = func(r *rand.Rand) int64 {
+			return r.Int63n(max)
 		}
 	}
 
@@ -338,21 +978,61 @@ func genNounsNWithReturn(n int) string {
 	return value
 }
 
-func randGeoPoint() (int, int, int, int) {
-	lat := customRand.Intn(181) - 90
+func randGeoPoint(r *rand.Rand) (int, int, int, int) {
+	lat := r.Intn(181) - 90
 	var latD int
 	if lat != -90 && lat != 90 {
-		latD = customRand.Intn(100)
+		latD = r.Intn(100)
 	}
 	var longD int
-	long := customRand.Intn(361) - 180
+	long := r.Intn(361) - 180
 	if long != -180 && long != 180 {
-		longD = customRand.Intn(100)
+		longD = r.Intn(100)
 	}
 
 	return lat, latD, long, longD
 }
 
+// randHostname generates a hostname valid per RFC 1123:
+// labels of lowercase letters, digits and hyphens, not starting nor ending with a hyphen
+func randHostname(r *rand.Rand) string {
+	totLabels := r.Intn(3) + 1
+	labels := make([]string, 0, totLabels)
+	totLength := 0
+	for i := 0; i < totLabels; i++ {
+		var label string
+		if i == 0 {
+			label = hostnameLabel(randomdata.Adjective() + "-" + randomdata.Noun())
+		} else {
+			label = hostnameLabel(randomdata.Noun())
+		}
+
+		if len(label) == 0 {
+			label = "host"
+		}
+
+		// +1 for the dot separator
+		if totLength+len(label)+1 > hostnameMaxLength {
+			break
+		}
+
+		totLength += len(label) + 1
+		labels = append(labels, label)
+	}
+
+	return strings.Join(labels, ".")
+}
+
+// hostnameLabel normalises a string to a valid RFC 1123 hostname label
+func hostnameLabel(s string) string {
+	label := hostnameLabelRegex.ReplaceAllString(strings.ToLower(s), "")
+	if len(label) > hostnameMaxLabelLength {
+		label = label[:hostnameMaxLabelLength]
+	}
+
+	return strings.Trim(label, "-")
+}
+
 func bindConstantKeyword(field Field, fieldMap map[string]any) error {
 	var emitFNotReturn emitFNotReturn
 	emitFNotReturn = func(state *genState, buf *bytes.Buffer) error {
@@ -371,14 +1051,34 @@ func bindConstantKeyword(field Field, fieldMap map[string]any) error {
 }
 
 func bindKeyword(fieldCfg ConfigField, field Field, fieldMap map[string]any) error {
+	if err := fieldCfg.ValidForDistribution(); err != nil {
+		return err
+	}
+
 	if len(fieldCfg.Enum) > 0 {
+		fieldCfg = withActiveEnumSubset(fieldCfg)
+		enumIndex := makeEnumIndexFunc(fieldCfg)
+
 		var emitFNotReturn emitFNotReturn
 		emitFNotReturn = func(state *genState, buf *bytes.Buffer) error {
-			idx := customRand.Intn(len(fieldCfg.Enum))
+			idx := enumIndex(state.rand)
 			buf.WriteString(fieldCfg.Enum[idx])
 			return nil
 		}
 
+		fieldMap[field.Name] = emitFNotReturn
+	} else if len(fieldCfg.Pattern) > 0 {
+		pattern, err := makePatternFunc(fieldCfg.Pattern)
+		if err != nil {
+			return fmt.Errorf("field %s has an invalid pattern: %w", field.Name, err)
+		}
+
+		var emitFNotReturn emitFNotReturn
+		emitFNotReturn = func(state *genState, buf *bytes.Buffer) error {
+			pattern(state.rand, buf)
+			return nil
+		}
+
 		fieldMap[field.Name] = emitFNotReturn
 	} else if len(field.Example) > 0 {
 		totWords, joiner := totWordsAndJoiner(field.Example)
@@ -447,10 +1147,27 @@ func bindStatic(field Field, v any, fieldMap map[string]any) error {
 	return nil
 }
 
+func bindRawJSON(field Field, rawJSON string, fieldMap map[string]any) error {
+	if !json.Valid([]byte(rawJSON)) {
+		return fmt.Errorf("%w: %s", rawJSONNotValid, field.Name)
+	}
+
+	vstr := []byte(rawJSON)
+
+	var emitFNotReturn emitFNotReturn
+	emitFNotReturn = func(state *genState, buf *bytes.Buffer) error {
+		buf.Write(vstr)
+		return nil
+	}
+	fieldMap[field.Name] = emitFNotReturn
+
+	return nil
+}
+
 func bindBool(field Field, fieldMap map[string]any) error {
 	var emitFNotReturn emitFNotReturn
 	emitFNotReturn = func(state *genState, buf *bytes.Buffer) error {
-		switch customRand.Int() % 2 {
+		switch state.rand.Int() % 2 {
 		case 0:
 			buf.WriteString("false")
 		case 1:
@@ -463,22 +1180,15 @@ func bindBool(field Field, fieldMap map[string]any) error {
 	return nil
 }
 
-func bindGeoPoint(field Field, fieldMap map[string]any) error {
-	var emitFNotReturn emitFNotReturn
-	emitFNotReturn = func(state *genState, buf *bytes.Buffer) error {
-		lat, latD, long, longD := randGeoPoint()
-		_, err := fmt.Fprintf(buf, "%d.%d,%d.%d", lat, latD, long, longD)
+func bindGeoPoint(fieldCfg ConfigField, field Field, fieldMap map[string]any) error {
+	geoPointFunc, err := makeGeoPointFunc(fieldCfg)
+	if err != nil {
 		return err
 	}
 
-	fieldMap[field.Name] = emitFNotReturn
-	return nil
-}
-
-func bindWordN(field Field, n int, fieldMap map[string]any) error {
 	var emitFNotReturn emitFNotReturn
 	emitFNotReturn = func(state *genState, buf *bytes.Buffer) error {
-		genNounsN(customRand.Intn(n), buf)
+		buf.WriteString(geoPointFunc(state.rand))
 		return nil
 	}
 
@@ -486,367 +1196,1770 @@ func bindWordN(field Field, n int, fieldMap map[string]any) error {
 	return nil
 }
 
-func bindNearTime(fieldCfg ConfigField, field Field, fieldMap map[string]any) error {
-	if err := fieldCfg.ValidForDateField(); err != nil {
+func bindText(fieldCfg ConfigField, field Field, fieldMap map[string]any) error {
+	minWords, maxWords, err := textWords(fieldCfg, field)
+	if err != nil {
 		return err
 	}
 
 	var emitFNotReturn emitFNotReturn
 	emitFNotReturn = func(state *genState, buf *bytes.Buffer) error {
-		newTime := nearTime(fieldCfg, state)
-
-		buf.WriteString(newTime.Format(FieldTypeTimeLayout))
+		genText(state.rand, minWords, maxWords, fieldCfg.Punctuation, buf)
 		return nil
 	}
+
 	fieldMap[field.Name] = emitFNotReturn
 	return nil
 }
 
-func nearTime(fieldCfg ConfigField, state *genState) time.Time {
-	var offset time.Duration
-	from, errFrom := fieldCfg.Range.FromAsTime()
-	to, errTo := fieldCfg.Range.ToAsTime()
-	if errFrom == nil && errTo == nil {
-		timeNowToBind = from
-		fieldCfg.Period = to.UTC().Sub(from.UTC())
+func bindGeoShape(fieldCfg ConfigField, field Field, fieldMap map[string]any) error {
+	geoShapeFunc, err := makeGeoShapeFunc(fieldCfg)
+	if err != nil {
+		return err
 	}
 
-	if errFrom == nil && errTo != nil {
-		if from.UTC().After(timeNowToBind.UTC()) {
-			fieldCfg.Period = from.UTC().Sub(timeNowToBind.UTC())
-		} else {
-			fieldCfg.Period = timeNowToBind.UTC().Sub(from.UTC())
-		}
+	var emitFNotReturn emitFNotReturn
+	emitFNotReturn = func(state *genState, buf *bytes.Buffer) error {
+		buf.WriteString(geoShapeFunc(state.rand))
+		return nil
+	}
+
+	fieldMap[field.Name] = emitFNotReturn
+	return nil
+}
 
+func bindPoint(fieldCfg ConfigField, field Field, fieldMap map[string]any) error {
+	pointFunc, err := makePointFunc(fieldCfg)
+	if err != nil {
+		return err
 	}
 
-	if errFrom != nil && errTo == nil {
-		if to.UTC().After(timeNowToBind.UTC()) {
-			fieldCfg.Period = to.UTC().Sub(timeNowToBind.UTC())
-		} else {
-			fieldCfg.Period = timeNowToBind.UTC().Sub(to.UTC())
-		}
+	var emitFNotReturn emitFNotReturn
+	emitFNotReturn = func(state *genState, buf *bytes.Buffer) error {
+		buf.WriteString(pointFunc(state.rand))
+		return nil
 	}
 
-	if fieldCfg.Period > 0 && state.totEvents > 0 {
-		offset = time.Duration((fieldCfg.Period.Nanoseconds() / int64(state.totEvents)) * int64(state.counter))
-	} else if fieldCfg.Period < 0 && state.totEvents > 0 {
-		offset = time.Duration((fieldCfg.Period.Nanoseconds() / int64(state.totEvents)) * (int64(state.totEvents - state.counter)))
-	} else {
-		offset = time.Duration(customRand.Intn(FieldTypeDurationSpan)) * time.Millisecond
+	fieldMap[field.Name] = emitFNotReturn
+	return nil
+}
+
+func bindHostname(field Field, fieldMap map[string]any) error {
+	var emitFNotReturn emitFNotReturn
+	emitFNotReturn = func(state *genState, buf *bytes.Buffer) error {
+		buf.WriteString(randHostname(state.rand))
+		return nil
 	}
 
-	newTime := timeNowToBind.Add(offset)
+	fieldMap[field.Name] = emitFNotReturn
+	return nil
+}
 
-	if state.totEvents <= 0 {
-		timeNowToBind = newTime
+func bindMoney(fieldCfg ConfigField, field Field, fieldMap map[string]any) error {
+	currencies, err := moneyCurrencies(fieldCfg)
+	if err != nil {
+		return err
 	}
 
-	return newTime
+	amountFunc := makeFloatFunc(fieldCfg, field)
+
+	var emitFNotReturnCurrency emitFNotReturn
+	emitFNotReturnCurrency = func(state *genState, buf *bytes.Buffer) error {
+		buf.WriteString(moneyCurrencyForEvent(field.Name, currencies, state))
+		return nil
+	}
+
+	var emitFNotReturnAmount emitFNotReturn
+	emitFNotReturnAmount = func(state *genState, buf *bytes.Buffer) error {
+		currency := moneyCurrencyForEvent(field.Name, currencies, state)
+		v := make([]byte, 0, 32)
+		v = strconv.AppendFloat(v, amountFunc(state.rand), 'f', currencyMinorUnits[currency], 64)
+		buf.Write(v)
+		return nil
+	}
+
+	fieldMap[field.Name+moneyCurrencySuffix] = emitFNotReturnCurrency
+	fieldMap[field.Name+moneyAmountSuffix] = emitFNotReturnAmount
+	return nil
 }
 
-func bindIP(field Field, fieldMap map[string]any) error {
+func bindASN(fieldCfg ConfigField, field Field, fieldMap map[string]any) error {
+	asns := asnTable(fieldCfg)
+
+	var emitFNotReturnNumber emitFNotReturn
+	emitFNotReturnNumber = func(state *genState, buf *bytes.Buffer) error {
+		buf.WriteString(strconv.FormatInt(asnForEvent(field.Name, asns, state).Number, 10))
+		return nil
+	}
+
+	var emitFNotReturnOrganizationName emitFNotReturn
+	emitFNotReturnOrganizationName = func(state *genState, buf *bytes.Buffer) error {
+		buf.WriteString(asnForEvent(field.Name, asns, state).Organization)
+		return nil
+	}
+
+	fieldMap[field.Name+asnNumberSuffix] = emitFNotReturnNumber
+	fieldMap[field.Name+asnOrganizationNameSuffix] = emitFNotReturnOrganizationName
+	return nil
+}
+
+func bindPersonName(fieldCfg ConfigField, field Field, fieldMap map[string]any) error {
+	pool, err := personNamePoolForLocale(fieldCfg)
+	if err != nil {
+		return err
+	}
+
 	var emitFNotReturn emitFNotReturn
 	emitFNotReturn = func(state *genState, buf *bytes.Buffer) error {
-		i0, i1, i2, i3 := randIP()
+		buf.WriteString(randPersonName(state.rand, pool))
+		return nil
+	}
 
-		_, err := fmt.Fprintf(buf, "%d.%d.%d.%d", i0, i1, i2, i3)
+	fieldMap[field.Name] = emitFNotReturn
+	return nil
+}
+
+func bindPath(fieldCfg ConfigField, field Field, fieldMap map[string]any) error {
+	pathFunc, err := makePathFunc(fieldCfg)
+	if err != nil {
 		return err
 	}
 
+	var emitFNotReturn emitFNotReturn
+	emitFNotReturn = func(state *genState, buf *bytes.Buffer) error {
+		buf.WriteString(pathFunc(state.rand))
+		return nil
+	}
+
 	fieldMap[field.Name] = emitFNotReturn
+	return nil
+}
+
+func bindRegistryPath(fieldCfg ConfigField, field Field, fieldMap map[string]any) error {
+	registryPathFunc, err := makeRegistryPathFunc(fieldCfg)
+	if err != nil {
+		return err
+	}
 
+	var emitFNotReturn emitFNotReturn
+	emitFNotReturn = func(state *genState, buf *bytes.Buffer) error {
+		buf.WriteString(registryPathFunc(state.rand))
+		return nil
+	}
+
+	fieldMap[field.Name] = emitFNotReturn
 	return nil
 }
 
-func fuzzyInt(previous int64, fuzziness, min, max float64) int64 {
-	lowerBound := float64(previous) * (1 - fuzziness)
-	higherBound := float64(previous) * (1 + fuzziness)
-	lowerBound = math.Max(lowerBound, min)
-	higherBound = math.Min(higherBound, max)
-	return customRand.Int63n(int64(math.Ceil(higherBound-lowerBound))) + int64(lowerBound)
+func bindSQLStatement(fieldCfg ConfigField, field Field, fieldMap map[string]any) error {
+	sqlStatementFunc, err := makeSQLStatementFunc(fieldCfg)
+	if err != nil {
+		return err
+	}
+
+	var emitFNotReturn emitFNotReturn
+	emitFNotReturn = func(state *genState, buf *bytes.Buffer) error {
+		buf.WriteString(sqlStatementFunc(state.rand))
+		return nil
+	}
+
+	fieldMap[field.Name] = emitFNotReturn
+	return nil
 }
 
-func bindLong(fieldCfg ConfigField, field Field, fieldMap map[string]any) error {
-	dummyFunc := makeIntFunc(fieldCfg, field)
+func bindOS(field Field, fieldMap map[string]any) error {
+	var emitFNotReturnName emitFNotReturn
+	emitFNotReturnName = func(state *genState, buf *bytes.Buffer) error {
+		buf.WriteString(osForEvent(field.Name, state).name)
+		return nil
+	}
 
-	if fieldCfg.Fuzziness <= 0 {
-		var emitFNotReturn emitFNotReturn
-		emitFNotReturn = func(state *genState, buf *bytes.Buffer) error {
-			v := make([]byte, 0, 32)
-			v = strconv.AppendInt(v, dummyFunc(), 10)
-			buf.Write(v)
-			return nil
-		}
+	var emitFNotReturnVersion emitFNotReturn
+	emitFNotReturnVersion = func(state *genState, buf *bytes.Buffer) error {
+		buf.WriteString(osForEvent(field.Name, state).version)
+		return nil
+	}
 
-		fieldMap[field.Name] = emitFNotReturn
+	var emitFNotReturnFamily emitFNotReturn
+	emitFNotReturnFamily = func(state *genState, buf *bytes.Buffer) error {
+		buf.WriteString(osForEvent(field.Name, state).family)
+		return nil
+	}
+
+	fieldMap[field.Name+osNameSuffix] = emitFNotReturnName
+	fieldMap[field.Name+osVersionSuffix] = emitFNotReturnVersion
+	fieldMap[field.Name+osFamilySuffix] = emitFNotReturnFamily
+	return nil
+}
 
+func bindProcess(field Field, fieldMap map[string]any) error {
+	var emitFNotReturnPID emitFNotReturn
+	emitFNotReturnPID = func(state *genState, buf *bytes.Buffer) error {
+		buf.WriteString(strconv.FormatInt(processForEvent(field.Name, state).pid, 10))
 		return nil
 	}
 
-	min, _ := fieldCfg.Range.MinAsFloat64()
-	max, _ := fieldCfg.Range.MaxAsFloat64()
+	var emitFNotReturnName emitFNotReturn
+	emitFNotReturnName = func(state *genState, buf *bytes.Buffer) error {
+		buf.WriteString(processForEvent(field.Name, state).name)
+		return nil
+	}
 
-	var emitFNotReturn emitFNotReturn
-	emitFNotReturn = func(state *genState, buf *bytes.Buffer) error {
-		var dummyInt int64
-		if previousDummyInt, ok := state.prevCache[field.Name].(int64); ok {
-			if previousDummyInt == 0 {
-				previousDummyInt = 1
-			}
-			dummyInt = fuzzyInt(previousDummyInt, fieldCfg.Fuzziness, min, max)
-		} else {
-			dummyInt = dummyFunc()
-		}
-		state.prevCache[field.Name] = dummyInt
+	var emitFNotReturnParentPID emitFNotReturn
+	emitFNotReturnParentPID = func(state *genState, buf *bytes.Buffer) error {
+		buf.WriteString(strconv.FormatInt(processForEvent(field.Name, state).parentPID, 10))
+		return nil
+	}
+
+	var emitFNotReturnParentName emitFNotReturn
+	emitFNotReturnParentName = func(state *genState, buf *bytes.Buffer) error {
+		buf.WriteString(processForEvent(field.Name, state).parentName)
+		return nil
+	}
+
+	fieldMap[field.Name+processPIDSuffix] = emitFNotReturnPID
+	fieldMap[field.Name+processNameSuffix] = emitFNotReturnName
+	fieldMap[field.Name+processParentPIDSuffix] = emitFNotReturnParentPID
+	fieldMap[field.Name+processParentNameSuffix] = emitFNotReturnParentName
+	return nil
+}
+
+func bindCloud(field Field, fieldMap map[string]any) error {
+	var emitFNotReturnProvider emitFNotReturn
+	emitFNotReturnProvider = func(state *genState, buf *bytes.Buffer) error {
+		buf.WriteString(cloudForEvent(field.Name, state).provider)
+		return nil
+	}
+
+	var emitFNotReturnAccountID emitFNotReturn
+	emitFNotReturnAccountID = func(state *genState, buf *bytes.Buffer) error {
+		buf.WriteString(cloudForEvent(field.Name, state).accountID)
+		return nil
+	}
+
+	var emitFNotReturnRegion emitFNotReturn
+	emitFNotReturnRegion = func(state *genState, buf *bytes.Buffer) error {
+		buf.WriteString(cloudForEvent(field.Name, state).region)
+		return nil
+	}
+
+	var emitFNotReturnAvailabilityZone emitFNotReturn
+	emitFNotReturnAvailabilityZone = func(state *genState, buf *bytes.Buffer) error {
+		buf.WriteString(cloudForEvent(field.Name, state).availabilityZone)
+		return nil
+	}
+
+	fieldMap[field.Name+cloudProviderSuffix] = emitFNotReturnProvider
+	fieldMap[field.Name+cloudAccountIDSuffix] = emitFNotReturnAccountID
+	fieldMap[field.Name+cloudRegionSuffix] = emitFNotReturnRegion
+	fieldMap[field.Name+cloudAvailabilityZoneSuffix] = emitFNotReturnAvailabilityZone
+	return nil
+}
+
+func bindKubernetes(field Field, fieldMap map[string]any) error {
+	var emitFNotReturnNamespace emitFNotReturn
+	emitFNotReturnNamespace = func(state *genState, buf *bytes.Buffer) error {
+		buf.WriteString(kubernetesForEvent(field.Name, state).namespace)
+		return nil
+	}
+
+	var emitFNotReturnDeploymentName emitFNotReturn
+	emitFNotReturnDeploymentName = func(state *genState, buf *bytes.Buffer) error {
+		buf.WriteString(kubernetesForEvent(field.Name, state).deploymentName)
+		return nil
+	}
+
+	var emitFNotReturnPodName emitFNotReturn
+	emitFNotReturnPodName = func(state *genState, buf *bytes.Buffer) error {
+		buf.WriteString(kubernetesForEvent(field.Name, state).podName)
+		return nil
+	}
+
+	var emitFNotReturnContainerID emitFNotReturn
+	emitFNotReturnContainerID = func(state *genState, buf *bytes.Buffer) error {
+		buf.WriteString(kubernetesForEvent(field.Name, state).containerID)
+		return nil
+	}
+
+	var emitFNotReturnContainerImageName emitFNotReturn
+	emitFNotReturnContainerImageName = func(state *genState, buf *bytes.Buffer) error {
+		buf.WriteString(kubernetesForEvent(field.Name, state).containerImage)
+		return nil
+	}
+
+	containerPrefix := kubernetesContainerPrefix(field.Name)
+	fieldMap[field.Name+kubernetesNamespaceSuffix] = emitFNotReturnNamespace
+	fieldMap[field.Name+kubernetesDeploymentNameSuffix] = emitFNotReturnDeploymentName
+	fieldMap[field.Name+kubernetesPodNameSuffix] = emitFNotReturnPodName
+	fieldMap[containerPrefix+containerIDField] = emitFNotReturnContainerID
+	fieldMap[containerPrefix+containerImageNameField] = emitFNotReturnContainerImageName
+	return nil
+}
+
+func bindDNS(field Field, fieldMap map[string]any) error {
+	var emitFNotReturnQuestionName emitFNotReturn
+	emitFNotReturnQuestionName = func(state *genState, buf *bytes.Buffer) error {
+		buf.WriteString(dnsForEvent(field.Name, state).questionName)
+		return nil
+	}
+
+	var emitFNotReturnQuestionType emitFNotReturn
+	emitFNotReturnQuestionType = func(state *genState, buf *bytes.Buffer) error {
+		buf.WriteString(dnsForEvent(field.Name, state).questionType)
+		return nil
+	}
+
+	var emitFNotReturnAnswersName emitFNotReturn
+	emitFNotReturnAnswersName = func(state *genState, buf *bytes.Buffer) error {
+		buf.WriteString(dnsForEvent(field.Name, state).answerName)
+		return nil
+	}
+
+	var emitFNotReturnAnswersType emitFNotReturn
+	emitFNotReturnAnswersType = func(state *genState, buf *bytes.Buffer) error {
+		buf.WriteString(dnsForEvent(field.Name, state).answerType)
+		return nil
+	}
+
+	var emitFNotReturnAnswersData emitFNotReturn
+	emitFNotReturnAnswersData = func(state *genState, buf *bytes.Buffer) error {
+		buf.WriteString(dnsForEvent(field.Name, state).answerData)
+		return nil
+	}
+
+	fieldMap[field.Name+dnsQuestionNameSuffix] = emitFNotReturnQuestionName
+	fieldMap[field.Name+dnsQuestionTypeSuffix] = emitFNotReturnQuestionType
+	fieldMap[field.Name+dnsAnswersNameSuffix] = emitFNotReturnAnswersName
+	fieldMap[field.Name+dnsAnswersTypeSuffix] = emitFNotReturnAnswersType
+	fieldMap[field.Name+dnsAnswersDataSuffix] = emitFNotReturnAnswersData
+	return nil
+}
+
+func bindTLS(fieldCfg ConfigField, field Field, fieldMap map[string]any) error {
+	versions, err := tlsVersions(fieldCfg)
+	if err != nil {
+		return err
+	}
+
+	var emitFNotReturnVersion emitFNotReturn
+	emitFNotReturnVersion = func(state *genState, buf *bytes.Buffer) error {
+		buf.WriteString(tlsForEvent(field.Name, versions, state).version)
+		return nil
+	}
+
+	var emitFNotReturnVersionProtocol emitFNotReturn
+	emitFNotReturnVersionProtocol = func(state *genState, buf *bytes.Buffer) error {
+		buf.WriteString(tlsVersionProtocol)
+		return nil
+	}
+
+	var emitFNotReturnCipher emitFNotReturn
+	emitFNotReturnCipher = func(state *genState, buf *bytes.Buffer) error {
+		buf.WriteString(tlsForEvent(field.Name, versions, state).cipher)
+		return nil
+	}
+
+	var emitFNotReturnCommonName emitFNotReturn
+	emitFNotReturnCommonName = func(state *genState, buf *bytes.Buffer) error {
+		buf.WriteString(tlsForEvent(field.Name, versions, state).commonName)
+		return nil
+	}
+
+	fieldMap[field.Name+tlsVersionSuffix] = emitFNotReturnVersion
+	fieldMap[field.Name+tlsVersionProtocolSuffix] = emitFNotReturnVersionProtocol
+	fieldMap[field.Name+tlsCipherSuffix] = emitFNotReturnCipher
+	fieldMap[field.Name+tlsServerCommonNameSuffix] = emitFNotReturnCommonName
+
+	// the validity dates of the server certificate are bound as a `validity` field
+	return bindValidity(fieldCfg, tlsX509Field(field), fieldMap)
+}
+
+func bindSession(fieldCfg ConfigField, field Field, fieldMap map[string]any) error {
+	length, err := sessionLength(fieldCfg, field)
+	if err != nil {
+		return err
+	}
+
+	var emitFNotReturnID emitFNotReturn
+	emitFNotReturnID = func(state *genState, buf *bytes.Buffer) error {
+		buf.WriteString(sessionForEvent(field.Name, length, state).id)
+		return nil
+	}
+
+	var emitFNotReturnStep emitFNotReturn
+	emitFNotReturnStep = func(state *genState, buf *bytes.Buffer) error {
 		v := make([]byte, 0, 32)
-		v = strconv.AppendInt(v, dummyInt, 10)
+		v = strconv.AppendInt(v, sessionForEvent(field.Name, length, state).step, 10)
 		buf.Write(v)
 		return nil
 	}
 
-	fieldMap[field.Name] = emitFNotReturn
+	var emitFNotReturnUserName emitFNotReturn
+	emitFNotReturnUserName = func(state *genState, buf *bytes.Buffer) error {
+		buf.WriteString(sessionForEvent(field.Name, length, state).userName)
+		return nil
+	}
+
+	fieldMap[field.Name+sessionIDSuffix] = emitFNotReturnID
+	fieldMap[field.Name+sessionStepSuffix] = emitFNotReturnStep
+	fieldMap[field.Name+sessionUserNameSuffix] = emitFNotReturnUserName
+	return nil
+}
+
+func bindEventCategorization(fieldCfg ConfigField, field Field, fieldMap map[string]any) error {
+	categories, err := eventCategories(fieldCfg)
+	if err != nil {
+		return err
+	}
+
+	var emitFNotReturnCategory emitFNotReturn
+	emitFNotReturnCategory = func(state *genState, buf *bytes.Buffer) error {
+		buf.WriteString(eventCategorizationForEvent(field.Name, categories, state).category)
+		return nil
+	}
+
+	var emitFNotReturnType emitFNotReturn
+	emitFNotReturnType = func(state *genState, buf *bytes.Buffer) error {
+		buf.WriteString(eventCategorizationForEvent(field.Name, categories, state).eventType)
+		return nil
+	}
+
+	var emitFNotReturnAction emitFNotReturn
+	emitFNotReturnAction = func(state *genState, buf *bytes.Buffer) error {
+		buf.WriteString(eventCategorizationForEvent(field.Name, categories, state).action)
+		return nil
+	}
+
+	fieldMap[field.Name+eventCategorySuffix] = emitFNotReturnCategory
+	fieldMap[field.Name+eventTypeSuffix] = emitFNotReturnType
+	fieldMap[field.Name+eventActionSuffix] = emitFNotReturnAction
+	return nil
+}
+
+func bindUser(fieldCfg ConfigField, field Field, fieldMap map[string]any) error {
+	poolSize, err := userPoolSize(fieldCfg, field)
+	if err != nil {
+		return err
+	}
+
+	var emitFNotReturnID emitFNotReturn
+	emitFNotReturnID = func(state *genState, buf *bytes.Buffer) error {
+		buf.WriteString(userForEvent(field.Name, fieldCfg, poolSize, state).id)
+		return nil
+	}
+
+	var emitFNotReturnName emitFNotReturn
+	emitFNotReturnName = func(state *genState, buf *bytes.Buffer) error {
+		buf.WriteString(userForEvent(field.Name, fieldCfg, poolSize, state).name)
+		return nil
+	}
+
+	var emitFNotReturnGroupName emitFNotReturn
+	emitFNotReturnGroupName = func(state *genState, buf *bytes.Buffer) error {
+		buf.WriteString(userForEvent(field.Name, fieldCfg, poolSize, state).groupName)
+		return nil
+	}
+
+	fieldMap[field.Name+userIDSuffix] = emitFNotReturnID
+	fieldMap[field.Name+userNameSuffix] = emitFNotReturnName
+	fieldMap[field.Name+userGroupNameSuffix] = emitFNotReturnGroupName
+	return nil
+}
+
+func bindWebTransaction(field Field, fieldMap map[string]any) error {
+	var emitFNotReturnMethod emitFNotReturn
+	emitFNotReturnMethod = func(state *genState, buf *bytes.Buffer) error {
+		buf.WriteString(webTransactionForEvent(field.Name, state).method)
+		return nil
+	}
+
+	var emitFNotReturnRequestBytes emitFNotReturn
+	emitFNotReturnRequestBytes = func(state *genState, buf *bytes.Buffer) error {
+		buf.WriteString(strconv.FormatInt(webTransactionForEvent(field.Name, state).requestBytes, 10))
+		return nil
+	}
+
+	var emitFNotReturnStatusCode emitFNotReturn
+	emitFNotReturnStatusCode = func(state *genState, buf *bytes.Buffer) error {
+		buf.WriteString(strconv.Itoa(webTransactionForEvent(field.Name, state).statusCode))
+		return nil
+	}
+
+	var emitFNotReturnResponseBytes emitFNotReturn
+	emitFNotReturnResponseBytes = func(state *genState, buf *bytes.Buffer) error {
+		buf.WriteString(strconv.FormatInt(webTransactionForEvent(field.Name, state).responseBytes, 10))
+		return nil
+	}
+
+	var emitFNotReturnDuration emitFNotReturn
+	emitFNotReturnDuration = func(state *genState, buf *bytes.Buffer) error {
+		buf.WriteString(strconv.FormatInt(webTransactionForEvent(field.Name, state).duration, 10))
+		return nil
+	}
+
+	fieldMap[field.Name+webRequestMethodSuffix] = emitFNotReturnMethod
+	fieldMap[field.Name+webRequestBytesSuffix] = emitFNotReturnRequestBytes
+	fieldMap[field.Name+webResponseStatusSuffix] = emitFNotReturnStatusCode
+	fieldMap[field.Name+webResponseBytesSuffix] = emitFNotReturnResponseBytes
+	fieldMap[webTransactionEventPrefix(field.Name)+webTransactionDurationField] = emitFNotReturnDuration
+	return nil
+}
+
+func bindValidity(fieldCfg ConfigField, field Field, fieldMap map[string]any) error {
+	if err := fieldCfg.ValidForDateField(); err != nil {
+		return err
+	}
+
+	validity, err := validityWindow(fieldCfg, field)
+	if err != nil {
+		return err
+	}
+
+	layout := dateLayout(fieldCfg)
+
+	var emitFNotReturnNotBefore emitFNotReturn
+	emitFNotReturnNotBefore = func(state *genState, buf *bytes.Buffer) error {
+		buf.WriteString(formatDate(validityNotBeforeForEvent(field.Name, fieldCfg, state), layout))
+		return nil
+	}
+
+	var emitFNotReturnNotAfter emitFNotReturn
+	emitFNotReturnNotAfter = func(state *genState, buf *bytes.Buffer) error {
+		buf.WriteString(formatDate(validityNotBeforeForEvent(field.Name, fieldCfg, state).Add(validity), layout))
+		return nil
+	}
+
+	fieldMap[field.Name+validityNotBeforeSuffix] = emitFNotReturnNotBefore
+	fieldMap[field.Name+validityNotAfterSuffix] = emitFNotReturnNotAfter
+	return nil
+}
+
+func bindCIDR(fieldCfg ConfigField, field Field, fieldMap map[string]any) error {
+	cidrFunc, err := makeCIDRFunc(fieldCfg)
+	if err != nil {
+		return err
+	}
+
+	var emitFNotReturn emitFNotReturn
+	emitFNotReturn = func(state *genState, buf *bytes.Buffer) error {
+		buf.WriteString(cidrFunc(state.rand))
+		return nil
+	}
+
+	fieldMap[field.Name] = emitFNotReturn
+	return nil
+}
+
+func bindULID(field Field, fieldMap map[string]any) error {
+	var emitFNotReturn emitFNotReturn
+	emitFNotReturn = func(state *genState, buf *bytes.Buffer) error {
+		buf.WriteString(ulidForEvent(field.Name, state))
+		return nil
+	}
+
+	fieldMap[field.Name] = emitFNotReturn
+	return nil
+}
+
+func bindUUID(fieldCfg ConfigField, field Field, fieldMap map[string]any) error {
+	version, err := uuidVersion(fieldCfg, field)
+	if err != nil {
+		return err
+	}
+
+	var emitFNotReturn emitFNotReturn
+	emitFNotReturn = func(state *genState, buf *bytes.Buffer) error {
+		buf.WriteString(uuidForEvent(field.Name, version, state))
+		return nil
+	}
+
+	fieldMap[field.Name] = emitFNotReturn
+	return nil
+}
+
+func bindHexToken(fieldCfg ConfigField, field Field, fieldMap map[string]any) error {
+	length, err := hexTokenLength(fieldCfg, field)
+	if err != nil {
+		return err
+	}
+
+	var emitFNotReturn emitFNotReturn
+	emitFNotReturn = func(state *genState, buf *bytes.Buffer) error {
+		buf.WriteString(randStringFromAlphabet(state.rand, hexAlphabet, length))
+		return nil
+	}
+
+	fieldMap[field.Name] = emitFNotReturn
+	return nil
+}
+
+func bindWordN(field Field, n int, fieldMap map[string]any) error {
+	var emitFNotReturn emitFNotReturn
+	emitFNotReturn = func(state *genState, buf *bytes.Buffer) error {
+		genNounsN(state.rand.Intn(n), buf)
+		return nil
+	}
+
+	fieldMap[field.Name] = emitFNotReturn
+	return nil
+}
+
+func bindNearTime(fieldCfg ConfigField, field Field, fieldMap map[string]any) error {
+	if err := fieldCfg.ValidForDateField(); err != nil {
+		return err
+	}
+
+	layout := dateLayout(fieldCfg)
+	if fieldCfg.Mode == config.DateModeAligned {
+		base := alignedTimeBase(fieldCfg)
+
+		var emitFNotReturn emitFNotReturn
+		emitFNotReturn = func(state *genState, buf *bytes.Buffer) error {
+			buf.WriteString(formatDate(alignedTime(base, fieldCfg, state), layout))
+			return nil
+		}
+		fieldMap[field.Name] = emitFNotReturn
+		return nil
+	}
+
+	if fieldCfg.Mode == config.DateModeMonotonic {
+		base := alignedTimeBase(fieldCfg)
+
+		var emitFNotReturn emitFNotReturn
+		emitFNotReturn = func(state *genState, buf *bytes.Buffer) error {
+			buf.WriteString(formatDate(monotonicTime(base, field, fieldCfg, state), layout))
+			return nil
+		}
+		fieldMap[field.Name] = emitFNotReturn
+		return nil
+	}
+
+	var emitFNotReturn emitFNotReturn
+	emitFNotReturn = func(state *genState, buf *bytes.Buffer) error {
+		newTime := nearTime(fieldCfg, state)
+
+		buf.WriteString(formatDate(newTime, layout))
+		return nil
+	}
+	fieldMap[field.Name] = emitFNotReturn
+	return nil
+}
+
+// alignedTimeBase returns the `base` of an `aligned` or `monotonic` date field, defaulting to the time now the generator is bound at
+func alignedTimeBase(fieldCfg ConfigField) time.Time {
+	if fieldCfg.Base != nil {
+		return fieldCfg.Base.Time
+	}
+
+	return timeNowToBind
+}
+
+// alignedTime returns the date of the current event for an `aligned` date field, regardless the wall clock
+func alignedTime(base time.Time, fieldCfg ConfigField, state *genState) time.Time {
+	return base.Add(fieldCfg.Interval*time.Duration(state.alignedStep()) + jitter(state.rand, fieldCfg))
+}
+
+// monotonicTime returns the date of the current event for a `monotonic` date field: `base` for the first event, then the
+// date of the previous event, kept in the state, plus a random delta between 0 and `max_delta`
+func monotonicTime(base time.Time, field Field, fieldCfg ConfigField, state *genState) time.Time {
+	maxDelta := fieldCfg.MaxDelta
+	if maxDelta == 0 {
+		maxDelta = defaultMonotonicMaxDelta
+	}
+
+	newTime := base
+	if previous, ok := state.prevCache[field.Name].(time.Time); ok {
+		newTime = previous.Add(time.Duration(state.rand.Int63n(int64(maxDelta) + 1)))
+	}
+
+	state.prevCache[field.Name] = newTime
+	return newTime
+}
+
+// jitter returns a random offset within ±`jitter`, drawn from `jitter_distribution`
+func jitter(r *rand.Rand, fieldCfg ConfigField) time.Duration {
+	if fieldCfg.Jitter <= 0 {
+		return 0
+	}
+
+	if fieldCfg.JitterDistribution == config.JitterDistributionNormal {
+		// ±`jitter` is three standard deviations: clamp the rare values outside it
+		offset := r.NormFloat64() * float64(fieldCfg.Jitter) / 3
+		offset = math.Max(-float64(fieldCfg.Jitter), math.Min(float64(fieldCfg.Jitter), offset))
+		return time.Duration(offset)
+	}
+
+	return time.Duration(r.Int63n(2*int64(fieldCfg.Jitter)+1)) - fieldCfg.Jitter
+}
+
+func nearTime(fieldCfg ConfigField, state *genState) time.Time {
+	var offset time.Duration
+	from, errFrom := fieldCfg.Range.FromAsTime()
+	to, errTo := fieldCfg.Range.ToAsTime()
+	if errFrom == nil && errTo == nil {
+		timeNowToBind = from
+		fieldCfg.Period = to.UTC().Sub(from.UTC())
+	}
+
+	if errFrom == nil && errTo != nil {
+		if from.UTC().After(timeNowToBind.UTC()) {
+			fieldCfg.Period = from.UTC().Sub(timeNowToBind.UTC())
+		} else {
+			fieldCfg.Period = timeNowToBind.UTC().Sub(from.UTC())
+		}
+
+	}
+
+	if errFrom != nil && errTo == nil {
+		if to.UTC().After(timeNowToBind.UTC()) {
+			fieldCfg.Period = to.UTC().Sub(timeNowToBind.UTC())
+		} else {
+			fieldCfg.Period = timeNowToBind.UTC().Sub(to.UTC())
+		}
+	}
+
+	if fieldCfg.Period > 0 && state.totEvents > 0 {
+		offset = time.Duration((fieldCfg.Period.Nanoseconds() / int64(state.totEvents)) * int64(state.counter))
+	} else if fieldCfg.Period < 0 && state.totEvents > 0 {
+		offset = time.Duration((fieldCfg.Period.Nanoseconds() / int64(state.totEvents)) * (int64(state.totEvents - state.counter)))
+	} else {
+		offset = time.Duration(state.rand.Intn(FieldTypeDurationSpan)) * time.Millisecond
+	}
+
+	newTime := timeNowToBind.Add(offset)
+
+	if state.totEvents <= 0 {
+		timeNowToBind = newTime
+	}
+
+	return newTime
+}
+
+func bindIP(fieldCfg ConfigField, field Field, fieldMap map[string]any) error {
+	ipFunc, err := makeIPFunc(fieldCfg)
+	if err != nil {
+		return err
+	}
+
+	var emitFNotReturn emitFNotReturn
+	emitFNotReturn = func(state *genState, buf *bytes.Buffer) error {
+		buf.WriteString(ipFunc(state.rand))
+		return nil
+	}
+
+	fieldMap[field.Name] = emitFNotReturn
+
+	return nil
+}
+
+func fuzzyInt(r *rand.Rand, previous int64, fuzziness, min, max float64) int64 {
+	lowerBound := float64(previous) * (1 - fuzziness)
+	higherBound := float64(previous) * (1 + fuzziness)
+	lowerBound = math.Max(lowerBound, min)
+	higherBound = math.Min(higherBound, max)
+	return r.Int63n(int64(math.Ceil(higherBound-lowerBound))) + int64(lowerBound)
+}
+
+func bindLong(fieldCfg ConfigField, field Field, fieldMap map[string]any) error {
+	dummyFunc := makeIntFunc(fieldCfg, field)
+
+	if fieldCfg.Fuzziness <= 0 {
+		var emitFNotReturn emitFNotReturn
+		emitFNotReturn = func(state *genState, buf *bytes.Buffer) error {
+			v := make([]byte, 0, 32)
+			v = strconv.AppendInt(v, dummyFunc(state.rand), 10)
+			buf.Write(v)
+			return nil
+		}
+
+		fieldMap[field.Name] = emitFNotReturn
+
+		return nil
+	}
+
+	min, max := numericBounds(fieldCfg, float64(integerTypeMin(field.Type)), float64(integerTypeMax(field.Type)))
+
+	var emitFNotReturn emitFNotReturn
+	emitFNotReturn = func(state *genState, buf *bytes.Buffer) error {
+		var dummyInt int64
+		if previousDummyInt, ok := state.prevCache[field.Name].(int64); ok {
+			if previousDummyInt == 0 {
+				previousDummyInt = 1
+			}
+			dummyInt = fuzzyInt(state.rand, previousDummyInt, fieldCfg.Fuzziness, min, max)
+		} else {
+			dummyInt = dummyFunc(state.rand)
+		}
+		state.prevCache[field.Name] = dummyInt
+		v := make([]byte, 0, 32)
+		v = strconv.AppendInt(v, dummyInt, 10)
+		buf.Write(v)
+		return nil
+	}
+
+	fieldMap[field.Name] = emitFNotReturn
+	return nil
+}
+
+func bindUnsignedLong(fieldCfg ConfigField, field Field, fieldMap map[string]any) error {
+	dummyFunc := makeUnsignedLongFunc(fieldCfg, field)
+
+	if fieldCfg.Fuzziness <= 0 {
+		var emitFNotReturn emitFNotReturn
+		emitFNotReturn = func(state *genState, buf *bytes.Buffer) error {
+			v := make([]byte, 0, 32)
+			v = strconv.AppendUint(v, dummyFunc(state.rand), 10)
+			buf.Write(v)
+			return nil
+		}
+
+		fieldMap[field.Name] = emitFNotReturn
+
+		return nil
+	}
+
+	min, _ := fieldCfg.Range.MinAsUint64()
+	max, _ := fieldCfg.Range.MaxAsUint64()
+
+	var emitFNotReturn emitFNotReturn
+	emitFNotReturn = func(state *genState, buf *bytes.Buffer) error {
+		var dummyUint uint64
+		if previousDummyUint, ok := state.prevCache[field.Name].(uint64); ok {
+			if previousDummyUint == 0 {
+				previousDummyUint = 1
+			}
+			dummyUint = fuzzyUnsignedLong(state.rand, previousDummyUint, fieldCfg.Fuzziness, min, max)
+		} else {
+			dummyUint = dummyFunc(state.rand)
+		}
+		state.prevCache[field.Name] = dummyUint
+		v := make([]byte, 0, 32)
+		v = strconv.AppendUint(v, dummyUint, 10)
+		buf.Write(v)
+		return nil
+	}
+
+	fieldMap[field.Name] = emitFNotReturn
+	return nil
+}
+
+func fuzzyFloat(r *rand.Rand, previous, fuzziness, min, max float64) float64 {
+	lowerBound := previous * (1 - fuzziness)
+	higherBound := previous * (1 + fuzziness)
+	// for negative values the bounds are swapped
+	if lowerBound > higherBound {
+		lowerBound, higherBound = higherBound, lowerBound
+	}
+
+	lowerBound = math.Max(lowerBound, min)
+	higherBound = math.Min(higherBound, max)
+	return lowerBound + r.Float64()*(higherBound-lowerBound)
+}
+
+func bindDouble(fieldCfg ConfigField, field Field, fieldMap map[string]any) error {
+	dummyFunc := makeFloatFunc(fieldCfg, field)
+
+	if fieldCfg.Fuzziness <= 0 {
+		var emitFNotReturn emitFNotReturn
+		emitFNotReturn = func(state *genState, buf *bytes.Buffer) error {
+			dummyFloat := scaledFloat(field, dummyFunc(state.rand))
+			buf.Write(appendFieldDouble(make([]byte, 0, 32), field, dummyFloat, fieldCfg.OmitIntegerDecimals))
+			return nil
+		}
+
+		fieldMap[field.Name] = emitFNotReturn
+		return nil
+	}
+
+	min, max := numericBounds(fieldCfg, -math.MaxFloat64, math.MaxFloat64)
+
+	var emitFNotReturn emitFNotReturn
+	emitFNotReturn = func(state *genState, buf *bytes.Buffer) error {
+		var dummyFloat float64
+		if previousDummyFloat, ok := state.prevCache[field.Name].(float64); ok {
+			dummyFloat = fuzzyFloat(state.rand, previousDummyFloat, fieldCfg.Fuzziness, min, max)
+		} else {
+			dummyFloat = dummyFunc(state.rand)
+		}
+		dummyFloat = scaledFloat(field, dummyFloat)
+		state.prevCache[field.Name] = dummyFloat
+		buf.Write(appendFieldDouble(make([]byte, 0, 32), field, dummyFloat, fieldCfg.OmitIntegerDecimals))
+
+		return nil
+	}
+
+	fieldMap[field.Name] = emitFNotReturn
+	return nil
+}
+
+// fieldCardinality returns the number of distinct values generated for a field with `cardinality`: for a field
+// with `enum` it is limited to the values that can be emitted, those with a positive weight in the active subset
+func fieldCardinality(fieldCfg ConfigField) int {
+	cardinality := fieldCfg.Cardinality
+	if len(fieldCfg.Enum) == 0 {
+		return cardinality
+	}
+
+	distinct := make(map[string]struct{}, len(fieldCfg.Enum))
+	for idx, value := range fieldCfg.Enum {
+		if len(fieldCfg.EnumWeights) == 0 || fieldCfg.EnumWeights[idx] > 0 {
+			distinct[value] = struct{}{}
+		}
+	}
+
+	emittable := len(distinct)
+	if fieldCfg.ActiveSubset > 0 && fieldCfg.ActiveSubset < emittable {
+		emittable = fieldCfg.ActiveSubset
+	}
+
+	if cardinality > emittable {
+		return emittable
+	}
+
+	return cardinality
+}
+
+// cardinalityIndex returns the index into the values of a field with `cardinality` for the current event, or element
+// of an array field: the fields
+// in a `cardinality_group` share the index, cycling through the highest cardinality of the group, so that they vary
+// together (ie: the same `source.ip` always comes with the same few `source.port`)
+func cardinalityIndex(state *genState, cardinality, groupSize int) int {
+	idx := state.counter
+	// each element of an array gets its own index, so that the elements of an event are distinct
+	if state.arrayMax > 0 {
+		idx = idx*uint64(state.arrayMax) + uint64(state.arrayElement)
+	}
+
+	if groupSize > 0 {
+		idx %= uint64(groupSize)
+	}
+
+	return int(idx % uint64(cardinality))
+}
+
+func bindCardinality(cfg Config, field Field, fieldMap map[string]any) error {
+
+	fieldCfg, _ := cfg.GetField(field.Name)
+	cardinality := fieldCardinality(fieldCfg)
+	var groupSize int
+	if len(fieldCfg.CardinalityGroup) > 0 {
+		groupSize = cfg.CardinalityGroupSize(fieldCfg.CardinalityGroup)
+	}
+
+	if strings.HasSuffix(field.Name, ".*") {
+		field.Name = replacer.Replace(field.Name)
+	}
+
+	// Go ahead and bind the original field
+	if err := bindByType(cfg, field, fieldMap); err != nil {
+		return err
+	}
+
+	// We will wrap the function we just generated
+	boundF, ok := fieldMap[field.Name].(emitFNotReturn)
+	if !ok {
+		return errors.New("cannot bind cardinality")
+	}
+
+	var emitFNotReturn emitFNotReturn
+	emitFNotReturn = func(state *genState, buf *bytes.Buffer) error {
+		// Generate the distinct values up front, the first time the field is emitted
+		if len(state.prevCacheCardinality[field.Name]) == 0 {
+			values := make([]any, 0, cardinality)
+			var tmp bytes.Buffer
+			for len(values) < cardinality {
+				found := false
+				for i := 0; i < uniqueMaxTries; i++ {
+					tmp.Reset()
+					if err := boundF(state, &tmp); err != nil {
+						return err
+					}
+
+					value := tmp.String()
+					if !isDupeAny(state.prevCacheForDup[field.Name], value) {
+						state.prevCacheForDup[field.Name][value] = struct{}{}
+						values = append(values, []byte(value))
+						found = true
+						break
+					}
+				}
+
+				// the values space of the field is narrower than the cardinality: it is capped at the values found
+				if !found {
+					break
+				}
+			}
+
+			state.prevCacheCardinality[field.Name] = values
+		}
+
+		values := state.prevCacheCardinality[field.Name]
+
+		buf.Write(values[cardinalityIndex(state, len(values), groupSize)].([]byte))
+		return nil
+	}
+
+	fieldMap[field.Name] = emitFNotReturn
+	return nil
+}
+
+func bindUnique(cfg Config, field Field, fieldMap map[string]any) error {
+	// Go ahead and bind the original field
+	if err := bindByType(cfg, field, fieldMap); err != nil {
+		return err
+	}
+
+	// We will wrap the function we just generated
+	boundF, ok := fieldMap[field.Name].(emitFNotReturn)
+	if !ok {
+		return errors.New("cannot bind unique")
+	}
+
+	var emitFNotReturn emitFNotReturn
+	emitFNotReturn = func(state *genState, buf *bytes.Buffer) error {
+		var tmp bytes.Buffer
+		for i := 0; i < uniqueMaxTries; i++ {
+			tmp.Reset()
+			if err := boundF(state, &tmp); err != nil {
+				return err
+			}
+
+			value := tmp.String()
+			if !isDupeAny(state.prevCacheForDup[field.Name], value) {
+				state.prevCacheForDup[field.Name][value] = struct{}{}
+				buf.Write(tmp.Bytes())
+				return nil
+			}
+		}
+
+		return fmt.Errorf("%w for field %s after %d tries", uniqueValuesExhausted, field.Name, uniqueMaxTries)
+	}
+
+	fieldMap[field.Name] = emitFNotReturn
+	return nil
+}
+
+func makeDynamicStub(boundF any) emitFNotReturn {
+	return func(state *genState, buf *bytes.Buffer) error {
+		v := state.pool.Get()
+		tmp := v.(*bytes.Buffer)
+		tmp.Reset()
+		defer state.pool.Put(tmp)
+
+		// Fire the bound function, write into temp buffer
+		err := boundF.(emitFNotReturn)(state, tmp)
+		if err != nil {
+			return err
+		}
+
+		// If bound function did not write for some reason; abort
+		if tmp.Len() == 0 {
+			return nil
+		}
+
+		// ok, formatted as expected, swap it out the payload
+		buf.Write(tmp.Bytes())
+		return nil
+	}
+}
+
+func makeDynamicStubWithReturn(boundF any) emitF {
+	return func(state *genState) any {
+		return boundF.(emitF)(state)
+	}
+}
+
+func bindConstantKeywordWithReturn(field Field, fieldMap map[string]any) error {
+	var emitF emitF
+	emitF = func(state *genState) any {
+		value, ok := state.prevCache[field.Name].(string)
+		if !ok {
+			// randomdata.Adjective() + randomdata.Noun() -> 364 * 527 (~190k) different values
+			value = randomdata.Adjective() + randomdata.Noun()
+			state.prevCache[field.Name] = value
+		}
+		return value
+	}
+
+	fieldMap[field.Name] = emitF
+	return nil
+}
+
+func bindKeywordWithReturn(fieldCfg ConfigField, field Field, fieldMap map[string]any) error {
+	if err := fieldCfg.ValidForDistribution(); err != nil {
+		return err
+	}
+
+	if len(fieldCfg.Enum) > 0 {
+		fieldCfg = withActiveEnumSubset(fieldCfg)
+		enumIndex := makeEnumIndexFunc(fieldCfg)
+
+		var emitF emitF
+		emitF = func(state *genState) any {
+			idx := enumIndex(state.rand)
+			return fieldCfg.Enum[idx]
+		}
+
+		fieldMap[field.Name] = emitF
+	} else if len(fieldCfg.Pattern) > 0 {
+		pattern, err := makePatternFunc(fieldCfg.Pattern)
+		if err != nil {
+			return fmt.Errorf("field %s has an invalid pattern: %w", field.Name, err)
+		}
+
+		var emitF emitF
+		emitF = func(state *genState) any {
+			var buf bytes.Buffer
+			pattern(state.rand, &buf)
+			return buf.String()
+		}
+
+		fieldMap[field.Name] = emitF
+	} else if len(field.Example) > 0 {
+		totWords, joiner := totWordsAndJoiner(field.Example)
+
+		return bindJoinRandWithReturn(field, totWords, joiner, fieldMap)
+	} else {
+		var emitF emitF
+		emitF = func(state *genState) any {
+			// randomdata.Adjective() + randomdata.Noun() -> 364 * 527 (~190k) different values
+			return randomdata.Adjective() + randomdata.Noun()
+		}
+
+		fieldMap[field.Name] = emitF
+	}
+	return nil
+}
+
+func bindJoinRandWithReturn(field Field, N int, joiner string, fieldMap map[string]any) error {
+	var emitF emitF
+	emitF = func(state *genState) any {
+		value := ""
+		for i := 0; i < N-1; i++ {
+			value += randomdata.Noun() + joiner
+		}
+
+		// randomdata.Adjective() + randomdata.Noun() -> 364 * 527 (~190k) different values
+		value += randomdata.Adjective()
+		value += randomdata.Noun()
+
+		return value
+	}
+
+	fieldMap[field.Name] = emitF
+	return nil
+}
+
+func bindStaticWithReturn(field Field, v any, fieldMap map[string]any) error {
+	var emitF emitF
+	emitF = func(state *genState) any {
+		return v
+	}
+
+	fieldMap[field.Name] = emitF
+	return nil
+}
+
+func bindRawJSONWithReturn(field Field, rawJSON string, fieldMap map[string]any) error {
+	if !json.Valid([]byte(rawJSON)) {
+		return fmt.Errorf("%w: %s", rawJSONNotValid, field.Name)
+	}
+
+	var emitF emitF
+	emitF = func(state *genState) any {
+		return rawJSON
+	}
+
+	fieldMap[field.Name] = emitF
+	return nil
+}
+
+func bindBoolWithReturn(field Field, fieldMap map[string]any) error {
+	var emitF emitF
+	emitF = func(state *genState) any {
+		switch state.rand.Int() % 2 {
+		case 0:
+			return false
+		default:
+			return true
+		}
+	}
+
+	fieldMap[field.Name] = emitF
+	return nil
+}
+
+func bindGeoPointWithReturn(fieldCfg ConfigField, field Field, fieldMap map[string]any) error {
+	geoPointFunc, err := makeGeoPointFunc(fieldCfg)
+	if err != nil {
+		return err
+	}
+
+	var emitF emitF
+	emitF = func(state *genState) any {
+		return geoPointFunc(state.rand)
+	}
+
+	fieldMap[field.Name] = emitF
+
+	return nil
+}
+
+func bindTextWithReturn(fieldCfg ConfigField, field Field, fieldMap map[string]any) error {
+	minWords, maxWords, err := textWords(fieldCfg, field)
+	if err != nil {
+		return err
+	}
+
+	var emitF emitF
+	emitF = func(state *genState) any {
+		var buf bytes.Buffer
+		genText(state.rand, minWords, maxWords, fieldCfg.Punctuation, &buf)
+		return buf.String()
+	}
+
+	fieldMap[field.Name] = emitF
+
+	return nil
+}
+
+func bindGeoShapeWithReturn(fieldCfg ConfigField, field Field, fieldMap map[string]any) error {
+	geoShapeFunc, err := makeGeoShapeFunc(fieldCfg)
+	if err != nil {
+		return err
+	}
+
+	var emitF emitF
+	emitF = func(state *genState) any {
+		return geoShapeFunc(state.rand)
+	}
+
+	fieldMap[field.Name] = emitF
+
+	return nil
+}
+
+func bindPointWithReturn(fieldCfg ConfigField, field Field, fieldMap map[string]any) error {
+	pointFunc, err := makePointFunc(fieldCfg)
+	if err != nil {
+		return err
+	}
+
+	var emitF emitF
+	emitF = func(state *genState) any {
+		return pointFunc(state.rand)
+	}
+
+	fieldMap[field.Name] = emitF
+
+	return nil
+}
+
+func bindHostnameWithReturn(field Field, fieldMap map[string]any) error {
+	var emitF emitF
+	emitF = func(state *genState) any {
+		return randHostname(state.rand)
+	}
+
+	fieldMap[field.Name] = emitF
+	return nil
+}
+
+func bindMoneyWithReturn(fieldCfg ConfigField, field Field, fieldMap map[string]any) error {
+	currencies, err := moneyCurrencies(fieldCfg)
+	if err != nil {
+		return err
+	}
+
+	amountFunc := makeFloatFunc(fieldCfg, field)
+
+	var emitFCurrency emitF
+	emitFCurrency = func(state *genState) any {
+		return moneyCurrencyForEvent(field.Name, currencies, state)
+	}
+
+	var emitFAmount emitF
+	emitFAmount = func(state *genState) any {
+		currency := moneyCurrencyForEvent(field.Name, currencies, state)
+		// json.Number keeps the precision of the currency when rendered in the template
+		return json.Number(strconv.FormatFloat(amountFunc(state.rand), 'f', currencyMinorUnits[currency], 64))
+	}
+
+	fieldMap[field.Name+moneyCurrencySuffix] = emitFCurrency
+	fieldMap[field.Name+moneyAmountSuffix] = emitFAmount
+	return nil
+}
+
+func bindASNWithReturn(fieldCfg ConfigField, field Field, fieldMap map[string]any) error {
+	asns := asnTable(fieldCfg)
+
+	var emitFNumber emitF
+	emitFNumber = func(state *genState) any {
+		return asnForEvent(field.Name, asns, state).Number
+	}
+
+	var emitFOrganizationName emitF
+	emitFOrganizationName = func(state *genState) any {
+		return asnForEvent(field.Name, asns, state).Organization
+	}
+
+	fieldMap[field.Name+asnNumberSuffix] = emitFNumber
+	fieldMap[field.Name+asnOrganizationNameSuffix] = emitFOrganizationName
+	return nil
+}
+
+func bindPersonNameWithReturn(fieldCfg ConfigField, field Field, fieldMap map[string]any) error {
+	pool, err := personNamePoolForLocale(fieldCfg)
+	if err != nil {
+		return err
+	}
+
+	var emitF emitF
+	emitF = func(state *genState) any {
+		return randPersonName(state.rand, pool)
+	}
+
+	fieldMap[field.Name] = emitF
+	return nil
+}
+
+func bindPathWithReturn(fieldCfg ConfigField, field Field, fieldMap map[string]any) error {
+	pathFunc, err := makePathFunc(fieldCfg)
+	if err != nil {
+		return err
+	}
+
+	var emitF emitF
+	emitF = func(state *genState) any {
+		return pathFunc(state.rand)
+	}
+
+	fieldMap[field.Name] = emitF
+	return nil
+}
+
+func bindRegistryPathWithReturn(fieldCfg ConfigField, field Field, fieldMap map[string]any) error {
+	registryPathFunc, err := makeRegistryPathFunc(fieldCfg)
+	if err != nil {
+		return err
+	}
+
+	var emitF emitF
+	emitF = func(state *genState) any {
+		return registryPathFunc(state.rand)
+	}
+
+	fieldMap[field.Name] = emitF
+	return nil
+}
+
+func bindSQLStatementWithReturn(fieldCfg ConfigField, field Field, fieldMap map[string]any) error {
+	sqlStatementFunc, err := makeSQLStatementFunc(fieldCfg)
+	if err != nil {
+		return err
+	}
+
+	var emitF emitF
+	emitF = func(state *genState) any {
+		return sqlStatementFunc(state.rand)
+	}
+
+	fieldMap[field.Name] = emitF
+	return nil
+}
+
+func bindOSWithReturn(field Field, fieldMap map[string]any) error {
+	var emitFName emitF
+	emitFName = func(state *genState) any {
+		return osForEvent(field.Name, state).name
+	}
+
+	var emitFVersion emitF
+	emitFVersion = func(state *genState) any {
+		return osForEvent(field.Name, state).version
+	}
+
+	var emitFFamily emitF
+	emitFFamily = func(state *genState) any {
+		return osForEvent(field.Name, state).family
+	}
+
+	fieldMap[field.Name+osNameSuffix] = emitFName
+	fieldMap[field.Name+osVersionSuffix] = emitFVersion
+	fieldMap[field.Name+osFamilySuffix] = emitFFamily
+	return nil
+}
+
+func bindProcessWithReturn(field Field, fieldMap map[string]any) error {
+	var emitFPID emitF
+	emitFPID = func(state *genState) any {
+		return processForEvent(field.Name, state).pid
+	}
+
+	var emitFName emitF
+	emitFName = func(state *genState) any {
+		return processForEvent(field.Name, state).name
+	}
+
+	var emitFParentPID emitF
+	emitFParentPID = func(state *genState) any {
+		return processForEvent(field.Name, state).parentPID
+	}
+
+	var emitFParentName emitF
+	emitFParentName = func(state *genState) any {
+		return processForEvent(field.Name, state).parentName
+	}
+
+	fieldMap[field.Name+processPIDSuffix] = emitFPID
+	fieldMap[field.Name+processNameSuffix] = emitFName
+	fieldMap[field.Name+processParentPIDSuffix] = emitFParentPID
+	fieldMap[field.Name+processParentNameSuffix] = emitFParentName
+	return nil
+}
+
+func bindCloudWithReturn(field Field, fieldMap map[string]any) error {
+	var emitFProvider emitF
+	emitFProvider = func(state *genState) any {
+		return cloudForEvent(field.Name, state).provider
+	}
+
+	var emitFAccountID emitF
+	emitFAccountID = func(state *genState) any {
+		return cloudForEvent(field.Name, state).accountID
+	}
+
+	var emitFRegion emitF
+	emitFRegion = func(state *genState) any {
+		return cloudForEvent(field.Name, state).region
+	}
+
+	var emitFAvailabilityZone emitF
+	emitFAvailabilityZone = func(state *genState) any {
+		return cloudForEvent(field.Name, state).availabilityZone
+	}
+
+	fieldMap[field.Name+cloudProviderSuffix] = emitFProvider
+	fieldMap[field.Name+cloudAccountIDSuffix] = emitFAccountID
+	fieldMap[field.Name+cloudRegionSuffix] = emitFRegion
+	fieldMap[field.Name+cloudAvailabilityZoneSuffix] = emitFAvailabilityZone
+	return nil
+}
+
+func bindKubernetesWithReturn(field Field, fieldMap map[string]any) error {
+	var emitFNamespace emitF
+	emitFNamespace = func(state *genState) any {
+		return kubernetesForEvent(field.Name, state).namespace
+	}
+
+	var emitFDeploymentName emitF
+	emitFDeploymentName = func(state *genState) any {
+		return kubernetesForEvent(field.Name, state).deploymentName
+	}
+
+	var emitFPodName emitF
+	emitFPodName = func(state *genState) any {
+		return kubernetesForEvent(field.Name, state).podName
+	}
+
+	var emitFContainerID emitF
+	emitFContainerID = func(state *genState) any {
+		return kubernetesForEvent(field.Name, state).containerID
+	}
+
+	var emitFContainerImageName emitF
+	emitFContainerImageName = func(state *genState) any {
+		return kubernetesForEvent(field.Name, state).containerImage
+	}
+
+	containerPrefix := kubernetesContainerPrefix(field.Name)
+	fieldMap[field.Name+kubernetesNamespaceSuffix] = emitFNamespace
+	fieldMap[field.Name+kubernetesDeploymentNameSuffix] = emitFDeploymentName
+	fieldMap[field.Name+kubernetesPodNameSuffix] = emitFPodName
+	fieldMap[containerPrefix+containerIDField] = emitFContainerID
+	fieldMap[containerPrefix+containerImageNameField] = emitFContainerImageName
 	return nil
 }
 
-func fuzzyFloat(previous, fuzziness, min, max float64) float64 {
-	lowerBound := previous * (1 - fuzziness)
-	higherBound := previous * (1 + fuzziness)
-	lowerBound = math.Max(lowerBound, min)
-	higherBound = math.Min(higherBound, max)
-	return lowerBound + customRand.Float64()*(higherBound-lowerBound)
-}
+func bindDNSWithReturn(field Field, fieldMap map[string]any) error {
+	var emitFQuestionName emitF
+	emitFQuestionName = func(state *genState) any {
+		return dnsForEvent(field.Name, state).questionName
+	}
 
-func bindDouble(fieldCfg ConfigField, field Field, fieldMap map[string]any) error {
-	dummyFunc := makeFloatFunc(fieldCfg, field)
+	var emitFQuestionType emitF
+	emitFQuestionType = func(state *genState) any {
+		return dnsForEvent(field.Name, state).questionType
+	}
 
-	if fieldCfg.Fuzziness <= 0 {
-		var emitFNotReturn emitFNotReturn
-		emitFNotReturn = func(state *genState, buf *bytes.Buffer) error {
-			dummyFloat := dummyFunc()
-			_, err := fmt.Fprintf(buf, "%f", dummyFloat)
-			return err
-		}
+	var emitFAnswersName emitF
+	emitFAnswersName = func(state *genState) any {
+		return dnsForEvent(field.Name, state).answerName
+	}
 
-		fieldMap[field.Name] = emitFNotReturn
-		return nil
+	var emitFAnswersType emitF
+	emitFAnswersType = func(state *genState) any {
+		return dnsForEvent(field.Name, state).answerType
 	}
 
-	min, _ := fieldCfg.Range.MinAsFloat64()
-	max, _ := fieldCfg.Range.MaxAsFloat64()
+	var emitFAnswersData emitF
+	emitFAnswersData = func(state *genState) any {
+		return dnsForEvent(field.Name, state).answerData
+	}
 
-	var emitFNotReturn emitFNotReturn
-	emitFNotReturn = func(state *genState, buf *bytes.Buffer) error {
-		var dummyFloat float64
-		if previousDummyFloat, ok := state.prevCache[field.Name].(float64); ok {
-			dummyFloat = fuzzyFloat(previousDummyFloat, fieldCfg.Fuzziness, min, max)
-		} else {
-			dummyFloat = dummyFunc()
-		}
-		state.prevCache[field.Name] = dummyFloat
-		_, err := fmt.Fprintf(buf, "%f", dummyFloat)
+	fieldMap[field.Name+dnsQuestionNameSuffix] = emitFQuestionName
+	fieldMap[field.Name+dnsQuestionTypeSuffix] = emitFQuestionType
+	fieldMap[field.Name+dnsAnswersNameSuffix] = emitFAnswersName
+	fieldMap[field.Name+dnsAnswersTypeSuffix] = emitFAnswersType
+	fieldMap[field.Name+dnsAnswersDataSuffix] = emitFAnswersData
+	return nil
+}
 
+func bindTLSWithReturn(fieldCfg ConfigField, field Field, fieldMap map[string]any) error {
+	versions, err := tlsVersions(fieldCfg)
+	if err != nil {
 		return err
 	}
 
-	fieldMap[field.Name] = emitFNotReturn
-	return nil
-}
+	var emitFVersion emitF
+	emitFVersion = func(state *genState) any {
+		return tlsForEvent(field.Name, versions, state).version
+	}
 
-func bindCardinality(cfg Config, field Field, fieldMap map[string]any) error {
+	var emitFVersionProtocol emitF
+	emitFVersionProtocol = func(state *genState) any {
+		return tlsVersionProtocol
+	}
 
-	fieldCfg, _ := cfg.GetField(field.Name)
-	cardinality := fieldCfg.Cardinality
+	var emitFCipher emitF
+	emitFCipher = func(state *genState) any {
+		return tlsForEvent(field.Name, versions, state).cipher
+	}
 
-	if strings.HasSuffix(field.Name, ".*") {
-		field.Name = replacer.Replace(field.Name)
+	var emitFCommonName emitF
+	emitFCommonName = func(state *genState) any {
+		return tlsForEvent(field.Name, versions, state).commonName
 	}
 
-	// Go ahead and bind the original field
-	if err := bindByType(cfg, field, fieldMap); err != nil {
+	fieldMap[field.Name+tlsVersionSuffix] = emitFVersion
+	fieldMap[field.Name+tlsVersionProtocolSuffix] = emitFVersionProtocol
+	fieldMap[field.Name+tlsCipherSuffix] = emitFCipher
+	fieldMap[field.Name+tlsServerCommonNameSuffix] = emitFCommonName
+
+	// the validity dates of the server certificate are bound as a `validity` field
+	return bindValidityWithReturn(fieldCfg, tlsX509Field(field), fieldMap)
+}
+
+func bindEventCategorizationWithReturn(fieldCfg ConfigField, field Field, fieldMap map[string]any) error {
+	categories, err := eventCategories(fieldCfg)
+	if err != nil {
 		return err
 	}
 
-	// We will wrap the function we just generated
-	boundF, ok := fieldMap[field.Name].(emitFNotReturn)
-	if !ok {
-		return errors.New("cannot bind cardinality")
+	var emitFCategory emitF
+	emitFCategory = func(state *genState) any {
+		return eventCategorizationForEvent(field.Name, categories, state).category
 	}
 
-	var emitFNotReturn emitFNotReturn
-	emitFNotReturn = func(state *genState, buf *bytes.Buffer) error {
-		// Have we rolled over once?  If not, generate a value and cache it.
-		if len(state.prevCacheCardinality[field.Name]) < cardinality {
-
-			// Do college try dupe detection on value;
-			// Allow dupe if no unique value in nTries.
-			nTries := 11 // "These go to 11."
-			var tmp bytes.Buffer
-			var value []byte
-			for i := 0; i < nTries; i++ {
+	var emitFType emitF
+	emitFType = func(state *genState) any {
+		return eventCategorizationForEvent(field.Name, categories, state).eventType
+	}
 
-				tmp.Reset()
-				if err := boundF(state, &tmp); err != nil {
-					return err
-				}
+	var emitFAction emitF
+	emitFAction = func(state *genState) any {
+		return eventCategorizationForEvent(field.Name, categories, state).action
+	}
 
-				value = tmp.Bytes()
-				if !isDupeAny(state.prevCacheForDup[field.Name], string(value)) {
-					break
-				}
-			}
+	fieldMap[field.Name+eventCategorySuffix] = emitFCategory
+	fieldMap[field.Name+eventTypeSuffix] = emitFType
+	fieldMap[field.Name+eventActionSuffix] = emitFAction
+	return nil
+}
 
-			state.prevCacheForDup[field.Name][string(value)] = struct{}{}
-			state.prevCacheCardinality[field.Name] = append(state.prevCacheCardinality[field.Name], value)
-		}
+func bindUserWithReturn(fieldCfg ConfigField, field Field, fieldMap map[string]any) error {
+	poolSize, err := userPoolSize(fieldCfg, field)
+	if err != nil {
+		return err
+	}
 
-		idx := int(state.counter % uint64(cardinality))
+	var emitFID emitF
+	emitFID = func(state *genState) any {
+		return userForEvent(field.Name, fieldCfg, poolSize, state).id
+	}
 
-		// Safety check; should be a noop
-		if idx >= len(state.prevCacheCardinality[field.Name]) {
-			idx = len(state.prevCacheCardinality[field.Name]) - 1
-		}
+	var emitFName emitF
+	emitFName = func(state *genState) any {
+		return userForEvent(field.Name, fieldCfg, poolSize, state).name
+	}
 
-		choice := state.prevCacheCardinality[field.Name][idx].([]byte)
-		buf.Write(choice)
-		return nil
+	var emitFGroupName emitF
+	emitFGroupName = func(state *genState) any {
+		return userForEvent(field.Name, fieldCfg, poolSize, state).groupName
 	}
 
-	fieldMap[field.Name] = emitFNotReturn
+	fieldMap[field.Name+userIDSuffix] = emitFID
+	fieldMap[field.Name+userNameSuffix] = emitFName
+	fieldMap[field.Name+userGroupNameSuffix] = emitFGroupName
 	return nil
 }
 
-func makeDynamicStub(boundF any) emitFNotReturn {
-	return func(state *genState, buf *bytes.Buffer) error {
-		v := state.pool.Get()
-		tmp := v.(*bytes.Buffer)
-		tmp.Reset()
-		defer state.pool.Put(tmp)
+func bindWebTransactionWithReturn(field Field, fieldMap map[string]any) error {
+	var emitFMethod emitF
+	emitFMethod = func(state *genState) any {
+		return webTransactionForEvent(field.Name, state).method
+	}
 
-		// Fire the bound function, write into temp buffer
-		err := boundF.(emitFNotReturn)(state, tmp)
-		if err != nil {
-			return err
-		}
+	var emitFRequestBytes emitF
+	emitFRequestBytes = func(state *genState) any {
+		return webTransactionForEvent(field.Name, state).requestBytes
+	}
 
-		// If bound function did not write for some reason; abort
-		if tmp.Len() == 0 {
-			return nil
-		}
+	var emitFStatusCode emitF
+	emitFStatusCode = func(state *genState) any {
+		return webTransactionForEvent(field.Name, state).statusCode
+	}
 
-		// ok, formatted as expected, swap it out the payload
-		buf.Write(tmp.Bytes())
-		return nil
+	var emitFResponseBytes emitF
+	emitFResponseBytes = func(state *genState) any {
+		return webTransactionForEvent(field.Name, state).responseBytes
 	}
-}
 
-func makeDynamicStubWithReturn(boundF any) emitF {
-	return func(state *genState) any {
-		return boundF.(emitF)(state)
+	var emitFDuration emitF
+	emitFDuration = func(state *genState) any {
+		return webTransactionForEvent(field.Name, state).duration
 	}
+
+	fieldMap[field.Name+webRequestMethodSuffix] = emitFMethod
+	fieldMap[field.Name+webRequestBytesSuffix] = emitFRequestBytes
+	fieldMap[field.Name+webResponseStatusSuffix] = emitFStatusCode
+	fieldMap[field.Name+webResponseBytesSuffix] = emitFResponseBytes
+	fieldMap[webTransactionEventPrefix(field.Name)+webTransactionDurationField] = emitFDuration
+	return nil
 }
 
-func bindConstantKeywordWithReturn(field Field, fieldMap map[string]any) error {
-	var emitF emitF
-	emitF = func(state *genState) any {
-		value, ok := state.prevCache[field.Name].(string)
-		if !ok {
-			// randomdata.Adjective() + randomdata.Noun() -> 364 * 527 (~190k) different values
-			value = randomdata.Adjective() + randomdata.Noun()
-			state.prevCache[field.Name] = value
-		}
-		return value
+func bindSessionWithReturn(fieldCfg ConfigField, field Field, fieldMap map[string]any) error {
+	length, err := sessionLength(fieldCfg, field)
+	if err != nil {
+		return err
 	}
 
-	fieldMap[field.Name] = emitF
+	var emitFID emitF
+	emitFID = func(state *genState) any {
+		return sessionForEvent(field.Name, length, state).id
+	}
+
+	var emitFStep emitF
+	emitFStep = func(state *genState) any {
+		return sessionForEvent(field.Name, length, state).step
+	}
+
+	var emitFUserName emitF
+	emitFUserName = func(state *genState) any {
+		return sessionForEvent(field.Name, length, state).userName
+	}
+
+	fieldMap[field.Name+sessionIDSuffix] = emitFID
+	fieldMap[field.Name+sessionStepSuffix] = emitFStep
+	fieldMap[field.Name+sessionUserNameSuffix] = emitFUserName
 	return nil
 }
 
-func bindKeywordWithReturn(fieldCfg ConfigField, field Field, fieldMap map[string]any) error {
-	if len(fieldCfg.Enum) > 0 {
-		var emitF emitF
-		emitF = func(state *genState) any {
-			idx := customRand.Intn(len(fieldCfg.Enum))
-			return fieldCfg.Enum[idx]
+func bindValidityWithReturn(fieldCfg ConfigField, field Field, fieldMap map[string]any) error {
+	if err := fieldCfg.ValidForDateField(); err != nil {
+		return err
+	}
+
+	validity, err := validityWindow(fieldCfg, field)
+	if err != nil {
+		return err
+	}
+
+	var emitFNotBefore emitF
+	emitFNotBefore = func(state *genState) any {
+		notBefore := validityNotBeforeForEvent(field.Name, fieldCfg, state)
+		// with a `format` preset the value is emitted as already formatted string
+		if len(fieldCfg.Format) > 0 {
+			return formattedDate(notBefore, dateLayout(fieldCfg))
 		}
 
-		fieldMap[field.Name] = emitF
-	} else if len(field.Example) > 0 {
-		totWords, joiner := totWordsAndJoiner(field.Example)
+		return notBefore
+	}
 
-		return bindJoinRandWithReturn(field, totWords, joiner, fieldMap)
-	} else {
-		var emitF emitF
-		emitF = func(state *genState) any {
-			// randomdata.Adjective() + randomdata.Noun() -> 364 * 527 (~190k) different values
-			return randomdata.Adjective() + randomdata.Noun()
+	var emitFNotAfter emitF
+	emitFNotAfter = func(state *genState) any {
+		notAfter := validityNotBeforeForEvent(field.Name, fieldCfg, state).Add(validity)
+		if len(fieldCfg.Format) > 0 {
+			return formattedDate(notAfter, dateLayout(fieldCfg))
 		}
 
-		fieldMap[field.Name] = emitF
+		return notAfter
 	}
+
+	fieldMap[field.Name+validityNotBeforeSuffix] = emitFNotBefore
+	fieldMap[field.Name+validityNotAfterSuffix] = emitFNotAfter
 	return nil
 }
 
-func bindJoinRandWithReturn(field Field, N int, joiner string, fieldMap map[string]any) error {
+func bindULIDWithReturn(field Field, fieldMap map[string]any) error {
 	var emitF emitF
 	emitF = func(state *genState) any {
-		value := ""
-		for i := 0; i < N-1; i++ {
-			value += randomdata.Noun() + joiner
-		}
-
-		// randomdata.Adjective() + randomdata.Noun() -> 364 * 527 (~190k) different values
-		value += randomdata.Adjective()
-		value += randomdata.Noun()
-
-		return value
+		return ulidForEvent(field.Name, state)
 	}
 
 	fieldMap[field.Name] = emitF
 	return nil
 }
 
-func bindStaticWithReturn(field Field, v any, fieldMap map[string]any) error {
+func bindUUIDWithReturn(fieldCfg ConfigField, field Field, fieldMap map[string]any) error {
+	version, err := uuidVersion(fieldCfg, field)
+	if err != nil {
+		return err
+	}
+
 	var emitF emitF
 	emitF = func(state *genState) any {
-		return v
+		return uuidForEvent(field.Name, version, state)
 	}
 
 	fieldMap[field.Name] = emitF
 	return nil
 }
 
-func bindBoolWithReturn(field Field, fieldMap map[string]any) error {
+func bindHexTokenWithReturn(fieldCfg ConfigField, field Field, fieldMap map[string]any) error {
+	length, err := hexTokenLength(fieldCfg, field)
+	if err != nil {
+		return err
+	}
+
 	var emitF emitF
 	emitF = func(state *genState) any {
-		switch customRand.Int() % 2 {
-		case 0:
-			return false
-		default:
-			return true
-		}
+		return randStringFromAlphabet(state.rand, hexAlphabet, length)
 	}
 
 	fieldMap[field.Name] = emitF
 	return nil
 }
 
-func bindGeoPointWithReturn(field Field, fieldMap map[string]any) error {
+func bindCIDRWithReturn(fieldCfg ConfigField, field Field, fieldMap map[string]any) error {
+	cidrFunc, err := makeCIDRFunc(fieldCfg)
+	if err != nil {
+		return err
+	}
+
 	var emitF emitF
 	emitF = func(state *genState) any {
-		lat, latD, long, longD := randGeoPoint()
-		return fmt.Sprintf("%d.%d,%d.%d", lat, latD, long, longD)
+		return cidrFunc(state.rand)
 	}
 
 	fieldMap[field.Name] = emitF
-
 	return nil
 }
 
 func bindWordNWithReturn(field Field, n int, fieldMap map[string]any) error {
 	var emitF emitF
 	emitF = func(state *genState) any {
-		return genNounsNWithReturn(customRand.Intn(n))
+		return genNounsNWithReturn(state.rand.Intn(n))
 	}
 	fieldMap[field.Name] = emitF
 	return nil
@@ -857,8 +2970,45 @@ func bindNearTimeWithReturn(fieldCfg ConfigField, field Field, fieldMap map[stri
 		return err
 	}
 
+	if fieldCfg.Mode == config.DateModeAligned {
+		base := alignedTimeBase(fieldCfg)
+
+		var emitF emitF
+		emitF = func(state *genState) any {
+			if len(fieldCfg.Format) > 0 {
+				return formattedDate(alignedTime(base, fieldCfg, state), dateLayout(fieldCfg))
+			}
+
+			return alignedTime(base, fieldCfg, state)
+		}
+
+		fieldMap[field.Name] = emitF
+		return nil
+	}
+
+	if fieldCfg.Mode == config.DateModeMonotonic {
+		base := alignedTimeBase(fieldCfg)
+
+		var emitF emitF
+		emitF = func(state *genState) any {
+			if len(fieldCfg.Format) > 0 {
+				return formattedDate(monotonicTime(base, field, fieldCfg, state), dateLayout(fieldCfg))
+			}
+
+			return monotonicTime(base, field, fieldCfg, state)
+		}
+
+		fieldMap[field.Name] = emitF
+		return nil
+	}
+
 	var emitF emitF
 	emitF = func(state *genState) any {
+		// with a `format` the value is emitted as already formatted string, or as an integer for the epoch formats
+		if len(fieldCfg.Format) > 0 {
+			return formattedDate(nearTime(fieldCfg, state), dateLayout(fieldCfg))
+		}
+
 		return nearTime(fieldCfg, state)
 	}
 
@@ -866,22 +3016,25 @@ func bindNearTimeWithReturn(fieldCfg ConfigField, field Field, fieldMap map[stri
 	return nil
 }
 
-func bindIPWithReturn(field Field, fieldMap map[string]any) error {
+func bindIPWithReturn(fieldCfg ConfigField, field Field, fieldMap map[string]any) error {
+	ipFunc, err := makeIPFunc(fieldCfg)
+	if err != nil {
+		return err
+	}
+
 	var emitF emitF
 	emitF = func(state *genState) any {
-		i0, i1, i2, i3 := randIP()
-
-		return fmt.Sprintf("%d.%d.%d.%d", i0, i1, i2, i3)
+		return ipFunc(state.rand)
 	}
 
 	fieldMap[field.Name] = emitF
 	return nil
 }
-func randIP() (int, int, int, int) {
-	i0 := customRand.Intn(255)
-	i1 := customRand.Intn(255)
-	i2 := customRand.Intn(255)
-	i3 := customRand.Intn(255)
+func randIP(r *rand.Rand) (int, int, int, int) {
+	i0 := r.Intn(255)
+	i1 := r.Intn(255)
+	i2 := r.Intn(255)
+	i3 := r.Intn(255)
 
 	return i0, i1, i2, i3
 }
@@ -891,15 +3044,14 @@ func bindLongWithReturn(fieldCfg ConfigField, field Field, fieldMap map[string]a
 	if fieldCfg.Fuzziness <= 0 {
 		var emitF emitF
 		emitF = func(state *genState) any {
-			return dummyFunc()
+			return dummyFunc(state.rand)
 		}
 
 		fieldMap[field.Name] = emitF
 		return nil
 	}
 
-	min, _ := fieldCfg.Range.MinAsFloat64()
-	max, _ := fieldCfg.Range.MaxAsFloat64()
+	min, max := numericBounds(fieldCfg, float64(integerTypeMin(field.Type)), float64(integerTypeMax(field.Type)))
 
 	var emitF emitF
 	emitF = func(state *genState) any {
@@ -908,9 +3060,9 @@ func bindLongWithReturn(fieldCfg ConfigField, field Field, fieldMap map[string]a
 			if previousDummyInt == 0 {
 				previousDummyInt = 1
 			}
-			dummyInt = fuzzyInt(previousDummyInt, fieldCfg.Fuzziness, min, max)
+			dummyInt = fuzzyInt(state.rand, previousDummyInt, fieldCfg.Fuzziness, min, max)
 		} else {
-			dummyInt = dummyFunc()
+			dummyInt = dummyFunc(state.rand)
 		}
 		state.prevCache[field.Name] = dummyInt
 		return dummyInt
@@ -920,13 +3072,48 @@ func bindLongWithReturn(fieldCfg ConfigField, field Field, fieldMap map[string]a
 	return nil
 }
 
+func bindUnsignedLongWithReturn(fieldCfg ConfigField, field Field, fieldMap map[string]any) error {
+	dummyFunc := makeUnsignedLongFunc(fieldCfg, field)
+
+	if fieldCfg.Fuzziness <= 0 {
+		var emitF emitF
+		emitF = func(state *genState) any {
+			return dummyFunc(state.rand)
+		}
+
+		fieldMap[field.Name] = emitF
+		return nil
+	}
+
+	min, _ := fieldCfg.Range.MinAsUint64()
+	max, _ := fieldCfg.Range.MaxAsUint64()
+
+	var emitF emitF
+	emitF = func(state *genState) any {
+		var dummyUint uint64
+		if previousDummyUint, ok := state.prevCache[field.Name].(uint64); ok {
+			if previousDummyUint == 0 {
+				previousDummyUint = 1
+			}
+			dummyUint = fuzzyUnsignedLong(state.rand, previousDummyUint, fieldCfg.Fuzziness, min, max)
+		} else {
+			dummyUint = dummyFunc(state.rand)
+		}
+		state.prevCache[field.Name] = dummyUint
+		return dummyUint
+	}
+
+	fieldMap[field.Name] = emitF
+	return nil
+}
+
 func bindDoubleWithReturn(fieldCfg ConfigField, field Field, fieldMap map[string]any) error {
 	dummyFunc := makeFloatFunc(fieldCfg, field)
 
 	if fieldCfg.Fuzziness <= 0 {
 		var emitF emitF
 		emitF = func(state *genState) any {
-			return dummyFunc()
+			return scaledFloat(field, dummyFunc(state.rand))
 		}
 
 		fieldMap[field.Name] = emitF
@@ -934,17 +3121,17 @@ func bindDoubleWithReturn(fieldCfg ConfigField, field Field, fieldMap map[string
 		return nil
 	}
 
-	min, _ := fieldCfg.Range.MinAsFloat64()
-	max, _ := fieldCfg.Range.MaxAsFloat64()
+	min, max := numericBounds(fieldCfg, -math.MaxFloat64, math.MaxFloat64)
 
 	var emitF emitF
 	emitF = func(state *genState) any {
 		var dummyFloat float64
 		if previousDummyFloat, ok := state.prevCache[field.Name].(float64); ok {
-			dummyFloat = fuzzyFloat(previousDummyFloat, fieldCfg.Fuzziness, min, max)
+			dummyFloat = fuzzyFloat(state.rand, previousDummyFloat, fieldCfg.Fuzziness, min, max)
 		} else {
-			dummyFloat = dummyFunc()
+			dummyFloat = dummyFunc(state.rand)
 		}
+		dummyFloat = scaledFloat(field, dummyFloat)
 		state.prevCache[field.Name] = dummyFloat
 		return dummyFloat
 	}
@@ -957,7 +3144,11 @@ func bindDoubleWithReturn(fieldCfg ConfigField, field Field, fieldMap map[string
 func bindCardinalityWithReturn(cfg Config, field Field, fieldMap map[string]any) error {
 
 	fieldCfg, _ := cfg.GetField(field.Name)
-	cardinality := fieldCfg.Cardinality
+	cardinality := fieldCardinality(fieldCfg)
+	var groupSize int
+	if len(fieldCfg.CardinalityGroup) > 0 {
+		groupSize = cfg.CardinalityGroupSize(fieldCfg.CardinalityGroup)
+	}
 
 	if strings.HasSuffix(field.Name, ".*") {
 		field.Name = replacer.Replace(field.Name)
@@ -972,34 +3163,63 @@ func bindCardinalityWithReturn(cfg Config, field Field, fieldMap map[string]any)
 	boundFWithReturn := fieldMap[field.Name].(emitF)
 	var emitF emitF
 	emitF = func(state *genState) any {
-		var value any
-		// Have we rolled over once?  If not, generate a value and cache it.
-		if len(state.prevCacheCardinality[field.Name]) < cardinality {
-			// Do college try dupe detection on value;
-			// Allow dupe if no unique value in nTries.
-			nTries := 11 // "These go to 11."
-			for i := 0; i < nTries; i++ {
-				value = boundFWithReturn(state)
-
-				if !isDupeAny(state.prevCacheForDup[field.Name], value) {
+		// Generate the distinct values up front, the first time the field is emitted
+		if len(state.prevCacheCardinality[field.Name]) == 0 {
+			values := make([]any, 0, cardinality)
+			for len(values) < cardinality {
+				found := false
+				for i := 0; i < uniqueMaxTries; i++ {
+					value := boundFWithReturn(state)
+					if !isDupeAny(state.prevCacheForDup[field.Name], value) {
+						state.prevCacheForDup[field.Name][value] = struct{}{}
+						values = append(values, value)
+						found = true
+						break
+					}
+				}
+
+				// the values space of the field is narrower than the cardinality: it is capped at the values found
+				if !found {
 					break
 				}
 			}
 
-			state.prevCacheForDup[field.Name][value] = struct{}{}
-			state.prevCacheCardinality[field.Name] = append(state.prevCacheCardinality[field.Name], value)
+			state.prevCacheCardinality[field.Name] = values
 		}
 
-		idx := int(state.counter % uint64(cardinality))
+		values := state.prevCacheCardinality[field.Name]
 
-		// Safety check; should be a noop
-		if idx >= len(state.prevCacheCardinality[field.Name]) {
-			idx = len(state.prevCacheCardinality[field.Name]) - 1
-		}
+		return values[cardinalityIndex(state, len(values), groupSize)]
+	}
+
+	fieldMap[field.Name] = emitF
+	return nil
+}
+
+func bindUniqueWithReturn(cfg Config, field Field, fieldMap map[string]any) error {
+	// Go ahead and bind the original field
+	if err := bindByTypeWithReturn(cfg, field, fieldMap); err != nil {
+		return err
+	}
 
-		choice := state.prevCacheCardinality[field.Name][idx]
+	// We will wrap the function we just generated
+	boundFWithReturn, ok := fieldMap[field.Name].(emitF)
+	if !ok {
+		return errors.New("cannot bind unique")
+	}
+
+	var emitF emitF
+	emitF = func(state *genState) any {
+		for i := 0; i < uniqueMaxTries; i++ {
+			value := boundFWithReturn(state)
+			if !isDupeAny(state.prevCacheForDup[field.Name], value) {
+				state.prevCacheForDup[field.Name][value] = struct{}{}
+				return value
+			}
+		}
 
-		return choice
+		// the error is returned as value and reported by the template engine
+		return fmt.Errorf("%w for field %s after %d tries", uniqueValuesExhausted, field.Name, uniqueMaxTries)
 	}
 
 	fieldMap[field.Name] = emitF
@@ -0,0 +1,87 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package genlib
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sort"
+)
+
+var maxFieldsInvalidCap = errors.New("max fields per document must be positive")
+var maxFieldsDocumentNotObject = errors.New("capped document is not a JSON object")
+
+// GeneratorWithMaxFields wraps a Generator capping the number of fields of each document,
+// to simulate partial population of wide schemas
+type GeneratorWithMaxFields struct {
+	gen       Generator
+	maxFields int
+	tmp       bytes.Buffer
+	rand      *rand.Rand
+}
+
+// NewGeneratorWithMaxFields returns a Generator emitting the documents of gen with at most maxFields top level
+// fields, randomly selected for each document. The documents are re-encoded with their keys sorted.
+func NewGeneratorWithMaxFields(gen Generator, maxFields int) (*GeneratorWithMaxFields, error) {
+	if maxFields <= 0 {
+		return nil, maxFieldsInvalidCap
+	}
+
+	return &GeneratorWithMaxFields{
+		gen:       gen,
+		maxFields: maxFields,
+		rand:      newGeneratorRand(),
+	}, nil
+}
+
+func (gen *GeneratorWithMaxFields) Close() error {
+	return gen.gen.Close()
+}
+
+func (gen *GeneratorWithMaxFields) Emit(buf *bytes.Buffer) error {
+	gen.tmp.Reset()
+	if err := gen.gen.Emit(&gen.tmp); err != nil {
+		return err
+	}
+
+	var m map[string]json.RawMessage
+	if err := json.Unmarshal(gen.tmp.Bytes(), &m); err != nil || m == nil {
+		return fmt.Errorf("%w: %s", maxFieldsDocumentNotObject, gen.tmp.Bytes())
+	}
+
+	if len(m) <= gen.maxFields {
+		buf.Write(gen.tmp.Bytes())
+		return nil
+	}
+
+	// sort the keys so that the selection is reproducible with the same seed
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	// partial Fisher-Yates: the last maxFields keys are the selected ones
+	for i := len(keys) - 1; i >= len(keys)-gen.maxFields; i-- {
+		j := gen.rand.Intn(i + 1)
+		keys[i], keys[j] = keys[j], keys[i]
+	}
+
+	for _, key := range keys[:len(keys)-gen.maxFields] {
+		delete(m, key)
+	}
+
+	capped, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+
+	buf.Write(capped)
+	return nil
+}
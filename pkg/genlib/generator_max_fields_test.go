@@ -0,0 +1,77 @@
+package genlib
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/elastic/elastic-integration-corpus-generator-tool/pkg/genlib/config"
+)
+
+func Test_GeneratorWithMaxFields(t *testing.T) {
+	flds := make(Fields, 0, 20)
+	for i := 0; i < 20; i++ {
+		flds = append(flds, Field{Name: fmt.Sprintf("field%d", i), Type: FieldTypeKeyword})
+	}
+
+	cfg, err := config.LoadConfigFromYaml([]byte("max_fields_per_doc: 3\nfields:\n  - name: field0\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	g, err := NewGenerator(cfg, flds, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	seen := make(map[string]struct{})
+	nSpins := 100
+	for i := 0; i < nSpins; i++ {
+		var buf bytes.Buffer
+		if err := g.Emit(&buf); err != nil {
+			t.Fatal(err)
+		}
+
+		m := unmarshalJSONT[any](t, buf.Bytes())
+		if len(m) > 3 {
+			t.Errorf("Expected at most 3 fields, got %s", buf.String())
+		}
+
+		for key := range m {
+			seen[key] = struct{}{}
+		}
+	}
+
+	if len(seen) != len(flds) {
+		t.Errorf("Expected all the fields to be selected across documents, got %d", len(seen))
+	}
+}
+
+func Test_GeneratorWithMaxFieldsNotCapped(t *testing.T) {
+	flds := Fields{
+		{Name: "alpha", Type: FieldTypeKeyword},
+		{Name: "beta", Type: FieldTypeLong},
+	}
+
+	template := []byte(`{"alpha":"{{.alpha}}","beta":{{.beta}}}`)
+	g, err := NewGeneratorWithMaxFields(makeGeneratorWithCustomTemplate(t, config.Config{}, flds, template, 0), 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := g.Emit(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	if m := unmarshalJSONT[any](t, buf.Bytes()); len(m) != len(flds) {
+		t.Errorf("Expected all the fields, got %s", buf.String())
+	}
+}
+
+func Test_GeneratorWithMaxFieldsInvalidCap(t *testing.T) {
+	if _, err := NewGeneratorWithMaxFields(nil, 0); !errors.Is(err, maxFieldsInvalidCap) {
+		t.Errorf("Expected maxFieldsInvalidCap error, got %v", err)
+	}
+}
@@ -0,0 +1,164 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package genlib
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+var nestedOutputCollision = errors.New("field is both a value and an object")
+var nestedOutputDocumentNotObject = errors.New("nested output document is not a JSON object")
+
+// nestedObject is a JSON object whose keys keep the order they are first set in
+type nestedObject struct {
+	keys     []string
+	values   map[string]json.RawMessage
+	children map[string]*nestedObject
+}
+
+func newNestedObject() *nestedObject {
+	return &nestedObject{
+		values:   make(map[string]json.RawMessage),
+		children: make(map[string]*nestedObject),
+	}
+}
+
+// set sets the value at the path of the dotted key, creating the intermediate objects
+func (o *nestedObject) set(key string, value json.RawMessage) error {
+	parts := strings.Split(key, ".")
+	current := o
+	for i, part := range parts {
+		_, isValue := current.values[part]
+		child, isObject := current.children[part]
+
+		if i == len(parts)-1 {
+			if isValue || isObject {
+				return fmt.Errorf("%w: %s", nestedOutputCollision, key)
+			}
+
+			current.keys = append(current.keys, part)
+			current.values[part] = value
+			return nil
+		}
+
+		if isValue {
+			return fmt.Errorf("%w: %s", nestedOutputCollision, strings.Join(parts[:i+1], "."))
+		}
+
+		if !isObject {
+			child = newNestedObject()
+			current.keys = append(current.keys, part)
+			current.children[part] = child
+		}
+
+		current = child
+	}
+
+	return nil
+}
+
+func (o *nestedObject) write(buf *bytes.Buffer) {
+	buf.WriteByte('{')
+	for i, key := range o.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+
+		encodedKey, _ := json.Marshal(key)
+		buf.Write(encodedKey)
+		buf.WriteByte(':')
+
+		if child, ok := o.children[key]; ok {
+			child.write(buf)
+		} else {
+			buf.Write(o.values[key])
+		}
+	}
+
+	buf.WriteByte('}')
+}
+
+// nestedOutputCollisions returns an error if any of the fieldNames is both a value and the prefix of another
+// field name, so that it cannot be emitted as nested objects
+func nestedOutputCollisions(fieldNames []string) error {
+	sorted := append([]string(nil), fieldNames...)
+	sort.Strings(sorted)
+
+	for i, fieldName := range sorted {
+		// the field names sharing fieldName as prefix follow it when sorted
+		for _, other := range sorted[i+1:] {
+			if !strings.HasPrefix(other, fieldName) {
+				break
+			}
+
+			if strings.HasPrefix(other, fieldName+".") {
+				return fmt.Errorf("%w: %s and %s", nestedOutputCollision, fieldName, other)
+			}
+		}
+	}
+
+	return nil
+}
+
+// GeneratorWithNestedOutput wraps a Generator emitting its documents with the dotted keys as nested objects
+// (ie: `{"source":{"geo":{"city_name":"Rome"}}}` instead of `{"source.geo.city_name":"Rome"}`)
+type GeneratorWithNestedOutput struct {
+	gen Generator
+	tmp bytes.Buffer
+}
+
+// NewGeneratorWithNestedOutput returns a Generator emitting the documents of gen with the dotted keys as nested
+// objects, the keys of each object in the order they first appear in the document. An error is returned if any of
+// fieldNames, the keys of the documents of gen, is both a value and an object, that would make a document invalid.
+func NewGeneratorWithNestedOutput(gen Generator, fieldNames []string) (*GeneratorWithNestedOutput, error) {
+	if err := nestedOutputCollisions(fieldNames); err != nil {
+		return nil, err
+	}
+
+	return &GeneratorWithNestedOutput{
+		gen: gen,
+	}, nil
+}
+
+func (gen *GeneratorWithNestedOutput) Close() error {
+	return gen.gen.Close()
+}
+
+func (gen *GeneratorWithNestedOutput) Emit(buf *bytes.Buffer) error {
+	gen.tmp.Reset()
+	if err := gen.gen.Emit(&gen.tmp); err != nil {
+		return err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(gen.tmp.Bytes()))
+	if token, err := dec.Token(); err != nil || token != json.Delim('{') {
+		return fmt.Errorf("%w: %s", nestedOutputDocumentNotObject, gen.tmp.Bytes())
+	}
+
+	root := newNestedObject()
+	for dec.More() {
+		token, err := dec.Token()
+		if err != nil {
+			return fmt.Errorf("%w: %s", nestedOutputDocumentNotObject, gen.tmp.Bytes())
+		}
+
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			return fmt.Errorf("%w: %s", nestedOutputDocumentNotObject, gen.tmp.Bytes())
+		}
+
+		if err := root.set(token.(string), value); err != nil {
+			return err
+		}
+	}
+
+	root.write(buf)
+	return nil
+}
@@ -0,0 +1,105 @@
+package genlib
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/elastic/elastic-integration-corpus-generator-tool/pkg/genlib/config"
+)
+
+func Test_GeneratorWithNestedOutput(t *testing.T) {
+	flds := Fields{
+		{Name: "source.ip", Type: FieldTypeIP},
+		{Name: "source.geo.city_name", Type: FieldTypeKeyword},
+		{Name: "event.duration", Type: FieldTypeLong},
+		{Name: "source.port", Type: FieldTypeLong},
+	}
+
+	cfg, err := config.LoadConfigFromYaml([]byte("key_style: nested\nfields:\n  - name: source.geo.city_name\n    value: Rome\n  - name: event.duration\n    value: 10\n  - name: source.port\n    value: 443\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	g, err := NewGenerator(cfg, flds, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := g.Emit(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	m := unmarshalJSONT[any](t, buf.Bytes())
+	source, ok := m["source"].(map[string]any)
+	if !ok || len(m) != 2 {
+		t.Fatalf("Expected source and event objects, got %s", buf.String())
+	}
+
+	if geo, ok := source["geo"].(map[string]any); !ok || geo["city_name"] != "Rome" {
+		t.Errorf("Expected nested source.geo.city_name, got %s", buf.String())
+	}
+
+	if _, ok := source["ip"].(string); !ok || source["port"] != float64(443) {
+		t.Errorf("Expected nested source.ip and source.port, got %s", buf.String())
+	}
+
+	expectedPrefix := `{"source":{"ip":`
+	if !bytes.HasPrefix(buf.Bytes(), []byte(expectedPrefix)) {
+		t.Errorf("Expected keys in the order they first appear, got %s", buf.String())
+	}
+}
+
+func Test_GeneratorWithNestedOutputCollision(t *testing.T) {
+	flds := Fields{
+		{Name: "source.geo", Type: FieldTypeKeyword},
+		{Name: "source.geo.city_name", Type: FieldTypeKeyword},
+	}
+
+	cfg, err := config.LoadConfigFromYaml([]byte("key_style: nested\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := NewGenerator(cfg, flds, 0); !errors.Is(err, nestedOutputCollision) {
+		t.Errorf("Expected nestedOutputCollision error, got %v", err)
+	}
+}
+
+func Test_GeneratorWithNestedOutputCollisionAtEmit(t *testing.T) {
+	documents := &documentsGenerator{documents: []string{`{"alpha":"a","alpha.beta":"b"}`, `["alpha"]`}}
+	g, err := NewGeneratorWithNestedOutput(documents, []string{"alpha"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := g.Emit(&buf); !errors.Is(err, nestedOutputCollision) {
+		t.Errorf("Expected nestedOutputCollision error, got %v", err)
+	}
+
+	buf.Reset()
+	if err := g.Emit(&buf); !errors.Is(err, nestedOutputDocumentNotObject) {
+		t.Errorf("Expected nestedOutputDocumentNotObject error, got %v", err)
+	}
+}
+
+func Test_NestedOutputCollisions(t *testing.T) {
+	testCases := []struct {
+		fieldNames []string
+		collision  bool
+	}{
+		{fieldNames: []string{"source.ip", "source.port", "destination.ip"}},
+		{fieldNames: []string{"source", "source_ip", "source-ip"}},
+		{fieldNames: []string{"source.ip", "source", "source-ip"}, collision: true},
+		{fieldNames: []string{"message.keyword", "message"}, collision: true},
+	}
+
+	for _, testCase := range testCases {
+		err := nestedOutputCollisions(testCase.fieldNames)
+		if testCase.collision != errors.Is(err, nestedOutputCollision) {
+			t.Errorf("Expected collision %t for %v, got %v", testCase.collision, testCase.fieldNames, err)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package genlib
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+var pairedDocumentNotObject = errors.New("paired document is not a JSON object")
+
+// GeneratorWithPairs emits correlated pairs of documents, like an HTTP request followed by its response
+type GeneratorWithPairs struct {
+	request          Generator
+	response         Generator
+	correlatedFields []string
+	correlated       map[string]json.RawMessage
+	emitResponse     bool
+}
+
+// NewGeneratorWithPairs returns a Generator alternating documents of request and response: each response document
+// follows its request document and has the values of correlatedFields copied from it.
+func NewGeneratorWithPairs(request, response Generator, correlatedFields ...string) *GeneratorWithPairs {
+	return &GeneratorWithPairs{
+		request:          request,
+		response:         response,
+		correlatedFields: correlatedFields,
+	}
+}
+
+func (gen *GeneratorWithPairs) Close() error {
+	requestErr := gen.request.Close()
+	responseErr := gen.response.Close()
+	if requestErr != nil {
+		return requestErr
+	}
+
+	return responseErr
+}
+
+func (gen *GeneratorWithPairs) Emit(buf *bytes.Buffer) error {
+	if gen.emitResponse {
+		return gen.emitResponseDocument(buf)
+	}
+
+	return gen.emitRequestDocument(buf)
+}
+
+func (gen *GeneratorWithPairs) emitRequestDocument(buf *bytes.Buffer) error {
+	var doc bytes.Buffer
+	if err := gen.request.Emit(&doc); err != nil {
+		return err
+	}
+
+	var m map[string]json.RawMessage
+	if err := json.Unmarshal(doc.Bytes(), &m); err != nil {
+		return fmt.Errorf("%w: %s", pairedDocumentNotObject, err)
+	}
+
+	gen.correlated = make(map[string]json.RawMessage, len(gen.correlatedFields))
+	for _, fieldName := range gen.correlatedFields {
+		if v, ok := m[fieldName]; ok {
+			gen.correlated[fieldName] = v
+		}
+	}
+
+	gen.emitResponse = true
+	buf.Write(doc.Bytes())
+	return nil
+}
+
+func (gen *GeneratorWithPairs) emitResponseDocument(buf *bytes.Buffer) error {
+	var doc bytes.Buffer
+	if err := gen.response.Emit(&doc); err != nil {
+		return err
+	}
+
+	var m map[string]json.RawMessage
+	if err := json.Unmarshal(doc.Bytes(), &m); err != nil {
+		return fmt.Errorf("%w: %s", pairedDocumentNotObject, err)
+	}
+
+	for fieldName, v := range gen.correlated {
+		m[fieldName] = v
+	}
+
+	response, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+
+	gen.emitResponse = false
+	buf.Write(response)
+	return nil
+}
@@ -0,0 +1,93 @@
+package genlib
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/elastic/elastic-integration-corpus-generator-tool/pkg/genlib/config"
+)
+
+func Test_GeneratorWithPairs(t *testing.T) {
+	cfg, err := config.LoadConfigFromYaml([]byte(`fields:
+  - name: http.request.method
+    enum: ["GET", "POST", "PUT"]
+  - name: http.response.status_code
+    enum: ["200", "201", "404", "500"]
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	requestFlds := Fields{
+		{Name: "url.original", Type: FieldTypeKeyword},
+		{Name: "http.request.method", Type: FieldTypeKeyword},
+		{Name: "http.response.status_code", Type: FieldTypeKeyword},
+	}
+
+	responseFlds := Fields{
+		{Name: "url.original", Type: FieldTypeKeyword},
+		{Name: "http.response.status_code", Type: FieldTypeKeyword},
+		{Name: "http.response.bytes", Type: FieldTypeLong},
+	}
+
+	requestTemplate := []byte(`{"type":"request","url.original":"{{.url.original}}","http.request.method":"{{.http.request.method}}","http.response.status_code":"{{.http.response.status_code}}"}`)
+	responseTemplate := []byte(`{"type":"response","url.original":"{{.url.original}}","http.response.status_code":"{{.http.response.status_code}}","http.response.bytes":{{.http.response.bytes}}}`)
+
+	g := NewGeneratorWithPairs(
+		makeGeneratorWithCustomTemplate(t, cfg, requestFlds, requestTemplate, 0),
+		makeGeneratorWithCustomTemplate(t, cfg, responseFlds, responseTemplate, 0),
+		"url.original", "http.response.status_code",
+	)
+	defer func() {
+		_ = g.Close()
+	}()
+
+	nPairs := 100
+	for i := 0; i < nPairs; i++ {
+		var requestBuf, responseBuf bytes.Buffer
+		if err := g.Emit(&requestBuf); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := g.Emit(&responseBuf); err != nil {
+			t.Fatal(err)
+		}
+
+		request := unmarshalJSONT[any](t, requestBuf.Bytes())
+		response := unmarshalJSONT[any](t, responseBuf.Bytes())
+
+		if request["type"] != "request" || response["type"] != "response" {
+			t.Fatalf("Expected request followed by response, got %s and %s", requestBuf.String(), responseBuf.String())
+		}
+
+		for _, fieldName := range []string{"url.original", "http.response.status_code"} {
+			if request[fieldName] != response[fieldName] {
+				t.Errorf("Expected %s to be correlated, got %v and %v", fieldName, request[fieldName], response[fieldName])
+			}
+		}
+
+		if _, ok := response["http.response.bytes"].(float64); !ok {
+			t.Errorf("Expected response own fields to be preserved, got %s", responseBuf.String())
+		}
+	}
+}
+
+func Test_GeneratorWithPairsNotObject(t *testing.T) {
+	fld := Field{
+		Name: "alpha",
+		Type: FieldTypeKeyword,
+	}
+
+	template := []byte(`{{.alpha}}`)
+	g := NewGeneratorWithPairs(
+		makeGeneratorWithCustomTemplate(t, config.Config{}, Fields{fld}, template, 0),
+		makeGeneratorWithCustomTemplate(t, config.Config{}, Fields{fld}, template, 0),
+		"alpha",
+	)
+
+	var buf bytes.Buffer
+	if err := g.Emit(&buf); !errors.Is(err, pairedDocumentNotObject) {
+		t.Errorf("Expected pairedDocumentNotObject error, got %v", err)
+	}
+}
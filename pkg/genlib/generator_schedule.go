@@ -0,0 +1,205 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package genlib
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var scheduleEmpty = errors.New("schedule has no points")
+var scheduleInvalidPoint = errors.New("invalid schedule point")
+
+// SchedulePoint is the rate, in events per second, documents are emitted at Offset from the start of the generation
+type SchedulePoint struct {
+	Offset          time.Duration
+	EventsPerSecond float64
+}
+
+// Schedule is a list of points, by increasing offset, the emission rate is linearly interpolated between.
+// The rate before the first point is the one of the first point, and after the last point the one of the last point.
+type Schedule []SchedulePoint
+
+// LoadSchedule reads a Schedule from CSV records of offset and events per second (ie: `1h30m,250`).
+// The offset is a time.Duration, and an optional header record (`offset,events_per_second`) is skipped.
+func LoadSchedule(r io.Reader) (Schedule, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = 2
+	reader.TrimLeadingSpace = true
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(records) > 0 && strings.TrimSpace(records[0][0]) == "offset" {
+		records = records[1:]
+	}
+
+	schedule := make(Schedule, 0, len(records))
+	for _, record := range records {
+		offset, err := time.ParseDuration(strings.TrimSpace(record[0]))
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v (%s)", scheduleInvalidPoint, record, err)
+		}
+
+		eventsPerSecond, err := strconv.ParseFloat(strings.TrimSpace(record[1]), 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v (%s)", scheduleInvalidPoint, record, err)
+		}
+
+		schedule = append(schedule, SchedulePoint{Offset: offset, EventsPerSecond: eventsPerSecond})
+	}
+
+	if err := schedule.validate(); err != nil {
+		return nil, err
+	}
+
+	return schedule, nil
+}
+
+func (s Schedule) validate() error {
+	if len(s) == 0 {
+		return scheduleEmpty
+	}
+
+	for i, point := range s {
+		if point.Offset < 0 || point.EventsPerSecond < 0 || math.IsInf(point.EventsPerSecond, 0) || math.IsNaN(point.EventsPerSecond) {
+			return fmt.Errorf("%w: offset and events per second must not be negative at offset %s", scheduleInvalidPoint, point.Offset)
+		}
+
+		if i > 0 && point.Offset <= s[i-1].Offset {
+			return fmt.Errorf("%w: offsets must be increasing at offset %s", scheduleInvalidPoint, point.Offset)
+		}
+	}
+
+	return nil
+}
+
+// RateAt returns the rate, in events per second, at offset from the start of the generation
+func (s Schedule) RateAt(offset time.Duration) float64 {
+	if offset <= s[0].Offset {
+		return s[0].EventsPerSecond
+	}
+
+	for i := 1; i < len(s); i++ {
+		if offset < s[i].Offset {
+			from, to := s[i-1], s[i]
+			progress := float64(offset-from.Offset) / float64(to.Offset-from.Offset)
+			return from.EventsPerSecond + (to.EventsPerSecond-from.EventsPerSecond)*progress
+		}
+	}
+
+	return s[len(s)-1].EventsPerSecond
+}
+
+// offsetOfEvent returns the offset from the start of the generation the n-th event is due at, that is when the
+// integral of the rate reaches n, and false if it is never due because the rate drops to zero for good.
+func (s Schedule) offsetOfEvent(n float64) (time.Duration, bool) {
+	// before the first point the rate is constant
+	if first := s[0]; n > 0 && first.Offset > 0 {
+		events := first.EventsPerSecond * first.Offset.Seconds()
+		if n <= events {
+			return seconds(n / first.EventsPerSecond), true
+		}
+
+		n -= events
+	}
+
+	if n <= 0 {
+		return 0, true
+	}
+
+	for i := 1; i < len(s); i++ {
+		from, to := s[i-1], s[i]
+		duration := (to.Offset - from.Offset).Seconds()
+		events := (from.EventsPerSecond + to.EventsPerSecond) / 2 * duration
+		if n > events {
+			n -= events
+			continue
+		}
+
+		// solve slope/2*x^2 + rate*x = n for the seconds x after the start of the segment
+		slope := (to.EventsPerSecond - from.EventsPerSecond) / duration
+		if slope == 0 {
+			return from.Offset + seconds(n/from.EventsPerSecond), true
+		}
+
+		x := (-from.EventsPerSecond + math.Sqrt(from.EventsPerSecond*from.EventsPerSecond+2*slope*n)) / slope
+		return from.Offset + seconds(x), true
+	}
+
+	last := s[len(s)-1]
+	if last.EventsPerSecond == 0 {
+		return 0, false
+	}
+
+	return last.Offset + seconds(n/last.EventsPerSecond), true
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
+
+// GeneratorWithSchedule paces the documents of a Generator to follow the emission rate of a Schedule
+type GeneratorWithSchedule struct {
+	gen      Generator
+	schedule Schedule
+	start    time.Time
+	emitted  uint64
+	// now and sleep allow replacing the clock in tests
+	now   func() time.Time
+	sleep func(time.Duration)
+}
+
+// NewGeneratorWithSchedule returns a Generator emitting the documents of gen paced by schedule: Emit blocks until
+// the next document is due according to the rate of the schedule at the time elapsed since the first document.
+// If the rate drops to zero after the last point of the schedule, the Generator stops once the last due document
+// is emitted.
+func NewGeneratorWithSchedule(gen Generator, schedule Schedule) (*GeneratorWithSchedule, error) {
+	if err := schedule.validate(); err != nil {
+		return nil, err
+	}
+
+	return &GeneratorWithSchedule{
+		gen:      gen,
+		schedule: schedule,
+		now:      time.Now,
+		sleep:    time.Sleep,
+	}, nil
+}
+
+func (gen *GeneratorWithSchedule) Close() error {
+	return gen.gen.Close()
+}
+
+func (gen *GeneratorWithSchedule) Emit(buf *bytes.Buffer) error {
+	if gen.start.IsZero() {
+		gen.start = gen.now()
+	}
+
+	offset, ok := gen.schedule.offsetOfEvent(float64(gen.emitted))
+	if !ok {
+		return io.EOF
+	}
+
+	if wait := gen.start.Add(offset).Sub(gen.now()); wait > 0 {
+		gen.sleep(wait)
+	}
+
+	if err := gen.gen.Emit(buf); err != nil {
+		return err
+	}
+
+	gen.emitted += 1
+	return nil
+}
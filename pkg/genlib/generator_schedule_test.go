@@ -0,0 +1,141 @@
+package genlib
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/elastic/elastic-integration-corpus-generator-tool/pkg/genlib/config"
+)
+
+// fakeClock is a clock that only advances when sleeping
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func (c *fakeClock) Sleep(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+func makeGeneratorWithSchedule(t *testing.T, schedule Schedule, clock *fakeClock) *GeneratorWithSchedule {
+	template := []byte(`{ "alpha": {{.alpha}} }`)
+	g, err := NewGeneratorWithSchedule(makeGeneratorWithCustomTemplate(t, config.Config{}, Fields{{Name: "alpha", Type: FieldTypeLong}}, template, 0), schedule)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	g.now = clock.Now
+	g.sleep = clock.Sleep
+	return g
+}
+
+func Test_GeneratorWithSchedule(t *testing.T) {
+	schedule, err := LoadSchedule(strings.NewReader("offset,events_per_second\n0s,10\n10s,10\n20s,100\n30s,0\n40s,0\n50s,20\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	clock := &fakeClock{now: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)}
+	start := clock.now
+	g := makeGeneratorWithSchedule(t, schedule, clock)
+
+	// events emitted in each 10 seconds segment
+	segments := make([]int, 7)
+	for {
+		var buf bytes.Buffer
+		if err := g.Emit(&buf); err != nil {
+			t.Fatal(err)
+		}
+
+		segment := int(clock.now.Sub(start) / (10 * time.Second))
+		if segment >= len(segments) {
+			break
+		}
+
+		segments[segment] += 1
+	}
+
+	// the rate is interpolated between the points, and constant after the last one
+	expected := []float64{100, 550, 500, 0, 100, 200, 200}
+	for i, events := range segments {
+		if math.Abs(float64(events)-expected[i]) > 1 {
+			t.Errorf("Expected about %v events between %ds and %ds, got %d", expected[i], i*10, (i+1)*10, events)
+		}
+	}
+}
+
+func Test_GeneratorWithScheduleStops(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)}
+	g := makeGeneratorWithSchedule(t, Schedule{{Offset: 0, EventsPerSecond: 10}, {Offset: 2 * time.Second, EventsPerSecond: 0}}, clock)
+
+	var emitted int
+	for {
+		var buf bytes.Buffer
+		err := g.Emit(&buf)
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		emitted += 1
+	}
+
+	// 10 events due in the 2 seconds the rate drops from 10 to 0, the first one at the start
+	if emitted != 11 {
+		t.Errorf("Expected 11 events, got %d", emitted)
+	}
+}
+
+func Test_ScheduleRateAt(t *testing.T) {
+	schedule := Schedule{{Offset: time.Minute, EventsPerSecond: 10}, {Offset: 2 * time.Minute, EventsPerSecond: 30}}
+
+	testCases := []struct {
+		offset time.Duration
+		rate   float64
+	}{
+		{offset: 0, rate: 10},
+		{offset: time.Minute, rate: 10},
+		{offset: 90 * time.Second, rate: 20},
+		{offset: 2 * time.Minute, rate: 30},
+		{offset: time.Hour, rate: 30},
+	}
+
+	for _, testCase := range testCases {
+		if rate := schedule.RateAt(testCase.offset); rate != testCase.rate {
+			t.Errorf("Expected rate %v at %s, got %v", testCase.rate, testCase.offset, rate)
+		}
+	}
+}
+
+func Test_LoadScheduleInvalid(t *testing.T) {
+	testCases := []struct {
+		scenario string
+		csv      string
+		err      error
+	}{
+		{scenario: "empty", csv: "offset,events_per_second\n", err: scheduleEmpty},
+		{scenario: "invalid offset", csv: "1h,10\nlater,10\n", err: scheduleInvalidPoint},
+		{scenario: "invalid rate", csv: "0s,fast\n", err: scheduleInvalidPoint},
+		{scenario: "negative rate", csv: "0s,-1\n", err: scheduleInvalidPoint},
+		{scenario: "decreasing offsets", csv: "1h,10\n30m,10\n", err: scheduleInvalidPoint},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.scenario, func(t *testing.T) {
+			if _, err := LoadSchedule(strings.NewReader(testCase.csv)); !errors.Is(err, testCase.err) {
+				t.Errorf("Expected %v error, got %v", testCase.err, err)
+			}
+		})
+	}
+}
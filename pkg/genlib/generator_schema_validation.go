@@ -0,0 +1,365 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package genlib
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"unicode/utf8"
+)
+
+var schemaNotValid = errors.New("json schema not valid")
+
+// SchemaViolation is a violation of the JSON Schema by an emitted document
+type SchemaViolation struct {
+	// Document is the index of the document in the emitted ones
+	Document int
+	// Path is the JSON pointer of the violating value in the document
+	Path    string
+	Message string
+}
+
+func (v SchemaViolation) String() string {
+	return fmt.Sprintf("document %d at %s: %s", v.Document, v.Path, v.Message)
+}
+
+// jsonSchema is a compiled JSON Schema.
+// NOTE: only a subset of the draft 7 validation keywords is supported, the schemas with any other one are not valid:
+// `type`, `enum`, `const`, `required`, `properties`, `additionalProperties`, `items`,
+// `minimum`, `maximum`, `minLength`, `maxLength` and `pattern`. Annotations (ie: `title`) are allowed.
+type jsonSchema struct {
+	rejectAll            bool
+	types                []string
+	enum                 []any
+	constValue           any
+	hasConst             bool
+	required             []string
+	properties           map[string]*jsonSchema
+	additionalProperties *jsonSchema
+	items                *jsonSchema
+	minimum              *float64
+	maximum              *float64
+	minLength            *int
+	maxLength            *int
+	pattern              *regexp.Regexp
+}
+
+// rawJSONSchema is the JSON encoding of the supported keywords of a JSON Schema
+type rawJSONSchema struct {
+	Type                 json.RawMessage            `json:"type"`
+	Enum                 []any                      `json:"enum"`
+	Const                json.RawMessage            `json:"const"`
+	Required             []string                   `json:"required"`
+	Properties           map[string]json.RawMessage `json:"properties"`
+	AdditionalProperties json.RawMessage            `json:"additionalProperties"`
+	Items                json.RawMessage            `json:"items"`
+	Minimum              *float64                   `json:"minimum"`
+	Maximum              *float64                   `json:"maximum"`
+	MinLength            *int                       `json:"minLength"`
+	MaxLength            *int                       `json:"maxLength"`
+	Pattern              string                     `json:"pattern"`
+}
+
+// jsonSchemaKeywords are the keywords of a JSON Schema that are supported: the validation keywords of rawJSONSchema,
+// and the annotations that don't affect the validation
+var jsonSchemaKeywords = map[string]struct{}{
+	"type": {}, "enum": {}, "const": {}, "required": {}, "properties": {}, "additionalProperties": {}, "items": {},
+	"minimum": {}, "maximum": {}, "minLength": {}, "maxLength": {}, "pattern": {},
+	"$schema": {}, "$id": {}, "$comment": {}, "title": {}, "description": {}, "default": {}, "examples": {},
+}
+
+var jsonSchemaTypes = map[string]struct{}{
+	"null": {}, "boolean": {}, "object": {}, "array": {}, "number": {}, "integer": {}, "string": {},
+}
+
+func compileJSONSchema(schema []byte) (*jsonSchema, error) {
+	// boolean schemas: `true` accepts and `false` rejects any value
+	switch string(bytes.TrimSpace(schema)) {
+	case "true":
+		return &jsonSchema{}, nil
+	case "false":
+		return &jsonSchema{rejectAll: true}, nil
+	}
+
+	var keywords map[string]json.RawMessage
+	if err := json.Unmarshal(schema, &keywords); err != nil {
+		return nil, fmt.Errorf("%w: %s", schemaNotValid, err)
+	}
+
+	// the keywords that are not supported are reported rather than ignored, that would accept any value for them
+	for keyword := range keywords {
+		if _, ok := jsonSchemaKeywords[keyword]; !ok {
+			return nil, fmt.Errorf("%w: unsupported keyword %s", schemaNotValid, keyword)
+		}
+	}
+
+	var raw rawJSONSchema
+	if err := json.Unmarshal(schema, &raw); err != nil {
+		return nil, fmt.Errorf("%w: %s", schemaNotValid, err)
+	}
+
+	compiled := &jsonSchema{
+		enum:      raw.Enum,
+		required:  raw.Required,
+		minimum:   raw.Minimum,
+		maximum:   raw.Maximum,
+		minLength: raw.MinLength,
+		maxLength: raw.MaxLength,
+	}
+
+	if len(raw.Type) > 0 {
+		var types []string
+		var singleType string
+		if err := json.Unmarshal(raw.Type, &singleType); err == nil {
+			types = []string{singleType}
+		} else if err := json.Unmarshal(raw.Type, &types); err != nil {
+			return nil, fmt.Errorf("%w: type must be a string or an array of strings", schemaNotValid)
+		}
+
+		for _, t := range types {
+			if _, ok := jsonSchemaTypes[t]; !ok {
+				return nil, fmt.Errorf("%w: unknown type %s", schemaNotValid, t)
+			}
+		}
+
+		compiled.types = types
+	}
+
+	if len(raw.Const) > 0 {
+		if err := json.Unmarshal(raw.Const, &compiled.constValue); err != nil {
+			return nil, fmt.Errorf("%w: %s", schemaNotValid, err)
+		}
+
+		compiled.hasConst = true
+	}
+
+	if len(raw.Properties) > 0 {
+		compiled.properties = make(map[string]*jsonSchema, len(raw.Properties))
+		for name, property := range raw.Properties {
+			propertySchema, err := compileJSONSchema(property)
+			if err != nil {
+				return nil, err
+			}
+
+			compiled.properties[name] = propertySchema
+		}
+	}
+
+	if len(raw.AdditionalProperties) > 0 {
+		additionalProperties, err := compileJSONSchema(raw.AdditionalProperties)
+		if err != nil {
+			return nil, err
+		}
+
+		compiled.additionalProperties = additionalProperties
+	}
+
+	if len(raw.Items) > 0 {
+		items, err := compileJSONSchema(raw.Items)
+		if err != nil {
+			return nil, err
+		}
+
+		compiled.items = items
+	}
+
+	if len(raw.Pattern) > 0 {
+		pattern, err := regexp.Compile(raw.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", schemaNotValid, err)
+		}
+
+		compiled.pattern = pattern
+	}
+
+	return compiled, nil
+}
+
+// jsonType returns the JSON Schema type of a decoded JSON value
+func jsonType(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case map[string]any:
+		return "object"
+	case []any:
+		return "array"
+	case float64:
+		if v == math.Trunc(v) {
+			return "integer"
+		}
+
+		return "number"
+	default:
+		return "string"
+	}
+}
+
+// validate appends to violations the violations of the schema by value at path
+func (s *jsonSchema) validate(value any, path string, violations []SchemaViolation) []SchemaViolation {
+	if s.rejectAll {
+		return append(violations, SchemaViolation{Path: path, Message: "value not allowed"})
+	}
+
+	if s.types != nil {
+		valueType := jsonType(value)
+
+		var valid bool
+		for _, t := range s.types {
+			if t == valueType || (t == "number" && valueType == "integer") {
+				valid = true
+				break
+			}
+		}
+
+		if !valid {
+			return append(violations, SchemaViolation{Path: path, Message: fmt.Sprintf("expected type %v, got %s", s.types, valueType)})
+		}
+	}
+
+	if s.hasConst && !jsonEqual(s.constValue, value) {
+		violations = append(violations, SchemaViolation{Path: path, Message: fmt.Sprintf("expected %v", s.constValue)})
+	}
+
+	if s.enum != nil {
+		var valid bool
+		for _, v := range s.enum {
+			if jsonEqual(v, value) {
+				valid = true
+				break
+			}
+		}
+
+		if !valid {
+			violations = append(violations, SchemaViolation{Path: path, Message: fmt.Sprintf("value %v not in enum %v", value, s.enum)})
+		}
+	}
+
+	switch v := value.(type) {
+	case map[string]any:
+		for _, name := range s.required {
+			if _, ok := v[name]; !ok {
+				violations = append(violations, SchemaViolation{Path: path, Message: fmt.Sprintf("missing required property %s", name)})
+			}
+		}
+
+		// iterate the properties in order so that the violations are reported deterministically
+		names := make([]string, 0, len(v))
+		for name := range v {
+			names = append(names, name)
+		}
+
+		sort.Strings(names)
+
+		for _, name := range names {
+			if property, ok := s.properties[name]; ok {
+				violations = property.validate(v[name], path+"/"+name, violations)
+			} else if s.additionalProperties != nil {
+				violations = s.additionalProperties.validate(v[name], path+"/"+name, violations)
+			}
+		}
+	case []any:
+		if s.items != nil {
+			for i, item := range v {
+				violations = s.items.validate(item, fmt.Sprintf("%s/%d", path, i), violations)
+			}
+		}
+	case float64:
+		if s.minimum != nil && v < *s.minimum {
+			violations = append(violations, SchemaViolation{Path: path, Message: fmt.Sprintf("value %v lower than minimum %v", v, *s.minimum)})
+		}
+
+		if s.maximum != nil && v > *s.maximum {
+			violations = append(violations, SchemaViolation{Path: path, Message: fmt.Sprintf("value %v greater than maximum %v", v, *s.maximum)})
+		}
+	case string:
+		length := utf8.RuneCountInString(v)
+		if s.minLength != nil && length < *s.minLength {
+			violations = append(violations, SchemaViolation{Path: path, Message: fmt.Sprintf("length %d lower than minLength %d", length, *s.minLength)})
+		}
+
+		if s.maxLength != nil && length > *s.maxLength {
+			violations = append(violations, SchemaViolation{Path: path, Message: fmt.Sprintf("length %d greater than maxLength %d", length, *s.maxLength)})
+		}
+
+		if s.pattern != nil && !s.pattern.MatchString(v) {
+			violations = append(violations, SchemaViolation{Path: path, Message: fmt.Sprintf("value %s does not match pattern %s", v, s.pattern)})
+		}
+	}
+
+	return violations
+}
+
+func jsonEqual(a, b any) bool {
+	aJSON, _ := json.Marshal(a)
+	bJSON, _ := json.Marshal(b)
+	return bytes.Equal(aJSON, bJSON)
+}
+
+// GeneratorWithSchemaValidation wraps a Generator validating each emitted document against a JSON Schema
+type GeneratorWithSchemaValidation struct {
+	gen        Generator
+	schema     *jsonSchema
+	documents  int
+	violations []SchemaViolation
+	tmp        bytes.Buffer
+}
+
+// NewGeneratorWithSchemaValidation returns a Generator emitting the documents of gen unchanged, collecting their
+// violations of schema: see Violations. An error is returned if schema cannot be compiled.
+func NewGeneratorWithSchemaValidation(gen Generator, schema []byte) (*GeneratorWithSchemaValidation, error) {
+	compiled, err := compileJSONSchema(schema)
+	if err != nil {
+		return nil, err
+	}
+
+	return &GeneratorWithSchemaValidation{
+		gen:    gen,
+		schema: compiled,
+	}, nil
+}
+
+func (gen *GeneratorWithSchemaValidation) Close() error {
+	return gen.gen.Close()
+}
+
+func (gen *GeneratorWithSchemaValidation) Emit(buf *bytes.Buffer) error {
+	gen.tmp.Reset()
+	if err := gen.gen.Emit(&gen.tmp); err != nil {
+		return err
+	}
+
+	document := gen.documents
+	gen.documents += 1
+
+	var value any
+	if err := json.Unmarshal(gen.tmp.Bytes(), &value); err != nil {
+		gen.violations = append(gen.violations, SchemaViolation{Document: document, Path: "/", Message: fmt.Sprintf("not valid JSON: %s", err)})
+	} else {
+		for _, violation := range gen.schema.validate(value, "", nil) {
+			violation.Document = document
+			if len(violation.Path) == 0 {
+				violation.Path = "/"
+			}
+
+			gen.violations = append(gen.violations, violation)
+		}
+	}
+
+	buf.Write(gen.tmp.Bytes())
+	return nil
+}
+
+// Violations returns the violations of the JSON Schema by the documents emitted so far
+func (gen *GeneratorWithSchemaValidation) Violations() []SchemaViolation {
+	return gen.violations
+}
@@ -0,0 +1,114 @@
+package genlib
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/elastic/elastic-integration-corpus-generator-tool/pkg/genlib/config"
+)
+
+var testJSONSchema = []byte(`{
+  "type": "object",
+  "required": ["host.name", "event.duration"],
+  "properties": {
+    "host.name": {"type": "string", "minLength": 1},
+    "event.duration": {"type": "integer", "minimum": 0, "maximum": 100},
+    "event.outcome": {"enum": ["success", "failure"]}
+  },
+  "additionalProperties": false
+}`)
+
+func Test_GeneratorWithSchemaValidationValid(t *testing.T) {
+	flds := Fields{
+		{Name: "host.name", Type: FieldTypeKeyword},
+		{Name: "event.duration", Type: FieldTypeLong},
+		{Name: "event.outcome", Type: FieldTypeKeyword},
+	}
+
+	cfg, err := config.LoadConfigFromYaml([]byte(`fields:
+  - name: event.duration
+    range:
+      min: 0
+      max: 100
+  - name: event.outcome
+    enum: ["success", "failure"]
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := []byte(`{"host.name":"{{.host.name}}","event.duration":{{.event.duration}},"event.outcome":"{{.event.outcome}}"}`)
+	g, err := NewGeneratorWithSchemaValidation(makeGeneratorWithCustomTemplate(t, cfg, flds, template, 0), testJSONSchema)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	nSpins := 100
+	for i := 0; i < nSpins; i++ {
+		var buf bytes.Buffer
+		if err := g.Emit(&buf); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if len(g.Violations()) > 0 {
+		t.Errorf("Expected no violations, got %v", g.Violations())
+	}
+}
+
+func Test_GeneratorWithSchemaValidationViolations(t *testing.T) {
+	flds := Fields{
+		{Name: "event.duration", Type: FieldTypeKeyword},
+		{Name: "message", Type: FieldTypeKeyword},
+	}
+
+	// host.name is missing, event.duration is a string and message is not allowed
+	template := []byte(`{"event.duration":"{{.event.duration}}","message":"{{.message}}"}`)
+	g, err := NewGeneratorWithSchemaValidation(makeGeneratorWithCustomTemplate(t, config.Config{}, flds, template, 0), testJSONSchema)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	nSpins := 2
+	for i := 0; i < nSpins; i++ {
+		var buf bytes.Buffer
+		if err := g.Emit(&buf); err != nil {
+			t.Fatal(err)
+		}
+
+		// the document is emitted unchanged
+		if m := unmarshalJSONT[string](t, buf.Bytes()); len(m) != len(flds) {
+			t.Errorf("Expected unchanged document, got %s", buf.String())
+		}
+	}
+
+	expected := []SchemaViolation{
+		{Document: 0, Path: "/", Message: "missing required property host.name"},
+		{Document: 0, Path: "/event.duration", Message: "expected type [integer], got string"},
+		{Document: 0, Path: "/message", Message: "value not allowed"},
+		{Document: 1, Path: "/", Message: "missing required property host.name"},
+		{Document: 1, Path: "/event.duration", Message: "expected type [integer], got string"},
+		{Document: 1, Path: "/message", Message: "value not allowed"},
+	}
+
+	violations := g.Violations()
+	if len(violations) != len(expected) {
+		t.Fatalf("Expected violations %v, got %v", expected, violations)
+	}
+
+	for i := range expected {
+		if violations[i] != expected[i] {
+			t.Errorf("Expected violation %v, got %v", expected[i], violations[i])
+		}
+	}
+}
+
+func Test_GeneratorWithSchemaValidationInvalidSchema(t *testing.T) {
+	// the keywords that are not supported are not valid, rather than ignored
+	for _, schema := range []string{`{"type": "text"}`, `{"pattern": "("}`, `not json`, `{"$ref": "#/definitions/event"}`, `{"anyOf": [{"type": "string"}]}`, `{"properties": {"source.ip": {"format": "ipv4"}}}`, `{"items": {"type": "string"}, "minItems": 1}`} {
+		if _, err := NewGeneratorWithSchemaValidation(nil, []byte(schema)); !errors.Is(err, schemaNotValid) {
+			t.Errorf("Expected schemaNotValid error for %s, got %v", schema, err)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package genlib
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+var staticFieldsDocumentNotObject = errors.New("document with static fields is not a JSON object")
+
+// GeneratorWithStaticFields wraps a Generator adding the same fields to each document,
+// ie: to stamp the corpus with the version of the fields schema it is generated from
+type GeneratorWithStaticFields struct {
+	gen    Generator
+	fields []byte
+	tmp    bytes.Buffer
+}
+
+// NewGeneratorWithStaticFields returns a Generator emitting the documents of gen with the staticFields added
+// as first keys, sorted by name. An error is returned if a value cannot be encoded as JSON.
+func NewGeneratorWithStaticFields(gen Generator, staticFields map[string]any) (*GeneratorWithStaticFields, error) {
+	names := make([]string, 0, len(staticFields))
+	for name := range staticFields {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	var fields bytes.Buffer
+	for _, name := range names {
+		key, _ := json.Marshal(name)
+		value, err := json.Marshal(staticFields[name])
+		if err != nil {
+			return nil, fmt.Errorf("cannot encode static field %s: %w", name, err)
+		}
+
+		fields.Write(key)
+		fields.WriteByte(':')
+		fields.Write(value)
+		fields.WriteByte(',')
+	}
+
+	return &GeneratorWithStaticFields{
+		gen:    gen,
+		fields: fields.Bytes(),
+	}, nil
+}
+
+func (gen *GeneratorWithStaticFields) Close() error {
+	return gen.gen.Close()
+}
+
+func (gen *GeneratorWithStaticFields) Emit(buf *bytes.Buffer) error {
+	gen.tmp.Reset()
+	if err := gen.gen.Emit(&gen.tmp); err != nil {
+		return err
+	}
+
+	document := bytes.TrimSpace(gen.tmp.Bytes())
+	if len(document) < 2 || document[0] != '{' || document[len(document)-1] != '}' {
+		return fmt.Errorf("%w: %s", staticFieldsDocumentNotObject, gen.tmp.Bytes())
+	}
+
+	if len(gen.fields) == 0 {
+		buf.Write(document)
+		return nil
+	}
+
+	// the static fields are spliced after the opening brace, so that the document is not re-encoded
+	buf.WriteByte('{')
+	rest := bytes.TrimSpace(document[1:])
+	if rest[0] == '}' {
+		// empty document: drop the trailing comma
+		buf.Write(gen.fields[:len(gen.fields)-1])
+	} else {
+		buf.Write(gen.fields)
+	}
+
+	buf.Write(rest)
+	return nil
+}
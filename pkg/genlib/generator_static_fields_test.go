@@ -0,0 +1,71 @@
+package genlib
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+type documentsGenerator struct {
+	documents []string
+	i         int
+}
+
+func (gen *documentsGenerator) Emit(buf *bytes.Buffer) error {
+	buf.WriteString(gen.documents[gen.i%len(gen.documents)])
+	gen.i += 1
+	return nil
+}
+
+func (gen *documentsGenerator) Close() error {
+	return nil
+}
+
+func Test_GeneratorWithStaticFields(t *testing.T) {
+	documents := &documentsGenerator{documents: []string{`{"alpha":"a"}`, `{}`, ` { "beta" : 1 } `}}
+	g, err := NewGeneratorWithStaticFields(documents, map[string]any{"schema.version": "8.2.0", "schema.hash": "abc"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []map[string]any{
+		{"schema.version": "8.2.0", "schema.hash": "abc", "alpha": "a"},
+		{"schema.version": "8.2.0", "schema.hash": "abc"},
+		{"schema.version": "8.2.0", "schema.hash": "abc", "beta": float64(1)},
+	}
+
+	for _, e := range expected {
+		var buf bytes.Buffer
+		if err := g.Emit(&buf); err != nil {
+			t.Fatal(err)
+		}
+
+		var m map[string]any
+		if err := json.Unmarshal(buf.Bytes(), &m); err != nil {
+			t.Fatalf("Expected valid JSON, got %s: %s", buf.String(), err)
+		}
+
+		if len(m) != len(e) {
+			t.Errorf("Expected %v, got %s", e, buf.String())
+		}
+
+		for k, v := range e {
+			if m[k] != v {
+				t.Errorf("Expected %s to be %v, got %s", k, v, buf.String())
+			}
+		}
+	}
+}
+
+func Test_GeneratorWithStaticFieldsNotObject(t *testing.T) {
+	g, err := NewGeneratorWithStaticFields(&documentsGenerator{documents: []string{`["alpha"]`}}, map[string]any{"schema.version": "8.2.0"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := g.Emit(&buf); !errors.Is(err, staticFieldsDocumentNotObject) {
+		t.Errorf("Expected staticFieldsDocumentNotObject, got %v", err)
+	}
+}
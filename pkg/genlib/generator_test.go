@@ -3,11 +3,17 @@ package genlib
 import (
 	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 	"github.com/elastic/elastic-integration-corpus-generator-tool/pkg/genlib/config"
 	"github.com/elastic/elastic-integration-corpus-generator-tool/pkg/genlib/fields"
 	"log"
+	"math"
 	"math/rand"
+	"net"
 	"os"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 )
@@ -329,3 +335,1134 @@ func Benchmark_GeneratorTextTemplateVPCFlowLogs(b *testing.B) {
 		buf.Reset()
 	}
 }
+
+func Test_DynamicObjectKeysReproducibleWithSeed(t *testing.T) {
+	flds := Fields{
+		{
+			Name: "labels.*",
+			Type: FieldTypeObject,
+		},
+		{
+			Name:       "tags",
+			Type:       FieldTypeObject,
+			ObjectType: FieldTypeLong,
+		},
+	}
+
+	generate := func() ([]byte, []Field) {
+		InitGeneratorRandSeed(42)
+
+		var templates [][]byte
+		var objectKeysFields []Field
+		// Several rounds, so that dupe detection between generated keys kicks in
+		for i := 0; i < 20; i++ {
+			template, objectKeysField := generateCustomTemplateFromField(Config{}, flds)
+			templates = append(templates, template)
+			objectKeysFields = append(objectKeysFields, objectKeysField...)
+		}
+
+		return bytes.Join(templates, []byte("\n")), objectKeysFields
+	}
+
+	firstTemplate, firstObjectKeysFields := generate()
+	secondTemplate, secondObjectKeysFields := generate()
+
+	if !bytes.Equal(firstTemplate, secondTemplate) {
+		t.Errorf("Expected identical templates with the same seed:\n%s\n%s", firstTemplate, secondTemplate)
+	}
+
+	if len(firstObjectKeysFields) != len(secondObjectKeysFields) {
+		t.Fatalf("Expected identical dynamic keys with the same seed, got %d and %d keys", len(firstObjectKeysFields), len(secondObjectKeysFields))
+	}
+
+	for i := range firstObjectKeysFields {
+		if firstObjectKeysFields[i].Name != secondObjectKeysFields[i].Name {
+			t.Errorf("Expected identical dynamic key at position %d, got %s and %s", i, firstObjectKeysFields[i].Name, secondObjectKeysFields[i].Name)
+		}
+	}
+}
+
+func Test_IllegalConfigCombination(t *testing.T) {
+	testCases := []struct {
+		scenario  string
+		fieldType string
+		config    string
+		hasError  bool
+	}{
+		{
+			scenario:  "enum and range",
+			fieldType: FieldTypeKeyword,
+			config:    "fields:\n  - name: alpha\n    enum: [\"a\", \"b\"]\n    range:\n      min: 1\n      max: 10",
+			hasError:  true,
+		},
+		{
+			scenario:  "value and cardinality",
+			fieldType: FieldTypeKeyword,
+			config:    "fields:\n  - name: alpha\n    value: a\n    cardinality: 10",
+			hasError:  true,
+		},
+		{
+			scenario:  "value and enum",
+			fieldType: FieldTypeKeyword,
+			config:    "fields:\n  - name: alpha\n    value: a\n    enum: [\"a\", \"b\"]",
+			hasError:  true,
+		},
+		{
+			scenario:  "raw_json and range",
+			fieldType: FieldTypeLong,
+			config:    "fields:\n  - name: alpha\n    raw_json: '{\"a\": 1}'\n    range:\n      min: 1",
+			hasError:  true,
+		},
+		{
+			scenario:  "unique and cardinality",
+			fieldType: FieldTypeLong,
+			config:    "fields:\n  - name: alpha\n    unique: true\n    cardinality: 10",
+			hasError:  true,
+		},
+		{
+			scenario:  "sequence and enum",
+			fieldType: FieldTypeKeyword,
+			config:    "fields:\n  - name: alpha\n    sequence: [\"a\", \"b\"]\n    enum: [\"a\", \"b\"]",
+			hasError:  true,
+		},
+		{
+			scenario:  "value and array",
+			fieldType: FieldTypeKeyword,
+			config:    "fields:\n  - name: alpha\n    value: a\n    array:\n      max: 2",
+			hasError:  true,
+		},
+		{
+			scenario:  "aligned mode and cardinality",
+			fieldType: FieldTypeDate,
+			config:    "fields:\n  - name: alpha\n    mode: aligned\n    interval: 1s\n    cardinality: 3",
+			hasError:  true,
+		},
+		{
+			scenario:  "monotonic mode and cardinality",
+			fieldType: FieldTypeDate,
+			config:    "fields:\n  - name: alpha\n    mode: monotonic\n    max_delta: 1s\n    cardinality: 3",
+			hasError:  true,
+		},
+		{
+			scenario:  "enum and cardinality",
+			fieldType: FieldTypeKeyword,
+			config:    "fields:\n  - name: alpha\n    enum: [\"a\", \"b\"]\n    cardinality: 2",
+		},
+		{
+			scenario:  "enum and range for money",
+			fieldType: FieldTypeMoney,
+			config:    "fields:\n  - name: alpha\n    enum: [\"USD\"]\n    range:\n      min: 1\n      max: 10",
+		},
+		{
+			scenario:  "enum and range for tls",
+			fieldType: FieldTypeTLS,
+			config:    "fields:\n  - name: alpha\n    enum: [\"1.3\"]\n    range:\n      from: \"2023-01-01T00:00:00-00:00\"",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.scenario, func(t *testing.T) {
+			cfg, err := config.LoadConfigFromYaml([]byte(testCase.config))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			flds := Fields{{Name: "alpha", Type: testCase.fieldType}}
+			_, err = NewGenerator(cfg, flds, 0)
+			if testCase.hasError && !errors.Is(err, illegalConfigCombination) {
+				t.Errorf("Expected illegalConfigCombination error, got %v", err)
+			}
+
+			if !testCase.hasError && err != nil {
+				t.Errorf("Unexpected error %v", err)
+			}
+		})
+	}
+}
+
+func Test_KeywordEnumReachesAllValues(t *testing.T) {
+	testCases := []struct {
+		scenario string
+		config   string
+		enum     []string
+	}{
+		{
+			scenario: "single value",
+			config:   "fields:\n  - name: alpha\n    enum: [\"OK\"]",
+			enum:     []string{"OK"},
+		},
+		{
+			scenario: "two values",
+			config:   "fields:\n  - name: alpha\n    enum: [\"OK\", \"KO\"]",
+			enum:     []string{"OK", "KO"},
+		},
+		{
+			scenario: "five values",
+			config:   "fields:\n  - name: alpha\n    enum: [\"a\", \"b\", \"c\", \"d\", \"e\"]",
+			enum:     []string{"a", "b", "c", "d", "e"},
+		},
+		{
+			scenario: "single value with zipf distribution",
+			config:   "fields:\n  - name: alpha\n    enum: [\"OK\"]\n    distribution: zipf",
+			enum:     []string{"OK"},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.scenario, func(t *testing.T) {
+			cfg, err := config.LoadConfigFromYaml([]byte(testCase.config))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			nSpins := 1000
+			g, err := NewGenerator(cfg, Fields{{Name: "alpha", Type: FieldTypeKeyword}}, uint64(nSpins))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			seen := make(map[string]int)
+			for i := 0; i < nSpins; i++ {
+				var buf bytes.Buffer
+				if err := g.Emit(&buf); err != nil {
+					t.Fatal(err)
+				}
+
+				m := unmarshalJSONT[string](t, buf.Bytes())
+				seen[m["alpha"]] += 1
+			}
+
+			for _, value := range testCase.enum {
+				if seen[value] == 0 {
+					t.Errorf("Expected enum value %s to be emitted, got %v", value, seen)
+				}
+			}
+
+			if len(seen) != len(testCase.enum) {
+				t.Errorf("Expected only enum values %v, got %v", testCase.enum, seen)
+			}
+		})
+	}
+}
+
+func Test_ULIDEncoding(t *testing.T) {
+	// 01ARZ3NDEKTSV4RRFFQ69G5FAV is the example ULID of the specification
+	u := ulid{0x01, 0x56, 0x3e, 0x3a, 0xb5, 0xd3, 0xd6, 0x76, 0x4c, 0x61, 0xef, 0xb9, 0x93, 0x02, 0xbd, 0x5b}
+	if u.String() != "01ARZ3NDEKTSV4RRFFQ69G5FAV" {
+		t.Errorf("Expected 01ARZ3NDEKTSV4RRFFQ69G5FAV, got %s", u.String())
+	}
+
+	next := u.next(customRand, 0x01563e3ab5d3)
+	if next.String() != "01ARZ3NDEKTSV4RRFFQ69G5FAW" {
+		t.Errorf("Expected 01ARZ3NDEKTSV4RRFFQ69G5FAW in the same millisecond, got %s", next.String())
+	}
+
+	later := u.next(customRand, 0x01563e3ab5d4)
+	if later.String()[:10] != "01ARZ3NDEM" {
+		t.Errorf("Expected timestamp 01ARZ3NDEM in the next millisecond, got %s", later.String())
+	}
+}
+
+func Test_RangeMinMaxBounds(t *testing.T) {
+	cfg, err := config.LoadConfigFromYaml([]byte("fields:\n  - name: port\n    range:\n      min: 1024\n      max: 65535\n  - name: ratio\n    range:\n      min: 1024\n      max: 65535"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	nSpins := 1000
+	g, err := NewGenerator(cfg, Fields{{Name: "port", Type: FieldTypeLong}, {Name: "ratio", Type: FieldTypeDouble}}, uint64(nSpins))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < nSpins; i++ {
+		var buf bytes.Buffer
+		if err := g.Emit(&buf); err != nil {
+			t.Fatal(err)
+		}
+
+		m := unmarshalJSONT[float64](t, buf.Bytes())
+		for _, name := range []string{"port", "ratio"} {
+			if m[name] < 1024 || m[name] > 65535 {
+				t.Errorf("Expected %s between 1024 and 65535, got %v", name, m[name])
+			}
+		}
+	}
+}
+
+func Test_LongRangeBounds(t *testing.T) {
+	cfg, err := config.LoadConfigFromYaml([]byte("fields:\n  - name: single\n    min: 10\n    max: 10\n  - name: pair\n    min: 1\n    max: 2\n  - name: negative\n    min: -5\n    max: -1\n  - name: min_only\n    min: 100\n  - name: byte_min_only\n    min: 100\n  - name: range_pair\n    range:\n      min: 1\n      max: 2"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	flds := Fields{
+		{Name: "single", Type: FieldTypeLong},
+		{Name: "pair", Type: FieldTypeLong},
+		{Name: "negative", Type: FieldTypeLong},
+		{Name: "min_only", Type: FieldTypeLong},
+		{Name: "byte_min_only", Type: FieldTypeByte},
+		{Name: "range_pair", Type: FieldTypeLong},
+	}
+
+	// the bounds are inclusive, and a missing max defaults to the largest value of the integer type,
+	// while the max of `range` is exclusive
+	bounds := map[string][2]float64{
+		"single":        {10, 10},
+		"pair":          {1, 2},
+		"negative":      {-5, -1},
+		"min_only":      {100, math.MaxInt64},
+		"byte_min_only": {100, math.MaxInt8},
+		"range_pair":    {1, 1},
+	}
+
+	nSpins := 1000
+	g, err := NewGenerator(cfg, flds, uint64(nSpins))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	distinct := make(map[string]map[float64]struct{})
+	for i := 0; i < nSpins; i++ {
+		var buf bytes.Buffer
+		if err := g.Emit(&buf); err != nil {
+			t.Fatal(err)
+		}
+
+		m := unmarshalJSONT[float64](t, buf.Bytes())
+		for name, bound := range bounds {
+			if m[name] < bound[0] || m[name] > bound[1] {
+				t.Errorf("Expected %s between %v and %v, got %v", name, bound[0], bound[1], m[name])
+			}
+
+			if distinct[name] == nil {
+				distinct[name] = make(map[float64]struct{})
+			}
+
+			distinct[name][m[name]] = struct{}{}
+		}
+	}
+
+	if len(distinct["pair"]) != 2 {
+		t.Errorf("Expected both 1 and 2 for pair, got %v", distinct["pair"])
+	}
+
+	if len(distinct["negative"]) != 5 {
+		t.Errorf("Expected all the values between -5 and -1 for negative, got %v", distinct["negative"])
+	}
+}
+
+func Test_DoublesAreFinite(t *testing.T) {
+	cfg, err := config.LoadConfigFromYaml([]byte("fields:\n  - name: negative\n    range:\n      min: -10\n      max: -1\n  - name: across\n    range:\n      min: -5\n      max: 5\n  - name: huge\n    range:\n      min: -1.7e308\n      max: 1.7e308\n  - name: min_only\n    range:\n      min: 100\n  - name: max_only\n    range:\n      max: -100"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	flds := Fields{
+		{Name: "default", Type: FieldTypeDouble},
+		{Name: "negative", Type: FieldTypeDouble},
+		{Name: "across", Type: FieldTypeDouble},
+		{Name: "huge", Type: FieldTypeDouble},
+		{Name: "min_only", Type: FieldTypeDouble},
+		{Name: "max_only", Type: FieldTypeDouble},
+	}
+
+	bounds := map[string][2]float64{
+		"negative": {-10, -1},
+		"across":   {-5, 5},
+		"huge":     {-1.7e308, 1.7e308},
+		"min_only": {100, math.MaxFloat64},
+		"max_only": {-math.MaxFloat64, -100},
+	}
+
+	nSpins := 100000
+	g, err := NewGenerator(cfg, flds, uint64(nSpins))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var negatives int
+	for i := 0; i < nSpins; i++ {
+		var buf bytes.Buffer
+		if err := g.Emit(&buf); err != nil {
+			t.Fatal(err)
+		}
+
+		m := unmarshalJSONT[json.RawMessage](t, buf.Bytes())
+		for _, field := range flds {
+			value, err := strconv.ParseFloat(string(m[field.Name]), 64)
+			if err != nil {
+				t.Fatalf("Expected %s to parse as float, got %s: %s", field.Name, m[field.Name], err)
+			}
+
+			if math.IsNaN(value) || math.IsInf(value, 0) {
+				t.Fatalf("Expected finite %s, got %v", field.Name, value)
+			}
+
+			if b, ok := bounds[field.Name]; ok && (value < b[0] || value > b[1]) {
+				t.Errorf("Expected %s between %v and %v, got %v", field.Name, b[0], b[1], value)
+			}
+
+			if field.Name == "across" && value < 0 {
+				negatives += 1
+			}
+		}
+	}
+
+	if negatives == 0 {
+		t.Errorf("Expected negative values for a range with negative min")
+	}
+}
+
+func Test_IPPoolsWeights(t *testing.T) {
+	cfg, err := config.LoadConfigFromYaml([]byte("fields:\n  - name: source.ip\n    ip_pools:\n      - cidr: 10.0.0.0/8\n        weight: 0.7\n      - cidr: 192.168.0.0/16\n        weight: 0.2\n      - cidr: 2001:db8::/32\n        weight: 0.1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pools := []struct {
+		cidr   string
+		weight float64
+	}{
+		{cidr: "10.0.0.0/8", weight: 0.7},
+		{cidr: "192.168.0.0/16", weight: 0.2},
+		{cidr: "2001:db8::/32", weight: 0.1},
+	}
+
+	flds := Fields{{Name: "source.ip", Type: FieldTypeIP}}
+
+	nSpins := 10000
+	g, err := NewGenerator(cfg, flds, uint64(nSpins))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	counts := make([]int, len(pools))
+	for i := 0; i < nSpins; i++ {
+		var buf bytes.Buffer
+		if err := g.Emit(&buf); err != nil {
+			t.Fatal(err)
+		}
+
+		m := unmarshalJSONT[string](t, buf.Bytes())
+		ip := net.ParseIP(m["source.ip"])
+		if ip == nil {
+			t.Fatalf("Expected valid IP, got %s", m["source.ip"])
+		}
+
+		inPool := false
+		for j, pool := range pools {
+			_, ipNet, _ := net.ParseCIDR(pool.cidr)
+			if ipNet.Contains(ip) {
+				counts[j] += 1
+				inPool = true
+				break
+			}
+		}
+
+		if !inPool {
+			t.Errorf("Expected IP within the pools, got %s", ip)
+		}
+	}
+
+	for j, pool := range pools {
+		proportion := float64(counts[j]) / float64(nSpins)
+		if math.Abs(proportion-pool.weight) > 0.02 {
+			t.Errorf("Expected proportion of %s close to %v, got %v", pool.cidr, pool.weight, proportion)
+		}
+	}
+}
+
+func Test_IPPoolsInvalidConfig(t *testing.T) {
+	testCases := []struct {
+		scenario string
+		config   string
+	}{
+		{
+			scenario: "invalid cidr",
+			config:   "fields:\n  - name: alpha\n    ip_pools:\n      - cidr: 10.0.0.0/33\n        weight: 1",
+		},
+		{
+			scenario: "negative weight",
+			config:   "fields:\n  - name: alpha\n    ip_pools:\n      - cidr: 10.0.0.0/8\n        weight: -1",
+		},
+		{
+			scenario: "zero weights",
+			config:   "fields:\n  - name: alpha\n    ip_pools:\n      - cidr: 10.0.0.0/8",
+		},
+		{
+			scenario: "ip_version",
+			config:   "fields:\n  - name: alpha\n    ip_version: v6\n    ip_pools:\n      - cidr: 10.0.0.0/8\n        weight: 1",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.scenario, func(t *testing.T) {
+			cfg, err := config.LoadConfigFromYaml([]byte(testCase.config))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if _, err := NewGenerator(cfg, Fields{{Name: "alpha", Type: FieldTypeIP}}, 0); err == nil {
+				t.Errorf("Expected error")
+			}
+		})
+	}
+}
+
+func Test_OutputReproducibleWithSeed(t *testing.T) {
+	var flds Fields
+	for _, fieldType := range []string{
+		FieldTypeBool, FieldTypeKeyword, FieldTypeDate, FieldTypeIP, FieldTypeDouble, FieldTypeFloat, FieldTypeLong,
+		FieldTypeGeoPoint, FieldTypeHostname, FieldTypeMoney, FieldTypeCIDR, FieldTypeASN, FieldTypePersonName,
+		FieldTypePath, FieldTypeOS, FieldTypeProcess, FieldTypeSQLStatement, FieldTypeCloud, FieldTypeValidity,
+		FieldTypeKubernetes, FieldTypeDNS, FieldTypeSession, FieldTypeULID, FieldTypeUUID, FieldTypeHexToken, FieldTypeRegistryPath,
+		FieldTypeEventCategorization, FieldTypeGeoShape, FieldTypePoint, FieldTypeUser, FieldTypeText,
+		FieldTypeWebTransaction,
+	} {
+		flds = append(flds, Field{Name: "field_" + fieldType, Type: fieldType})
+	}
+
+	flds = append(flds, Field{Name: "message", Type: FieldTypeKeyword, Example: "a message"})
+
+	template := []byte(`{{ generate "field_ip" }} {{ generate "field_keyword" }} {{ awsAZFromRegion "us-east-1" }}`)
+	timeNow := time.Now()
+	nSpins := 100
+
+	generate := func() []byte {
+		InitGeneratorTimeNow(timeNow)
+		InitGeneratorRandSeed(42)
+
+		g, err := NewGenerator(Config{}, flds, uint64(nSpins))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var buf bytes.Buffer
+		for i := 0; i < nSpins; i++ {
+			if err := g.Emit(&buf); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		g = makeGeneratorWithTextTemplate(t, Config{}, flds, template, uint64(nSpins))
+		for i := 0; i < nSpins; i++ {
+			if err := g.Emit(&buf); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		return buf.Bytes()
+	}
+
+	first := generate()
+	second := generate()
+	if !bytes.Equal(first, second) {
+		t.Errorf("Expected identical output with the same seed")
+	}
+}
+
+// decodeGeohash returns the center of the cell of geohash and the maximum error of its latitude and longitude
+func decodeGeohash(geohash string) (float64, float64, float64, float64) {
+	latInterval := [2]float64{-90, 90}
+	lonInterval := [2]float64{-180, 180}
+
+	isLon := true
+	for _, c := range geohash {
+		ch := strings.IndexRune(geohashAlphabet, c)
+		for mask := 16; mask > 0; mask >>= 1 {
+			interval := &latInterval
+			if isLon {
+				interval = &lonInterval
+			}
+
+			mid := (interval[0] + interval[1]) / 2
+			if ch&mask != 0 {
+				interval[0] = mid
+			} else {
+				interval[1] = mid
+			}
+
+			isLon = !isLon
+		}
+	}
+
+	return (latInterval[0] + latInterval[1]) / 2, (lonInterval[0] + lonInterval[1]) / 2, (latInterval[1] - latInterval[0]) / 2, (lonInterval[1] - lonInterval[0]) / 2
+}
+
+// checkGeoJSONGeometry checks that geometry is a GeoJSON geometry accepted by the Elasticsearch geo_shape parser, and
+// returns its type: positions within bounds, line strings of at least two positions, and polygons of a closed
+// counterclockwise linear ring of at least four positions
+func checkGeoJSONGeometry(t *testing.T, geometry json.RawMessage) string {
+	var g struct {
+		Type        string          `json:"type"`
+		Coordinates json.RawMessage `json:"coordinates"`
+	}
+
+	if err := json.Unmarshal(geometry, &g); err != nil {
+		t.Fatalf("Expected a GeoJSON geometry, got %s: %v", geometry, err)
+	}
+
+	var positions [][2]float64
+	switch g.Type {
+	case geometryTypePoint:
+		var position [2]float64
+		if err := json.Unmarshal(g.Coordinates, &position); err != nil {
+			t.Fatal(err)
+		}
+
+		positions = append(positions, position)
+	case geometryTypeLineString:
+		if err := json.Unmarshal(g.Coordinates, &positions); err != nil {
+			t.Fatal(err)
+		}
+
+		if len(positions) < 2 {
+			t.Errorf("Expected a line string of at least 2 positions, got %s", geometry)
+		}
+	case geometryTypePolygon:
+		var rings [][][2]float64
+		if err := json.Unmarshal(g.Coordinates, &rings); err != nil {
+			t.Fatal(err)
+		}
+
+		if len(rings) != 1 {
+			t.Fatalf("Expected a polygon of a single linear ring, got %s", geometry)
+		}
+
+		positions = rings[0]
+		if len(positions) < 4 || positions[0] != positions[len(positions)-1] {
+			t.Errorf("Expected a closed linear ring of at least 4 positions, got %s", geometry)
+		}
+
+		var area float64
+		for i := 0; i < len(positions)-1; i++ {
+			area += positions[i][0]*positions[i+1][1] - positions[i+1][0]*positions[i][1]
+		}
+
+		if area <= 0 {
+			t.Errorf("Expected a counterclockwise linear ring, got %s", geometry)
+		}
+	default:
+		t.Fatalf("Expected a Point, LineString or Polygon, got %s", geometry)
+	}
+
+	for _, position := range positions {
+		if position[0] < -180 || position[0] > 180 || position[1] < -90 || position[1] > 90 {
+			t.Errorf("Expected longitude and latitude within bounds, got %s", geometry)
+		}
+	}
+
+	return g.Type
+}
+
+func Test_GeohashDecodesWithinPrecision(t *testing.T) {
+	// well-known geohash of the Jutland peninsula, from the original geohash specification
+	if geohash := encodeGeohash(57.64911, 10.40744, 11); geohash != "u4pruydqqvj" {
+		t.Errorf("Expected geohash u4pruydqqvj, got %s", geohash)
+	}
+
+	nSpins := 10000
+	for i := 0; i < nSpins; i++ {
+		lat := customRand.Float64()*180 - 90
+		lon := customRand.Float64()*360 - 180
+		precision := 1 + customRand.Intn(geohashMaxPrecision)
+
+		geohash := encodeGeohash(lat, lon, precision)
+		decodedLat, decodedLon, latErr, lonErr := decodeGeohash(geohash)
+		if math.Abs(decodedLat-lat) > latErr || math.Abs(decodedLon-lon) > lonErr {
+			t.Fatalf("Expected geohash %s to decode within %f,%f of %f,%f, got %f,%f", geohash, latErr, lonErr, lat, lon, decodedLat, decodedLon)
+		}
+	}
+}
+
+func Test_GeoPointInvalidConfig(t *testing.T) {
+	testCases := []struct {
+		scenario string
+		config   string
+	}{
+		{
+			scenario: "unknown geo_format",
+			config:   "fields:\n  - name: alpha\n    geo_format: wkt",
+		},
+		{
+			scenario: "geohash_precision too high",
+			config:   "fields:\n  - name: alpha\n    geo_format: geohash\n    geohash_precision: 13",
+		},
+		{
+			scenario: "geohash_precision without geo_format",
+			config:   "fields:\n  - name: alpha\n    geohash_precision: 5",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.scenario, func(t *testing.T) {
+			cfg, err := config.LoadConfigFromYaml([]byte(testCase.config))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if _, err := NewGenerator(cfg, Fields{{Name: "alpha", Type: FieldTypeGeoPoint}}, 0); err == nil {
+				t.Errorf("Expected an error")
+			}
+		})
+	}
+}
+
+func Test_GeoShapeInvalidConfig(t *testing.T) {
+	cfg, err := config.LoadConfigFromYaml([]byte("fields:\n  - name: alpha\n    geometry_types: [Point, MultiPolygon]"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := NewGenerator(cfg, Fields{{Name: "alpha", Type: FieldTypeGeoShape}}, 0); err == nil {
+		t.Errorf("Expected error for unknown geometry type")
+	}
+}
+
+func Test_RegistryPathInvalidConfig(t *testing.T) {
+	testCases := []struct {
+		scenario string
+		config   string
+	}{
+		{
+			scenario: "unknown hive",
+			config:   "fields:\n  - name: alpha\n    hives: [\"HKLM\", \"HKEY_UNKNOWN\"]",
+		},
+		{
+			scenario: "invalid depth range",
+			config:   "fields:\n  - name: alpha\n    range:\n      min: 0\n      max: 3",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.scenario, func(t *testing.T) {
+			cfg, err := config.LoadConfigFromYaml([]byte(testCase.config))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if _, err := NewGenerator(cfg, Fields{{Name: "alpha", Type: FieldTypeRegistryPath}}, 0); err == nil {
+				t.Errorf("Expected an error")
+			}
+		})
+	}
+}
+
+func Test_DerivedDirectionInvalidConfig(t *testing.T) {
+	testCases := []struct {
+		scenario string
+		config   string
+	}{
+		{
+			scenario: "internal_networks without direction_of",
+			config:   "fields:\n  - name: network.direction\n    internal_networks: [10.0.0.0/8]",
+		},
+		{
+			scenario: "single ip field",
+			config:   "fields:\n  - name: network.direction\n    direction_of: [source.ip]",
+		},
+		{
+			scenario: "undefined ip field",
+			config:   "fields:\n  - name: network.direction\n    direction_of: [source.ip, client.ip]",
+		},
+		{
+			scenario: "invalid cidr",
+			config:   "fields:\n  - name: network.direction\n    direction_of: [source.ip, destination.ip]\n    internal_networks: [10.0.0.0/33]",
+		},
+	}
+
+	flds := Fields{
+		{Name: "network.direction", Type: FieldTypeKeyword},
+		{Name: "source.ip", Type: FieldTypeIP},
+		{Name: "destination.ip", Type: FieldTypeIP},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.scenario, func(t *testing.T) {
+			cfg, err := config.LoadConfigFromYaml([]byte(testCase.config))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if _, err := NewGenerator(cfg, flds, 0); err == nil {
+				t.Errorf("Expected an error")
+			}
+		})
+	}
+}
+
+func Test_CacheForInvalidConfig(t *testing.T) {
+	cfg, err := config.LoadConfigFromYaml([]byte("fields:\n  - name: alpha\n    cache_for: -1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := NewGenerator(cfg, Fields{{Name: "alpha", Type: FieldTypeLong}}, 0); err == nil {
+		t.Errorf("Expected an error")
+	}
+}
+
+func Test_TimeNowDefaultsToWallClock(t *testing.T) {
+	defer InitGeneratorTimeNow(timeNowToBind)
+	InitGeneratorTimeNow(time.Time{})
+
+	flds := Fields{{Name: "alpha", Type: FieldTypeDate}}
+	cfg, err := config.LoadConfigFromYaml([]byte("fields:\n  - name: alpha\n    mode: aligned\n    interval: 1s\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	before := time.Now()
+	g, err := NewGenerator(cfg, flds, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	after := time.Now()
+
+	var buf bytes.Buffer
+	if err := g.Emit(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	// the first aligned date is the base, that defaults to the time now of the generator
+	m := unmarshalJSONT[string](t, buf.Bytes())
+	alpha, err := time.Parse(time.RFC3339Nano, m["alpha"])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if alpha.Before(before.Truncate(time.Microsecond)) || alpha.After(after) {
+		t.Errorf("Expected a date between %s and %s, got %s", before, after, alpha)
+	}
+}
+
+func Test_TLSUnknownVersion(t *testing.T) {
+	cfg, err := config.LoadConfigFromYaml([]byte("fields:\n  - name: tls\n    enum: [\"1.4\"]"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := NewGenerator(cfg, Fields{{Name: "tls", Type: FieldTypeTLS}}, 0); err == nil {
+		t.Errorf("Expected error for unknown TLS version")
+	}
+}
+
+func Test_EventCategorizationUnknownCategory(t *testing.T) {
+	cfg, err := config.LoadConfigFromYaml([]byte("fields:\n  - name: event\n    enum: [\"not_a_category\"]"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := NewGenerator(cfg, Fields{{Name: "event", Type: FieldTypeEventCategorization}}, 0); err == nil {
+		t.Errorf("Expected error for unknown ECS event category")
+	}
+}
+
+func Test_ECSEventActionsForAllowedTypes(t *testing.T) {
+	// every allowed combination of category and type must have actions, and only allowed combinations
+	for category, eventTypes := range ecsEventTypes {
+		if len(ecsEventActions[category]) != len(eventTypes) {
+			t.Errorf("Expected actions for the %d types allowed with %s, got %d", len(eventTypes), category, len(ecsEventActions[category]))
+		}
+
+		for _, eventType := range eventTypes {
+			if len(ecsEventActions[category][eventType]) == 0 {
+				t.Errorf("Expected actions for %s/%s", category, eventType)
+			}
+		}
+	}
+
+	if len(ecsEventActions) != len(ecsEventTypes) {
+		t.Errorf("Expected actions for %d categories, got %d", len(ecsEventTypes), len(ecsEventActions))
+	}
+}
+
+func Test_NullProbabilityInvalidConfig(t *testing.T) {
+	for _, fieldConfig := range []string{
+		"first_only: true",
+		"keyword_multi_field: true",
+	} {
+		cfg, err := config.LoadConfigFromYaml([]byte("fields:\n  - name: alpha\n    null_probability: 0.5\n    " + fieldConfig))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := NewGenerator(cfg, Fields{{Name: "alpha", Type: FieldTypeKeyword}}, 0); !errors.Is(err, illegalConfigCombination) {
+			t.Errorf("Expected illegal config combination for %s, got %v", fieldConfig, err)
+		}
+	}
+
+	// a group of fields can't be omitted as a whole
+	cfg, err := config.LoadConfigFromYaml([]byte("fields:\n  - name: host.os\n    null_probability: 0.5"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := NewGenerator(cfg, Fields{{Name: "host.os", Type: FieldTypeOS}}, 0); !errors.Is(err, illegalConfigCombination) {
+		t.Errorf("Expected illegal config combination for a group of fields, got %v", err)
+	}
+}
+
+func Test_DimensionInvalidConfig(t *testing.T) {
+	cfg, err := config.LoadConfigFromYaml([]byte("fields:\n  - name: host.name\n    dimension: true"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := NewGenerator(cfg, Fields{{Name: "host.name", Type: FieldTypeKeyword}}, 0); err == nil {
+		t.Errorf("Expected error for a dimension without enum")
+	}
+}
+
+func Test_TextInvalidConfig(t *testing.T) {
+	for _, words := range []string{"{min: 0, max: 3}", "{min: 5, max: 3}"} {
+		cfg, err := config.LoadConfigFromYaml([]byte("fields:\n  - name: message\n    words: " + words))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := NewGenerator(cfg, Fields{{Name: "message", Type: FieldTypeText}}, 0); err == nil {
+			t.Errorf("Expected error for words %s", words)
+		}
+	}
+}
+
+func Test_UUIDInvalidConfig(t *testing.T) {
+	cfg, err := config.LoadConfigFromYaml([]byte("fields:\n  - name: trace.id\n    uuid_version: 1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := NewGenerator(cfg, Fields{{Name: "trace.id", Type: FieldTypeUUID}}, 0); err == nil {
+		t.Errorf("Expected error for uuid_version 1")
+	}
+}
+
+// checkUUID checks value is a canonical lowercase UUID of version, with the RFC 9562 variant
+func checkUUID(t *testing.T, value string, version int) {
+	t.Helper()
+
+	if len(value) != 36 {
+		t.Fatalf("Expected a 36 characters UUID, got %s", value)
+	}
+
+	for i, c := range value {
+		if i == 8 || i == 13 || i == 18 || i == 23 {
+			if c != '-' {
+				t.Fatalf("Expected groups of 8-4-4-4-12 characters, got %s", value)
+			}
+
+			continue
+		}
+
+		if !strings.ContainsRune(hexAlphabet, c) {
+			t.Fatalf("Expected lowercase hex characters, got %s", value)
+		}
+	}
+
+	if value[14] != strconv.Itoa(version)[0] {
+		t.Errorf("Expected UUID version %d, got %s", version, value)
+	}
+
+	if !strings.ContainsRune("89ab", rune(value[19])) {
+		t.Errorf("Expected RFC 9562 variant, got %s", value)
+	}
+}
+
+func Test_PatternInvalidConfig(t *testing.T) {
+	for _, fieldConfig := range []string{
+		"pattern: \"eni-[0-9a-f\"",
+		"pattern: \"\\\\bword\\\\b\"",
+		"pattern: \"[a-z]+\"\n    enum: [a, b]",
+		// `"` and `\` cannot be written as is in a JSON string
+		"pattern: 'a\"b'",
+		"pattern: 'a\\\\c'",
+		"pattern: '[\\\\\"]'",
+	} {
+		cfg, err := config.LoadConfigFromYaml([]byte("fields:\n  - name: alpha\n    " + fieldConfig))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := NewGenerator(cfg, Fields{{Name: "alpha", Type: FieldTypeKeyword}}, 0); err == nil {
+			t.Errorf("Expected error for %s", fieldConfig)
+		}
+	}
+}
+
+func Test_ValueFromTemplateInvalidConfig(t *testing.T) {
+	flds := Fields{{Name: "alpha", Type: FieldTypeKeyword}, {Name: "beta", Type: FieldTypeKeyword}}
+	for _, configYaml := range []string{
+		// reference to a field that is not defined
+		"fields:\n  - name: alpha\n    value_from_template: \"{{.gamma}}\"",
+		// unsupported action
+		"fields:\n  - name: alpha\n    value_from_template: \"{{if .beta}}beta{{end}}\"",
+		// value_from_template and enum
+		"fields:\n  - name: alpha\n    value_from_template: \"{{.beta}}\"\n    enum: [a, b]",
+		// cycle of a field with itself
+		"fields:\n  - name: alpha\n    value_from_template: \"{{.alpha}}\"",
+		// cycle of two fields
+		"fields:\n  - name: alpha\n    value_from_template: \"{{.beta}}\"\n  - name: beta\n    value_from_template: \"-{{.alpha}}\"",
+	} {
+		cfg, err := config.LoadConfigFromYaml([]byte(configYaml))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := NewGenerator(cfg, flds, 0); err == nil {
+			t.Errorf("Expected error for %s", configYaml)
+		}
+	}
+}
+
+func Test_UserInvalidConfig(t *testing.T) {
+	for _, fieldConfig := range []string{
+		"user_pool_size: -1",
+		"entity_cache_size: -1",
+		"user_pool_size: 20\n    entity_cache_size: 10",
+	} {
+		cfg, err := config.LoadConfigFromYaml([]byte("fields:\n  - name: user\n    " + fieldConfig))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := NewGenerator(cfg, Fields{{Name: "user", Type: FieldTypeUser}}, 0); err == nil {
+			t.Errorf("Expected error for %s", fieldConfig)
+		}
+	}
+}
+
+func Test_ArrayInvalidConfig(t *testing.T) {
+	testCases := []struct {
+		scenario  string
+		fieldType string
+		config    string
+	}{
+		{
+			scenario:  "min greater than max",
+			fieldType: FieldTypeLong,
+			config:    "fields:\n  - name: alpha\n    array:\n      min: 3\n      max: 1",
+		},
+		{
+			scenario:  "negative min",
+			fieldType: FieldTypeLong,
+			config:    "fields:\n  - name: alpha\n    array:\n      min: -1\n      max: 1",
+		},
+		{
+			scenario:  "array_size",
+			fieldType: FieldTypeKeyword,
+			config:    "fields:\n  - name: alpha\n    enum: [\"a\", \"b\"]\n    array_size: 2\n    array:\n      max: 2",
+		},
+		{
+			scenario:  "group of fields",
+			fieldType: FieldTypeMoney,
+			config:    "fields:\n  - name: alpha\n    array:\n      max: 2",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.scenario, func(t *testing.T) {
+			cfg, err := config.LoadConfigFromYaml([]byte(testCase.config))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if _, err := NewGenerator(cfg, Fields{{Name: "alpha", Type: testCase.fieldType}}, 0); err == nil {
+				t.Errorf("Expected error")
+			}
+		})
+	}
+}
+
+func Test_OutputNotInterleavedBetweenGenerators(t *testing.T) {
+	flds := Fields{
+		{Name: "alpha", Type: FieldTypeKeyword},
+		{Name: "beta", Type: FieldTypeLong},
+		{Name: "gamma", Type: FieldTypeIP},
+	}
+
+	nSpins := 100
+	emit := func(g Generator, buf *bytes.Buffer) {
+		if err := g.Emit(buf); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	InitGeneratorRandSeed(42)
+	g, err := NewGenerator(Config{}, flds, uint64(nSpins))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var alone bytes.Buffer
+	for i := 0; i < nSpins; i++ {
+		emit(g, &alone)
+	}
+
+	// the draws of another generator emitting at the same time don't change the output
+	InitGeneratorRandSeed(42)
+	g, err = NewGenerator(Config{}, flds, uint64(nSpins))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	other, err := NewGenerator(Config{}, flds, uint64(nSpins))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var interleaved, otherBuf bytes.Buffer
+	for i := 0; i < nSpins; i++ {
+		emit(g, &interleaved)
+		emit(other, &otherBuf)
+	}
+
+	if !bytes.Equal(alone.Bytes(), interleaved.Bytes()) {
+		t.Errorf("Expected identical output when another generator emits at the same time")
+	}
+}
+
+func Test_EntityAttributesEviction(t *testing.T) {
+	ea := newEntityAttributes(2)
+
+	ea.attributes("alpha")["attribute"] = 1
+	ea.attributes("beta")["attribute"] = 2
+
+	// alpha is seen again, beta becomes the least recently seen entity
+	if ea.attributes("alpha")["attribute"] != 1 {
+		t.Errorf("Expected alpha attribute to be cached")
+	}
+
+	ea.attributes("gamma")["attribute"] = 3
+
+	if _, ok := ea.attributes("beta")["attribute"]; ok {
+		t.Errorf("Expected beta to be evicted")
+	}
+
+	if ea.order.Len() != 2 || len(ea.byID) != 2 {
+		t.Errorf("Expected cache to be bound to 2 entities, got %d", ea.order.Len())
+	}
+}
+
+func Test_ZipfDrawsFromGivenRand(t *testing.T) {
+	fieldCfg := ConfigField{
+		Enum:         []string{"a", "b", "c", "d", "e"},
+		Distribution: config.DistributionZipf,
+	}
+
+	// the random generator at bind time doesn't matter, only the one values are generated with does
+	InitGeneratorRandSeed(1)
+	enumIndex := makeEnumIndexFunc(fieldCfg)
+	InitGeneratorRandSeed(2)
+	otherEnumIndex := makeEnumIndexFunc(fieldCfg)
+
+	r := rand.New(rand.NewSource(42))
+	otherR := rand.New(rand.NewSource(42))
+	for i := 0; i < 100; i++ {
+		if got, expected := otherEnumIndex(otherR), enumIndex(r); got != expected {
+			t.Fatalf("Expected index %d, got %d", expected, got)
+		}
+	}
+}
@@ -22,6 +22,7 @@ type GeneratorWithCustomTemplate struct {
 	totEvents        uint64
 	emitters         []emitter
 	trailingTemplate []byte
+	trimSeparator    bool
 	state            *genState
 }
 
@@ -86,8 +87,13 @@ func NewGeneratorWithCustomTemplate(template []byte, cfg Config, fields Fields,
 	// Parse the template and extract relevant information
 	orderedFields, templateFieldsMap, trailingTemplate := parseCustomTemplate(template)
 
+	initGeneratorTimeNowIfUnset()
+
 	// Preprocess the fields, generating appropriate emit functions
 	state := newGenState()
+	// the fields are bound with the random generator of the state, the one they are generated with
+	defer useRand(state.rand)()
+
 	fieldMap := make(map[string]any)
 	fieldTypes := make(map[string]string)
 	for _, field := range fields {
@@ -95,11 +101,61 @@ func NewGeneratorWithCustomTemplate(template []byte, cfg Config, fields Fields,
 			return nil, err
 		}
 
+		if err := bindKeywordMultiField(cfg, field, fieldMap, false); err != nil {
+			return nil, err
+		}
+
 		fieldTypes[field.Name] = field.Type
 		state.prevCacheForDup[field.Name] = make(map[any]struct{})
 		state.prevCacheCardinality[field.Name] = make([]any, 0)
 	}
 
+	if err := bindSeries(cfg, fields, fieldMap, false); err != nil {
+		return nil, err
+	}
+
+	state.seriesLength = seriesLength(cfg, fields)
+
+	if err := bindEnumByValue(cfg, fields, fieldMap, false); err != nil {
+		return nil, err
+	}
+
+	if err := bindDerivedLag(cfg, fields, fieldMap, false); err != nil {
+		return nil, err
+	}
+
+	if err := bindDerivedDuration(cfg, fields, fieldMap, false); err != nil {
+		return nil, err
+	}
+
+	if err := bindDerivedRatio(cfg, fields, fieldMap, false); err != nil {
+		return nil, err
+	}
+
+	if err := bindDerivedDirection(cfg, fields, fieldMap, false); err != nil {
+		return nil, err
+	}
+
+	if err := bindValueFromTemplate(cfg, fields, fieldMap, false); err != nil {
+		return nil, err
+	}
+
+	if err := bindRelatedFields(cfg, fields, fieldMap, false); err != nil {
+		return nil, err
+	}
+
+	if err := bindEntityAttributes(cfg, fields, fieldMap, false); err != nil {
+		return nil, err
+	}
+
+	if err := bindFirstOnly(cfg, fields, fieldMap, false); err != nil {
+		return nil, err
+	}
+
+	if err := bindNullProbability(cfg, fields, fieldMap, false); err != nil {
+		return nil, err
+	}
+
 	// Roll into slice of emit functions
 	emitters := make([]emitter, 0, len(fieldMap))
 	for _, fieldName := range orderedFields {
@@ -113,7 +169,7 @@ func NewGeneratorWithCustomTemplate(template []byte, cfg Config, fields Fields,
 
 	state.totEvents = totEvents
 
-	return &GeneratorWithCustomTemplate{emitters: emitters, trailingTemplate: trailingTemplate, totEvents: totEvents, state: state}, nil
+	return &GeneratorWithCustomTemplate{emitters: emitters, trailingTemplate: trailingTemplate, trimSeparator: hasNullableFields(cfg, fields), totEvents: totEvents, state: state}, nil
 }
 
 func (gen *GeneratorWithCustomTemplate) Close() error {
@@ -121,6 +177,7 @@ func (gen *GeneratorWithCustomTemplate) Close() error {
 }
 
 func (gen *GeneratorWithCustomTemplate) Emit(buf *bytes.Buffer) error {
+	gen.state.useRandomdata()
 	if err := gen.emit(buf); err != nil {
 		return err
 	}
@@ -132,6 +189,7 @@ func (gen *GeneratorWithCustomTemplate) Emit(buf *bytes.Buffer) error {
 
 func (gen *GeneratorWithCustomTemplate) emit(buf *bytes.Buffer) error {
 	if gen.totEvents == 0 || gen.state.counter < gen.totEvents {
+		start := buf.Len()
 		for _, e := range gen.emitters {
 			buf.Write(e.prefix)
 			if err := e.emitFunc(gen.state, buf); err != nil {
@@ -140,6 +198,9 @@ func (gen *GeneratorWithCustomTemplate) emit(buf *bytes.Buffer) error {
 		}
 
 		buf.Write(gen.trailingTemplate)
+		if gen.trimSeparator {
+			trimDanglingSeparator(buf, start)
+		}
 	} else {
 		return io.EOF
 	}
@@ -2,10 +2,15 @@ package genlib
 
 import (
 	"bytes"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/elastic/elastic-integration-corpus-generator-tool/pkg/genlib/config"
+	"math"
 	"math/rand"
 	"net"
+	"reflect"
+	"regexp"
 	"strconv"
 	"strings"
 	"testing"
@@ -218,10 +223,7 @@ func test_CardinalityTWithCustomTemplate[T any](t *testing.T, ty string) {
 	}
 
 	t.Logf("for type %s, with template: %s", ty, string(template))
-	for cardinality := 1000; cardinality >= 10; cardinality /= 10 {
-
-		currentCardinality := 1000
-		currentCardinality /= cardinality
+	for cardinality := 1; cardinality <= 100; cardinality *= 10 {
 
 		rangeTrailing := ""
 		if ty == FieldTypeFloat {
@@ -229,13 +231,14 @@ func test_CardinalityTWithCustomTemplate[T any](t *testing.T, ty string) {
 		}
 
 		rangeMin := rand.Intn(100)
-		rangeMax := rand.Intn(10000-rangeMin) + rangeMin
+		// Keep the range wide enough to generate the highest expected cardinality, that is capped at the values found
+		rangeMax := rand.Intn(9000-rangeMin) + rangeMin + 1000
 
 		// Add the range to get some variety in integers
 		tmpl := "fields:\n  - name: alpha\n    cardinality: %d\n    range:\n      min: %d%s\n      max: %d%s\n"
 		tmpl += "  - name: beta\n    cardinality: %d\n    range:\n      min: %d%s\n      max: %d%s"
 
-		yaml := []byte(fmt.Sprintf(tmpl, currentCardinality, rangeMin, rangeTrailing, rangeMax, rangeTrailing, currentCardinality*2, rangeMin, rangeTrailing, rangeMax, rangeTrailing))
+		yaml := []byte(fmt.Sprintf(tmpl, cardinality, rangeMin, rangeTrailing, rangeMax, rangeTrailing, cardinality*2, rangeMin, rangeTrailing, rangeMax, rangeTrailing))
 		cfg, err := config.LoadConfigFromYaml(yaml)
 		if err != nil {
 			t.Fatal(err)
@@ -277,11 +280,11 @@ func test_CardinalityTWithCustomTemplate[T any](t *testing.T, ty string) {
 			vmapBeta[v] = vmapBeta[v] + 1
 		}
 
-		if len(vmapAlpha) != 1000/cardinality {
-			t.Errorf("Expected cardinality of %d got %d", 1000/cardinality, len(vmapAlpha))
+		if len(vmapAlpha) != cardinality {
+			t.Errorf("Expected cardinality of %d got %d", cardinality, len(vmapAlpha))
 		}
-		if len(vmapBeta) != 2000/cardinality {
-			t.Errorf("Expected cardinality of %d got %d", 2000/cardinality, len(vmapBeta))
+		if len(vmapBeta) != cardinality*2 {
+			t.Errorf("Expected cardinality of %d got %d", cardinality*2, len(vmapBeta))
 		}
 	}
 }
@@ -378,6 +381,34 @@ func Test_FieldStaticOverrideBoolWithCustomTemplate(t *testing.T) {
 	}
 }
 
+func Test_FieldGeoPointGeohashWithCustomTemplate(t *testing.T) {
+	fld := Field{
+		Name: "alpha",
+		Type: FieldTypeGeoPoint,
+	}
+
+	template := []byte(`{"alpha":"{{.alpha}}"}`)
+	t.Logf("with template: %s", string(template))
+
+	for _, precision := range []int{0, 1, 5, 12} {
+		t.Run(strconv.Itoa(precision), func(t *testing.T) {
+			configYaml := []byte("fields:\n  - name: alpha\n    geo_format: geohash\n    geohash_precision: " + strconv.Itoa(precision))
+			expectedLength := precision
+			if precision == 0 {
+				expectedLength = geohashMaxPrecision
+			}
+
+			nSpins := 1024
+			for i := 0; i < nSpins; i++ {
+				b := testSingleTWithCustomTemplate[string](t, fld, configYaml, template)
+				if len(b) != expectedLength || strings.Trim(b, geohashAlphabet) != "" {
+					t.Fatalf("Expected a geohash of %d characters, got %s", expectedLength, b)
+				}
+			}
+		})
+	}
+}
+
 func Test_FieldGeoPointWithCustomTemplate(t *testing.T) {
 	fld := Field{
 		Name: "alpha",
@@ -442,7 +473,8 @@ func Test_FieldDateWithCustomTemplate(t *testing.T) {
 	t.Logf("with template: %s", string(template))
 	nSpins := rand.Intn(1024) + 1
 	for i := 0; i < nSpins; i++ {
-		previous := timeNowToBind
+		// FieldTypeTimeLayout has microsecond precision: the dates are compared at that precision
+		previous := timeNowToBind.Truncate(time.Microsecond)
 
 		b := testSingleTWithCustomTemplate[string](t, fld, nil, template)
 
@@ -896,95 +928,3787 @@ func Test_FieldDateAndRangeFromAndToNegativeWithCustomTemplate(t *testing.T) {
 	}
 }
 
-func Test_FieldIPWithCustomTemplate(t *testing.T) {
+func Test_FieldDateAlignedWithCustomTemplate(t *testing.T) {
 	fld := Field{
 		Name: "alpha",
-		Type: FieldTypeIP,
+		Type: FieldTypeDate,
+	}
+
+	cfg, err := config.LoadConfigFromYaml([]byte("fields:\n  - name: alpha\n    mode: aligned\n    interval: 90s\n    base: \"2023-01-01T00:00:00.123456-00:00\""))
+	if err != nil {
+		t.Fatal(err)
 	}
 
+	base := time.Date(2023, 1, 1, 0, 0, 0, 123456000, time.UTC)
+	interval := 90 * time.Second
+
 	template := []byte(`{"alpha":"{{.alpha}}"}`)
 	t.Logf("with template: %s", string(template))
-	nSpins := rand.Intn(1024) + 1
+
+	nSpins := 100
+	g := makeGeneratorWithCustomTemplate(t, cfg, Fields{fld}, template, uint64(nSpins))
+
 	for i := 0; i < nSpins; i++ {
+		var buf bytes.Buffer
+		if err := g.Emit(&buf); err != nil {
+			t.Fatal(err)
+		}
 
-		b := testSingleTWithCustomTemplate[string](t, fld, nil, template)
+		m := unmarshalJSONT[string](t, buf.Bytes())
 
-		if ip := net.ParseIP(b); ip == nil {
-			t.Errorf("Fail parse ip %s", b)
+		ts, err := time.Parse(FieldTypeTimeLayout, m[fld.Name])
+		if err != nil {
+			t.Fatalf("Fail parse timestamp %v", err)
+		}
+
+		expectedTime := base.Add(time.Duration(i) * interval)
+		if !ts.Equal(expectedTime) {
+			t.Errorf("Expected %s for document %d, got %s", expectedTime, i, ts)
 		}
 	}
 }
 
-func Test_FieldFloatsWithCustomTemplate(t *testing.T) {
-	_testNumericWithCustomTemplate[float64](t, FieldTypeDouble)
-	_testNumericWithCustomTemplate[float32](t, FieldTypeFloat)
-	_testNumericWithCustomTemplate[float32](t, FieldTypeHalfFloat)
-	_testNumericWithCustomTemplate[float64](t, FieldTypeScaledFloat)
+func Test_FieldDateAlignedJitterWithCustomTemplate(t *testing.T) {
+	fld := Field{
+		Name: "alpha",
+		Type: FieldTypeDate,
+	}
+
+	base := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	interval := 90 * time.Second
+	jitter := 10 * time.Second
+
+	template := []byte(`{"alpha":"{{.alpha}}"}`)
+	t.Logf("with template: %s", string(template))
+
+	for _, distribution := range []string{config.JitterDistributionUniform, config.JitterDistributionNormal} {
+		t.Run(distribution, func(t *testing.T) {
+			cfg, err := config.LoadConfigFromYaml([]byte("fields:\n  - name: alpha\n    mode: aligned\n    interval: 90s\n    jitter: 10s\n    jitter_distribution: " + distribution + "\n    base: \"2023-01-01T00:00:00-00:00\""))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			nSpins := 100
+			generate := func() []string {
+				InitGeneratorRandSeed(42)
+				g := makeGeneratorWithCustomTemplate(t, cfg, Fields{fld}, template, uint64(nSpins))
+
+				docs := make([]string, 0, nSpins)
+				for i := 0; i < nSpins; i++ {
+					var buf bytes.Buffer
+					if err := g.Emit(&buf); err != nil {
+						t.Fatal(err)
+					}
+
+					docs = append(docs, buf.String())
+				}
+
+				return docs
+			}
+
+			docs := generate()
+			var totJittered int
+			for i, doc := range docs {
+				m := unmarshalJSONT[string](t, []byte(doc))
+
+				ts, err := time.Parse(time.RFC3339Nano, m[fld.Name])
+				if err != nil {
+					t.Fatalf("Fail parse timestamp %v", err)
+				}
+
+				scheduled := base.Add(time.Duration(i) * interval)
+				diff := ts.Sub(scheduled)
+				if diff < -jitter || diff > jitter {
+					t.Errorf("Expected %s within ±%s of %s, got %s", ts, jitter, scheduled, diff)
+				}
+
+				if diff != 0 {
+					totJittered++
+				}
+			}
 
+			if totJittered == 0 {
+				t.Errorf("Expected jittered timestamps")
+			}
+
+			if !reflect.DeepEqual(docs, generate()) {
+				t.Errorf("Expected the same timestamps with the same seed")
+			}
+		})
+	}
 }
 
-func Test_FieldIntegersWithCustomTemplate(t *testing.T) {
-	_testNumericWithCustomTemplate[int](t, FieldTypeInteger)
-	_testNumericWithCustomTemplate[int64](t, FieldTypeLong)
-	_testNumericWithCustomTemplate[uint64](t, FieldTypeUnsignedLong)
+func Test_FieldDateMonotonicWithCustomTemplate(t *testing.T) {
+	fld := Field{
+		Name: "alpha",
+		Type: FieldTypeDate,
+	}
+
+	cfg, err := config.LoadConfigFromYaml([]byte("fields:\n  - name: alpha\n    mode: monotonic\n    max_delta: 500ms\n    base: \"2023-01-01T00:00:00+00:00\""))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template, _ := generateCustomTemplateFromField(cfg, Fields{fld})
+	t.Logf("with template: %s", string(template))
+
+	nSpins := 1000
+	g := makeGeneratorWithCustomTemplate(t, cfg, Fields{fld}, template, uint64(nSpins))
+
+	previous := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	var last time.Time
+	for i := 0; i < nSpins; i++ {
+		var buf bytes.Buffer
+		if err := g.Emit(&buf); err != nil {
+			t.Fatal(err)
+		}
+
+		m := unmarshalJSONT[string](t, buf.Bytes())
+		alpha, err := time.Parse(time.RFC3339Nano, m[fld.Name])
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// the first date is the base, the following ones move forward by up to max_delta
+		if delta := alpha.Sub(previous); delta < 0 || delta > 500*time.Millisecond {
+			t.Fatalf("Expected a delta between 0 and 500ms, got %s", delta)
+		}
+
+		previous, last = alpha, alpha
+	}
+
+	if !last.After(time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected the dates to move forward, got %s", last)
+	}
 }
 
-func _testNumericWithCustomTemplate[T any](t *testing.T, ty string) {
+func Test_FieldDateFormatWithCustomTemplate(t *testing.T) {
+	testCases := []struct {
+		format   string
+		expected []string
+	}{
+		{
+			format:   config.DateFormatApacheCLF,
+			expected: []string{"10/Oct/2000:13:55:36 -0700", "11/Oct/2000:13:55:36 -0700"},
+		},
+		{
+			format:   config.DateFormatNginx,
+			expected: []string{"10/Oct/2000:13:55:36 -0700", "11/Oct/2000:13:55:36 -0700"},
+		},
+		{
+			format:   config.DateFormatSyslogBSD,
+			expected: []string{"Oct 10 13:55:36", "Oct 11 13:55:36"},
+		},
+		{
+			format:   config.DateFormatRFC1123Z,
+			expected: []string{"Tue, 10 Oct 2000 13:55:36 -0700", "Wed, 11 Oct 2000 13:55:36 -0700"},
+		},
+		{
+			format:   "2006-01-02 15:04:05",
+			expected: []string{"2000-10-10 13:55:36", "2000-10-11 13:55:36"},
+		},
+	}
+
 	fld := Field{
 		Name: "alpha",
-		Type: ty,
+		Type: FieldTypeDate,
 	}
 
-	template := []byte(`{"alpha":{{.alpha}}}`)
+	for _, testCase := range testCases {
+		t.Run(testCase.format, func(t *testing.T) {
+			cfg, err := config.LoadConfigFromYaml([]byte("fields:\n  - name: alpha\n    mode: aligned\n    interval: 24h\n    base: \"2000-10-10T13:55:36-07:00\"\n    format: \"" + testCase.format + "\""))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			template, _ := generateCustomTemplateFromField(cfg, Fields{fld})
+			t.Logf("with template: %s", string(template))
+
+			g := makeGeneratorWithCustomTemplate(t, cfg, Fields{fld}, template, uint64(len(testCase.expected)))
+
+			for _, expected := range testCase.expected {
+				var buf bytes.Buffer
+				if err := g.Emit(&buf); err != nil {
+					t.Fatal(err)
+				}
+
+				m := unmarshalJSONT[string](t, buf.Bytes())
+				if m[fld.Name] != expected {
+					t.Errorf("Expected %s, got %s", expected, m[fld.Name])
+				}
+			}
+		})
+	}
+}
+
+func Test_FieldDateEpochFormatWithCustomTemplate(t *testing.T) {
+	testCases := []struct {
+		format   string
+		expected []int64
+	}{
+		{
+			format:   config.DateFormatEpochMillis,
+			expected: []int64{971211336000, 971297736000},
+		},
+		{
+			format:   config.DateFormatEpochSecond,
+			expected: []int64{971211336, 971297736},
+		},
+	}
+
+	fld := Field{
+		Name: "alpha",
+		Type: FieldTypeDate,
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.format, func(t *testing.T) {
+			cfg, err := config.LoadConfigFromYaml([]byte("fields:\n  - name: alpha\n    mode: aligned\n    interval: 24h\n    base: \"2000-10-10T13:55:36-07:00\"\n    format: " + testCase.format))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			template, _ := generateCustomTemplateFromField(cfg, Fields{fld})
+			t.Logf("with template: %s", string(template))
+
+			g := makeGeneratorWithCustomTemplate(t, cfg, Fields{fld}, template, uint64(len(testCase.expected)))
+
+			for _, expected := range testCase.expected {
+				var buf bytes.Buffer
+				if err := g.Emit(&buf); err != nil {
+					t.Fatal(err)
+				}
+
+				// the epoch timestamp is emitted as a bare integer
+				m := unmarshalJSONT[int64](t, buf.Bytes())
+				if m[fld.Name] != expected {
+					t.Errorf("Expected %d, got %s", expected, buf.String())
+				}
+			}
+		})
+	}
+}
+
+func Test_FieldIPWithCustomTemplate(t *testing.T) {
+	fld := Field{
+		Name: "alpha",
+		Type: FieldTypeIP,
+	}
+
+	template := []byte(`{"alpha":"{{.alpha}}"}`)
 	t.Logf("with template: %s", string(template))
 	nSpins := rand.Intn(1024) + 1
 	for i := 0; i < nSpins; i++ {
-		testSingleTWithCustomTemplate[T](t, fld, nil, template)
+
+		b := testSingleTWithCustomTemplate[string](t, fld, nil, template)
+
+		if ip := net.ParseIP(b); ip == nil {
+			t.Errorf("Fail parse ip %s", b)
+		}
 	}
 }
 
-func testSingleTWithCustomTemplate[T any](t *testing.T, fld Field, yaml []byte, template []byte) T {
-	var err error
-	var cfg Config
+func Test_FieldHostnameWithCustomTemplate(t *testing.T) {
+	fld := Field{
+		Name: "alpha",
+		Type: FieldTypeHostname,
+	}
 
-	if yaml != nil {
-		cfg, err = config.LoadConfigFromYaml(yaml)
-		if err != nil {
-			t.Fatal(err)
+	template := []byte(`{"alpha":"{{.alpha}}"}`)
+	t.Logf("with template: %s", string(template))
+	nSpins := rand.Intn(1024) + 1
+	for i := 0; i < nSpins; i++ {
+		b := testSingleTWithCustomTemplate[string](t, fld, nil, template)
+
+		if !isValidHostname(b) {
+			t.Errorf("Invalid RFC 1123 hostname %s", b)
 		}
 	}
+}
 
-	g := makeGeneratorWithCustomTemplate(t, cfg, []Field{fld}, template, 0)
-
-	var buf bytes.Buffer
+func Test_FieldHostnameWithCardinalityWithCustomTemplate(t *testing.T) {
+	fld := Field{
+		Name: "alpha",
+		Type: FieldTypeHostname,
+	}
 
-	if err := g.Emit(&buf); err != nil {
+	cfg, err := config.LoadConfigFromYaml([]byte("fields:\n  - name: alpha\n    cardinality: 10"))
+	if err != nil {
 		t.Fatal(err)
 	}
 
-	// BufferWithMutex should now contain an event shaped like {"alpha": "constant_keyword"}
-	m := unmarshalJSONT[T](t, buf.Bytes())
+	template := []byte(`{"alpha":"{{.alpha}}"}`)
+	t.Logf("with template: %s", string(template))
+	nSpins := 1024
+	g := makeGeneratorWithCustomTemplate(t, cfg, []Field{fld}, template, uint64(nSpins))
 
-	if len(m) != 1 {
-		t.Errorf("Expected map size 1, got %d", len(m))
+	vmap := make(map[string]struct{})
+	for i := 0; i < nSpins; i++ {
+		var buf bytes.Buffer
+		if err := g.Emit(&buf); err != nil {
+			t.Fatal(err)
+		}
+
+		m := unmarshalJSONT[string](t, buf.Bytes())
+		if !isValidHostname(m[fld.Name]) {
+			t.Errorf("Invalid RFC 1123 hostname %s", m[fld.Name])
+		}
+
+		vmap[m[fld.Name]] = struct{}{}
 	}
 
-	v, ok := m[fld.Name]
+	if len(vmap) > 10 {
+		t.Errorf("Expected cardinality of at most 10 got %d", len(vmap))
+	}
+}
 
-	if !ok {
-		t.Errorf("Missing key %v", fld.Name)
+var hostnameLabelRFC1123 = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?$`)
 
+func isValidHostname(hostname string) bool {
+	if len(hostname) == 0 || len(hostname) > 253 {
+		return false
 	}
 
-	return v
+	for _, label := range strings.Split(hostname, ".") {
+		if !hostnameLabelRFC1123.MatchString(label) {
+			return false
+		}
+	}
+
+	return true
 }
 
-func makeGeneratorWithCustomTemplate(t *testing.T, cfg Config, fields Fields, template []byte, totEvents uint64) Generator {
-	g, err := NewGeneratorWithCustomTemplate(template, cfg, fields, totEvents)
+func Test_FieldKeywordMultiFieldWithCustomTemplate(t *testing.T) {
+	fld := Field{
+		Name: "alpha",
+		Type: "text",
+	}
 
+	cfg, err := config.LoadConfigFromYaml([]byte("fields:\n  - name: alpha\n    keyword_multi_field: true"))
 	if err != nil {
 		t.Fatal(err)
 	}
 
-	return g
+	template, _ := generateCustomTemplateFromField(cfg, Fields{fld})
+	t.Logf("with template: %s", string(template))
+
+	nSpins := rand.Intn(1024) + 1
+	g := makeGeneratorWithCustomTemplate(t, cfg, Fields{fld}, template, uint64(nSpins))
+
+	for i := 0; i < nSpins; i++ {
+		var buf bytes.Buffer
+		if err := g.Emit(&buf); err != nil {
+			t.Fatal(err)
+		}
+
+		m := unmarshalJSONT[string](t, buf.Bytes())
+		if len(m) != 2 {
+			t.Errorf("Expected map size 2, got %d", len(m))
+		}
+
+		v, ok := m["alpha"]
+		if !ok {
+			t.Errorf("Missing key alpha")
+		}
+
+		keywordV, ok := m["alpha.keyword"]
+		if !ok {
+			t.Errorf("Missing key alpha.keyword")
+		}
+
+		if v != keywordV {
+			t.Errorf("Expected alpha.keyword to be equal to alpha: %s != %s", keywordV, v)
+		}
+	}
+}
+
+func Test_FieldMoneyWithCustomTemplate(t *testing.T) {
+	testCases := []struct {
+		currency      string
+		totalDecimals int
+	}{
+		{currency: "JPY", totalDecimals: 0},
+		{currency: "USD", totalDecimals: 2},
+		{currency: "KWD", totalDecimals: 3},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.currency, func(t *testing.T) {
+			fld := Field{
+				Name: "price",
+				Type: FieldTypeMoney,
+			}
+
+			yaml := fmt.Sprintf("fields:\n  - name: price\n    enum: [%s]\n    range:\n      min: 1\n      max: 100000", testCase.currency)
+			cfg, err := config.LoadConfigFromYaml([]byte(yaml))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			template, _ := generateCustomTemplateFromField(cfg, Fields{fld})
+			t.Logf("with template: %s", string(template))
+
+			nSpins := rand.Intn(1024) + 1
+			g := makeGeneratorWithCustomTemplate(t, cfg, Fields{fld}, template, uint64(nSpins))
+
+			for i := 0; i < nSpins; i++ {
+				var buf bytes.Buffer
+				if err := g.Emit(&buf); err != nil {
+					t.Fatal(err)
+				}
+
+				m := unmarshalJSONT[json.RawMessage](t, buf.Bytes())
+				if len(m) != 2 {
+					t.Errorf("Expected map size 2, got %d", len(m))
+				}
+
+				if string(m["price.currency"]) != `"`+testCase.currency+`"` {
+					t.Errorf("Expected currency %s, got %s", testCase.currency, m["price.currency"])
+				}
+
+				amount := string(m["price.amount"])
+				totalDecimals := 0
+				if idx := strings.Index(amount, "."); idx > -1 {
+					totalDecimals = len(amount) - idx - 1
+				}
+
+				if totalDecimals != testCase.totalDecimals {
+					t.Errorf("Expected %d decimals for %s, got amount %s", testCase.totalDecimals, testCase.currency, amount)
+				}
+			}
+		})
+	}
+}
+
+func Test_FieldRawJSONWithCustomTemplate(t *testing.T) {
+	flds := Fields{
+		{
+			Name: "alpha",
+			Type: FieldTypeObject,
+		},
+		{
+			Name: "beta",
+			Type: FieldTypeKeyword,
+		},
+	}
+
+	rawJSON := `{"nested": {"list": [1, 2.5, "three"], "flag": true}, "empty": null}`
+	cfg, err := config.LoadConfigFromYaml([]byte("fields:\n  - name: alpha\n    raw_json: '" + rawJSON + "'"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template, _ := generateCustomTemplateFromField(cfg, flds[1:])
+	template = append([]byte(`{"alpha": {{.alpha}}, `), template[2:]...)
+	t.Logf("with template: %s", string(template))
+
+	g := makeGeneratorWithCustomTemplate(t, cfg, flds, template, 1)
+
+	var buf bytes.Buffer
+	if err := g.Emit(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	if !json.Valid(buf.Bytes()) {
+		t.Fatalf("Expected valid JSON document, got %s", buf.String())
+	}
+
+	m := unmarshalJSONT[json.RawMessage](t, buf.Bytes())
+	if string(m["alpha"]) != rawJSON {
+		t.Errorf("Expected raw JSON %s, got %s", rawJSON, m["alpha"])
+	}
+
+	if _, ok := m["beta"]; !ok {
+		t.Errorf("Missing key beta")
+	}
+}
+
+func Test_FieldRawJSONNotValidWithCustomTemplate(t *testing.T) {
+	fld := Field{
+		Name: "alpha",
+		Type: FieldTypeKeyword,
+	}
+
+	cfg, err := config.LoadConfigFromYaml([]byte("fields:\n  - name: alpha\n    raw_json: '{\"not\": valid}'"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template, _ := generateCustomTemplateFromField(cfg, Fields{fld})
+	if _, err := NewGeneratorWithCustomTemplate(template, cfg, Fields{fld}, 1); !errors.Is(err, rawJSONNotValid) {
+		t.Errorf("Expected rawJSONNotValid error, got %v", err)
+	}
+}
+
+func Test_FieldUniqueWithCustomTemplate(t *testing.T) {
+	fld := Field{
+		Name: "alpha",
+		Type: FieldTypeLong,
+	}
+
+	cfg, err := config.LoadConfigFromYaml([]byte("fields:\n  - name: alpha\n    unique: true\n    range:\n      min: 0\n      max: 1000"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := []byte(`{"alpha":{{.alpha}}}`)
+	t.Logf("with template: %s", string(template))
+
+	nSpins := 500
+	g := makeGeneratorWithCustomTemplate(t, cfg, Fields{fld}, template, uint64(nSpins))
+
+	vmap := make(map[int64]struct{})
+	for i := 0; i < nSpins; i++ {
+		var buf bytes.Buffer
+		if err := g.Emit(&buf); err != nil {
+			t.Fatal(err)
+		}
+
+		m := unmarshalJSONT[int64](t, buf.Bytes())
+		if _, ok := vmap[m[fld.Name]]; ok {
+			t.Errorf("Value %d generated more than once", m[fld.Name])
+		}
+
+		vmap[m[fld.Name]] = struct{}{}
+	}
+}
+
+func Test_FieldUniqueExhaustedWithCustomTemplate(t *testing.T) {
+	fld := Field{
+		Name: "alpha",
+		Type: FieldTypeLong,
+	}
+
+	cfg, err := config.LoadConfigFromYaml([]byte("fields:\n  - name: alpha\n    unique: true\n    range:\n      min: 0\n      max: 10"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := []byte(`{"alpha":{{.alpha}}}`)
+	t.Logf("with template: %s", string(template))
+
+	nSpins := 20
+	g := makeGeneratorWithCustomTemplate(t, cfg, Fields{fld}, template, uint64(nSpins))
+
+	for i := 0; i < nSpins; i++ {
+		var buf bytes.Buffer
+		if err = g.Emit(&buf); err != nil {
+			break
+		}
+	}
+
+	if !errors.Is(err, uniqueValuesExhausted) {
+		t.Errorf("Expected uniqueValuesExhausted error, got %v", err)
+	}
+}
+
+func Test_FieldCardinalityCappedWithCustomTemplate(t *testing.T) {
+	fld := Field{
+		Name: "alpha",
+		Type: FieldTypeLong,
+	}
+
+	cfg, err := config.LoadConfigFromYaml([]byte("fields:\n  - name: alpha\n    cardinality: 20\n    range:\n      min: 0\n      max: 10"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := []byte(`{"alpha":{{.alpha}}}`)
+	t.Logf("with template: %s", string(template))
+
+	nSpins := 100
+	g := makeGeneratorWithCustomTemplate(t, cfg, Fields{fld}, template, uint64(nSpins))
+
+	var values []float64
+	distinct := make(map[float64]struct{})
+	for i := 0; i < nSpins; i++ {
+		var buf bytes.Buffer
+		if err := g.Emit(&buf); err != nil {
+			t.Fatal(err)
+		}
+
+		m := unmarshalJSONT[float64](t, buf.Bytes())
+		values = append(values, m["alpha"])
+		distinct[m["alpha"]] = struct{}{}
+	}
+
+	// the range has fewer values than the cardinality: it is capped at the values found, that are cycled through
+	if len(distinct) > 11 {
+		t.Errorf("Expected at most 11 distinct values, got %d", len(distinct))
+	}
+
+	for i, value := range values {
+		if value != values[i%len(distinct)] {
+			t.Errorf("Expected values cycled every %d events, got %v", len(distinct), values)
+			break
+		}
+	}
+}
+
+func Test_FieldCardinalityWithEnumWithCustomTemplate(t *testing.T) {
+	fld := Field{
+		Name: "alpha",
+		Type: FieldTypeKeyword,
+	}
+
+	cfg, err := config.LoadConfigFromYaml([]byte("fields:\n  - name: alpha\n    cardinality: 5\n    enum: [a, b, c]"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := []byte(`{"alpha":"{{.alpha}}"}`)
+	t.Logf("with template: %s", string(template))
+
+	nSpins := 100
+	g := makeGeneratorWithCustomTemplate(t, cfg, Fields{fld}, template, uint64(nSpins))
+
+	// the cardinality is limited to the values of the enum
+	vmap := make(map[string]int)
+	for i := 0; i < nSpins; i++ {
+		var buf bytes.Buffer
+		if err := g.Emit(&buf); err != nil {
+			t.Fatal(err)
+		}
+
+		m := unmarshalJSONT[string](t, buf.Bytes())
+		vmap[m[fld.Name]] += 1
+	}
+
+	if len(vmap) != 3 {
+		t.Errorf("Expected cardinality of 3, got %v", vmap)
+	}
+}
+
+func Test_FieldCardinalityGroupWithCustomTemplate(t *testing.T) {
+	flds := Fields{
+		{Name: "source.ip", Type: FieldTypeIP},
+		{Name: "source.port", Type: FieldTypeLong},
+	}
+
+	template := []byte(`{"source.ip":"{{.source.ip}}","source.port":{{.source.port}}}`)
+	t.Logf("with template: %s", string(template))
+
+	for _, testCase := range []struct {
+		group         string
+		expectedPairs int
+	}{
+		// the fields vary independently: every combination of their values is emitted
+		{group: "", expectedPairs: 50},
+		// the fields share the index into their values: each port comes always with the same ip
+		{group: "host", expectedPairs: 25},
+	} {
+		configYaml := fmt.Sprintf(`fields:
+  - name: source.ip
+    cardinality: 10
+    cardinality_group: "%[1]s"
+  - name: source.port
+    cardinality: 25
+    cardinality_group: "%[1]s"
+    range:
+      min: 1024
+      max: 65535
+`, testCase.group)
+		cfg, err := config.LoadConfigFromYaml([]byte(configYaml))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		nSpins := 200
+		g := makeGeneratorWithCustomTemplate(t, cfg, flds, template, uint64(nSpins))
+
+		pairs := make(map[string]struct{})
+		ipsByPort := make(map[float64]map[string]struct{})
+		for i := 0; i < nSpins; i++ {
+			var buf bytes.Buffer
+			if err := g.Emit(&buf); err != nil {
+				t.Fatal(err)
+			}
+
+			m := unmarshalJSONT[any](t, buf.Bytes())
+			ip, port := m["source.ip"].(string), m["source.port"].(float64)
+			pairs[fmt.Sprintf("%s:%v", ip, port)] = struct{}{}
+			if _, ok := ipsByPort[port]; !ok {
+				ipsByPort[port] = make(map[string]struct{})
+			}
+
+			ipsByPort[port][ip] = struct{}{}
+		}
+
+		if len(pairs) != testCase.expectedPairs {
+			t.Errorf("Expected %d pairs with group %q, got %d", testCase.expectedPairs, testCase.group, len(pairs))
+		}
+
+		if len(testCase.group) == 0 {
+			continue
+		}
+
+		for port, ips := range ipsByPort {
+			if len(ips) != 1 {
+				t.Errorf("Expected port %v to come with a single ip, got %v", port, ips)
+			}
+		}
+	}
+}
+
+func Test_FieldEnumByValueWithCustomTemplate(t *testing.T) {
+	flds := Fields{
+		{
+			Name: "cloud.provider",
+			Type: FieldTypeKeyword,
+		},
+		{
+			Name: "cloud.region",
+			Type: FieldTypeKeyword,
+		},
+	}
+
+	configYaml := `fields:
+  - name: cloud.provider
+    enum: ["aws", "gcp"]
+  - name: cloud.region
+    depends_on: cloud.provider
+    enum_by_value:
+      aws: ["us-east-1", "eu-west-1"]
+      gcp: ["us-central1", "europe-west1"]
+`
+	cfg, err := config.LoadConfigFromYaml([]byte(configYaml))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	regionsByProvider := map[string]map[string]struct{}{
+		"aws": {"us-east-1": {}, "eu-west-1": {}},
+		"gcp": {"us-central1": {}, "europe-west1": {}},
+	}
+
+	// region is emitted before the provider it depends on
+	template := []byte(`{"cloud.region":"{{.cloud.region}}", "cloud.provider":"{{.cloud.provider}}"}`)
+	t.Logf("with template: %s", string(template))
+
+	nSpins := 1024
+	g := makeGeneratorWithCustomTemplate(t, cfg, flds, template, uint64(nSpins))
+
+	providers := make(map[string]int)
+	for i := 0; i < nSpins; i++ {
+		var buf bytes.Buffer
+		if err := g.Emit(&buf); err != nil {
+			t.Fatal(err)
+		}
+
+		m := unmarshalJSONT[string](t, buf.Bytes())
+		provider, region := m["cloud.provider"], m["cloud.region"]
+		if _, ok := regionsByProvider[provider][region]; !ok {
+			t.Errorf("Unexpected region %s for provider %s", region, provider)
+		}
+
+		providers[provider] += 1
+	}
+
+	if len(providers) != 2 {
+		t.Errorf("Expected both providers to be generated, got %v", providers)
+	}
+}
+
+func Test_FieldFirstOnlyWithCustomTemplate(t *testing.T) {
+	testCases := []struct {
+		scenario string
+		fields   Fields
+	}{
+		{
+			scenario: "with other fields",
+			fields: Fields{
+				{Name: "alpha", Type: FieldTypeKeyword},
+				{Name: "beta", Type: FieldTypeLong},
+				{Name: "gamma", Type: FieldTypeDate},
+				{Name: "header", Type: FieldTypeKeyword},
+			},
+		},
+		{
+			scenario: "first only fields only",
+			fields: Fields{
+				{Name: "beta", Type: FieldTypeLong},
+				{Name: "header", Type: FieldTypeKeyword},
+			},
+		},
+	}
+
+	configYaml := `fields:
+  - name: header
+    first_only: true
+  - name: beta
+    first_only: true
+`
+	cfg, err := config.LoadConfigFromYaml([]byte(configYaml))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.scenario, func(t *testing.T) {
+			template, _ := generateCustomTemplateFromField(cfg, testCase.fields)
+			t.Logf("with template: %s", string(template))
+
+			nSpins := 5
+			g := makeGeneratorWithCustomTemplate(t, cfg, testCase.fields, template, uint64(nSpins))
+
+			for i := 0; i < nSpins; i++ {
+				var buf bytes.Buffer
+				if err := g.Emit(&buf); err != nil {
+					t.Fatal(err)
+				}
+
+				if !json.Valid(buf.Bytes()) {
+					t.Fatalf("Expected valid JSON document, got %s", buf.String())
+				}
+
+				m := unmarshalJSONT[any](t, buf.Bytes())
+				for _, fld := range testCase.fields {
+					_, ok := m[fld.Name]
+					isFirstOnly := fld.Name == "header" || fld.Name == "beta"
+					if isFirstOnly && i == 0 && !ok {
+						t.Errorf("Missing key %s in first document", fld.Name)
+					}
+
+					if isFirstOnly && i > 0 && ok {
+						t.Errorf("Unexpected key %s in document %d", fld.Name, i)
+					}
+
+					if !isFirstOnly && !ok {
+						t.Errorf("Missing key %s in document %d", fld.Name, i)
+					}
+				}
+			}
+		})
+	}
+}
+
+func Test_FieldCIDRWithCustomTemplate(t *testing.T) {
+	testCases := []struct {
+		scenario  string
+		config    string
+		isIPv4    bool
+		isIPv6    bool
+		minPrefix int
+		maxPrefix int
+	}{
+		{
+			scenario:  "default",
+			config:    "fields:\n  - name: alpha",
+			isIPv4:    true,
+			minPrefix: 8,
+			maxPrefix: 32,
+		},
+		{
+			scenario:  "ipv4 with range",
+			config:    "fields:\n  - name: alpha\n    ip_version: v4\n    range:\n      min: 16\n      max: 24",
+			isIPv4:    true,
+			minPrefix: 16,
+			maxPrefix: 24,
+		},
+		{
+			scenario:  "ipv6 with range",
+			config:    "fields:\n  - name: alpha\n    ip_version: v6\n    range:\n      min: 32\n      max: 64",
+			isIPv6:    true,
+			minPrefix: 32,
+			maxPrefix: 64,
+		},
+		{
+			scenario:  "both",
+			config:    "fields:\n  - name: alpha\n    ip_version: both\n    range:\n      min: 8\n      max: 32",
+			isIPv4:    true,
+			isIPv6:    true,
+			minPrefix: 8,
+			maxPrefix: 32,
+		},
+	}
+
+	fld := Field{
+		Name: "alpha",
+		Type: FieldTypeCIDR,
+	}
+
+	template := []byte(`{"alpha":"{{.alpha}}"}`)
+	t.Logf("with template: %s", string(template))
+
+	for _, testCase := range testCases {
+		t.Run(testCase.scenario, func(t *testing.T) {
+			var totIPv4, totIPv6 int
+			nSpins := 1024
+			for i := 0; i < nSpins; i++ {
+				b := testSingleTWithCustomTemplate[string](t, fld, []byte(testCase.config), template)
+
+				ip, ipNet, err := net.ParseCIDR(b)
+				if err != nil {
+					t.Fatalf("Fail parse CIDR %s: %v", b, err)
+				}
+
+				if !ip.Equal(ipNet.IP) || ipNet.String() != b {
+					t.Errorf("Expected CIDR %s in canonical network address form", b)
+				}
+
+				prefix, _ := ipNet.Mask.Size()
+				if prefix < testCase.minPrefix || prefix > testCase.maxPrefix {
+					t.Errorf("Prefix length of %s out of range [%d, %d]", b, testCase.minPrefix, testCase.maxPrefix)
+				}
+
+				if ip.To4() != nil {
+					totIPv4++
+				} else {
+					totIPv6++
+				}
+			}
+
+			if testCase.isIPv4 != (totIPv4 > 0) || testCase.isIPv6 != (totIPv6 > 0) {
+				t.Errorf("Unexpected IP versions: %d IPv4 and %d IPv6 CIDRs", totIPv4, totIPv6)
+			}
+		})
+	}
+}
+
+func Test_KeyStyleWithCustomTemplate(t *testing.T) {
+	testCases := []struct {
+		keyStyle string
+		expected string
+	}{
+		{keyStyle: config.KeyStyleDotted, expected: "source.ip"},
+		{keyStyle: config.KeyStyleSnake, expected: "source_ip"},
+		{keyStyle: config.KeyStyleCamel, expected: "sourceIp"},
+	}
+
+	fields := Fields{
+		{Name: "source.ip", Type: FieldTypeIP},
+		{Name: "event.duration", Type: FieldTypeLong},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.keyStyle, func(t *testing.T) {
+			cfg, err := config.LoadConfigFromYaml([]byte("key_style: " + testCase.keyStyle + "\nfields:\n  - name: event.duration\n    value: 10\n"))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			template, _ := generateCustomTemplateFromField(cfg, fields)
+			t.Logf("with template: %s", string(template))
+
+			g := makeGeneratorWithCustomTemplate(t, cfg, fields, template, 1)
+
+			var buf bytes.Buffer
+			if err := g.Emit(&buf); err != nil {
+				t.Fatal(err)
+			}
+
+			m := unmarshalJSONT[any](t, buf.Bytes())
+			if len(m) != len(fields) {
+				t.Errorf("Expected %d keys, got %v", len(fields), m)
+			}
+
+			if _, ok := m[testCase.expected]; !ok {
+				t.Errorf("Missing key %s in %s", testCase.expected, buf.String())
+			}
+
+			durationKey := cfg.FieldKey("event.duration")
+			if v, ok := m[durationKey]; !ok || v != float64(10) {
+				t.Errorf("Expected key %s with value 10 in %s", durationKey, buf.String())
+			}
+		})
+	}
+}
+
+func Test_FieldRelatedFieldsWithCustomTemplate(t *testing.T) {
+	flds := Fields{
+		{Name: "related.ip", Type: FieldTypeIP},
+		{Name: "source.ip", Type: FieldTypeIP},
+		{Name: "destination.ip", Type: FieldTypeIP},
+	}
+
+	cfg, err := config.LoadConfigFromYaml([]byte("fields:\n  - name: related.ip\n    related_fields: [\"source.ip\", \"destination.ip\"]"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the related field comes first in the template, before the fields it aggregates
+	template, _ := generateCustomTemplateFromField(cfg, flds)
+	t.Logf("with template: %s", string(template))
+
+	nSpins := 100
+	g := makeGeneratorWithCustomTemplate(t, cfg, flds, template, uint64(nSpins))
+
+	for i := 0; i < nSpins; i++ {
+		var buf bytes.Buffer
+		if err := g.Emit(&buf); err != nil {
+			t.Fatal(err)
+		}
+
+		m := unmarshalJSONT[any](t, buf.Bytes())
+		related, ok := m["related.ip"].([]any)
+		if !ok {
+			t.Fatalf("Expected array for related.ip, got %s", buf.String())
+		}
+
+		expected := []any{m["source.ip"]}
+		if m["destination.ip"] != m["source.ip"] {
+			expected = append(expected, m["destination.ip"])
+		}
+
+		if !reflect.DeepEqual(expected, related) {
+			t.Errorf("Expected related.ip %v, got %v", expected, related)
+		}
+	}
+}
+
+func Test_FieldValueFromTemplateWithCustomTemplate(t *testing.T) {
+	flds := Fields{
+		{Name: "url.original", Type: FieldTypeKeyword},
+		{Name: "url.full", Type: FieldTypeKeyword},
+		{Name: "url.scheme", Type: FieldTypeKeyword},
+		{Name: "url.domain", Type: FieldTypeKeyword},
+		{Name: "url.path", Type: FieldTypeKeyword},
+	}
+
+	configYaml := `fields:
+  - name: url.original
+    value_from_template: "{{.url.full}}"
+  - name: url.full
+    value_from_template: "{{.url.scheme}}://{{.url.domain}}{{.url.path}}"
+  - name: url.scheme
+    enum: ["http", "https"]
+  - name: url.domain
+    pattern: "[a-z]{3,8}\\.com"
+  - name: url.path
+    pattern: "/[a-z]{1,5}"`
+
+	cfg, err := config.LoadConfigFromYaml([]byte(configYaml))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the templates come first in the template, before the fields they reference
+	template, _ := generateCustomTemplateFromField(cfg, flds)
+	t.Logf("with template: %s", string(template))
+
+	nSpins := 100
+	g := makeGeneratorWithCustomTemplate(t, cfg, flds, template, uint64(nSpins))
+
+	for i := 0; i < nSpins; i++ {
+		var buf bytes.Buffer
+		if err := g.Emit(&buf); err != nil {
+			t.Fatal(err)
+		}
+
+		m := unmarshalJSONT[string](t, buf.Bytes())
+		expected := m["url.scheme"] + "://" + m["url.domain"] + m["url.path"]
+		if m["url.full"] != expected {
+			t.Errorf("Expected url.full %s, got %s", expected, m["url.full"])
+		}
+
+		if m["url.original"] != m["url.full"] {
+			t.Errorf("Expected url.original equal to url.full %s, got %s", m["url.full"], m["url.original"])
+		}
+	}
+}
+
+func Test_FieldValueFromTemplateDoubleWithCustomTemplate(t *testing.T) {
+	flds := Fields{
+		{Name: "label", Type: FieldTypeKeyword},
+		{Name: "amount", Type: FieldTypeDouble},
+	}
+
+	configYaml := `fields:
+  - name: label
+    value_from_template: "{{.amount}} EUR"
+  - name: amount
+    range:
+      min: 1000000
+      max: 10000000`
+
+	cfg, err := config.LoadConfigFromYaml([]byte(configYaml))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template, _ := generateCustomTemplateFromField(cfg, flds)
+	t.Logf("with template: %s", string(template))
+
+	nSpins := 100
+	g := makeGeneratorWithCustomTemplate(t, cfg, flds, template, uint64(nSpins))
+
+	for i := 0; i < nSpins; i++ {
+		var buf bytes.Buffer
+		if err := g.Emit(&buf); err != nil {
+			t.Fatal(err)
+		}
+
+		// the double is rendered as the value of the field, without scientific notation
+		m := unmarshalJSONT[json.RawMessage](t, buf.Bytes())
+		expected := `"` + string(m["amount"]) + ` EUR"`
+		if string(m["label"]) != expected {
+			t.Errorf("Expected label %s, got %s", expected, m["label"])
+		}
+	}
+}
+
+func Test_FieldEnumArrayWithCustomTemplate(t *testing.T) {
+	fld := Field{
+		Name: "tags",
+		Type: FieldTypeKeyword,
+	}
+
+	enum := map[string]struct{}{"alpha": {}, "beta": {}, "gamma": {}, "delta": {}, "epsilon": {}}
+	cfg, err := config.LoadConfigFromYaml([]byte("fields:\n  - name: tags\n    array_size: 3\n    unique_within_doc: true\n    enum: [\"alpha\", \"beta\", \"gamma\", \"delta\", \"epsilon\"]"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template, _ := generateCustomTemplateFromField(cfg, Fields{fld})
+	t.Logf("with template: %s", string(template))
+
+	nSpins := 1000
+	g := makeGeneratorWithCustomTemplate(t, cfg, Fields{fld}, template, uint64(nSpins))
+
+	for i := 0; i < nSpins; i++ {
+		var buf bytes.Buffer
+		if err := g.Emit(&buf); err != nil {
+			t.Fatal(err)
+		}
+
+		m := unmarshalJSONT[[]string](t, buf.Bytes())
+		if len(m[fld.Name]) != 3 {
+			t.Fatalf("Expected 3 elements, got %s", buf.String())
+		}
+
+		seen := make(map[string]struct{})
+		for _, v := range m[fld.Name] {
+			if _, ok := enum[v]; !ok {
+				t.Errorf("Element %s not in enum", v)
+			}
+
+			if _, ok := seen[v]; ok {
+				t.Errorf("Element %s repeated in %s", v, buf.String())
+			}
+
+			seen[v] = struct{}{}
+		}
+	}
+}
+
+func Test_FieldEnumArrayTooLargeWithCustomTemplate(t *testing.T) {
+	fld := Field{
+		Name: "tags",
+		Type: FieldTypeKeyword,
+	}
+
+	cfg, err := config.LoadConfigFromYaml([]byte("fields:\n  - name: tags\n    array_size: 3\n    unique_within_doc: true\n    enum: [\"alpha\", \"beta\"]"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template, _ := generateCustomTemplateFromField(cfg, Fields{fld})
+	if _, err := NewGeneratorWithCustomTemplate(template, cfg, Fields{fld}, 0); err == nil {
+		t.Errorf("Expected error for array_size larger than enum")
+	}
+}
+
+func Test_FieldDoubleNoScientificNotationWithCustomTemplate(t *testing.T) {
+	testCases := []struct {
+		scenario       string
+		config         string
+		noDecimalPoint bool
+	}{
+		{
+			scenario: "very large",
+			config:   "fields:\n  - name: alpha\n    range:\n      min: 1e21\n      max: 1e22",
+		},
+		{
+			scenario: "very small",
+			config:   "fields:\n  - name: alpha\n    range:\n      min: 1e-10\n      max: 1e-9",
+		},
+		{
+			scenario: "very large with fuzziness",
+			config:   "fields:\n  - name: alpha\n    fuzziness: 0.1\n    range:\n      min: 1e21\n      max: 1e22",
+		},
+		{
+			scenario:       "integer values omitting decimals",
+			config:         "fields:\n  - name: alpha\n    omit_integer_decimals: true\n    range:\n      min: 1e21\n      max: 1e22",
+			noDecimalPoint: true,
+		},
+	}
+
+	fld := Field{
+		Name: "alpha",
+		Type: FieldTypeDouble,
+	}
+
+	template := []byte(`{"alpha":{{.alpha}}}`)
+	t.Logf("with template: %s", string(template))
+
+	for _, testCase := range testCases {
+		t.Run(testCase.scenario, func(t *testing.T) {
+			cfg, err := config.LoadConfigFromYaml([]byte(testCase.config))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			nSpins := 100
+			g := makeGeneratorWithCustomTemplate(t, cfg, Fields{fld}, template, uint64(nSpins))
+
+			for i := 0; i < nSpins; i++ {
+				var buf bytes.Buffer
+				if err := g.Emit(&buf); err != nil {
+					t.Fatal(err)
+				}
+
+				if strings.ContainsAny(buf.String(), "eE") {
+					t.Errorf("Unexpected scientific notation in %s", buf.String())
+				}
+
+				if testCase.noDecimalPoint && strings.Contains(buf.String(), ".") {
+					t.Errorf("Unexpected decimal point in %s", buf.String())
+				}
+
+				m := unmarshalJSONT[float64](t, buf.Bytes())
+				if m[fld.Name] < 0 {
+					t.Errorf("Unexpected negative value in %s", buf.String())
+				}
+			}
+		})
+	}
+}
+
+func Test_FieldDerivedDurationWithCustomTemplate(t *testing.T) {
+	flds := Fields{
+		{Name: "event.duration", Type: FieldTypeLong},
+		{Name: "event.end", Type: FieldTypeDate},
+		{Name: "event.start", Type: FieldTypeDate},
+	}
+
+	cfg, err := config.LoadConfigFromYaml([]byte(`fields:
+  - name: event.duration
+    duration_of: ["event.start", "event.end"]
+  - name: event.start
+    period: -1h
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template, _ := generateCustomTemplateFromField(cfg, flds)
+	t.Logf("with template: %s", string(template))
+
+	nSpins := 100
+	g := makeGeneratorWithCustomTemplate(t, cfg, flds, template, uint64(nSpins))
+
+	for i := 0; i < nSpins; i++ {
+		var buf bytes.Buffer
+		if err := g.Emit(&buf); err != nil {
+			t.Fatal(err)
+		}
+
+		m := unmarshalJSONT[any](t, buf.Bytes())
+		start, err := time.Parse(time.RFC3339Nano, m["event.start"].(string))
+		if err != nil {
+			t.Fatalf("Fail parse event.start: %v", err)
+		}
+
+		end, err := time.Parse(time.RFC3339Nano, m["event.end"].(string))
+		if err != nil {
+			t.Fatalf("Fail parse event.end: %v", err)
+		}
+
+		duration, ok := m["event.duration"].(float64)
+		if !ok {
+			t.Fatalf("Expected number for event.duration, got %s", buf.String())
+		}
+
+		if int64(duration) != end.Sub(start).Nanoseconds() {
+			t.Errorf("Expected event.duration %d, got %d", end.Sub(start).Nanoseconds(), int64(duration))
+		}
+	}
+}
+
+func Test_FieldDerivedLagWithCustomTemplate(t *testing.T) {
+	flds := Fields{
+		{Name: "event.ingested", Type: FieldTypeDate},
+		{Name: "@timestamp", Type: FieldTypeDate},
+	}
+
+	cfg, err := config.LoadConfigFromYaml([]byte(`fields:
+  - name: event.ingested
+    lag_of: "@timestamp"
+    max_lag: 2s
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template, _ := generateCustomTemplateFromField(cfg, flds)
+	t.Logf("with template: %s", string(template))
+
+	nSpins := 100
+	g := makeGeneratorWithCustomTemplate(t, cfg, flds, template, uint64(nSpins))
+
+	for i := 0; i < nSpins; i++ {
+		var buf bytes.Buffer
+		if err := g.Emit(&buf); err != nil {
+			t.Fatal(err)
+		}
+
+		m := unmarshalJSONT[string](t, buf.Bytes())
+		timestamp, err := time.Parse(time.RFC3339Nano, m["@timestamp"])
+		if err != nil {
+			t.Fatalf("Fail parse @timestamp: %v", err)
+		}
+
+		ingested, err := time.Parse(time.RFC3339Nano, m["event.ingested"])
+		if err != nil {
+			t.Fatalf("Fail parse event.ingested: %v", err)
+		}
+
+		if lag := ingested.Sub(timestamp); lag < 0 || lag > 2*time.Second {
+			t.Errorf("Expected event.ingested within 2s after @timestamp, got lag %s", lag)
+		}
+	}
+}
+
+func Test_FieldASNWithCustomTemplate(t *testing.T) {
+	testCases := []struct {
+		scenario string
+		config   string
+		table    map[int64]string
+	}{
+		{
+			scenario: "default table",
+			config:   "fields:\n  - name: source.as",
+		},
+		{
+			scenario: "overridden table",
+			config:   "fields:\n  - name: source.as\n    asns:\n      - number: 64512\n        organization: alpha\n      - number: 64513\n        organization: beta",
+			table:    map[int64]string{64512: "alpha", 64513: "beta"},
+		},
+	}
+
+	fld := Field{
+		Name: "source.as",
+		Type: FieldTypeASN,
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.scenario, func(t *testing.T) {
+			cfg, err := config.LoadConfigFromYaml([]byte(testCase.config))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			table := testCase.table
+			if table == nil {
+				table = make(map[int64]string)
+				for _, asn := range defaultASNs {
+					table[asn.Number] = asn.Organization
+				}
+			}
+
+			template, _ := generateCustomTemplateFromField(cfg, Fields{fld})
+			t.Logf("with template: %s", string(template))
+
+			nSpins := 100
+			g := makeGeneratorWithCustomTemplate(t, cfg, Fields{fld}, template, uint64(nSpins))
+
+			for i := 0; i < nSpins; i++ {
+				var buf bytes.Buffer
+				if err := g.Emit(&buf); err != nil {
+					t.Fatal(err)
+				}
+
+				m := unmarshalJSONT[any](t, buf.Bytes())
+				number, ok := m["source.as.number"].(float64)
+				if !ok {
+					t.Fatalf("Expected number for source.as.number, got %s", buf.String())
+				}
+
+				organization, ok := table[int64(number)]
+				if !ok {
+					t.Fatalf("Number %d not in table", int64(number))
+				}
+
+				if m["source.as.organization.name"] != organization {
+					t.Errorf("Expected organization %s for number %d, got %v", organization, int64(number), m["source.as.organization.name"])
+				}
+			}
+		})
+	}
+}
+
+func Test_FieldPersonNameWithCustomTemplate(t *testing.T) {
+	testCases := []struct {
+		locale      string
+		config      string
+		cardinality int
+	}{
+		{locale: "en_US", config: "fields:\n  - name: alpha"},
+		{locale: "de_DE", config: "fields:\n  - name: alpha\n    locale: de_DE"},
+		{locale: "ja_JP", config: "fields:\n  - name: alpha\n    locale: ja_JP"},
+		{locale: "it_IT", config: "fields:\n  - name: alpha\n    locale: it_IT\n    cardinality: 5", cardinality: 5},
+	}
+
+	fld := Field{
+		Name: "alpha",
+		Type: FieldTypePersonName,
+	}
+
+	template := []byte(`{"alpha":"{{.alpha}}"}`)
+	t.Logf("with template: %s", string(template))
+
+	for _, testCase := range testCases {
+		t.Run(testCase.locale, func(t *testing.T) {
+			cfg, err := config.LoadConfigFromYaml([]byte(testCase.config))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			pool := personNamePools[testCase.locale]
+			givenNames := make(map[string]struct{})
+			for _, name := range pool.givenNames {
+				givenNames[name] = struct{}{}
+			}
+
+			familyNames := make(map[string]struct{})
+			for _, name := range pool.familyNames {
+				familyNames[name] = struct{}{}
+			}
+
+			nSpins := 1024
+			g := makeGeneratorWithCustomTemplate(t, cfg, Fields{fld}, template, uint64(nSpins))
+
+			vmap := make(map[string]struct{})
+			for i := 0; i < nSpins; i++ {
+				var buf bytes.Buffer
+				if err := g.Emit(&buf); err != nil {
+					t.Fatal(err)
+				}
+
+				m := unmarshalJSONT[string](t, buf.Bytes())
+				names := strings.Split(m[fld.Name], " ")
+				if len(names) != 2 {
+					t.Fatalf("Expected given and family name, got %s", m[fld.Name])
+				}
+
+				givenName, familyName := names[0], names[1]
+				if pool.familyNameFirst {
+					givenName, familyName = familyName, givenName
+				}
+
+				if _, ok := givenNames[givenName]; !ok {
+					t.Errorf("Given name %s not in %s pool", givenName, testCase.locale)
+				}
+
+				if _, ok := familyNames[familyName]; !ok {
+					t.Errorf("Family name %s not in %s pool", familyName, testCase.locale)
+				}
+
+				vmap[m[fld.Name]] = struct{}{}
+			}
+
+			if testCase.cardinality > 0 && len(vmap) > testCase.cardinality {
+				t.Errorf("Expected cardinality of at most %d got %d", testCase.cardinality, len(vmap))
+			}
+		})
+	}
+}
+
+func Test_FieldPersonNameUnknownLocaleWithCustomTemplate(t *testing.T) {
+	fld := Field{
+		Name: "alpha",
+		Type: FieldTypePersonName,
+	}
+
+	cfg, err := config.LoadConfigFromYaml([]byte("fields:\n  - name: alpha\n    locale: xx_XX"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := NewGeneratorWithCustomTemplate([]byte(`{"alpha":"{{.alpha}}"}`), cfg, Fields{fld}, 0); err == nil {
+		t.Errorf("Expected error for unknown locale")
+	}
+}
+
+func Test_FieldRegistryPathWithCustomTemplate(t *testing.T) {
+	testCases := []struct {
+		scenario string
+		config   string
+		hives    []string
+		minDepth int
+		maxDepth int
+	}{
+		{
+			scenario: "default",
+			config:   "fields:\n  - name: alpha",
+			hives:    defaultRegistryHives,
+			minDepth: pathDefaultMinDepth,
+			maxDepth: pathDefaultMaxDepth,
+		},
+		{
+			scenario: "hives and depth",
+			config:   "fields:\n  - name: alpha\n    hives: [\"HKLM\", \"HKEY_CURRENT_USER\"]\n    range:\n      min: 2\n      max: 4",
+			hives:    []string{"HKLM", "HKEY_CURRENT_USER"},
+			minDepth: 2,
+			maxDepth: 4,
+		},
+	}
+
+	fld := Field{
+		Name: "alpha",
+		Type: FieldTypeRegistryPath,
+	}
+
+	template := []byte(`{"alpha":"{{.alpha}}"}`)
+	t.Logf("with template: %s", string(template))
+
+	for _, testCase := range testCases {
+		t.Run(testCase.scenario, func(t *testing.T) {
+			cfg, err := config.LoadConfigFromYaml([]byte(testCase.config))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			nSpins := 1024
+			g := makeGeneratorWithCustomTemplate(t, cfg, Fields{fld}, template, uint64(nSpins))
+			for i := 0; i < nSpins; i++ {
+				var buf bytes.Buffer
+				if err := g.Emit(&buf); err != nil {
+					t.Fatal(err)
+				}
+
+				// the backslashes are JSON-escaped: the document is valid JSON
+				m := unmarshalJSONT[string](t, buf.Bytes())
+				keys := strings.Split(m[fld.Name], `\`)
+
+				validHive := false
+				for _, hive := range testCase.hives {
+					validHive = validHive || keys[0] == hive
+				}
+
+				if !validHive {
+					t.Errorf("Expected a path starting with one of %v, got %s", testCase.hives, m[fld.Name])
+				}
+
+				if depth := len(keys) - 1; depth < testCase.minDepth || depth > testCase.maxDepth {
+					t.Errorf("Expected a depth between %d and %d, got %s", testCase.minDepth, testCase.maxDepth, m[fld.Name])
+				}
+			}
+		})
+	}
+}
+
+func Test_FieldPathDepthDistributionWithCustomTemplate(t *testing.T) {
+	testCases := []struct {
+		scenario string
+		config   string
+		minDepth int
+		maxDepth int
+		expected func(depth int) float64
+	}{
+		{
+			scenario: "uniform",
+			config:   "fields:\n  - name: alpha\n    range:\n      min: 2\n      max: 5",
+			minDepth: 2,
+			maxDepth: 5,
+			expected: func(depth int) float64 {
+				return 1.0 / 4
+			},
+		},
+		{
+			scenario: "geometric",
+			config:   "fields:\n  - name: alpha\n    depth_distribution: geometric\n    depth_probability: 0.5\n    range:\n      min: 1\n      max: 20",
+			minDepth: 1,
+			maxDepth: 20,
+			expected: func(depth int) float64 {
+				return math.Pow(0.5, float64(depth))
+			},
+		},
+	}
+
+	fld := Field{
+		Name: "alpha",
+		Type: FieldTypePath,
+	}
+
+	template := []byte(`{"alpha":"{{.alpha}}"}`)
+	t.Logf("with template: %s", string(template))
+
+	for _, testCase := range testCases {
+		t.Run(testCase.scenario, func(t *testing.T) {
+			cfg, err := config.LoadConfigFromYaml([]byte(testCase.config))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			nSpins := 10000
+			g := makeGeneratorWithCustomTemplate(t, cfg, Fields{fld}, template, uint64(nSpins))
+
+			histogram := make(map[int]int)
+			for i := 0; i < nSpins; i++ {
+				var buf bytes.Buffer
+				if err := g.Emit(&buf); err != nil {
+					t.Fatal(err)
+				}
+
+				m := unmarshalJSONT[string](t, buf.Bytes())
+				if !strings.HasPrefix(m[fld.Name], "/") {
+					t.Fatalf("Expected absolute path, got %s", m[fld.Name])
+				}
+
+				depth := strings.Count(m[fld.Name], "/")
+				if depth < testCase.minDepth || depth > testCase.maxDepth {
+					t.Fatalf("Depth of %s out of range [%d, %d]", m[fld.Name], testCase.minDepth, testCase.maxDepth)
+				}
+
+				histogram[depth]++
+			}
+
+			for depth := testCase.minDepth; depth <= testCase.minDepth+3; depth++ {
+				frequency := float64(histogram[depth]) / float64(nSpins)
+				if math.Abs(frequency-testCase.expected(depth)) > 0.03 {
+					t.Errorf("Expected frequency of depth %d around %f, got %f", depth, testCase.expected(depth), frequency)
+				}
+			}
+		})
+	}
+}
+
+func Test_FieldOSWithCustomTemplate(t *testing.T) {
+	fld := Field{
+		Name: "host.os",
+		Type: FieldTypeOS,
+	}
+
+	template, _ := generateCustomTemplateFromField(Config{}, Fields{fld})
+	t.Logf("with template: %s", string(template))
+
+	nSpins := 100
+	g := makeGeneratorWithCustomTemplate(t, Config{}, Fields{fld}, template, uint64(nSpins))
+
+	for i := 0; i < nSpins; i++ {
+		var buf bytes.Buffer
+		if err := g.Emit(&buf); err != nil {
+			t.Fatal(err)
+		}
+
+		m := unmarshalJSONT[string](t, buf.Bytes())
+		emitted := osEntry{name: m["host.os.name"], version: m["host.os.version"], family: m["host.os.family"]}
+
+		var found bool
+		for _, os := range osTable {
+			if os == emitted {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			t.Errorf("Name, version and family %v not from the same row", emitted)
+		}
+	}
+}
+
+func Test_FieldProcessWithCustomTemplate(t *testing.T) {
+	fld := Field{
+		Name: "process",
+		Type: FieldTypeProcess,
+	}
+
+	template, _ := generateCustomTemplateFromField(Config{}, Fields{fld})
+	t.Logf("with template: %s", string(template))
+
+	nSpins := 100
+	g := makeGeneratorWithCustomTemplate(t, Config{}, Fields{fld}, template, uint64(nSpins))
+
+	for i := 0; i < nSpins; i++ {
+		var buf bytes.Buffer
+		if err := g.Emit(&buf); err != nil {
+			t.Fatal(err)
+		}
+
+		m := unmarshalJSONT[any](t, buf.Bytes())
+		pid, ok := m["process.pid"].(float64)
+		if !ok || pid <= 0 || pid != float64(int64(pid)) {
+			t.Errorf("Expected a positive integer pid, got %v", m["process.pid"])
+		}
+
+		parentPID, ok := m["process.parent.pid"].(float64)
+		if !ok || parentPID <= 0 || parentPID != float64(int64(parentPID)) {
+			t.Errorf("Expected a positive integer parent pid, got %v", m["process.parent.pid"])
+		}
+
+		if parentPID == pid {
+			t.Errorf("Expected parent pid different from pid %v", pid)
+		}
+
+		var found bool
+		for _, process := range processTable {
+			if process.name != m["process.name"] {
+				continue
+			}
+
+			for _, parent := range process.parents {
+				if parent == m["process.parent.name"] {
+					found = true
+					break
+				}
+			}
+		}
+
+		if !found {
+			t.Errorf("Parent %v is not a plausible parent of %v", m["process.parent.name"], m["process.name"])
+		}
+	}
+}
+
+func Test_FieldEntityAttributesWithCustomTemplate(t *testing.T) {
+	flds := Fields{
+		{Name: "host.name", Type: FieldTypeKeyword},
+		{Name: "host.ip", Type: FieldTypeIP},
+		{Name: "host.uptime", Type: FieldTypeLong},
+	}
+
+	configYaml := `fields:
+  - name: host.name
+    enum: ["alpha", "beta", "gamma", "delta", "epsilon"]
+  - name: host.ip
+    entity: host.name
+  - name: host.uptime
+    entity: host.name
+`
+	cfg, err := config.LoadConfigFromYaml([]byte(configYaml))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// attributes are emitted before the entity id
+	template := []byte(`{"host.ip":"{{.host.ip}}", "host.uptime":{{.host.uptime}}, "host.name":"{{.host.name}}"}`)
+	t.Logf("with template: %s", string(template))
+
+	nSpins := 1024
+	g := makeGeneratorWithCustomTemplate(t, cfg, flds, template, uint64(nSpins))
+
+	type attributes struct {
+		ip     any
+		uptime any
+		seenAt int
+	}
+
+	entities := make(map[any]attributes)
+	var maxDistance int
+	for i := 0; i < nSpins; i++ {
+		var buf bytes.Buffer
+		if err := g.Emit(&buf); err != nil {
+			t.Fatal(err)
+		}
+
+		m := unmarshalJSONT[any](t, buf.Bytes())
+		name := m["host.name"]
+		if previous, ok := entities[name]; ok {
+			if previous.ip != m["host.ip"] || previous.uptime != m["host.uptime"] {
+				t.Errorf("Expected attributes %v and %v for %v, got %v and %v", previous.ip, previous.uptime, name, m["host.ip"], m["host.uptime"])
+			}
+
+			if i-previous.seenAt > maxDistance {
+				maxDistance = i - previous.seenAt
+			}
+		}
+
+		entities[name] = attributes{ip: m["host.ip"], uptime: m["host.uptime"], seenAt: i}
+	}
+
+	if len(entities) != 5 {
+		t.Errorf("Expected all the entities to be generated, got %v", entities)
+	}
+
+	if maxDistance < 2 {
+		t.Errorf("Expected entities to recur in distant documents")
+	}
+}
+
+func Test_FieldKeywordActiveSubsetWithCustomTemplate(t *testing.T) {
+	fld := Field{
+		Name: "alpha",
+		Type: FieldTypeKeyword,
+	}
+
+	cfg, err := config.LoadConfigFromYaml([]byte("fields:\n  - name: alpha\n    enum: [a, b, c, d, e, f, g, h, i, j]\n    active_subset: 3"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template, _ := generateCustomTemplateFromField(cfg, Fields{fld})
+	t.Logf("with template: %s", string(template))
+
+	activeSubset := func(seed int64) map[string]int {
+		InitGeneratorRandSeed(seed)
+
+		nSpins := 1000
+		g := makeGeneratorWithCustomTemplate(t, cfg, Fields{fld}, template, uint64(nSpins))
+
+		values := make(map[string]int)
+		for i := 0; i < nSpins; i++ {
+			var buf bytes.Buffer
+			if err := g.Emit(&buf); err != nil {
+				t.Fatal(err)
+			}
+
+			m := unmarshalJSONT[string](t, buf.Bytes())
+			values[m["alpha"]] += 1
+		}
+
+		return values
+	}
+
+	first := activeSubset(42)
+	if len(first) != 3 {
+		t.Errorf("Expected 3 distinct values, got %v", first)
+	}
+
+	// the subset is stable for the same seed
+	second := activeSubset(42)
+	for value := range first {
+		if _, ok := second[value]; !ok {
+			t.Errorf("Expected the same subset with the same seed, got %v and %v", first, second)
+		}
+	}
+}
+
+func Test_FieldKeywordWeightedEnumWithCustomTemplate(t *testing.T) {
+	fld := Field{
+		Name: "event.outcome",
+		Type: FieldTypeKeyword,
+	}
+
+	weights := map[string]float64{"ACCEPT": 90, "REJECT": 7, "DROP": 3}
+
+	cfg, err := config.LoadConfigFromYaml([]byte("fields:\n  - name: event.outcome\n    enum: [{value: ACCEPT, weight: 90}, {value: REJECT, weight: 7}, {value: DROP, weight: 3}]"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := []byte(`{"event.outcome":"{{.event.outcome}}"}`)
+	t.Logf("with template: %s", string(template))
+
+	nSpins := 10000
+	g := makeGeneratorWithCustomTemplate(t, cfg, Fields{fld}, template, uint64(nSpins))
+
+	frequencies := make(map[string]int)
+	for i := 0; i < nSpins; i++ {
+		var buf bytes.Buffer
+		if err := g.Emit(&buf); err != nil {
+			t.Fatal(err)
+		}
+
+		m := unmarshalJSONT[string](t, buf.Bytes())
+		frequencies[m[fld.Name]] += 1
+	}
+
+	for value, weight := range weights {
+		// the standard deviation of the rate of the most frequent value is 0.003
+		expected := weight / 100
+		rate := float64(frequencies[value]) / float64(nSpins)
+		if math.Abs(rate-expected) > 0.015 {
+			t.Errorf("Expected %s frequency %f, got %f", value, expected, rate)
+		}
+	}
+
+	if len(frequencies) != len(weights) {
+		t.Errorf("Expected only the enum values, got %v", frequencies)
+	}
+}
+
+func Test_FieldKeywordZipfWithCustomTemplate(t *testing.T) {
+	fld := Field{
+		Name: "url.path",
+		Type: FieldTypeKeyword,
+	}
+
+	enum := []string{"/", "/login", "/search", "/cart", "/checkout", "/help", "/about", "/jobs", "/blog", "/contact"}
+	testCases := []struct {
+		scenario   string
+		configYaml string
+		expected   float64
+	}{
+		{
+			scenario:   "uniform",
+			configYaml: "fields:\n  - name: url.path\n    enum: [\"" + strings.Join(enum, "\", \"") + "\"]",
+			expected:   0.1,
+		},
+		{
+			scenario:   "zipf",
+			configYaml: "fields:\n  - name: url.path\n    distribution: zipf\n    zipf_s: 2\n    enum: [\"" + strings.Join(enum, "\", \"") + "\"]",
+			// P(k) is proportional to (v + k)^-s
+			expected: 1 / func() float64 {
+				var sum float64
+				for k := range enum {
+					sum += math.Pow(float64(1+k), -2)
+				}
+				return sum
+			}(),
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.scenario, func(t *testing.T) {
+			cfg, err := config.LoadConfigFromYaml([]byte(testCase.configYaml))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			template := []byte(`{"url.path":"{{.url.path}}"}`)
+			nSpins := 10000
+			g := makeGeneratorWithCustomTemplate(t, cfg, Fields{fld}, template, uint64(nSpins))
+
+			frequencies := make(map[string]int)
+			for i := 0; i < nSpins; i++ {
+				var buf bytes.Buffer
+				if err := g.Emit(&buf); err != nil {
+					t.Fatal(err)
+				}
+
+				m := unmarshalJSONT[string](t, buf.Bytes())
+				frequencies[m["url.path"]] += 1
+			}
+
+			rate := float64(frequencies[enum[0]]) / float64(nSpins)
+			if math.Abs(rate-testCase.expected) > 0.03 {
+				t.Errorf("Expected %s frequency %f, got %f", enum[0], testCase.expected, rate)
+			}
+
+			if testCase.scenario == "zipf" {
+				for _, value := range enum[1:] {
+					if frequencies[value] >= frequencies[enum[0]] {
+						t.Errorf("Expected %s to dominate, got %v", enum[0], frequencies)
+					}
+				}
+			}
+		})
+	}
+}
+
+func Test_FieldSQLStatementWithCustomTemplate(t *testing.T) {
+	testCases := []struct {
+		scenario string
+		config   string
+		tables   []string
+		verbs    []string
+	}{
+		{
+			scenario: "default",
+			config:   "fields:\n  - name: db.statement",
+			tables:   []string{"users", "orders", "products", "sessions"},
+			verbs:    []string{"SELECT", "INSERT", "UPDATE", "DELETE"},
+		},
+		{
+			scenario: "with tables and weights",
+			config:   "fields:\n  - name: db.statement\n    sql_tables:\n      - name: invoices\n        columns: [\"id\", \"amount\"]\n    sql_statement_weights:\n      insert: 1\n      update: 1",
+			tables:   []string{"invoices"},
+			verbs:    []string{"INSERT", "UPDATE"},
+		},
+	}
+
+	fld := Field{
+		Name: "db.statement",
+		Type: FieldTypeSQLStatement,
+	}
+
+	template := []byte(`{"db.statement":"{{.db.statement}}"}`)
+	t.Logf("with template: %s", string(template))
+
+	for _, testCase := range testCases {
+		t.Run(testCase.scenario, func(t *testing.T) {
+			cfg, err := config.LoadConfigFromYaml([]byte(testCase.config))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			nSpins := 1000
+			g := makeGeneratorWithCustomTemplate(t, cfg, Fields{fld}, template, uint64(nSpins))
+
+			verbs := make(map[string]int)
+			for i := 0; i < nSpins; i++ {
+				var buf bytes.Buffer
+				if err := g.Emit(&buf); err != nil {
+					t.Fatal(err)
+				}
+
+				statement := unmarshalJSONT[string](t, buf.Bytes())[fld.Name]
+
+				verb, _, _ := strings.Cut(statement, " ")
+				verbs[verb] += 1
+
+				var knownTable bool
+				for _, table := range testCase.tables {
+					if strings.Contains(statement, " "+table+" ") {
+						knownTable = true
+						break
+					}
+				}
+
+				if !knownTable {
+					t.Errorf("Expected statement referencing one of %v, got %s", testCase.tables, statement)
+				}
+			}
+
+			if len(verbs) != len(testCase.verbs) {
+				t.Errorf("Expected verbs %v, got %v", testCase.verbs, verbs)
+			}
+
+			for _, verb := range testCase.verbs {
+				if verbs[verb] == 0 {
+					t.Errorf("Expected verbs %v, got %v", testCase.verbs, verbs)
+				}
+			}
+		})
+	}
+}
+
+func Test_FieldSequenceWithCustomTemplate(t *testing.T) {
+	fld := Field{
+		Name: "alpha",
+		Type: FieldTypeKeyword,
+	}
+
+	cfg, err := config.LoadConfigFromYaml([]byte("fields:\n  - name: alpha\n    sequence: [\"a\", \"b\", \"c\"]"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := []byte(`{"alpha":{{.alpha}}}`)
+	t.Logf("with template: %s", string(template))
+
+	sequence := []string{"a", "b", "c"}
+	nSpins := 10
+	g := makeGeneratorWithCustomTemplate(t, cfg, Fields{fld}, template, uint64(nSpins))
+
+	for i := 0; i < nSpins; i++ {
+		var buf bytes.Buffer
+		if err := g.Emit(&buf); err != nil {
+			t.Fatal(err)
+		}
+
+		m := unmarshalJSONT[string](t, buf.Bytes())
+		if m[fld.Name] != sequence[i%len(sequence)] {
+			t.Errorf("Expected %s in document %d, got %s", sequence[i%len(sequence)], i, m[fld.Name])
+		}
+	}
+}
+
+func Test_FieldCloudWithCustomTemplate(t *testing.T) {
+	fld := Field{
+		Name: "cloud",
+		Type: FieldTypeCloud,
+	}
+
+	template, _ := generateCustomTemplateFromField(Config{}, Fields{fld})
+	t.Logf("with template: %s", string(template))
+
+	nSpins := 100
+	g := makeGeneratorWithCustomTemplate(t, Config{}, Fields{fld}, template, uint64(nSpins))
+
+	for i := 0; i < nSpins; i++ {
+		var buf bytes.Buffer
+		if err := g.Emit(&buf); err != nil {
+			t.Fatal(err)
+		}
+
+		m := unmarshalJSONT[string](t, buf.Bytes())
+		if len(m["cloud.account.id"]) == 0 {
+			t.Errorf("Expected cloud.account.id, got %s", buf.String())
+		}
+
+		var found bool
+		for _, provider := range cloudTable {
+			if provider.name != m["cloud.provider"] {
+				continue
+			}
+
+			for _, region := range provider.regions {
+				if region.name != m["cloud.region"] {
+					continue
+				}
+
+				for _, availabilityZone := range region.availabilityZones {
+					if availabilityZone == m["cloud.availability_zone"] {
+						found = true
+					}
+				}
+			}
+		}
+
+		if !found {
+			t.Errorf("Availability zone %s not in region %s of provider %s", m["cloud.availability_zone"], m["cloud.region"], m["cloud.provider"])
+		}
+	}
+}
+
+func Test_FieldValidityWithCustomTemplate(t *testing.T) {
+	fld := Field{
+		Name: "tls.client",
+		Type: FieldTypeValidity,
+	}
+
+	cfg, err := config.LoadConfigFromYaml([]byte("fields:\n  - name: tls.client\n    validity: 2160h\n    period: -720h"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template, _ := generateCustomTemplateFromField(cfg, Fields{fld})
+	t.Logf("with template: %s", string(template))
+
+	nSpins := 100
+	g := makeGeneratorWithCustomTemplate(t, cfg, Fields{fld}, template, uint64(nSpins))
+
+	for i := 0; i < nSpins; i++ {
+		var buf bytes.Buffer
+		if err := g.Emit(&buf); err != nil {
+			t.Fatal(err)
+		}
+
+		m := unmarshalJSONT[string](t, buf.Bytes())
+		notBefore, err := time.Parse(time.RFC3339Nano, m["tls.client.not_before"])
+		if err != nil {
+			t.Fatalf("Fail parse tls.client.not_before: %v", err)
+		}
+
+		notAfter, err := time.Parse(time.RFC3339Nano, m["tls.client.not_after"])
+		if err != nil {
+			t.Fatalf("Fail parse tls.client.not_after: %v", err)
+		}
+
+		if !notAfter.After(notBefore) || notAfter.Sub(notBefore) != 2160*time.Hour {
+			t.Errorf("Expected not_after 2160h after not_before, got %s and %s", notBefore, notAfter)
+		}
+	}
+}
+
+func Test_FieldSeedWithCustomTemplate(t *testing.T) {
+	flds := Fields{
+		{Name: "host.name", Type: FieldTypeKeyword},
+		{Name: "message", Type: FieldTypeKeyword},
+	}
+
+	cfg, err := config.LoadConfigFromYaml([]byte("fields:\n  - name: host.name\n    seed: 42"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := []byte(`{"host.name":"{{.host.name}}","message":"{{.message}}"}`)
+	t.Logf("with template: %s", string(template))
+
+	nSpins := 10
+	generate := func(randSeed int64) []map[string]string {
+		InitGeneratorRandSeed(randSeed)
+		g := makeGeneratorWithCustomTemplate(t, cfg, flds, template, uint64(nSpins))
+
+		docs := make([]map[string]string, 0, nSpins)
+		for i := 0; i < nSpins; i++ {
+			var buf bytes.Buffer
+			if err := g.Emit(&buf); err != nil {
+				t.Fatal(err)
+			}
+
+			docs = append(docs, unmarshalJSONT[string](t, buf.Bytes()))
+		}
+
+		return docs
+	}
+
+	docs, otherDocs := generate(1), generate(2)
+
+	var messageChanged bool
+	for i := range docs {
+		if docs[i]["host.name"] != otherDocs[i]["host.name"] {
+			t.Errorf("Expected host.name with pinned seed not to change, got %s and %s", docs[i]["host.name"], otherDocs[i]["host.name"])
+		}
+
+		if docs[i]["message"] != otherDocs[i]["message"] {
+			messageChanged = true
+		}
+	}
+
+	if !messageChanged {
+		t.Errorf("Expected message to change with the global seed")
+	}
+}
+
+func Test_FieldKubernetesWithCustomTemplate(t *testing.T) {
+	fld := Field{
+		Name: "kubernetes",
+		Type: FieldTypeKubernetes,
+	}
+
+	template, _ := generateCustomTemplateFromField(Config{}, Fields{fld})
+	t.Logf("with template: %s", string(template))
+
+	nSpins := 100
+	g := makeGeneratorWithCustomTemplate(t, Config{}, Fields{fld}, template, uint64(nSpins))
+
+	for i := 0; i < nSpins; i++ {
+		var buf bytes.Buffer
+		if err := g.Emit(&buf); err != nil {
+			t.Fatal(err)
+		}
+
+		m := unmarshalJSONT[string](t, buf.Bytes())
+		if len(m["container.id"]) != 64 {
+			t.Errorf("Expected container.id of 64 characters, got %s", buf.String())
+		}
+
+		if !strings.HasPrefix(m["kubernetes.pod.name"], m["kubernetes.deployment.name"]+"-") {
+			t.Errorf("Pod name %s does not have deployment %s as prefix", m["kubernetes.pod.name"], m["kubernetes.deployment.name"])
+		}
+
+		var found bool
+		for _, deployment := range kubernetesTable {
+			if deployment.name == m["kubernetes.deployment.name"] {
+				found = deployment.namespace == m["kubernetes.namespace"] && deployment.image == m["container.image.name"]
+				break
+			}
+		}
+
+		if !found {
+			t.Errorf("Namespace %s or image %s not matching deployment %s", m["kubernetes.namespace"], m["container.image.name"], m["kubernetes.deployment.name"])
+		}
+	}
+}
+
+func Test_FieldDNSWithCustomTemplate(t *testing.T) {
+	fld := Field{
+		Name: "dns",
+		Type: FieldTypeDNS,
+	}
+
+	template, _ := generateCustomTemplateFromField(Config{}, Fields{fld})
+	t.Logf("with template: %s", string(template))
+
+	nSpins := 100
+	g := makeGeneratorWithCustomTemplate(t, Config{}, Fields{fld}, template, uint64(nSpins))
+
+	for i := 0; i < nSpins; i++ {
+		var buf bytes.Buffer
+		if err := g.Emit(&buf); err != nil {
+			t.Fatal(err)
+		}
+
+		m := unmarshalJSONT[string](t, buf.Bytes())
+		if m["dns.answers.name"] != m["dns.question.name"] || m["dns.answers.type"] != m["dns.question.type"] {
+			t.Errorf("Expected answer for question, got %s", buf.String())
+		}
+
+		ip := net.ParseIP(m["dns.answers.data"])
+		switch m["dns.question.type"] {
+		case "A":
+			if ip == nil || ip.To4() == nil {
+				t.Errorf("Expected IPv4 answer for A question, got %s", m["dns.answers.data"])
+			}
+		case "AAAA":
+			if ip == nil || ip.To4() != nil {
+				t.Errorf("Expected IPv6 answer for AAAA question, got %s", m["dns.answers.data"])
+			}
+		case "CNAME":
+			if ip != nil || len(m["dns.answers.data"]) == 0 {
+				t.Errorf("Expected domain answer for CNAME question, got %s", m["dns.answers.data"])
+			}
+		default:
+			t.Errorf("Unexpected question type %s", m["dns.question.type"])
+		}
+	}
+}
+
+func Test_FieldPolymorphicWithCustomTemplate(t *testing.T) {
+	fld := Field{
+		Name: "alpha",
+		Type: FieldTypeLong,
+	}
+
+	cfg, err := config.LoadConfigFromYaml([]byte("fields:\n  - name: alpha\n    polymorphic:\n      long: 0.8\n      keyword: 0.2"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template, _ := generateCustomTemplateFromField(cfg, Fields{fld})
+	t.Logf("with template: %s", string(template))
+
+	nSpins := 1000
+	g := makeGeneratorWithCustomTemplate(t, cfg, Fields{fld}, template, uint64(nSpins))
+
+	var numbers, texts int
+	for i := 0; i < nSpins; i++ {
+		var buf bytes.Buffer
+		if err := g.Emit(&buf); err != nil {
+			t.Fatal(err)
+		}
+
+		m := unmarshalJSONT[any](t, buf.Bytes())
+		switch m[fld.Name].(type) {
+		case float64:
+			numbers += 1
+		case string:
+			texts += 1
+		default:
+			t.Errorf("Unexpected value type %T in %s", m[fld.Name], buf.String())
+		}
+	}
+
+	if numbers < 700 || numbers > 900 || numbers+texts != nSpins {
+		t.Errorf("Expected about 80%% numbers and 20%% strings, got %d numbers and %d strings", numbers, texts)
+	}
+}
+
+func Test_FieldSessionWithCustomTemplate(t *testing.T) {
+	fld := Field{
+		Name: "session",
+		Type: FieldTypeSession,
+	}
+
+	cfg, err := config.LoadConfigFromYaml([]byte("fields:\n  - name: session\n    session_length: 5"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template, _ := generateCustomTemplateFromField(cfg, Fields{fld})
+	t.Logf("with template: %s", string(template))
+
+	nSpins := 20
+	g := makeGeneratorWithCustomTemplate(t, cfg, Fields{fld}, template, uint64(nSpins))
+
+	var previous map[string]any
+	sessionIDs := make(map[any]struct{})
+	for i := 0; i < nSpins; i++ {
+		var buf bytes.Buffer
+		if err := g.Emit(&buf); err != nil {
+			t.Fatal(err)
+		}
+
+		m := unmarshalJSONT[any](t, buf.Bytes())
+		step := m["session.step"].(float64)
+		if step != float64(i%5+1) {
+			t.Errorf("Expected step %d in document %d, got %v", i%5+1, i, step)
+		}
+
+		if step > 1 && (m["session.id"] != previous["session.id"] || m["session.user.name"] != previous["session.user.name"]) {
+			t.Errorf("Expected same session id and user within a session, got %v after %v", m, previous)
+		}
+
+		sessionIDs[m["session.id"]] = struct{}{}
+		previous = m
+	}
+
+	if len(sessionIDs) != nSpins/5 {
+		t.Errorf("Expected %d sessions, got %d", nSpins/5, len(sessionIDs))
+	}
+}
+
+func Test_FieldULIDWithCustomTemplate(t *testing.T) {
+	fld := Field{
+		Name: "alpha",
+		Type: FieldTypeULID,
+	}
+
+	template, _ := generateCustomTemplateFromField(Config{}, Fields{fld})
+	t.Logf("with template: %s", string(template))
+
+	nSpins := 100
+	g := makeGeneratorWithCustomTemplate(t, Config{}, Fields{fld}, template, uint64(nSpins))
+
+	var previous string
+	for i := 0; i < nSpins; i++ {
+		var buf bytes.Buffer
+		if err := g.Emit(&buf); err != nil {
+			t.Fatal(err)
+		}
+
+		m := unmarshalJSONT[string](t, buf.Bytes())
+		value := m[fld.Name]
+		if len(value) != 26 || strings.Trim(value, crockfordBase32Alphabet) != "" {
+			t.Errorf("Expected 26 characters Crockford base32 ULID, got %s", value)
+		}
+
+		if value <= previous {
+			t.Errorf("Expected ULID %s to sort after %s", value, previous)
+		}
+
+		previous = value
+	}
+}
+
+func Test_FieldUUIDWithCustomTemplate(t *testing.T) {
+	fld := Field{
+		Name: "trace.id",
+		Type: FieldTypeUUID,
+	}
+
+	for _, version := range []int{4, 7} {
+		cfg, err := config.LoadConfigFromYaml([]byte(fmt.Sprintf("fields:\n  - name: trace.id\n    uuid_version: %d", version)))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		template, _ := generateCustomTemplateFromField(cfg, Fields{fld})
+		t.Logf("with template: %s", string(template))
+
+		nSpins := 100
+		g := makeGeneratorWithCustomTemplate(t, cfg, Fields{fld}, template, uint64(nSpins))
+
+		var previous string
+		vmap := make(map[string]struct{})
+		for i := 0; i < nSpins; i++ {
+			var buf bytes.Buffer
+			if err := g.Emit(&buf); err != nil {
+				t.Fatal(err)
+			}
+
+			m := unmarshalJSONT[string](t, buf.Bytes())
+			value := m[fld.Name]
+			checkUUID(t, value, version)
+
+			// UUIDv7 are time ordered, and sort in generation order
+			if version == 7 && value <= previous {
+				t.Errorf("Expected UUID %s to sort after %s", value, previous)
+			}
+
+			previous = value
+			vmap[value] = struct{}{}
+		}
+
+		if len(vmap) != nSpins {
+			t.Errorf("Expected %d distinct UUIDs, got %d", nSpins, len(vmap))
+		}
+	}
+}
+
+func Test_FieldKeywordPatternWithCustomTemplate(t *testing.T) {
+	fld := Field{
+		Name: "alpha",
+		Type: FieldTypeKeyword,
+	}
+
+	for _, pattern := range []string{
+		`eni-[0-9a-f]{17}`,
+		`^(GET|POST|DELETE) /api/v[12]/\w{3,8}(/[0-9]+)?$`,
+		`[A-Z]{2}-\d{4,6}\.[^,]{2}`,
+	} {
+		cfg, err := config.LoadConfigFromYaml([]byte(fmt.Sprintf("fields:\n  - name: alpha\n    pattern: '%s'", pattern)))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		template, _ := generateCustomTemplateFromField(cfg, Fields{fld})
+		t.Logf("with template: %s", string(template))
+
+		nSpins := 100
+		g := makeGeneratorWithCustomTemplate(t, cfg, Fields{fld}, template, uint64(nSpins))
+
+		re := regexp.MustCompile(`^(?:` + pattern + `)$`)
+		vmap := make(map[string]struct{})
+		for i := 0; i < nSpins; i++ {
+			var buf bytes.Buffer
+			if err := g.Emit(&buf); err != nil {
+				t.Fatal(err)
+			}
+
+			m := unmarshalJSONT[string](t, buf.Bytes())
+			if !re.MatchString(m[fld.Name]) {
+				t.Errorf("Expected %s to match %s", m[fld.Name], pattern)
+			}
+
+			vmap[m[fld.Name]] = struct{}{}
+		}
+
+		if len(vmap) < nSpins/2 {
+			t.Errorf("Expected varied values for %s, got %d distinct", pattern, len(vmap))
+		}
+	}
+}
+
+func Test_FieldHexTokenWithCustomTemplate(t *testing.T) {
+	fld := Field{
+		Name: "alpha",
+		Type: FieldTypeHexToken,
+	}
+
+	cfg, err := config.LoadConfigFromYaml([]byte("fields:\n  - name: alpha\n    length: 40"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template, _ := generateCustomTemplateFromField(cfg, Fields{fld})
+	t.Logf("with template: %s", string(template))
+
+	nSpins := 100
+	g := makeGeneratorWithCustomTemplate(t, cfg, Fields{fld}, template, uint64(nSpins))
+
+	for i := 0; i < nSpins; i++ {
+		var buf bytes.Buffer
+		if err := g.Emit(&buf); err != nil {
+			t.Fatal(err)
+		}
+
+		m := unmarshalJSONT[string](t, buf.Bytes())
+		if len(m[fld.Name]) != 40 || strings.Trim(m[fld.Name], hexAlphabet) != "" {
+			t.Errorf("Expected 40 hex characters, got %s", m[fld.Name])
+		}
+	}
+}
+
+func Test_FieldIPVersionWithCustomTemplate(t *testing.T) {
+	testCases := []struct {
+		ipVersion string
+		minIPv6   int
+		maxIPv6   int
+	}{
+		{ipVersion: "v4", minIPv6: 0, maxIPv6: 0},
+		{ipVersion: "v6", minIPv6: 1000, maxIPv6: 1000},
+		{ipVersion: "both", minIPv6: 400, maxIPv6: 600},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.ipVersion, func(t *testing.T) {
+			fld := Field{
+				Name: "alpha",
+				Type: FieldTypeIP,
+			}
+
+			cfg, err := config.LoadConfigFromYaml([]byte("fields:\n  - name: alpha\n    ip_version: " + testCase.ipVersion))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			template, _ := generateCustomTemplateFromField(cfg, Fields{fld})
+			t.Logf("with template: %s", string(template))
+
+			nSpins := 1000
+			g := makeGeneratorWithCustomTemplate(t, cfg, Fields{fld}, template, uint64(nSpins))
+
+			var ipv6, compressed int
+			for i := 0; i < nSpins; i++ {
+				var buf bytes.Buffer
+				if err := g.Emit(&buf); err != nil {
+					t.Fatal(err)
+				}
+
+				m := unmarshalJSONT[string](t, buf.Bytes())
+				ip := net.ParseIP(m[fld.Name])
+				if ip == nil {
+					t.Fatalf("Expected valid IP, got %s", m[fld.Name])
+				}
+
+				if ip.To4() != nil {
+					continue
+				}
+
+				ipv6 += 1
+				if strings.Contains(m[fld.Name], "::") {
+					compressed += 1
+				}
+
+				// RFC 5952 canonical form: lowercase, zero compressed
+				if ip.String() != m[fld.Name] {
+					t.Errorf("Expected IPv6 in canonical form %s, got %s", ip.String(), m[fld.Name])
+				}
+			}
+
+			if ipv6 < testCase.minIPv6 || ipv6 > testCase.maxIPv6 {
+				t.Errorf("Expected between %d and %d IPv6 addresses, got %d", testCase.minIPv6, testCase.maxIPv6, ipv6)
+			}
+
+			if ipv6 > 0 && compressed == 0 {
+				t.Errorf("Expected zero compressed IPv6 addresses")
+			}
+		})
+	}
+}
+
+func Test_FieldDerivedRatioWithCustomTemplate(t *testing.T) {
+	flds := Fields{
+		{Name: "network.bytes", Type: FieldTypeLong},
+		{Name: "network.packets", Type: FieldTypeLong},
+	}
+
+	cfg, err := config.LoadConfigFromYaml([]byte("fields:\n  - name: network.packets\n    range:\n      min: 1\n      max: 100\n  - name: network.bytes\n    ratio_of: network.packets\n    ratio: 800\n    ratio_noise: 0.25"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template, _ := generateCustomTemplateFromField(cfg, flds)
+	t.Logf("with template: %s", string(template))
+
+	nSpins := 1000
+	g := makeGeneratorWithCustomTemplate(t, cfg, flds, template, uint64(nSpins))
+
+	for i := 0; i < nSpins; i++ {
+		var buf bytes.Buffer
+		if err := g.Emit(&buf); err != nil {
+			t.Fatal(err)
+		}
+
+		m := unmarshalJSONT[float64](t, buf.Bytes())
+		if m["network.bytes"] != math.Trunc(m["network.bytes"]) {
+			t.Errorf("Expected integer bytes, got %v", m["network.bytes"])
+		}
+
+		// 800 bytes per packet, +/- 25% noise, +/- 0.5 of rounding
+		if m["network.bytes"] < m["network.packets"]*600-0.5 || m["network.bytes"] > m["network.packets"]*1000+0.5 {
+			t.Errorf("Expected bytes between 600 and 1000 times packets, got %v bytes for %v packets", m["network.bytes"], m["network.packets"])
+		}
+	}
+}
+
+func Test_FieldDerivedRatioOfDoubleWithCustomTemplate(t *testing.T) {
+	flds := Fields{
+		{Name: "system.cpu.total.norm.pct", Type: FieldTypeDouble},
+		{Name: "system.cpu.total.pct", Type: FieldTypeDouble},
+	}
+
+	cfg, err := config.LoadConfigFromYaml([]byte("fields:\n  - name: system.cpu.total.norm.pct\n    range:\n      min: 1\n      max: 10\n  - name: system.cpu.total.pct\n    ratio_of: system.cpu.total.norm.pct\n    ratio: 4\n    ratio_noise: 0.1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template, _ := generateCustomTemplateFromField(cfg, flds)
+	t.Logf("with template: %s", string(template))
+
+	nSpins := 1000
+	g := makeGeneratorWithCustomTemplate(t, cfg, flds, template, uint64(nSpins))
+
+	for i := 0; i < nSpins; i++ {
+		var buf bytes.Buffer
+		if err := g.Emit(&buf); err != nil {
+			t.Fatal(err)
+		}
+
+		m := unmarshalJSONT[float64](t, buf.Bytes())
+		norm, total := m["system.cpu.total.norm.pct"], m["system.cpu.total.pct"]
+
+		// 4 times the other value, +/- 10% noise, +/- the rounding to 6 decimals
+		if total < norm*3.6-1e-5 || total > norm*4.4+1e-5 {
+			t.Errorf("Expected total between 3.6 and 4.4 times norm, got %v total for %v norm", total, norm)
+		}
+	}
+}
+
+func Test_FieldDerivedDirectionWithCustomTemplate(t *testing.T) {
+	flds := Fields{
+		{Name: "network.direction", Type: FieldTypeKeyword},
+		{Name: "source.ip", Type: FieldTypeIP},
+		{Name: "destination.ip", Type: FieldTypeIP},
+	}
+
+	configYaml := `fields:
+  - name: source.ip
+    ip_pools:
+      - cidr: 10.0.0.0/8
+        weight: 1
+      - cidr: 203.0.113.0/24
+        weight: 1
+  - name: destination.ip
+    ip_pools:
+      - cidr: 10.0.0.0/8
+        weight: 1
+      - cidr: 198.51.100.0/24
+        weight: 1
+  - name: network.direction
+    direction_of: [source.ip, destination.ip]
+    internal_networks: [10.0.0.0/8]`
+
+	cfg, err := config.LoadConfigFromYaml([]byte(configYaml))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template, _ := generateCustomTemplateFromField(cfg, flds)
+	t.Logf("with template: %s", string(template))
+
+	nSpins := 1000
+	g := makeGeneratorWithCustomTemplate(t, cfg, flds, template, uint64(nSpins))
+
+	directions := make(map[string]int)
+	for i := 0; i < nSpins; i++ {
+		var buf bytes.Buffer
+		if err := g.Emit(&buf); err != nil {
+			t.Fatal(err)
+		}
+
+		m := unmarshalJSONT[string](t, buf.Bytes())
+		sourceInternal := strings.HasPrefix(m["source.ip"], "10.")
+		destinationInternal := strings.HasPrefix(m["destination.ip"], "10.")
+
+		expected := "external"
+		switch {
+		case sourceInternal && destinationInternal:
+			expected = "internal"
+		case sourceInternal:
+			expected = "outbound"
+		case destinationInternal:
+			expected = "inbound"
+		}
+
+		if m["network.direction"] != expected {
+			t.Errorf("Expected direction %s, got %s", expected, buf.String())
+		}
+
+		directions[m["network.direction"]] += 1
+	}
+
+	for _, direction := range []string{"inbound", "outbound", "internal", "external"} {
+		if directions[direction] == 0 {
+			t.Errorf("Expected direction %s to be generated", direction)
+		}
+	}
+}
+
+func Test_FieldCacheForWithCustomTemplate(t *testing.T) {
+	fld := Field{
+		Name: "alpha",
+		Type: FieldTypeLong,
+	}
+
+	cfg, err := config.LoadConfigFromYaml([]byte("fields:\n  - name: alpha\n    cache_for: 3\n    range:\n      min: 1\n      max: 1000000000"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := []byte(`{"alpha":{{.alpha}}}`)
+	t.Logf("with template: %s", string(template))
+
+	nSpins := 30
+	g := makeGeneratorWithCustomTemplate(t, cfg, Fields{fld}, template, uint64(nSpins))
+
+	var previous int64
+	for i := 0; i < nSpins; i++ {
+		var buf bytes.Buffer
+		if err := g.Emit(&buf); err != nil {
+			t.Fatal(err)
+		}
+
+		m := unmarshalJSONT[int64](t, buf.Bytes())
+		// the value is generated at the first event of every 3, and emitted as is in the following 2
+		if i%3 == 0 && m[fld.Name] == previous {
+			t.Errorf("Expected a new value at event %d, got %d again", i, previous)
+		}
+
+		if i%3 != 0 && m[fld.Name] != previous {
+			t.Errorf("Expected the cached value %d at event %d, got %d", previous, i, m[fld.Name])
+		}
+
+		previous = m[fld.Name]
+	}
+}
+
+func Test_FieldGeoPointWithinBoundsWithCustomTemplate(t *testing.T) {
+	fld := Field{
+		Name: "alpha",
+		Type: FieldTypeGeoPoint,
+	}
+
+	bounds := "\n    geo_bounds:\n      top_left: {lat: 47.1, lon: 6.6}\n      bottom_right: {lat: 36.6, lon: 18.5}"
+	for _, geoFormat := range []string{"", config.GeoFormatGeohash} {
+		t.Run(geoFormat, func(t *testing.T) {
+			configYaml := "fields:\n  - name: alpha" + bounds
+			if len(geoFormat) > 0 {
+				configYaml += "\n    geo_format: " + geoFormat
+			}
+
+			cfg, err := config.LoadConfigFromYaml([]byte(configYaml))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			template, _ := generateCustomTemplateFromField(cfg, Fields{fld})
+			t.Logf("with template: %s", string(template))
+
+			nSpins := 1000
+			g := makeGeneratorWithCustomTemplate(t, cfg, Fields{fld}, template, uint64(nSpins))
+
+			for i := 0; i < nSpins; i++ {
+				var buf bytes.Buffer
+				if err := g.Emit(&buf); err != nil {
+					t.Fatal(err)
+				}
+
+				m := unmarshalJSONT[string](t, buf.Bytes())
+
+				var lat, lon float64
+				if geoFormat == config.GeoFormatGeohash {
+					lat, lon, _, _ = decodeGeohash(m["alpha"])
+				} else {
+					coordinates := strings.Split(m["alpha"], ",")
+					if len(coordinates) != 2 {
+						t.Fatalf("Expected lat,lon, got %s", m["alpha"])
+					}
+
+					lat, _ = strconv.ParseFloat(coordinates[0], 64)
+					lon, _ = strconv.ParseFloat(coordinates[1], 64)
+				}
+
+				if lat < 36.6 || lat > 47.1 || lon < 6.6 || lon > 18.5 {
+					t.Errorf("Expected a point within the bounds, got %s", m["alpha"])
+				}
+			}
+		})
+	}
+}
+
+func Test_FieldGeoShapeWithCustomTemplate(t *testing.T) {
+	fld := Field{
+		Name: "alpha",
+		Type: FieldTypeGeoShape,
+	}
+
+	for _, geometryTypes := range [][]string{nil, {geometryTypePolygon}} {
+		t.Run(strings.Join(geometryTypes, ","), func(t *testing.T) {
+			configYaml := "fields:\n  - name: alpha"
+			if len(geometryTypes) > 0 {
+				configYaml += "\n    geometry_types: [" + strings.Join(geometryTypes, ", ") + "]"
+			}
+
+			cfg, err := config.LoadConfigFromYaml([]byte(configYaml))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			template, _ := generateCustomTemplateFromField(cfg, Fields{fld})
+			t.Logf("with template: %s", string(template))
+
+			nSpins := 1000
+			g := makeGeneratorWithCustomTemplate(t, cfg, Fields{fld}, template, uint64(nSpins))
+
+			generated := make(map[string]int)
+			for i := 0; i < nSpins; i++ {
+				var buf bytes.Buffer
+				if err := g.Emit(&buf); err != nil {
+					t.Fatal(err)
+				}
+
+				m := unmarshalJSONT[json.RawMessage](t, buf.Bytes())
+				generated[checkGeoJSONGeometry(t, m["alpha"])] += 1
+			}
+
+			expected := geometryTypes
+			if len(expected) == 0 {
+				expected = defaultGeometryTypes
+			}
+
+			if len(generated) != len(expected) {
+				t.Errorf("Expected geometries of types %v, got %v", expected, generated)
+			}
+
+			for _, geometryType := range expected {
+				if generated[geometryType] == 0 {
+					t.Errorf("Expected %s geometries to be generated", geometryType)
+				}
+			}
+		})
+	}
+}
+
+func Test_FieldPointWithCustomTemplate(t *testing.T) {
+	fld := Field{
+		Name: "alpha",
+		Type: FieldTypePoint,
+	}
+
+	cfg, err := config.LoadConfigFromYaml([]byte("fields:\n  - name: alpha\n    range:\n      min: -5\n      max: 10"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template, _ := generateCustomTemplateFromField(cfg, Fields{fld})
+	t.Logf("with template: %s", string(template))
+
+	nSpins := 1000
+	g := makeGeneratorWithCustomTemplate(t, cfg, Fields{fld}, template, uint64(nSpins))
+
+	for i := 0; i < nSpins; i++ {
+		var buf bytes.Buffer
+		if err := g.Emit(&buf); err != nil {
+			t.Fatal(err)
+		}
+
+		m := unmarshalJSONT[map[string]float64](t, buf.Bytes())
+		point := m["alpha"]
+		if len(point) != 2 {
+			t.Fatalf("Expected a point of x and y, got %s", buf.String())
+		}
+
+		for _, coordinate := range []string{"x", "y"} {
+			if value, ok := point[coordinate]; !ok || value < -5 || value > 10 {
+				t.Errorf("Expected %s between -5 and 10, got %s", coordinate, buf.String())
+			}
+		}
+	}
+}
+
+func Test_FieldNumericAsStringWithCustomTemplate(t *testing.T) {
+	fld := Field{
+		Name:    "alpha",
+		Type:    FieldTypeLong,
+		Example: "1234567890123456789",
+	}
+
+	cfg, err := config.LoadConfigFromYaml([]byte("fields:\n  - name: alpha\n    numeric_as_string: true"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template, _ := generateCustomTemplateFromField(cfg, Fields{fld})
+	t.Logf("with template: %s", string(template))
+
+	nSpins := 100
+	g := makeGeneratorWithCustomTemplate(t, cfg, Fields{fld}, template, uint64(nSpins))
+
+	var beyondDoublePrecision int
+	for i := 0; i < nSpins; i++ {
+		var buf bytes.Buffer
+		if err := g.Emit(&buf); err != nil {
+			t.Fatal(err)
+		}
+
+		// the value is quoted, so that it can be unmarshalled as a string
+		m := unmarshalJSONT[string](t, buf.Bytes())
+		value, err := strconv.ParseInt(m["alpha"], 10, 64)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if strconv.FormatInt(value, 10) != m["alpha"] {
+			t.Errorf("Expected %s to round-trip, got %d", m["alpha"], value)
+		}
+
+		if value > 1<<53 {
+			beyondDoublePrecision += 1
+		}
+	}
+
+	if beyondDoublePrecision == 0 {
+		t.Errorf("Expected values beyond the precision of doubles to be generated")
+	}
+}
+
+func Test_FieldNullProbabilityWithCustomTemplate(t *testing.T) {
+	flds := Fields{
+		{Name: "alpha", Type: FieldTypeLong},
+		{Name: "beta", Type: FieldTypeKeyword},
+		{Name: "gamma", Type: FieldTypeKeyword},
+		{Name: "delta", Type: FieldTypeKeyword},
+	}
+
+	configYaml := `fields:
+  - name: alpha
+    null_probability: 0.5
+  - name: gamma
+    null_probability: 0.5
+    null_mode: "null"
+  - name: delta
+    null_probability: 0.5
+    null_mode: omit
+`
+	cfg, err := config.LoadConfigFromYaml([]byte(configYaml))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template, _ := generateCustomTemplateFromField(cfg, flds)
+	t.Logf("with template: %s", string(template))
+
+	nSpins := 1000
+	g := makeGeneratorWithCustomTemplate(t, cfg, flds, template, uint64(nSpins))
+
+	omitted := make(map[string]int)
+	nulls := 0
+	for i := 0; i < nSpins; i++ {
+		var buf bytes.Buffer
+		if err := g.Emit(&buf); err != nil {
+			t.Fatal(err)
+		}
+
+		// omitted fields must leave a valid document
+		m := unmarshalJSONT[any](t, buf.Bytes())
+		if _, ok := m["beta"]; !ok {
+			t.Errorf("Expected beta to be always emitted, got %s", buf.String())
+		}
+
+		for _, fieldName := range []string{"alpha", "delta"} {
+			if _, ok := m[fieldName]; !ok {
+				omitted[fieldName] += 1
+			}
+		}
+
+		gamma, ok := m["gamma"]
+		if !ok {
+			t.Errorf("Expected gamma to be emitted as null instead of being omitted, got %s", buf.String())
+		}
+
+		if gamma == nil {
+			nulls += 1
+		}
+	}
+
+	for _, fieldName := range []string{"alpha", "delta"} {
+		if omitted[fieldName] < nSpins/4 || omitted[fieldName] > nSpins*3/4 {
+			t.Errorf("Expected %s to be omitted about half of the times, got %d", fieldName, omitted[fieldName])
+		}
+	}
+
+	if nulls < nSpins/4 || nulls > nSpins*3/4 {
+		t.Errorf("Expected gamma to be null about half of the times, got %d", nulls)
+	}
+}
+
+func Test_MultiSeriesWithCustomTemplate(t *testing.T) {
+	flds := Fields{
+		{Name: "timestamp", Type: FieldTypeDate},
+		{Name: "host.name", Type: FieldTypeKeyword},
+		{Name: "metric.name", Type: FieldTypeKeyword},
+		{Name: "metric.value", Type: FieldTypeDouble},
+	}
+
+	configYaml := `fields:
+  - name: timestamp
+    mode: aligned
+    interval: 1m
+    base: "2023-01-01T00:00:00-00:00"
+  - name: host.name
+    dimension: true
+    enum: [alpha, beta, gamma]
+  - name: metric.name
+    dimension: true
+    enum: [cpu, memory]
+  - name: metric.value
+    fuzziness: 0.05
+    range:
+      min: 10
+      max: 1000
+`
+	cfg, err := config.LoadConfigFromYaml([]byte(configYaml))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template, _ := generateCustomTemplateFromField(cfg, flds)
+	t.Logf("with template: %s", string(template))
+
+	nSteps := 50
+	nSeries := 6
+	g := makeGeneratorWithCustomTemplate(t, cfg, flds, template, uint64(nSteps*nSeries))
+
+	series := make(map[string]map[string]int)
+	prevValues := make(map[string]float64)
+	for i := 0; i < nSteps*nSeries; i++ {
+		var buf bytes.Buffer
+		if err := g.Emit(&buf); err != nil {
+			t.Fatal(err)
+		}
+
+		m := unmarshalJSONT[any](t, buf.Bytes())
+		timestamp := m["timestamp"].(string)
+		if _, ok := series[timestamp]; !ok {
+			series[timestamp] = make(map[string]int)
+		}
+
+		key := m["host.name"].(string) + "/" + m["metric.name"].(string)
+		series[timestamp][key] += 1
+
+		// each series has its own trajectory, changing by up to the fuzziness at each step
+		value := m["metric.value"].(float64)
+		if prev, ok := prevValues[key]; ok && math.Abs(value-prev) > prev*0.05+1e-6 {
+			t.Errorf("Expected %s to change by up to 5%% from %f, got %f", key, prev, value)
+		}
+
+		prevValues[key] = value
+	}
+
+	if len(series) != nSteps {
+		t.Errorf("Expected %d timestamps, got %d", nSteps, len(series))
+	}
+
+	// every combination of dimensions is emitted exactly once per timestamp
+	for timestamp, combinations := range series {
+		if len(combinations) != nSeries {
+			t.Errorf("Expected %d series at %s, got %v", nSeries, timestamp, combinations)
+		}
+
+		for key, count := range combinations {
+			if count != 1 {
+				t.Errorf("Expected %s once at %s, got %d", key, timestamp, count)
+			}
+		}
+	}
+}
+
+func Test_FieldTextWithCustomTemplate(t *testing.T) {
+	fld := Field{
+		Name: "message",
+		Type: FieldTypeText,
+	}
+
+	for _, punctuation := range []bool{false, true} {
+		t.Run(strconv.FormatBool(punctuation), func(t *testing.T) {
+			configYaml := "fields:\n  - name: message\n    words:\n      min: 3\n      max: 30\n    punctuation: " + strconv.FormatBool(punctuation)
+			cfg, err := config.LoadConfigFromYaml([]byte(configYaml))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			template, _ := generateCustomTemplateFromField(cfg, Fields{fld})
+			t.Logf("with template: %s", string(template))
+
+			nSpins := 1000
+			g := makeGeneratorWithCustomTemplate(t, cfg, Fields{fld}, template, uint64(nSpins))
+
+			for i := 0; i < nSpins; i++ {
+				var buf bytes.Buffer
+				if err := g.Emit(&buf); err != nil {
+					t.Fatal(err)
+				}
+
+				m := unmarshalJSONT[string](t, buf.Bytes())
+				message := m["message"]
+
+				// words are separated by a single space
+				words := strings.Fields(message)
+				if len(words) < 3 || len(words) > 30 || strings.Join(words, " ") != message {
+					t.Fatalf("Expected between 3 and 30 words separated by a space, got %q", message)
+				}
+
+				if !punctuation {
+					if strings.ContainsAny(message, ".ABCDEFGHIJKLMNOPQRSTUVWXYZ") {
+						t.Errorf("Expected no punctuation, got %q", message)
+					}
+
+					continue
+				}
+
+				for _, sentence := range strings.SplitAfter(message, ". ") {
+					sentence = strings.TrimSpace(sentence)
+					if !strings.HasSuffix(sentence, ".") || sentence[0] < 'A' || sentence[0] > 'Z' {
+						t.Errorf("Expected capitalized sentences ending with a full stop, got %q", message)
+					}
+				}
+			}
+		})
+	}
+}
+
+func Test_FieldWebTransactionWithCustomTemplate(t *testing.T) {
+	fld := Field{
+		Name: "http",
+		Type: FieldTypeWebTransaction,
+	}
+
+	cfg, err := config.LoadConfigFromYaml([]byte("fields:\n  - name: http"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template, _ := generateCustomTemplateFromField(cfg, Fields{fld})
+	t.Logf("with template: %s", string(template))
+
+	nSpins := 5000
+	g := makeGeneratorWithCustomTemplate(t, cfg, Fields{fld}, template, uint64(nSpins))
+
+	statusCodes := make(map[float64]int)
+	for i := 0; i < nSpins; i++ {
+		var buf bytes.Buffer
+		if err := g.Emit(&buf); err != nil {
+			t.Fatal(err)
+		}
+
+		m := unmarshalJSONT[any](t, buf.Bytes())
+		method := m["http.request.method"].(string)
+		requestBytes := m["http.request.bytes"].(float64)
+		statusCode := m["http.response.status_code"].(float64)
+		responseBytes := m["http.response.bytes"].(float64)
+		duration := m["event.duration"].(float64)
+		statusCodes[statusCode] += 1
+
+		if (statusCode == 204 || statusCode == 304 || method == "HEAD") && responseBytes != 0 {
+			t.Errorf("Expected no response bytes for %s with status %v, got %v", method, statusCode, responseBytes)
+		}
+
+		if statusCode == 200 && method != "HEAD" && responseBytes == 0 {
+			t.Errorf("Expected response bytes for %s with status 200", method)
+		}
+
+		// requests without a body are made of the request line and headers only
+		if (method == "GET" || method == "HEAD" || method == "DELETE") && requestBytes > 1500 {
+			t.Errorf("Expected at most 1500 request bytes for %s, got %v", method, requestBytes)
+		}
+
+		if requestBytes <= 0 || duration <= 0 {
+			t.Errorf("Expected positive request bytes and duration, got %s", buf.String())
+		}
+	}
+
+	if statusCodes[204] == 0 {
+		t.Errorf("Expected some 204 responses, got %v", statusCodes)
+	}
+}
+
+func Test_FieldUserWithCustomTemplate(t *testing.T) {
+	fld := Field{
+		Name: "user",
+		Type: FieldTypeUser,
+	}
+
+	cfg, err := config.LoadConfigFromYaml([]byte("fields:\n  - name: user\n    user_pool_size: 20"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template, _ := generateCustomTemplateFromField(cfg, Fields{fld})
+	t.Logf("with template: %s", string(template))
+
+	nSpins := 1000
+	g := makeGeneratorWithCustomTemplate(t, cfg, Fields{fld}, template, uint64(nSpins))
+
+	names := make(map[string]string)
+	groups := make(map[string]string)
+	for i := 0; i < nSpins; i++ {
+		var buf bytes.Buffer
+		if err := g.Emit(&buf); err != nil {
+			t.Fatal(err)
+		}
+
+		m := unmarshalJSONT[string](t, buf.Bytes())
+		id, name, group := m["user.id"], m["user.name"], m["user.group.name"]
+		if len(id) == 0 || len(name) == 0 || len(group) == 0 {
+			t.Fatalf("Expected user id, name and group name, got %s", buf.String())
+		}
+
+		// a recurring user id always has the same name and group
+		if previous, ok := names[id]; ok && previous != name {
+			t.Errorf("Expected user %s to be named %s, got %s", id, previous, name)
+		}
+
+		if previous, ok := groups[id]; ok && previous != group {
+			t.Errorf("Expected user %s to belong to group %s, got %s", id, previous, group)
+		}
+
+		names[id] = name
+		groups[id] = group
+	}
+
+	if len(names) != 20 {
+		t.Errorf("Expected 20 distinct users, got %d", len(names))
+	}
+}
+
+func Test_FieldEventCategorizationWithCustomTemplate(t *testing.T) {
+	fld := Field{
+		Name: "event",
+		Type: FieldTypeEventCategorization,
+	}
+
+	cfg, err := config.LoadConfigFromYaml([]byte("fields:\n  - name: event\n    enum: [\"network\", \"iam\", \"web\"]"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template, _ := generateCustomTemplateFromField(cfg, Fields{fld})
+	t.Logf("with template: %s", string(template))
+
+	nSpins := 1000
+	g := makeGeneratorWithCustomTemplate(t, cfg, Fields{fld}, template, uint64(nSpins))
+
+	categories := make(map[string]int)
+	for i := 0; i < nSpins; i++ {
+		var buf bytes.Buffer
+		if err := g.Emit(&buf); err != nil {
+			t.Fatal(err)
+		}
+
+		m := unmarshalJSONT[string](t, buf.Bytes())
+		category, eventType, action := m["event.category"], m["event.type"], m["event.action"]
+		categories[category] += 1
+
+		allowed := false
+		for _, allowedType := range ecsEventTypes[category] {
+			allowed = allowed || allowedType == eventType
+		}
+
+		if !allowed {
+			t.Errorf("Expected an event type allowed with %s, got %s", category, eventType)
+		}
+
+		found := false
+		for _, allowedAction := range ecsEventActions[category][eventType] {
+			found = found || allowedAction == action
+		}
+
+		if !found {
+			t.Errorf("Expected an action for %s/%s, got %s", category, eventType, action)
+		}
+	}
+
+	for _, category := range []string{"network", "iam", "web"} {
+		if categories[category] == 0 {
+			t.Errorf("Expected %s category to be generated", category)
+		}
+	}
+
+	if len(categories) != 3 {
+		t.Errorf("Expected only categories in enum, got %v", categories)
+	}
+}
+
+func Test_FieldTLSWithCustomTemplate(t *testing.T) {
+	fld := Field{
+		Name: "tls",
+		Type: FieldTypeTLS,
+	}
+
+	cfg, err := config.LoadConfigFromYaml([]byte("fields:\n  - name: tls\n    enum: [\"1.0\", \"1.1\", \"1.2\", \"1.3\"]\n    validity: 720h"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template, _ := generateCustomTemplateFromField(cfg, Fields{fld})
+	t.Logf("with template: %s", string(template))
+
+	nSpins := 1000
+	g := makeGeneratorWithCustomTemplate(t, cfg, Fields{fld}, template, uint64(nSpins))
+
+	versions := make(map[string]int)
+	for i := 0; i < nSpins; i++ {
+		var buf bytes.Buffer
+		if err := g.Emit(&buf); err != nil {
+			t.Fatal(err)
+		}
+
+		m := unmarshalJSONT[string](t, buf.Bytes())
+		version := m["tls.version"]
+		versions[version] += 1
+
+		if m["tls.version_protocol"] != "tls" {
+			t.Errorf("Expected tls version protocol, got %s", m["tls.version_protocol"])
+		}
+
+		// TLS 1.3 cipher suites don't name the key exchange, older ones do and can't be negotiated with TLS 1.3
+		cipher := m["tls.cipher"]
+		if !strings.HasPrefix(cipher, "TLS_") || (version == "1.3") == strings.Contains(cipher, "_WITH_") {
+			t.Errorf("Expected cipher valid for TLS %s, got %s", version, cipher)
+		}
+
+		if len(m["tls.server.x509.subject.common_name"]) == 0 {
+			t.Errorf("Expected server certificate common name, got %s", buf.String())
+		}
+
+		notBefore, err := time.Parse(FieldTypeTimeLayout, m["tls.server.x509.not_before"])
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		notAfter, err := time.Parse(FieldTypeTimeLayout, m["tls.server.x509.not_after"])
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if notAfter.Sub(notBefore) != 720*time.Hour {
+			t.Errorf("Expected not_after 720h after not_before, got %s and %s", notBefore, notAfter)
+		}
+	}
+
+	for _, version := range []string{"1.0", "1.1", "1.2", "1.3"} {
+		if versions[version] == 0 {
+			t.Errorf("Expected TLS %s to be generated", version)
+		}
+	}
+}
+
+func Test_FieldScaledFloatWithCustomTemplate(t *testing.T) {
+	fld := Field{
+		Name:          "alpha",
+		Type:          FieldTypeScaledFloat,
+		ScalingFactor: 100,
+	}
+
+	cfg, err := config.LoadConfigFromYaml([]byte("fields:\n  - name: alpha\n    range:\n      min: 0\n      max: 100"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template, _ := generateCustomTemplateFromField(cfg, Fields{fld})
+	t.Logf("with template: %s", string(template))
+
+	nSpins := 1000
+	g := makeGeneratorWithCustomTemplate(t, cfg, Fields{fld}, template, uint64(nSpins))
+
+	for i := 0; i < nSpins; i++ {
+		var buf bytes.Buffer
+		if err := g.Emit(&buf); err != nil {
+			t.Fatal(err)
+		}
+
+		m := unmarshalJSONT[json.RawMessage](t, buf.Bytes())
+		value := string(m[fld.Name])
+
+		// a scaling factor of 100 stores the value with 2 decimals
+		if i := strings.IndexByte(value, '.'); i >= 0 && len(value)-i-1 > 2 {
+			t.Errorf("Expected at most 2 decimals, got %s", value)
+		}
+
+		v, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if v < 0 || v > 100 {
+			t.Errorf("Expected value between 0 and 100, got %v", v)
+		}
+	}
+}
+
+func Test_FieldArrayWithCustomTemplate(t *testing.T) {
+	flds := Fields{
+		{Name: "alpha", Type: FieldTypeLong},
+		{Name: "beta", Type: FieldTypeDate},
+		{Name: "gamma", Type: FieldTypeIP},
+		{Name: "delta", Type: FieldTypeKeyword},
+	}
+
+	cfg, err := config.LoadConfigFromYaml([]byte("fields:\n  - name: alpha\n    array:\n      min: 2\n      max: 2\n    range:\n      min: 1\n      max: 10\n  - name: beta\n    array:\n      min: 1\n      max: 3\n  - name: gamma\n    array:\n      min: 1\n      max: 1\n  - name: delta\n    cardinality: 3\n    array:\n      min: 0\n      max: 3"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template, _ := generateCustomTemplateFromField(cfg, flds)
+	t.Logf("with template: %s", string(template))
+
+	nSpins := 1000
+	g := makeGeneratorWithCustomTemplate(t, cfg, flds, template, uint64(nSpins))
+
+	var empty int
+	deltas := make(map[string]struct{})
+	for i := 0; i < nSpins; i++ {
+		var buf bytes.Buffer
+		if err := g.Emit(&buf); err != nil {
+			t.Fatal(err)
+		}
+
+		// the document is valid JSON also when the last field is an empty array
+		m := unmarshalJSONT[[]any](t, buf.Bytes())
+
+		if len(m["alpha"]) != 2 {
+			t.Errorf("Expected 2 alpha elements, got %v", m["alpha"])
+		}
+
+		for _, alpha := range m["alpha"] {
+			if v, ok := alpha.(float64); !ok || v < 1 || v > 10 {
+				t.Errorf("Expected alpha elements between 1 and 10, got %v", alpha)
+			}
+		}
+
+		if len(m["beta"]) < 1 || len(m["beta"]) > 3 {
+			t.Errorf("Expected between 1 and 3 beta elements, got %v", m["beta"])
+		}
+
+		for _, beta := range m["beta"] {
+			if _, err := time.Parse(time.RFC3339Nano, beta.(string)); err != nil {
+				t.Errorf("Expected beta elements to be dates, got %v", beta)
+			}
+		}
+
+		if len(m["gamma"]) != 1 || net.ParseIP(m["gamma"][0].(string)) == nil {
+			t.Errorf("Expected a single gamma IP, got %v", m["gamma"])
+		}
+
+		if len(m["delta"]) > 3 {
+			t.Errorf("Expected at most 3 delta elements, got %v", m["delta"])
+		}
+
+		if len(m["delta"]) == 0 {
+			empty += 1
+		}
+
+		// the cardinality applies to each element, so that the elements of a document are distinct
+		elements := make(map[string]struct{})
+		for _, delta := range m["delta"] {
+			deltas[delta.(string)] = struct{}{}
+			elements[delta.(string)] = struct{}{}
+		}
+
+		if len(elements) != len(m["delta"]) {
+			t.Errorf("Expected distinct delta elements, got %v", m["delta"])
+		}
+	}
+
+	if empty == 0 {
+		t.Errorf("Expected empty delta arrays")
+	}
+
+	if len(deltas) != 3 {
+		t.Errorf("Expected 3 distinct delta values, got %d", len(deltas))
+	}
+}
+
+func Test_FieldFloatsWithCustomTemplate(t *testing.T) {
+	_testNumericWithCustomTemplate[float64](t, FieldTypeDouble)
+	_testNumericWithCustomTemplate[float32](t, FieldTypeFloat)
+	_testNumericWithCustomTemplate[float32](t, FieldTypeHalfFloat)
+	_testNumericWithCustomTemplate[float64](t, FieldTypeScaledFloat)
+
+}
+
+func Test_FieldIntegersWithCustomTemplate(t *testing.T) {
+	_testNumericWithCustomTemplate[int](t, FieldTypeInteger)
+	_testNumericWithCustomTemplate[int64](t, FieldTypeLong)
+	_testNumericWithCustomTemplate[uint64](t, FieldTypeUnsignedLong)
+}
+
+func Test_FieldUnsignedLongAboveInt64WithCustomTemplate(t *testing.T) {
+	testCases := []struct {
+		scenario string
+		config   string
+		min      uint64
+		max      uint64
+	}{
+		{
+			scenario: "up to max uint64",
+			config:   "fields:\n  - name: alpha\n    range:\n      min: 0\n      max: 18446744073709551615",
+			min:      0,
+			max:      math.MaxUint64,
+		},
+		{
+			scenario: "above max int64",
+			config:   "fields:\n  - name: alpha\n    range:\n      min: 9223372036854775808\n      max: 18446744073709551615",
+			min:      math.MaxInt64 + 1,
+			max:      math.MaxUint64,
+		},
+		{
+			scenario: "above max int64 with fuzziness",
+			config:   "fields:\n  - name: alpha\n    fuzziness: 0.1\n    range:\n      min: 9223372036854775808\n      max: 18446744073709551615",
+			min:      math.MaxInt64 + 1,
+			max:      math.MaxUint64,
+		},
+	}
+
+	fld := Field{
+		Name: "alpha",
+		Type: FieldTypeUnsignedLong,
+	}
+
+	template := []byte(`{"alpha":{{.alpha}}}`)
+	t.Logf("with template: %s", string(template))
+
+	for _, testCase := range testCases {
+		t.Run(testCase.scenario, func(t *testing.T) {
+			cfg, err := config.LoadConfigFromYaml([]byte(testCase.config))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			nSpins := 1024
+			g := makeGeneratorWithCustomTemplate(t, cfg, Fields{fld}, template, uint64(nSpins))
+
+			var aboveInt64 int
+			for i := 0; i < nSpins; i++ {
+				var buf bytes.Buffer
+				if err := g.Emit(&buf); err != nil {
+					t.Fatal(err)
+				}
+
+				// the values are unquoted JSON numbers, that wouldn't fit in an int64
+				m := unmarshalJSONT[uint64](t, buf.Bytes())
+				if m[fld.Name] < testCase.min || m[fld.Name] > testCase.max {
+					t.Fatalf("Expected a value between %d and %d, got %s", testCase.min, testCase.max, buf.String())
+				}
+
+				if m[fld.Name] > math.MaxInt64 {
+					aboveInt64++
+				}
+			}
+
+			if aboveInt64 == 0 {
+				t.Errorf("Expected values above max int64")
+			}
+		})
+	}
+}
+
+func Test_FieldIntegerTypesBoundsWithCustomTemplate(t *testing.T) {
+	testCases := []struct {
+		scenario  string
+		fieldType string
+		config    string
+		min       int64
+		max       int64
+	}{
+		{
+			scenario:  "byte",
+			fieldType: FieldTypeByte,
+			config:    "fields:\n  - name: alpha",
+			min:       0,
+			max:       math.MaxInt8,
+		},
+		{
+			scenario:  "short",
+			fieldType: FieldTypeShort,
+			config:    "fields:\n  - name: alpha",
+			min:       0,
+			max:       math.MaxInt16,
+		},
+		{
+			scenario:  "integer",
+			fieldType: FieldTypeInteger,
+			config:    "fields:\n  - name: alpha",
+			min:       0,
+			max:       math.MaxInt32,
+		},
+		{
+			scenario:  "byte with fuzziness",
+			fieldType: FieldTypeByte,
+			config:    "fields:\n  - name: alpha\n    fuzziness: 0.5",
+			min:       0,
+			max:       math.MaxInt8,
+		},
+		{
+			scenario:  "short with range",
+			fieldType: FieldTypeShort,
+			config:    "fields:\n  - name: alpha\n    range:\n      min: 40000\n      max: 50000",
+			min:       40000,
+			max:       50000,
+		},
+	}
+
+	template := []byte(`{"alpha":{{.alpha}}}`)
+	t.Logf("with template: %s", string(template))
+
+	for _, testCase := range testCases {
+		t.Run(testCase.scenario, func(t *testing.T) {
+			fld := Field{
+				Name: "alpha",
+				Type: testCase.fieldType,
+				// an example with more digits than the type can hold
+				Example: "12345678901234",
+			}
+
+			cfg, err := config.LoadConfigFromYaml([]byte(testCase.config))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			nSpins := 1024
+			g := makeGeneratorWithCustomTemplate(t, cfg, Fields{fld}, template, uint64(nSpins))
+
+			for i := 0; i < nSpins; i++ {
+				var buf bytes.Buffer
+				if err := g.Emit(&buf); err != nil {
+					t.Fatal(err)
+				}
+
+				m := unmarshalJSONT[int64](t, buf.Bytes())
+				if m[fld.Name] < testCase.min || m[fld.Name] > testCase.max {
+					t.Fatalf("Expected a value between %d and %d, got %s", testCase.min, testCase.max, buf.String())
+				}
+			}
+		})
+	}
+}
+
+func _testNumericWithCustomTemplate[T any](t *testing.T, ty string) {
+	fld := Field{
+		Name: "alpha",
+		Type: ty,
+	}
+
+	template := []byte(`{"alpha":{{.alpha}}}`)
+	t.Logf("with template: %s", string(template))
+	nSpins := rand.Intn(1024) + 1
+	for i := 0; i < nSpins; i++ {
+		testSingleTWithCustomTemplate[T](t, fld, nil, template)
+	}
+}
+
+func testSingleTWithCustomTemplate[T any](t *testing.T, fld Field, yaml []byte, template []byte) T {
+	var err error
+	var cfg Config
+
+	if yaml != nil {
+		cfg, err = config.LoadConfigFromYaml(yaml)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	g := makeGeneratorWithCustomTemplate(t, cfg, []Field{fld}, template, 0)
+
+	var buf bytes.Buffer
+
+	if err := g.Emit(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	// BufferWithMutex should now contain an event shaped like {"alpha": "constant_keyword"}
+	m := unmarshalJSONT[T](t, buf.Bytes())
+
+	if len(m) != 1 {
+		t.Errorf("Expected map size 1, got %d", len(m))
+	}
+
+	v, ok := m[fld.Name]
+
+	if !ok {
+		t.Errorf("Missing key %v", fld.Name)
+
+	}
+
+	return v
+}
+
+func makeGeneratorWithCustomTemplate(t *testing.T, cfg Config, fields Fields, template []byte, totEvents uint64) Generator {
+	g, err := NewGeneratorWithCustomTemplate(template, cfg, fields, totEvents)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return g
+}
+
+func Test_FieldMoneyUnknownCurrencyWithCustomTemplate(t *testing.T) {
+	fld := Field{
+		Name: "price",
+		Type: FieldTypeMoney,
+	}
+
+	cfg, err := config.LoadConfigFromYaml([]byte("fields:\n  - name: price\n    enum: [XYZ]"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template, _ := generateCustomTemplateFromField(cfg, Fields{fld})
+	if _, err := NewGeneratorWithCustomTemplate(template, cfg, Fields{fld}, 1); err == nil {
+		t.Errorf("Expected error for unknown currency code")
+	}
+}
+
+func Test_FieldCIDRInvalidConfigWithCustomTemplate(t *testing.T) {
+	fld := Field{
+		Name: "alpha",
+		Type: FieldTypeCIDR,
+	}
+
+	for _, configYaml := range []string{
+		"fields:\n  - name: alpha\n    ip_version: v5",
+		"fields:\n  - name: alpha\n    range:\n      min: 8\n      max: 33",
+		"fields:\n  - name: alpha\n    range:\n      min: 24\n      max: 16",
+	} {
+		cfg, err := config.LoadConfigFromYaml([]byte(configYaml))
+		if errors.Is(err, config.ErrRangeMinMaxInvalidConfig) {
+			// a range with min greater than max is already rejected when loading the config
+			continue
+		}
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := NewGeneratorWithCustomTemplate([]byte(`{"alpha":"{{.alpha}}"}`), cfg, Fields{fld}, 1); err == nil {
+			t.Errorf("Expected error for config %s", configYaml)
+		}
+	}
 }
@@ -9,7 +9,6 @@ import (
 	"errors"
 	"github.com/Masterminds/sprig/v3"
 	"io"
-	"math/rand"
 	"text/template"
 )
 
@@ -17,10 +16,11 @@ var generateOnFieldNotInFieldsYaml = errors.New("generate called on a field not
 
 // GeneratorWithTextTemplate
 type GeneratorWithTextTemplate struct {
-	tpl       *template.Template
-	state     *genState
-	errChan   chan error
-	totEvents uint64
+	tpl           *template.Template
+	state         *genState
+	errChan       chan error
+	totEvents     uint64
+	trimSeparator bool
 }
 
 // awsAZs list all possible AZs for a specific AWS region
@@ -49,18 +49,73 @@ var awsAZs map[string][]string = map[string][]string{
 }
 
 func NewGeneratorWithTextTemplate(tpl []byte, cfg Config, fields Fields, totEvents uint64) (*GeneratorWithTextTemplate, error) {
+	initGeneratorTimeNowIfUnset()
+
 	// Preprocess the fields, generating appropriate bound function
 	state := newGenState()
+	// the fields are bound with the random generator of the state, the one they are generated with
+	defer useRand(state.rand)()
+
 	fieldMap := make(map[string]any)
 	for _, field := range fields {
 		if err := bindField(cfg, field, fieldMap, true); err != nil {
 			return nil, err
 		}
 
+		if err := bindKeywordMultiField(cfg, field, fieldMap, true); err != nil {
+			return nil, err
+		}
+
 		state.prevCacheForDup[field.Name] = make(map[any]struct{})
 		state.prevCacheCardinality[field.Name] = make([]any, 0)
 	}
 
+	if err := bindSeries(cfg, fields, fieldMap, true); err != nil {
+		return nil, err
+	}
+
+	state.seriesLength = seriesLength(cfg, fields)
+
+	if err := bindEnumByValue(cfg, fields, fieldMap, true); err != nil {
+		return nil, err
+	}
+
+	if err := bindDerivedLag(cfg, fields, fieldMap, true); err != nil {
+		return nil, err
+	}
+
+	if err := bindDerivedDuration(cfg, fields, fieldMap, true); err != nil {
+		return nil, err
+	}
+
+	if err := bindDerivedRatio(cfg, fields, fieldMap, true); err != nil {
+		return nil, err
+	}
+
+	if err := bindDerivedDirection(cfg, fields, fieldMap, true); err != nil {
+		return nil, err
+	}
+
+	if err := bindValueFromTemplate(cfg, fields, fieldMap, true); err != nil {
+		return nil, err
+	}
+
+	if err := bindRelatedFields(cfg, fields, fieldMap, true); err != nil {
+		return nil, err
+	}
+
+	if err := bindEntityAttributes(cfg, fields, fieldMap, true); err != nil {
+		return nil, err
+	}
+
+	if err := bindFirstOnly(cfg, fields, fieldMap, true); err != nil {
+		return nil, err
+	}
+
+	if err := bindNullProbability(cfg, fields, fieldMap, true); err != nil {
+		return nil, err
+	}
+
 	errChan := make(chan error)
 
 	templateFns := sprig.TxtFuncMap()
@@ -71,17 +126,31 @@ func NewGeneratorWithTextTemplate(tpl []byte, cfg Config, fields Fields, totEven
 			return "NoAZ"
 		}
 
-		return azs[rand.Intn(len(azs))]
+		return azs[state.rand.Intn(len(azs))]
+	}
+
+	fieldsByName := make(map[string]Field, len(fields))
+	for _, field := range fields {
+		fieldsByName[field.Name] = field
+	}
+
+	templateFns["formatDouble"] = func(field string, value any) any {
+		return formatDouble(cfg, fieldsByName[field], value)
 	}
 
-	templateFns["generate"] = func(field string) any {
+	templateFns["generate"] = func(field string) (any, error) {
 		bindF, ok := fieldMap[field].(emitF)
 		if !ok {
 			close(errChan)
-			return nil
+			return nil, nil
+		}
+
+		value := bindF(state)
+		if err, ok := value.(error); ok {
+			return nil, err
 		}
 
-		return bindF(state)
+		return value, nil
 	}
 
 	t := template.New("generator")
@@ -94,7 +163,7 @@ func NewGeneratorWithTextTemplate(tpl []byte, cfg Config, fields Fields, totEven
 
 	state.totEvents = totEvents
 
-	return &GeneratorWithTextTemplate{tpl: parsedTpl, totEvents: totEvents, state: state, errChan: errChan}, nil
+	return &GeneratorWithTextTemplate{tpl: parsedTpl, totEvents: totEvents, state: state, errChan: errChan, trimSeparator: hasNullableFields(cfg, fields)}, nil
 }
 
 func (gen *GeneratorWithTextTemplate) Close() error {
@@ -102,6 +171,7 @@ func (gen *GeneratorWithTextTemplate) Close() error {
 }
 
 func (gen *GeneratorWithTextTemplate) Emit(buf *bytes.Buffer) error {
+	gen.state.useRandomdata()
 	if err := gen.emit(buf); err != nil {
 		return err
 	}
@@ -116,10 +186,15 @@ func (gen *GeneratorWithTextTemplate) emit(buf *bytes.Buffer) error {
 		case <-gen.errChan:
 			return generateOnFieldNotInFieldsYaml
 		default:
+			start := buf.Len()
 			err := gen.tpl.Execute(buf, nil)
 			if err != nil {
 				return err
 			}
+
+			if gen.trimSeparator {
+				trimDanglingSeparator(buf, start)
+			}
 		}
 	} else {
 		return io.EOF
@@ -0,0 +1,115 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package genlib
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+
+	"github.com/elastic/elastic-integration-corpus-generator-tool/pkg/genlib/config"
+)
+
+// geohashAlphabet is the base32 alphabet of geohashes
+const geohashAlphabet = "0123456789bcdefghjkmnpqrstuvwxyz"
+
+// geohashMaxPrecision is the default and maximum `geohash_precision`, as in Elasticsearch
+const geohashMaxPrecision = 12
+
+// makeGeoBoundedFunc returns a function generating coordinates uniformly within the `geo_bounds` of the field
+func makeGeoBoundedFunc(bounds *config.GeoBounds) func(r *rand.Rand) (float64, float64) {
+	top, left := *bounds.TopLeft.Lat, *bounds.TopLeft.Lon
+	bottom, right := *bounds.BottomRight.Lat, *bounds.BottomRight.Lon
+
+	return func(r *rand.Rand) (float64, float64) {
+		lat := bottom + r.Float64()*(top-bottom)
+		lon := left + r.Float64()*(right-left)
+		return lat, lon
+	}
+}
+
+// makeGeoPointFunc returns a function generating geo points in the format set by `geo_format`:
+// `lat,lon` by default, or a geohash of `geohash_precision` characters with `geohash`.
+// With `geo_bounds` the points are generated within the bounding box.
+func makeGeoPointFunc(fieldCfg ConfigField) (func(r *rand.Rand) string, error) {
+	switch fieldCfg.GeoFormat {
+	case "":
+		if fieldCfg.GeohashPrecision != 0 {
+			return nil, fmt.Errorf("geohash_precision requires geo_format geohash")
+		}
+
+		if fieldCfg.GeoBounds != nil {
+			boundedFunc := makeGeoBoundedFunc(fieldCfg.GeoBounds)
+			return func(r *rand.Rand) string {
+				lat, lon := boundedFunc(r)
+				return strconv.FormatFloat(lat, 'f', 6, 64) + "," + strconv.FormatFloat(lon, 'f', 6, 64)
+			}, nil
+		}
+
+		return func(r *rand.Rand) string {
+			lat, latD, long, longD := randGeoPoint(r)
+			return fmt.Sprintf("%d.%d,%d.%d", lat, latD, long, longD)
+		}, nil
+	case config.GeoFormatGeohash:
+		precision := fieldCfg.GeohashPrecision
+		if precision == 0 {
+			precision = geohashMaxPrecision
+		}
+
+		if precision < 1 || precision > geohashMaxPrecision {
+			return nil, fmt.Errorf("geohash_precision must be between 1 and %d, got %d", geohashMaxPrecision, precision)
+		}
+
+		if fieldCfg.GeoBounds != nil {
+			boundedFunc := makeGeoBoundedFunc(fieldCfg.GeoBounds)
+			return func(r *rand.Rand) string {
+				lat, lon := boundedFunc(r)
+				return encodeGeohash(lat, lon, precision)
+			}, nil
+		}
+
+		return func(r *rand.Rand) string {
+			lat := r.Float64()*180 - 90
+			lon := r.Float64()*360 - 180
+			return encodeGeohash(lat, lon, precision)
+		}, nil
+	default:
+		return nil, fmt.Errorf("invalid geo_format: %s", fieldCfg.GeoFormat)
+	}
+}
+
+// encodeGeohash returns the geohash of precision characters of the cell the coordinate falls in: each character
+// encodes 5 bits, alternately halving the longitude and latitude intervals, starting from the longitude
+func encodeGeohash(lat, lon float64, precision int) string {
+	latInterval := [2]float64{-90, 90}
+	lonInterval := [2]float64{-180, 180}
+
+	geohash := make([]byte, 0, precision)
+	isLon := true
+	var ch, bit int
+	for len(geohash) < precision {
+		interval, value := &latInterval, lat
+		if isLon {
+			interval, value = &lonInterval, lon
+		}
+
+		mid := (interval[0] + interval[1]) / 2
+		ch <<= 1
+		if value >= mid {
+			ch |= 1
+			interval[0] = mid
+		} else {
+			interval[1] = mid
+		}
+
+		isLon = !isLon
+		if bit++; bit == 5 {
+			geohash = append(geohash, geohashAlphabet[ch])
+			ch, bit = 0, 0
+		}
+	}
+
+	return string(geohash)
+}
@@ -0,0 +1,142 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package genlib
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"math/rand"
+	"strconv"
+)
+
+// GeoJSON geometry types generated for `geo_shape` fields
+const (
+	geometryTypePoint      = "Point"
+	geometryTypeLineString = "LineString"
+	geometryTypePolygon    = "Polygon"
+)
+
+// defaultGeometryTypes are the geometry types chosen from when no `geometry_types` is set
+var defaultGeometryTypes = []string{geometryTypePoint, geometryTypeLineString, geometryTypePolygon}
+
+// geoShapeMaxRadius is the max distance, in degrees, of the vertices of line strings and polygons from their center:
+// centers are kept far enough from the poles and the antimeridian that shapes never cross them
+const geoShapeMaxRadius = 5
+
+// geoShapeMaxVertices is the max number of distinct vertices of line strings and polygons
+const geoShapeMaxVertices = 8
+
+// defaultPointRange is the range of the coordinates of `point` fields when no `range` is set
+const defaultPointRange = 1000
+
+func geometryTypes(fieldCfg ConfigField) ([]string, error) {
+	if len(fieldCfg.GeometryTypes) == 0 {
+		return defaultGeometryTypes, nil
+	}
+
+	for _, geometryType := range fieldCfg.GeometryTypes {
+		switch geometryType {
+		case geometryTypePoint, geometryTypeLineString, geometryTypePolygon:
+		default:
+			return nil, fmt.Errorf("unknown geometry type: %s, must be one of %s, %s or %s", geometryType, geometryTypePoint, geometryTypeLineString, geometryTypePolygon)
+		}
+	}
+
+	return fieldCfg.GeometryTypes, nil
+}
+
+// makeGeoShapeFunc returns a function generating GeoJSON geometries of one of the `geometry_types`.
+// Polygons are convex, with their vertices in counterclockwise order, so that they are never self-intersecting.
+func makeGeoShapeFunc(fieldCfg ConfigField) (func(r *rand.Rand) string, error) {
+	types, err := geometryTypes(fieldCfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(r *rand.Rand) string {
+		buf := bytes.NewBufferString(`{"type":"`)
+		geometryType := types[r.Intn(len(types))]
+		buf.WriteString(geometryType)
+		buf.WriteString(`","coordinates":`)
+
+		if geometryType == geometryTypePoint {
+			writeGeoJSONPosition(buf, r.Float64()*360-180, r.Float64()*180-90)
+			buf.WriteByte('}')
+			return buf.String()
+		}
+
+		lon := r.Float64()*(360-4*geoShapeMaxRadius) - 180 + 2*geoShapeMaxRadius
+		lat := r.Float64()*(180-4*geoShapeMaxRadius) - 90 + 2*geoShapeMaxRadius
+		radius := r.Float64()*(geoShapeMaxRadius-0.1) + 0.1
+		vertices := r.Intn(geoShapeMaxVertices-2) + 3
+
+		// the vertices are at increasing angles around the center, so that consecutive vertices are distinct
+		positions := make([][2]float64, 0, vertices+1)
+		for i := 0; i < vertices; i++ {
+			angle := 2 * math.Pi * (float64(i) + r.Float64()*0.5) / float64(vertices)
+			positions = append(positions, [2]float64{lon + radius*math.Cos(angle), lat + radius*math.Sin(angle)})
+		}
+
+		if geometryType == geometryTypeLineString {
+			writeGeoJSONPositions(buf, positions)
+			buf.WriteByte('}')
+			return buf.String()
+		}
+
+		// the linear ring of a polygon is closed: its last position is the first one
+		positions = append(positions, positions[0])
+		buf.WriteByte('[')
+		writeGeoJSONPositions(buf, positions)
+		buf.WriteString("]}")
+		return buf.String()
+	}, nil
+}
+
+func writeGeoJSONPositions(buf *bytes.Buffer, positions [][2]float64) {
+	buf.WriteByte('[')
+	for i, position := range positions {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+
+		writeGeoJSONPosition(buf, position[0], position[1])
+	}
+
+	buf.WriteByte(']')
+}
+
+// writeGeoJSONPosition writes a GeoJSON position, longitude first
+func writeGeoJSONPosition(buf *bytes.Buffer, lon, lat float64) {
+	buf.WriteByte('[')
+	buf.WriteString(strconv.FormatFloat(lon, 'f', 6, 64))
+	buf.WriteByte(',')
+	buf.WriteString(strconv.FormatFloat(lat, 'f', 6, 64))
+	buf.WriteByte(']')
+}
+
+// makePointFunc returns a function generating cartesian `{"x": x, "y": y}` points, with both coordinates in the
+// `range` of the field, defaulting to between -1000 and 1000
+func makePointFunc(fieldCfg ConfigField) (func(r *rand.Rand) string, error) {
+	min, err := fieldCfg.Range.MinAsFloat64()
+	if err != nil {
+		min = -defaultPointRange
+	}
+
+	max, err := fieldCfg.Range.MaxAsFloat64()
+	if err != nil {
+		max = defaultPointRange
+	}
+
+	if min > max {
+		return nil, fmt.Errorf("range min %v greater than max %v", min, max)
+	}
+
+	return func(r *rand.Rand) string {
+		x := min + r.Float64()*(max-min)
+		y := min + r.Float64()*(max-min)
+		return `{"x":` + strconv.FormatFloat(x, 'f', 6, 64) + `,"y":` + strconv.FormatFloat(y, 'f', 6, 64) + `}`
+	}, nil
+}
@@ -0,0 +1,195 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package genlib
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/rand"
+)
+
+const (
+	// crockfordBase32Alphabet is the Crockford's base32 alphabet ULIDs are encoded with
+	crockfordBase32Alphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+	// ulidLength is the length of an encoded ULID: 128 bits in 26 characters of 5 bits
+	ulidLength = 26
+	// defaultHexTokenLength is the length of a `hex_token` field when `length` is not set
+	defaultHexTokenLength = 32
+	// defaultUUIDVersion is the version of the UUIDs of a `uuid` field when `uuid_version` is not set
+	defaultUUIDVersion = 4
+)
+
+// ulid is the last ULID generated for a `ulid` field: 48 bits of milliseconds timestamp and 80 bits of entropy
+type ulid [16]byte
+
+// next returns the ULID following u for ms: a new random entropy drawn from r when ms is after the timestamp of u,
+// otherwise the entropy of u incremented by one, so that ULIDs generated in the same millisecond still sort in
+// generation order
+func (u ulid) next(r *rand.Rand, ms uint64) ulid {
+	prevMS := uint64(u[0])<<40 | uint64(u[1])<<32 | uint64(u[2])<<24 | uint64(u[3])<<16 | uint64(u[4])<<8 | uint64(u[5])
+	if ms > prevMS {
+		var n ulid
+		for i := 0; i < 6; i++ {
+			n[i] = byte(ms >> (40 - 8*i))
+		}
+
+		_, _ = r.Read(n[6:])
+		return n
+	}
+
+	n := u
+	for i := len(n) - 1; i >= 0; i-- {
+		// on entropy overflow the carry moves the timestamp to the next millisecond
+		n[i] += 1
+		if n[i] != 0 {
+			break
+		}
+	}
+
+	return n
+}
+
+func (u ulid) String() string {
+	// the 128 bits are encoded from the most significant, left padded with 2 zero bits to 130
+	encoded := make([]byte, ulidLength)
+	for i := range encoded {
+		var index byte
+		for bit := i*5 - 2; bit < i*5+3; bit++ {
+			index <<= 1
+			if bit >= 0 && u[bit/8]&(0x80>>(bit%8)) != 0 {
+				index |= 1
+			}
+		}
+
+		encoded[i] = crockfordBase32Alphabet[index]
+	}
+
+	return string(encoded)
+}
+
+// ulidForEvent returns the ULID of a `ulid` field for the current event: ULIDs are time ordered,
+// starting from the `now` the generator is initialised with, and sort in generation order.
+func ulidForEvent(fieldName string, state *genState) string {
+	if value, ok := state.eventValue(fieldName); ok {
+		return value.(string)
+	}
+
+	prev, _ := state.prevCache[fieldName].(ulid)
+	u := prev.next(state.rand, uint64(timeNowToBind.UnixMilli()))
+	state.prevCache[fieldName] = u
+
+	value := u.String()
+	state.setEventValue(fieldName, value)
+
+	return value
+}
+
+func hexTokenLength(fieldCfg ConfigField, field Field) (int, error) {
+	if fieldCfg.Length < 0 {
+		return 0, fmt.Errorf("field %s has a negative length", field.Name)
+	}
+
+	if fieldCfg.Length == 0 {
+		return defaultHexTokenLength, nil
+	}
+
+	return fieldCfg.Length, nil
+}
+
+func uuidVersion(fieldCfg ConfigField, field Field) (int, error) {
+	switch fieldCfg.UUIDVersion {
+	case 0:
+		return defaultUUIDVersion, nil
+	case 4, 7:
+		return fieldCfg.UUIDVersion, nil
+	default:
+		return 0, fmt.Errorf("field %s has a uuid_version not 4 nor 7", field.Name)
+	}
+}
+
+// uuidV7 is the last UUIDv7 generated for a `uuid` field: 48 bits of milliseconds timestamp, and 12 bits of rand_a
+// and 62 bits of rand_b used as a counter within the same millisecond
+type uuidV7 struct {
+	ms    uint64
+	randA uint64
+	randB uint64
+}
+
+// next returns the UUIDv7 following u for ms: new random bits drawn from r when ms is after the timestamp of u,
+// otherwise the random bits of u incremented by one, so that UUIDs generated in the same millisecond still sort in
+// generation order
+func (u uuidV7) next(r *rand.Rand, ms uint64) uuidV7 {
+	if ms > u.ms {
+		return uuidV7{ms: ms, randA: uint64(r.Intn(1 << 12)), randB: r.Uint64() >> 2}
+	}
+
+	n := u
+	n.randB = (n.randB + 1) & (1<<62 - 1)
+	if n.randB == 0 {
+		// on overflow the carry moves to rand_a, and then to the timestamp
+		n.randA = (n.randA + 1) & (1<<12 - 1)
+		if n.randA == 0 {
+			n.ms += 1
+		}
+	}
+
+	return n
+}
+
+func (u uuidV7) bytes() [16]byte {
+	var b [16]byte
+	for i := 0; i < 6; i++ {
+		b[i] = byte(u.ms >> (40 - 8*i))
+	}
+
+	b[6] = 0x70 | byte(u.randA>>8)
+	b[7] = byte(u.randA)
+	b[8] = 0x80 | byte(u.randB>>56)
+	for i := 9; i < 16; i++ {
+		b[i] = byte(u.randB >> (120 - 8*i))
+	}
+
+	return b
+}
+
+// formatUUID returns the canonical lowercase form of the UUID b: 32 hex characters in groups of 8-4-4-4-12
+func formatUUID(b [16]byte) string {
+	encoded := make([]byte, 36)
+	hex.Encode(encoded[0:8], b[0:4])
+	encoded[8] = '-'
+	hex.Encode(encoded[9:13], b[4:6])
+	encoded[13] = '-'
+	hex.Encode(encoded[14:18], b[6:8])
+	encoded[18] = '-'
+	hex.Encode(encoded[19:23], b[8:10])
+	encoded[23] = '-'
+	hex.Encode(encoded[24:], b[10:])
+
+	return string(encoded)
+}
+
+// randomUUIDV4 returns a random UUIDv4 (RFC 9562): 122 random bits, with the version and variant bits set
+func randomUUIDV4(r *rand.Rand) string {
+	var b [16]byte
+	_, _ = r.Read(b[:])
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+
+	return formatUUID(b)
+}
+
+// uuidForEvent returns the UUID of a `uuid` field for the current event: a random UUIDv4, or a UUIDv7 (RFC 9562)
+// time ordered from the `now` the generator is initialised with, that sorts in generation order.
+func uuidForEvent(fieldName string, version int, state *genState) string {
+	if version != 7 {
+		return randomUUIDV4(state.rand)
+	}
+
+	prev, _ := state.prevCache[fieldName].(uuidV7)
+	u := prev.next(state.rand, uint64(timeNowToBind.UnixMilli()))
+	state.prevCache[fieldName] = u
+
+	return formatUUID(u.bytes())
+}
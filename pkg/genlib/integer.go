@@ -0,0 +1,43 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package genlib
+
+import (
+	"math"
+)
+
+// integerTypeMax returns the largest value of the integer type of the field: int8 for byte, int16 for short,
+// int32 for integer and int64 otherwise
+func integerTypeMax(fieldType string) int64 {
+	switch fieldType {
+	case FieldTypeByte:
+		return math.MaxInt8
+	case FieldTypeShort:
+		return math.MaxInt16
+	case FieldTypeInteger:
+		return math.MaxInt32
+	default:
+		return math.MaxInt64
+	}
+}
+
+// integerTypeMin returns the smallest value of the integer type of the field
+func integerTypeMin(fieldType string) int64 {
+	return -integerTypeMax(fieldType) - 1
+}
+
+// int64OfFloat64 converts f to int64, clamping it to the bounds of int64: float64(math.MaxInt64) is 2^63, that would
+// overflow
+func int64OfFloat64(f float64) int64 {
+	if f >= math.MaxInt64 {
+		return math.MaxInt64
+	}
+
+	if f <= math.MinInt64 {
+		return math.MinInt64
+	}
+
+	return int64(f)
+}
@@ -0,0 +1,123 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package genlib
+
+import (
+	"fmt"
+	"math/rand"
+	"net"
+
+	"github.com/elastic/elastic-integration-corpus-generator-tool/pkg/genlib/config"
+)
+
+// makeIPFunc returns a function generating IP addresses of the IP version set by `ip_version`:
+// with `both` half of the values are IPv4 and half IPv6
+func makeIPFunc(fieldCfg ConfigField) (func(r *rand.Rand) string, error) {
+	if len(fieldCfg.IPPools) > 0 {
+		if len(fieldCfg.IPVersion) > 0 {
+			return nil, fmt.Errorf("ip_pools cannot be combined with ip_version")
+		}
+
+		return makeIPPoolsFunc(fieldCfg.IPPools)
+	}
+
+	switch fieldCfg.IPVersion {
+	case "", config.IPVersion4:
+		return randIPv4, nil
+	case config.IPVersion6:
+		return randIPv6, nil
+	case config.IPVersionBoth:
+		return func(r *rand.Rand) string {
+			if r.Intn(2) == 0 {
+				return randIPv4(r)
+			}
+
+			return randIPv6(r)
+		}, nil
+	default:
+		return nil, fmt.Errorf("invalid ip_version: %s", fieldCfg.IPVersion)
+	}
+}
+
+// makeIPPoolsFunc returns a function generating IP addresses within the CIDR of one of the pools, chosen according
+// to their weights (ie: mostly internal addresses with occasional external ones)
+func makeIPPoolsFunc(pools []config.IPPool) (func(r *rand.Rand) string, error) {
+	ipNets := make([]*net.IPNet, 0, len(pools))
+	weights := make([]float64, 0, len(pools))
+	var totWeight float64
+	for _, pool := range pools {
+		_, ipNet, err := net.ParseCIDR(pool.CIDR)
+		if err != nil {
+			return nil, fmt.Errorf("invalid ip pool cidr: %w", err)
+		}
+
+		if pool.Weight < 0 {
+			return nil, fmt.Errorf("negative weight for ip pool: %s", pool.CIDR)
+		}
+
+		if pool.Weight > 0 {
+			ipNets = append(ipNets, ipNet)
+			weights = append(weights, pool.Weight)
+		}
+
+		totWeight += pool.Weight
+	}
+
+	if totWeight <= 0 {
+		return nil, fmt.Errorf("ip pool weights must not be all zero")
+	}
+
+	return func(r *rand.Rand) string {
+		// fallback for rounding errors
+		ipNet := ipNets[len(ipNets)-1]
+		v := r.Float64() * totWeight
+		for i, weight := range weights {
+			if v < weight {
+				ipNet = ipNets[i]
+				break
+			}
+
+			v -= weight
+		}
+
+		return randIPInNet(r, ipNet)
+	}, nil
+}
+
+// randIPInNet generates an IP address within ipNet, randomising the host bits
+func randIPInNet(r *rand.Rand, ipNet *net.IPNet) string {
+	ip := make(net.IP, len(ipNet.IP))
+	_, _ = r.Read(ip)
+	for i := range ip {
+		ip[i] = ipNet.IP[i] | ip[i]&^ipNet.Mask[i]
+	}
+
+	return ip.String()
+}
+
+func randIPv4(r *rand.Rand) string {
+	i0, i1, i2, i3 := randIP(r)
+	return fmt.Sprintf("%d.%d.%d.%d", i0, i1, i2, i3)
+}
+
+// randIPv6 generates a global unicast IPv6 address (2000::/3) in RFC 5952 canonical form (ie: `2001:db8::1`).
+// Half of the addresses have a run of zero groups, so that the zero compression is exercised.
+func randIPv6(r *rand.Rand) string {
+	ip := make(net.IP, net.IPv6len)
+	_, _ = r.Read(ip)
+	// 2000::/3, that is never formatted as an IPv4-mapped address
+	ip[0] = 0x20 | ip[0]&0x1f
+
+	if r.Intn(2) == 0 {
+		// zero between 2 and 6 of the groups after the first two
+		from := 2 + r.Intn(5)
+		to := from + 2 + r.Intn(7-from)
+		for i := from * 2; i < to*2; i++ {
+			ip[i] = 0
+		}
+	}
+
+	return ip.String()
+}
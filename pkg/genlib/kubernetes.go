@@ -0,0 +1,88 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package genlib
+
+import (
+	"math/rand"
+	"strings"
+)
+
+// kubernetesDeployment is a deployment with the namespace it runs in and the image of its container
+type kubernetesDeployment struct {
+	name      string
+	namespace string
+	image     string
+}
+
+// kubernetesTable are the deployments chosen from for a `kubernetes` field
+// NOTE: this list is not comprehensive
+var kubernetesTable = []kubernetesDeployment{
+	{name: "coredns", namespace: "kube-system", image: "registry.k8s.io/coredns/coredns:v1.11.1"},
+	{name: "metrics-server", namespace: "kube-system", image: "registry.k8s.io/metrics-server/metrics-server:v0.6.4"},
+	{name: "ingress-nginx-controller", namespace: "ingress-nginx", image: "registry.k8s.io/ingress-nginx/controller:v1.9.4"},
+	{name: "cert-manager", namespace: "cert-manager", image: "quay.io/jetstack/cert-manager-controller:v1.13.2"},
+	{name: "elastic-agent", namespace: "kube-system", image: "docker.elastic.co/beats/elastic-agent:8.11.1"},
+	{name: "frontend", namespace: "shop", image: "ghcr.io/example/shop-frontend:2.3.0"},
+	{name: "checkout", namespace: "shop", image: "ghcr.io/example/shop-checkout:1.8.2"},
+	{name: "redis", namespace: "shop", image: "docker.io/library/redis:7.2"},
+}
+
+// kubernetes is the generated value of a `kubernetes` field
+type kubernetes struct {
+	namespace      string
+	deploymentName string
+	podName        string
+	containerID    string
+	containerImage string
+}
+
+const (
+	// kubernetesNameAlphabet are the characters of the random suffixes of the pod names, without vowels
+	kubernetesNameAlphabet = "bcdfghjklmnpqrstvwxz2456789"
+	hexAlphabet            = "0123456789abcdef"
+)
+
+func randStringFromAlphabet(r *rand.Rand, alphabet string, n int) string {
+	var sb strings.Builder
+	sb.Grow(n)
+	for i := 0; i < n; i++ {
+		sb.WriteByte(alphabet[r.Intn(len(alphabet))])
+	}
+
+	return sb.String()
+}
+
+// kubernetesContainerPrefix returns the prefix of the `container` fields of a `kubernetes` field:
+// they are siblings of the field (ie: `container.id` for `kubernetes`)
+func kubernetesContainerPrefix(fieldName string) string {
+	if idx := strings.LastIndex(fieldName, "."); idx >= 0 {
+		return fieldName[:idx+1]
+	}
+
+	return ""
+}
+
+// kubernetesForEvent returns the kubernetes metadata of a `kubernetes` field for the current event,
+// so that the pod name embeds the deployment name and the namespace is the one of the deployment
+// regardless of the order they are emitted.
+func kubernetesForEvent(fieldName string, state *genState) kubernetes {
+	if value, ok := state.eventValue(fieldName); ok {
+		return value.(kubernetes)
+	}
+
+	deployment := kubernetesTable[state.rand.Intn(len(kubernetesTable))]
+	k := kubernetes{
+		namespace:      deployment.namespace,
+		deploymentName: deployment.name,
+		// <deployment>-<replica set hash>-<pod hash>
+		podName:        deployment.name + "-" + randStringFromAlphabet(state.rand, kubernetesNameAlphabet, 10) + "-" + randStringFromAlphabet(state.rand, kubernetesNameAlphabet, 5),
+		containerID:    randStringFromAlphabet(state.rand, hexAlphabet, 64),
+		containerImage: deployment.image,
+	}
+
+	state.setEventValue(fieldName, k)
+
+	return k
+}
@@ -0,0 +1,67 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package genlib
+
+import (
+	"fmt"
+)
+
+// currencyMinorUnits maps ISO 4217 currency codes to the number of digits of their minor unit
+// NOTE: this list is not comprehensive
+var currencyMinorUnits = map[string]int{
+	"AUD": 2,
+	"BHD": 3,
+	"BRL": 2,
+	"CAD": 2,
+	"CHF": 2,
+	"CLP": 0,
+	"CNY": 2,
+	"EUR": 2,
+	"GBP": 2,
+	"HKD": 2,
+	"INR": 2,
+	"ISK": 0,
+	"JOD": 3,
+	"JPY": 0,
+	"KRW": 0,
+	"KWD": 3,
+	"MXN": 2,
+	"NOK": 2,
+	"OMR": 3,
+	"SEK": 2,
+	"TND": 3,
+	"USD": 2,
+	"VND": 0,
+}
+
+// defaultCurrencies are the currencies chosen from when no `enum` is set for a `money` field
+var defaultCurrencies = []string{"USD", "EUR", "GBP", "JPY", "CHF", "CAD", "AUD", "CNY", "INR", "KWD"}
+
+func moneyCurrencies(fieldCfg ConfigField) ([]string, error) {
+	if len(fieldCfg.Enum) == 0 {
+		return defaultCurrencies, nil
+	}
+
+	for _, currency := range fieldCfg.Enum {
+		if _, ok := currencyMinorUnits[currency]; !ok {
+			return nil, fmt.Errorf("unknown ISO 4217 currency code: %s", currency)
+		}
+	}
+
+	return fieldCfg.Enum, nil
+}
+
+// moneyCurrencyForEvent returns the currency of a `money` field for the current event,
+// so that amount and currency are consistent regardless of the order they are emitted.
+func moneyCurrencyForEvent(fieldName string, currencies []string, state *genState) string {
+	if value, ok := state.eventValue(fieldName); ok {
+		return value.(string)
+	}
+
+	currency := currencies[state.rand.Intn(len(currencies))]
+	state.setEventValue(fieldName, currency)
+
+	return currency
+}
@@ -0,0 +1,155 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package genlib
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/elastic/elastic-integration-corpus-generator-tool/pkg/genlib/config"
+)
+
+// isNullableField returns whether the field is missing from some events, according to its `null_probability`
+func isNullableField(cfg Config, field Field) bool {
+	fieldCfg, ok := cfg.GetField(field.Name)
+	return ok && fieldCfg.NullProbability > 0 && !isDynamicObjectField(field)
+}
+
+// hasNullableFields returns whether any of the fields is missing from some events
+func hasNullableFields(cfg Config, fields Fields) bool {
+	for _, field := range fields {
+		if isNullableField(cfg, field) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// nullableFieldTemplate returns the template of a field missing from some events.
+// Like for first only fields, the whole key/value pair is emitted with its trailing comma: the comma dangling before
+// the closing brace when the last fields are omitted is trimmed once the event is emitted.
+func nullableFieldTemplate(cfg Config, field Field, fieldKey, fieldWrap string, isLast bool, templateEngine int) string {
+	fieldTrailer := " "
+	if isLast {
+		fieldTrailer = " }"
+	}
+
+	if templateEngine == customTemplateEngine {
+		// the emitter of the field writes the key/value pair and the separator
+		return fmt.Sprintf(`{{.%s}}%s`, field.Name, fieldTrailer)
+	}
+
+	fieldVariableName := fieldNormalizerRegex.ReplaceAllString(field.Name, "") + "Var"
+	fieldValue := fmt.Sprintf(`{{$%s}}`, fieldVariableName)
+	if field.Type == FieldTypeDate && !hasDateFormat(cfg, field) {
+		fieldValue = fmt.Sprintf(`{{$%s.Format "2006-01-02T15:04:05.999999999Z07:00"}}`, fieldVariableName)
+	}
+
+	if isFormattedDouble(cfg, field) {
+		fieldValue = fmt.Sprintf(`{{formatDouble "%s" $%s}}`, field.Name, fieldVariableName)
+	}
+
+	if isRegisteredType(field.Type) && !isArray(cfg, field) {
+		// the value is quoted by toJson
+		fieldValue = fmt.Sprintf(`{{toJson $%s}}`, fieldVariableName)
+		fieldWrap = ""
+	}
+
+	if fieldCfg, _ := cfg.GetField(field.Name); fieldCfg.NullMode == config.NullModeNull {
+		return fmt.Sprintf(`{{ $%s := generate "%s" }}"%s": {{ if kindIs "invalid" $%s }}null{{ else }}%s%s%s{{ end }}, %s`, fieldVariableName, field.Name, fieldKey, fieldVariableName, fieldWrap, fieldValue, fieldWrap, fieldTrailer)
+	}
+
+	return fmt.Sprintf(`{{ $%s := generate "%s" }}{{ if not (kindIs "invalid" $%s) }}"%s": %s%s%s, {{ end }}%s`, fieldVariableName, field.Name, fieldVariableName, fieldKey, fieldWrap, fieldValue, fieldWrap, fieldTrailer)
+}
+
+// bindNullProbability wraps the fields missing from some events: with their `null_probability` the field is omitted,
+// or emitted as `null` in `null` mode. In custom templates the emitter writes the whole key/value pair with its
+// trailing comma, so that the field can be omitted.
+func bindNullProbability(cfg Config, fields Fields, fieldMap map[string]any, withReturn bool) error {
+	for _, field := range fields {
+		if !isNullableField(cfg, field) {
+			continue
+		}
+
+		fieldCfg, _ := cfg.GetField(field.Name)
+		if fieldCfg.FirstOnly || fieldCfg.KeywordMultiField || len(emittedFieldNames(cfg, field)) > 1 {
+			return fmt.Errorf("%w for field %s: `null_probability` and `first_only`, `keyword_multi_field` or a type generating a group of fields", illegalConfigCombination, field.Name)
+		}
+
+		if withReturn {
+			boundF, ok := fieldMap[field.Name].(emitF)
+			if !ok {
+				return errors.New("cannot bind null probability")
+			}
+
+			var emitF emitF
+			emitF = func(state *genState) any {
+				if state.rand.Float64() < fieldCfg.NullProbability {
+					return nil
+				}
+
+				return boundF(state)
+			}
+
+			fieldMap[field.Name] = emitF
+			continue
+		}
+
+		boundF, ok := fieldMap[field.Name].(emitFNotReturn)
+		if !ok {
+			return errors.New("cannot bind null probability")
+		}
+
+		fieldWrap := fieldValueWrapByConfig(cfg, field)
+		fieldPrefix := []byte(`"` + cfg.FieldKey(field.Name) + `": ` + fieldWrap)
+		fieldTrailer := []byte(fieldWrap + ", ")
+		nullPair := []byte(`"` + cfg.FieldKey(field.Name) + `": null, `)
+
+		var emitFNotReturn emitFNotReturn
+		emitFNotReturn = func(state *genState, buf *bytes.Buffer) error {
+			if state.rand.Float64() < fieldCfg.NullProbability {
+				if fieldCfg.NullMode == config.NullModeNull {
+					buf.Write(nullPair)
+				}
+
+				return nil
+			}
+
+			buf.Write(fieldPrefix)
+			if err := boundF(state, buf); err != nil {
+				return err
+			}
+
+			buf.Write(fieldTrailer)
+			return nil
+		}
+
+		fieldMap[field.Name] = emitFNotReturn
+	}
+
+	return nil
+}
+
+// trimDanglingSeparator removes the comma before the closing brace of the event written in buf from start, left by
+// the fields omitted at the end of the event
+func trimDanglingSeparator(buf *bytes.Buffer, start int) {
+	event := buf.Bytes()[start:]
+	trimmed := bytes.TrimRight(event, " \t\r\n")
+	if !bytes.HasSuffix(trimmed, []byte("}")) {
+		return
+	}
+
+	beforeClosing := bytes.TrimRight(trimmed[:len(trimmed)-1], " \t\r\n")
+	if !bytes.HasSuffix(beforeClosing, []byte(",")) {
+		return
+	}
+
+	trailer := make([]byte, len(event)-len(beforeClosing))
+	copy(trailer, event[len(beforeClosing):])
+	buf.Truncate(start + len(beforeClosing) - 1)
+	buf.Write(trailer)
+}
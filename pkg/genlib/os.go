@@ -0,0 +1,42 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package genlib
+
+// osEntry is a row of the table operating systems are chosen from
+type osEntry struct {
+	name    string
+	version string
+	family  string
+}
+
+// osTable are the operating systems chosen from for an `os` field
+// NOTE: this list is not comprehensive
+var osTable = []osEntry{
+	{name: "Ubuntu", version: "20.04", family: "debian"},
+	{name: "Ubuntu", version: "22.04", family: "debian"},
+	{name: "Debian GNU/Linux", version: "11", family: "debian"},
+	{name: "Debian GNU/Linux", version: "12", family: "debian"},
+	{name: "CentOS Linux", version: "7", family: "redhat"},
+	{name: "Red Hat Enterprise Linux", version: "8.8", family: "redhat"},
+	{name: "Red Hat Enterprise Linux", version: "9.2", family: "redhat"},
+	{name: "Amazon Linux", version: "2", family: "redhat"},
+	{name: "Windows Server 2019 Datacenter", version: "10.0", family: "windows"},
+	{name: "Windows Server 2022 Datacenter", version: "10.0", family: "windows"},
+	{name: "macOS", version: "13.5", family: "darwin"},
+	{name: "macOS", version: "14.0", family: "darwin"},
+}
+
+// osForEvent returns the table row of an `os` field for the current event,
+// so that name, version and family are consistent regardless of the order they are emitted.
+func osForEvent(fieldName string, state *genState) osEntry {
+	if value, ok := state.eventValue(fieldName); ok {
+		return value.(osEntry)
+	}
+
+	os := osTable[state.rand.Intn(len(osTable))]
+	state.setEventValue(fieldName, os)
+
+	return os
+}
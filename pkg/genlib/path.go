@@ -0,0 +1,105 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package genlib
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+
+	"github.com/Pallinder/go-randomdata"
+	"github.com/elastic/elastic-integration-corpus-generator-tool/pkg/genlib/config"
+)
+
+// default depth bounds of generated paths, when no `range` is set
+const (
+	pathDefaultMinDepth = 1
+	pathDefaultMaxDepth = 8
+)
+
+// pathDefaultDepthProbability is the parameter of the geometric depth distribution, when no `depth_probability` is set
+const pathDefaultDepthProbability = 0.5
+
+// pathMaxTries is the number of attempts to draw a geometric depth within the depth bounds, before clamping it
+const pathMaxTries = 100
+
+// makePathFunc returns a function generating paths (ie: `/alpha/beta/gamma`) with depth, the number of segments,
+// within `range` and following `depth_distribution`
+func makePathFunc(fieldCfg ConfigField) (func(r *rand.Rand) string, error) {
+	depthFunc, err := makePathDepthFunc(fieldCfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(r *rand.Rand) string {
+		depth := depthFunc(r)
+
+		var b strings.Builder
+		for i := 0; i < depth; i++ {
+			b.WriteByte('/')
+			b.WriteString(strings.ToLower(randomdata.Noun()))
+		}
+
+		return b.String()
+	}, nil
+}
+
+// makePathDepthFunc returns a function generating the depth of paths, within `range` and following `depth_distribution`
+func makePathDepthFunc(fieldCfg ConfigField) (func(r *rand.Rand) int, error) {
+	minDepth, maxDepth := pathDefaultMinDepth, pathDefaultMaxDepth
+	if v, err := fieldCfg.Range.MinAsInt64(); err == nil {
+		minDepth = int(v)
+	}
+
+	if v, err := fieldCfg.Range.MaxAsInt64(); err == nil {
+		maxDepth = int(v)
+	}
+
+	if minDepth < 1 || minDepth > maxDepth {
+		return nil, fmt.Errorf("invalid path depth range [%d, %d]", minDepth, maxDepth)
+	}
+
+	var depthFunc func(r *rand.Rand) int
+	switch fieldCfg.DepthDistribution {
+	case "", config.DepthDistributionUniform:
+		depthFunc = func(r *rand.Rand) int {
+			return minDepth + r.Intn(maxDepth-minDepth+1)
+		}
+	case config.DepthDistributionGeometric:
+		p := pathDefaultDepthProbability
+		if fieldCfg.DepthProbability != 0 {
+			p = fieldCfg.DepthProbability
+		}
+
+		if p <= 0 || p > 1 {
+			return nil, fmt.Errorf("invalid depth_probability: %f", p)
+		}
+
+		depthFunc = func(r *rand.Rand) int {
+			return minDepth + geometricDepth(r, p, maxDepth-minDepth)
+		}
+	default:
+		return nil, fmt.Errorf("invalid depth_distribution: %s", fieldCfg.DepthDistribution)
+	}
+
+	return depthFunc, nil
+}
+
+// geometricDepth returns the number of failures before the first success of trials with probability p,
+// truncated at max: out of bound values are drawn again, so that the shape of the distribution is kept
+func geometricDepth(r *rand.Rand, p float64, max int) int {
+	for try := 0; try < pathMaxTries; try++ {
+		depth := 0
+		for depth <= max && r.Float64() >= p {
+			depth++
+		}
+
+		if depth <= max {
+			return depth
+		}
+	}
+
+	return max
+}
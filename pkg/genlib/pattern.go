@@ -0,0 +1,184 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package genlib
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand"
+	"regexp/syntax"
+	"strings"
+	"unicode"
+)
+
+// patternMaxUnboundedRepeat is the number of repetitions added to the minimum of unbounded quantifiers
+// (ie: `*`, `+` and `{n,}`) in a `pattern`
+const patternMaxUnboundedRepeat = 8
+
+// patternPrintableASCII are the ranges of the printable ASCII characters but `"` and `\`, that characters classes are
+// restricted to when they include any of them, and `.` is generated from, so that the values can be written as is in
+// a JSON string
+var patternPrintableASCII = []rune{0x20, 0x21, 0x23, 0x5b, 0x5d, 0x7e}
+
+// patternFunc writes a string matching a pattern to buf, drawing from r
+type patternFunc func(r *rand.Rand, buf *bytes.Buffer)
+
+// makePatternFunc returns a function generating strings matching the regular expression pattern (ie:
+// `eni-[0-9a-f]{17}`): it supports literals, character classes, `.`, quantifiers, alternation and groups. Anchors
+// are accepted and ignored, since the whole string matches the pattern. Any other construct returns an error.
+func makePatternFunc(pattern string) (patternFunc, error) {
+	re, err := syntax.Parse(pattern, syntax.Perl)
+	if err != nil {
+		return nil, err
+	}
+
+	return compilePattern(re)
+}
+
+func compilePattern(re *syntax.Regexp) (patternFunc, error) {
+	switch re.Op {
+	case syntax.OpEmptyMatch, syntax.OpBeginLine, syntax.OpEndLine, syntax.OpBeginText, syntax.OpEndText:
+		return func(r *rand.Rand, buf *bytes.Buffer) {}, nil
+	case syntax.OpLiteral:
+		literal := string(re.Rune)
+		if strings.IndexFunc(literal, isPatternUnsafeRune) >= 0 {
+			return nil, fmt.Errorf("unsupported literal in pattern: %q, `\"`, `\\` and control characters cannot be written as is in a JSON string", literal)
+		}
+
+		return func(r *rand.Rand, buf *bytes.Buffer) {
+			buf.WriteString(literal)
+		}, nil
+	case syntax.OpCharClass:
+		return makeCharClassFunc(re.Rune)
+	case syntax.OpAnyCharNotNL, syntax.OpAnyChar:
+		return makeCharClassFunc(patternPrintableASCII)
+	case syntax.OpCapture:
+		return compilePattern(re.Sub[0])
+	case syntax.OpConcat:
+		subs, err := compilePatterns(re.Sub)
+		if err != nil {
+			return nil, err
+		}
+
+		return func(r *rand.Rand, buf *bytes.Buffer) {
+			for _, sub := range subs {
+				sub(r, buf)
+			}
+		}, nil
+	case syntax.OpAlternate:
+		subs, err := compilePatterns(re.Sub)
+		if err != nil {
+			return nil, err
+		}
+
+		return func(r *rand.Rand, buf *bytes.Buffer) {
+			subs[r.Intn(len(subs))](r, buf)
+		}, nil
+	case syntax.OpStar:
+		return makeRepeatFunc(re.Sub[0], 0, -1)
+	case syntax.OpPlus:
+		return makeRepeatFunc(re.Sub[0], 1, -1)
+	case syntax.OpQuest:
+		return makeRepeatFunc(re.Sub[0], 0, 1)
+	case syntax.OpRepeat:
+		return makeRepeatFunc(re.Sub[0], re.Min, re.Max)
+	default:
+		return nil, fmt.Errorf("unsupported construct in pattern: %s", re)
+	}
+}
+
+// isPatternUnsafeRune returns true if r cannot be written as is in a JSON string
+func isPatternUnsafeRune(r rune) bool {
+	return r == '"' || r == '\\' || unicode.IsControl(r)
+}
+
+func compilePatterns(res []*syntax.Regexp) ([]patternFunc, error) {
+	subs := make([]patternFunc, 0, len(res))
+	for _, re := range res {
+		sub, err := compilePattern(re)
+		if err != nil {
+			return nil, err
+		}
+
+		subs = append(subs, sub)
+	}
+
+	return subs, nil
+}
+
+// makeRepeatFunc returns a function generating between min and max repetitions of re, max being -1 when unbounded
+func makeRepeatFunc(re *syntax.Regexp, min, max int) (patternFunc, error) {
+	sub, err := compilePattern(re)
+	if err != nil {
+		return nil, err
+	}
+
+	if max < 0 {
+		max = min + patternMaxUnboundedRepeat
+	}
+
+	return func(r *rand.Rand, buf *bytes.Buffer) {
+		n := min + r.Intn(max-min+1)
+		for i := 0; i < n; i++ {
+			sub(r, buf)
+		}
+	}, nil
+}
+
+// makeCharClassFunc returns a function generating a character of the class made of pairs of inclusive ranges:
+// the class is restricted to patternPrintableASCII if it includes any of them, otherwise to the characters that are
+// neither control characters nor surrogates, so that negated classes (ie: `[^,]`) generate readable strings. Classes
+// of only other characters (ie: `[\\"]`) return an error.
+func makeCharClassFunc(class []rune) (patternFunc, error) {
+	ranges := intersectRanges(class, patternPrintableASCII)
+	if len(ranges) == 0 {
+		ranges = intersectRanges(class, []rune{0xa0, 0xd7ff, 0xe000, unicode.MaxRune})
+	}
+
+	if len(ranges) == 0 {
+		return nil, fmt.Errorf("unsupported character class in pattern: it must include characters other than `\"`, `\\` and control characters")
+	}
+
+	var tot int
+	for i := 0; i < len(ranges); i += 2 {
+		tot += int(ranges[i+1]-ranges[i]) + 1
+	}
+
+	return func(r *rand.Rand, buf *bytes.Buffer) {
+		n := r.Intn(tot)
+		for i := 0; i < len(ranges); i += 2 {
+			size := int(ranges[i+1]-ranges[i]) + 1
+			if n < size {
+				buf.WriteRune(ranges[i] + rune(n))
+				return
+			}
+
+			n -= size
+		}
+	}, nil
+}
+
+// intersectRanges returns the intersection of the sorted pairs of inclusive ranges a and b
+func intersectRanges(a, b []rune) []rune {
+	var ranges []rune
+	for i := 0; i < len(a); i += 2 {
+		for j := 0; j < len(b); j += 2 {
+			lo, hi := a[i], a[i+1]
+			if b[j] > lo {
+				lo = b[j]
+			}
+
+			if b[j+1] < hi {
+				hi = b[j+1]
+			}
+
+			if lo <= hi {
+				ranges = append(ranges, lo, hi)
+			}
+		}
+	}
+
+	return ranges
+}
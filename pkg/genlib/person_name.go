@@ -0,0 +1,76 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package genlib
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+// defaultPersonNameLocale is the locale of a `person_name` field when no `locale` is set
+const defaultPersonNameLocale = "en_US"
+
+// personNamePool is the pool of given and family names of a locale
+type personNamePool struct {
+	givenNames  []string
+	familyNames []string
+	// familyNameFirst is true for locales where the family name precedes the given name
+	familyNameFirst bool
+}
+
+// personNamePools maps locales to their name pools
+// NOTE: these lists are not comprehensive
+var personNamePools = map[string]personNamePool{
+	"en_US": {
+		givenNames:  []string{"James", "Mary", "John", "Patricia", "Robert", "Jennifer", "Michael", "Linda", "William", "Elizabeth"},
+		familyNames: []string{"Smith", "Johnson", "Williams", "Brown", "Jones", "Garcia", "Miller", "Davis", "Wilson", "Anderson"},
+	},
+	"de_DE": {
+		givenNames:  []string{"Lukas", "Anna", "Maximilian", "Sophie", "Felix", "Marie", "Jonas", "Lena", "Paul", "Katharina"},
+		familyNames: []string{"Müller", "Schmidt", "Schneider", "Fischer", "Weber", "Meyer", "Wagner", "Becker", "Schulz", "Hoffmann"},
+	},
+	"es_ES": {
+		givenNames:  []string{"Antonio", "María", "Manuel", "Carmen", "José", "Lucía", "Francisco", "Isabel", "Javier", "Pilar"},
+		familyNames: []string{"García", "Rodríguez", "González", "Fernández", "López", "Martínez", "Sánchez", "Pérez", "Gómez", "Jiménez"},
+	},
+	"fr_FR": {
+		givenNames:  []string{"Jean", "Marie", "Pierre", "Camille", "Louis", "Chloé", "Gabriel", "Manon", "Hugo", "Juliette"},
+		familyNames: []string{"Martin", "Bernard", "Dubois", "Thomas", "Robert", "Richard", "Petit", "Durand", "Leroy", "Moreau"},
+	},
+	"it_IT": {
+		givenNames:  []string{"Giuseppe", "Maria", "Giovanni", "Anna", "Francesco", "Giulia", "Alessandro", "Chiara", "Lorenzo", "Francesca"},
+		familyNames: []string{"Rossi", "Russo", "Ferrari", "Esposito", "Bianchi", "Romano", "Colombo", "Ricci", "Marino", "Greco"},
+	},
+	"ja_JP": {
+		givenNames:      []string{"Haruto", "Yui", "Sota", "Hina", "Yuto", "Sakura", "Riku", "Aoi", "Ren", "Yuna"},
+		familyNames:     []string{"Sato", "Suzuki", "Takahashi", "Tanaka", "Watanabe", "Ito", "Yamamoto", "Nakamura", "Kobayashi", "Kato"},
+		familyNameFirst: true,
+	},
+}
+
+func personNamePoolForLocale(fieldCfg ConfigField) (personNamePool, error) {
+	locale := fieldCfg.Locale
+	if len(locale) == 0 {
+		locale = defaultPersonNameLocale
+	}
+
+	pool, ok := personNamePools[locale]
+	if !ok {
+		return personNamePool{}, fmt.Errorf("unknown locale for person name: %s", locale)
+	}
+
+	return pool, nil
+}
+
+// randPersonName returns a full name drawn from the pool
+func randPersonName(r *rand.Rand, pool personNamePool) string {
+	givenName := pool.givenNames[r.Intn(len(pool.givenNames))]
+	familyName := pool.familyNames[r.Intn(len(pool.familyNames))]
+	if pool.familyNameFirst {
+		return familyName + " " + givenName
+	}
+
+	return givenName + " " + familyName
+}
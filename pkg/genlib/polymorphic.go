@@ -0,0 +1,160 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package genlib
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"sort"
+)
+
+// polymorphicValue is the value of a polymorphic field: it is printed as JSON in text templates,
+// so that the values of the string types are quoted and the ones of the numeric types are not
+type polymorphicValue struct {
+	value any
+}
+
+func (p polymorphicValue) String() string {
+	b, err := json.Marshal(p.value)
+	if err != nil {
+		return "null"
+	}
+
+	return string(b)
+}
+
+func (p polymorphicValue) MarshalJSON() ([]byte, error) {
+	return json.Marshal(p.value)
+}
+
+func isPolymorphic(cfg Config, field Field) bool {
+	fieldCfg, ok := cfg.GetField(field.Name)
+	return ok && len(fieldCfg.Polymorphic) > 0
+}
+
+// makePolymorphicTypeFunc returns a function choosing one of the types of `polymorphic` according to their weights
+func makePolymorphicTypeFunc(fieldCfg ConfigField) (func(r *rand.Rand) string, error) {
+	types := make([]string, 0, len(fieldCfg.Polymorphic))
+	var totWeight float64
+	for fieldType, weight := range fieldCfg.Polymorphic {
+		if weight < 0 {
+			return nil, fmt.Errorf("negative weight for polymorphic type: %s", fieldType)
+		}
+
+		if weight > 0 {
+			types = append(types, fieldType)
+		}
+
+		totWeight += weight
+	}
+
+	if totWeight <= 0 {
+		return nil, fmt.Errorf("polymorphic weights must not be all zero")
+	}
+
+	// sort the types so that the choice is reproducible with the same seed
+	sort.Strings(types)
+
+	return func(r *rand.Rand) string {
+		v := r.Float64() * totWeight
+		for _, t := range types {
+			if v < fieldCfg.Polymorphic[t] {
+				return t
+			}
+
+			v -= fieldCfg.Polymorphic[t]
+		}
+
+		// fallback for rounding errors
+		return types[len(types)-1]
+	}, nil
+}
+
+// bindPolymorphic binds a field emitting, for each event, a value of one of the types of `polymorphic`, to deliberately
+// generate mapping conflicts. Each type is bound as it was the type of the field, so the other options of the field apply.
+func bindPolymorphic(cfg Config, fieldCfg ConfigField, field Field, fieldMap map[string]any, withReturn bool) error {
+	typeFunc, err := makePolymorphicTypeFunc(fieldCfg)
+	if err != nil {
+		return err
+	}
+
+	typedWraps := make(map[string]string, len(fieldCfg.Polymorphic))
+	typedFs := make(map[string]emitF, len(fieldCfg.Polymorphic))
+	typedFsNotReturn := make(map[string]emitFNotReturn, len(fieldCfg.Polymorphic))
+	for fieldType := range fieldCfg.Polymorphic {
+		typedField := field
+		typedField.Type = fieldType
+
+		typedFieldMap := make(map[string]any)
+		if withReturn {
+			err = bindByTypeWithReturn(cfg, typedField, typedFieldMap)
+		} else {
+			err = bindByType(cfg, typedField, typedFieldMap)
+		}
+
+		if err != nil {
+			return err
+		}
+
+		typedF, ok := typedFieldMap[field.Name]
+		if !ok || len(typedFieldMap) != 1 {
+			return fmt.Errorf("field %s cannot be polymorphic with type %s", field.Name, fieldType)
+		}
+
+		typedWraps[fieldType] = fieldValueWrapByType(typedField)
+		if isRegisteredType(fieldType) {
+			typedWraps[fieldType] = registeredTypeValueWrap(fieldCfg, typedField)
+		}
+
+		switch f := typedF.(type) {
+		case emitF:
+			typedFs[fieldType] = f
+		case emitFNotReturn:
+			typedFsNotReturn[fieldType] = f
+		}
+	}
+
+	if withReturn {
+		var emitF emitF
+		emitF = func(state *genState) any {
+			value := typedFs[typeFunc(state.rand)](state)
+			if _, ok := value.(error); ok {
+				return value
+			}
+
+			return polymorphicValue{value: value}
+		}
+
+		fieldMap[field.Name] = emitF
+		return nil
+	}
+
+	var emitFNotReturn emitFNotReturn
+	emitFNotReturn = func(state *genState, buf *bytes.Buffer) error {
+		fieldType := typeFunc(state.rand)
+		typedF := typedFsNotReturn[fieldType]
+		if typedWraps[fieldType] != "\"" {
+			return typedF(state, buf)
+		}
+
+		var value bytes.Buffer
+		if err := typedF(state, &value); err != nil {
+			return err
+		}
+
+		quoted, err := json.Marshal(value.String())
+		if err != nil {
+			return err
+		}
+
+		buf.Write(quoted)
+		return nil
+	}
+
+	fieldMap[field.Name] = emitFNotReturn
+	return nil
+}
@@ -0,0 +1,64 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package genlib
+
+// maxPID is the default upper bound of process identifiers on Linux
+const maxPID = 32768
+
+// processEntry is a process with the names of the processes that plausibly spawn it
+type processEntry struct {
+	name    string
+	parents []string
+}
+
+// processTable are the processes chosen from for a `process` field
+// NOTE: this list is not comprehensive
+var processTable = []processEntry{
+	{name: "sshd", parents: []string{"systemd", "sshd"}},
+	{name: "bash", parents: []string{"sshd", "tmux", "sudo", "login"}},
+	{name: "sudo", parents: []string{"bash", "zsh"}},
+	{name: "ls", parents: []string{"bash", "zsh"}},
+	{name: "grep", parents: []string{"bash", "zsh", "sh"}},
+	{name: "curl", parents: []string{"bash", "sh", "python3"}},
+	{name: "python3", parents: []string{"bash", "cron", "systemd"}},
+	{name: "sh", parents: []string{"cron", "python3", "java"}},
+	{name: "cron", parents: []string{"systemd"}},
+	{name: "nginx", parents: []string{"systemd", "nginx"}},
+	{name: "java", parents: []string{"systemd", "bash"}},
+	{name: "containerd-shim", parents: []string{"containerd"}},
+	{name: "cmd.exe", parents: []string{"explorer.exe", "services.exe"}},
+	{name: "powershell.exe", parents: []string{"explorer.exe", "cmd.exe"}},
+	{name: "svchost.exe", parents: []string{"services.exe"}},
+}
+
+// process is the generated value of a `process` field
+type process struct {
+	pid        int64
+	name       string
+	parentPID  int64
+	parentName string
+}
+
+// processForEvent returns the process of a `process` field for the current event,
+// so that the process and its parent are consistent regardless of the order they are emitted.
+// The parent pid is always lower than the pid, as parents are started before their children.
+func processForEvent(fieldName string, state *genState) process {
+	if value, ok := state.eventValue(fieldName); ok {
+		return value.(process)
+	}
+
+	entry := processTable[state.rand.Intn(len(processTable))]
+	pid := int64(state.rand.Intn(maxPID-1)) + 2
+	p := process{
+		pid:        pid,
+		name:       entry.name,
+		parentPID:  state.rand.Int63n(pid-1) + 1,
+		parentName: entry.parents[state.rand.Intn(len(entry.parents))],
+	}
+
+	state.setEventValue(fieldName, p)
+
+	return p
+}
@@ -0,0 +1,85 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package genlib
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+
+	"github.com/Pallinder/go-randomdata"
+)
+
+// registryPathSeparator is the backslash separating the keys of registry paths, already JSON-escaped
+const registryPathSeparator = `\\`
+
+// registryHiveAbbreviations maps the names of the registry hives to their abbreviation
+var registryHiveAbbreviations = map[string]string{
+	"HKEY_LOCAL_MACHINE":  "HKLM",
+	"HKEY_CURRENT_USER":   "HKCU",
+	"HKEY_USERS":          "HKU",
+	"HKEY_CLASSES_ROOT":   "HKCR",
+	"HKEY_CURRENT_CONFIG": "HKCC",
+}
+
+// defaultRegistryHives are the hives of the generated registry paths, when no `hives` are set
+var defaultRegistryHives = []string{"HKLM", "HKCU", "HKU", "HKCR", "HKCC"}
+
+// registryTopKeys are well-known keys directly under each hive, by the abbreviation of the hive
+var registryTopKeys = map[string][]string{
+	"HKLM": {"SOFTWARE", "SYSTEM", "SAM", "SECURITY", "HARDWARE"},
+	"HKCU": {"Software", "Control Panel", "Environment", "Console", "Network"},
+	"HKU":  {".DEFAULT", "S-1-5-18", "S-1-5-19", "S-1-5-20"},
+	"HKCR": {"CLSID", "Interface", "TypeLib", "AppID"},
+	"HKCC": {"System", "Software"},
+}
+
+// makeRegistryPathFunc returns a function generating Windows registry paths (ie: `HKLM\SOFTWARE\Alpha\Beta`) in
+// one of `hives`, by abbreviation or name, with depth, the number of keys under the hive, within `range` and
+// following `depth_distribution` as for paths. The first key is a well-known key of the hive, and the backslashes
+// are JSON-escaped, so that the value can be written as is in a JSON string.
+func makeRegistryPathFunc(fieldCfg ConfigField) (func(r *rand.Rand) string, error) {
+	hives := defaultRegistryHives
+	if len(fieldCfg.Hives) > 0 {
+		hives = fieldCfg.Hives
+	}
+
+	for _, hive := range hives {
+		if _, ok := registryTopKeys[registryHiveAbbreviation(hive)]; !ok {
+			return nil, fmt.Errorf("invalid registry hive: %s", hive)
+		}
+	}
+
+	depthFunc, err := makePathDepthFunc(fieldCfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(r *rand.Rand) string {
+		hive := hives[r.Intn(len(hives))]
+		topKeys := registryTopKeys[registryHiveAbbreviation(hive)]
+
+		var b strings.Builder
+		b.WriteString(hive)
+		b.WriteString(registryPathSeparator)
+		b.WriteString(topKeys[r.Intn(len(topKeys))])
+		for depth := depthFunc(r); depth > 1; depth-- {
+			noun := randomdata.Noun()
+			b.WriteString(registryPathSeparator)
+			b.WriteString(strings.ToUpper(noun[:1]) + noun[1:])
+		}
+
+		return b.String()
+	}, nil
+}
+
+// registryHiveAbbreviation returns the abbreviation of hive, that can be the name of the hive or already its abbreviation
+func registryHiveAbbreviation(hive string) string {
+	if abbreviation, ok := registryHiveAbbreviations[hive]; ok {
+		return abbreviation
+	}
+
+	return hive
+}
@@ -0,0 +1,125 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package genlib
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// bindRelatedFields binds the fields aggregating in an array the values of other fields in the same event.
+// The aggregated fields are wrapped, so that their value is generated once per event regardless the order the fields are emitted.
+func bindRelatedFields(cfg Config, fields Fields, fieldMap map[string]any, withReturn bool) error {
+	fieldsByName := make(map[string]Field, len(fields))
+	for _, field := range fields {
+		fieldsByName[field.Name] = field
+	}
+
+	wrapped := make(map[string]struct{})
+	for _, field := range fields {
+		fieldCfg, _ := cfg.GetField(field.Name)
+		if len(fieldCfg.RelatedFields) == 0 {
+			continue
+		}
+
+		for _, relatedFieldName := range fieldCfg.RelatedFields {
+			if _, ok := fieldMap[relatedFieldName]; !ok {
+				return fmt.Errorf("field %s aggregates field %s that is not defined", field.Name, relatedFieldName)
+			}
+
+			if _, ok := wrapped[relatedFieldName]; ok {
+				continue
+			}
+
+			if err := wrapEventValue(relatedFieldName, fieldMap, withReturn); err != nil {
+				return err
+			}
+
+			wrapped[relatedFieldName] = struct{}{}
+		}
+
+		if withReturn {
+			bindRelatedFieldsWithReturn(fieldCfg, field, fieldMap)
+		} else {
+			bindRelatedFieldsNotReturn(cfg, fieldCfg, field, fieldsByName, fieldMap)
+		}
+	}
+
+	return nil
+}
+
+func bindRelatedFieldsNotReturn(cfg Config, fieldCfg ConfigField, field Field, fieldsByName map[string]Field, fieldMap map[string]any) {
+	relatedFs := make([]emitFNotReturn, 0, len(fieldCfg.RelatedFields))
+	relatedWraps := make([]string, 0, len(fieldCfg.RelatedFields))
+	for _, relatedFieldName := range fieldCfg.RelatedFields {
+		relatedFs = append(relatedFs, fieldMap[relatedFieldName].(emitFNotReturn))
+		relatedWraps = append(relatedWraps, fieldValueWrapByConfig(cfg, fieldsByName[relatedFieldName]))
+	}
+
+	var emitFNotReturn emitFNotReturn
+	emitFNotReturn = func(state *genState, buf *bytes.Buffer) error {
+		seen := make(map[string]struct{}, len(relatedFs))
+		buf.WriteByte('[')
+		for i, relatedF := range relatedFs {
+			var tmp bytes.Buffer
+			if err := relatedF(state, &tmp); err != nil {
+				return err
+			}
+
+			if _, ok := seen[tmp.String()]; ok {
+				continue
+			}
+
+			if len(seen) > 0 {
+				buf.WriteByte(',')
+			}
+
+			seen[tmp.String()] = struct{}{}
+			if len(relatedWraps[i]) > 0 {
+				value, _ := json.Marshal(tmp.String())
+				buf.Write(value)
+			} else {
+				buf.Write(tmp.Bytes())
+			}
+		}
+
+		buf.WriteByte(']')
+		return nil
+	}
+
+	fieldMap[field.Name] = emitFNotReturn
+}
+
+func bindRelatedFieldsWithReturn(fieldCfg ConfigField, field Field, fieldMap map[string]any) {
+	relatedFs := make([]emitF, 0, len(fieldCfg.RelatedFields))
+	for _, relatedFieldName := range fieldCfg.RelatedFields {
+		relatedFs = append(relatedFs, fieldMap[relatedFieldName].(emitF))
+	}
+
+	var emitF emitF
+	emitF = func(state *genState) any {
+		seen := make(map[string]struct{}, len(relatedFs))
+		values := make(arrayValues, 0, len(relatedFs))
+		for _, relatedF := range relatedFs {
+			value := relatedF(state)
+			if err, ok := value.(error); ok {
+				return err
+			}
+
+			key := fmt.Sprint(value)
+			if _, ok := seen[key]; ok {
+				continue
+			}
+
+			seen[key] = struct{}{}
+			values = append(values, value)
+		}
+
+		return values
+	}
+
+	fieldMap[field.Name] = emitF
+}
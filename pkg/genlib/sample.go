@@ -0,0 +1,129 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package genlib
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/elastic/elastic-integration-corpus-generator-tool/pkg/genlib/config"
+)
+
+// SampleDocument generates a single document for the fields with the config, pretty-printed, to help authoring configs.
+// It returns as well the explanation of the config rule that drove the value of each field, by field name.
+func SampleDocument(cfg Config, fields Fields) ([]byte, map[string]string, error) {
+	gen, err := NewGenerator(cfg, fields, 1)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	defer func() {
+		_ = gen.Close()
+	}()
+
+	var buf bytes.Buffer
+	if err := gen.Emit(&buf); err != nil {
+		return nil, nil, err
+	}
+
+	var document bytes.Buffer
+	if err := json.Indent(&document, buf.Bytes(), "", "  "); err != nil {
+		return nil, nil, err
+	}
+
+	explanations := make(map[string]string, len(fields))
+	for _, field := range fields {
+		explanations[field.Name] = explainField(cfg, field)
+	}
+
+	return document.Bytes(), explanations, nil
+}
+
+// explainField returns the explanation of the config rule that drives the value of the field,
+// checking the rules in the order they are applied when binding the field
+func explainField(cfg Config, field Field) string {
+	fieldCfg, _ := cfg.GetField(field.Name)
+
+	var explanation string
+	switch {
+	case len(field.Value) > 0:
+		explanation = "value from the fields definition"
+	case fieldCfg.Value != nil:
+		explanation = fmt.Sprintf("value: the fixed value %v", fieldCfg.Value)
+	case len(fieldCfg.Sequence) > 0:
+		explanation = fmt.Sprintf("sequence: the %d values of the sequence in order", len(fieldCfg.Sequence))
+	case len(fieldCfg.RawJSON) > 0:
+		explanation = "raw_json: the pre-serialized JSON fragment"
+	case fieldCfg.ArraySize > 0 && len(fieldCfg.Enum) > 0:
+		explanation = fmt.Sprintf("enum with array_size: arrays of %d values of the enum", fieldCfg.ArraySize)
+	case len(fieldCfg.Polymorphic) > 0:
+		types := make([]string, 0, len(fieldCfg.Polymorphic))
+		for fieldType := range fieldCfg.Polymorphic {
+			types = append(types, fieldType)
+		}
+
+		sort.Strings(types)
+		explanation = fmt.Sprintf("polymorphic: a value of one of the types %s, chosen by weight", strings.Join(types, ", "))
+	case fieldCfg.Unique:
+		explanation = fmt.Sprintf("unique: a %s value not generated before", field.Type)
+	case fieldCfg.Cardinality > 0:
+		explanation = fmt.Sprintf("cardinality: one of %d %s values", fieldCfg.Cardinality, field.Type)
+	case len(fieldCfg.DependsOn) > 0:
+		explanation = fmt.Sprintf("enum_by_value: a value of the enum for the value of %s", fieldCfg.DependsOn)
+	case len(fieldCfg.LagOf) > 0:
+		explanation = fmt.Sprintf("lag_of: the value of %s plus a random lag", fieldCfg.LagOf)
+	case len(fieldCfg.DurationOf) > 0:
+		explanation = fmt.Sprintf("duration_of: the duration between %s", strings.Join(fieldCfg.DurationOf, " and "))
+	case len(fieldCfg.RatioOf) > 0:
+		explanation = fmt.Sprintf("ratio_of: the value of %s multiplied by %v, with noise", fieldCfg.RatioOf, fieldCfg.Ratio)
+	case len(fieldCfg.RelatedFields) > 0:
+		explanation = fmt.Sprintf("related_fields: the values of %s", strings.Join(fieldCfg.RelatedFields, ", "))
+	case len(fieldCfg.Enum) > 0:
+		explanation = fmt.Sprintf("enum: one of the %d values of the enum", len(fieldCfg.Enum))
+		if len(fieldCfg.Distribution) > 0 {
+			explanation += fmt.Sprintf(", with %s distribution", fieldCfg.Distribution)
+		}
+	case len(fieldCfg.IPPools) > 0:
+		cidrs := make([]string, 0, len(fieldCfg.IPPools))
+		for _, pool := range fieldCfg.IPPools {
+			cidrs = append(cidrs, pool.CIDR)
+		}
+
+		explanation = fmt.Sprintf("ip_pools: an address within one of %s, chosen by weight", strings.Join(cidrs, ", "))
+	case fieldCfg.GeoFormat == config.GeoFormatGeohash:
+		explanation = "geo_format: a random geohash"
+	case fieldCfg.Min != nil || fieldCfg.Max != nil:
+		explanation = fmt.Sprintf("min/max: a random %s value between min and max", field.Type)
+	case fieldCfg.Range.Min != nil || fieldCfg.Range.Max != nil || fieldCfg.Range.From != nil || fieldCfg.Range.To != nil:
+		explanation = fmt.Sprintf("range: a random %s value within the range", field.Type)
+	default:
+		explanation = fmt.Sprintf("type: a random %s value", field.Type)
+	}
+
+	if fieldCfg.Array != nil {
+		explanation += fmt.Sprintf("; array: arrays of %d to %d such values", fieldCfg.Array.Min, fieldCfg.Array.Max)
+	}
+
+	if len(fieldCfg.Entity) > 0 {
+		explanation += fmt.Sprintf("; entity: the same value for the same %s", fieldCfg.Entity)
+	}
+
+	if fieldCfg.Fuzziness > 0 {
+		explanation += fmt.Sprintf("; fuzziness: within %v of the previous value", fieldCfg.Fuzziness)
+	}
+
+	if fieldCfg.FirstOnly {
+		explanation += "; first_only: only in the first document"
+	}
+
+	if fieldCfg.Seed != nil {
+		explanation += fmt.Sprintf("; seed: its own random generator seeded with %d", *fieldCfg.Seed)
+	}
+
+	return explanation
+}
@@ -0,0 +1,68 @@
+package genlib
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/elastic/elastic-integration-corpus-generator-tool/pkg/genlib/config"
+)
+
+func Test_SampleDocument(t *testing.T) {
+	flds := Fields{
+		{Name: "alpha", Type: FieldTypeKeyword},
+		{Name: "beta", Type: FieldTypeKeyword},
+		{Name: "gamma", Type: FieldTypeLong},
+		{Name: "delta", Type: FieldTypeLong},
+		{Name: "epsilon", Type: FieldTypeKeyword},
+		{Name: "zeta", Type: FieldTypeLong},
+	}
+
+	cfg, err := config.LoadConfigFromYaml([]byte("fields:\n  - name: alpha\n    value: a\n  - name: beta\n    enum: [\"b\", \"c\"]\n  - name: gamma\n    range:\n      min: 1\n      max: 10\n  - name: delta\n    ratio_of: gamma\n    ratio: 2\n  - name: epsilon\n    cardinality: 5\n    seed: 42"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	document, explanations, err := SampleDocument(cfg, flds)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var m map[string]any
+	if err := json.Unmarshal(document, &m); err != nil {
+		t.Fatalf("Expected valid JSON, got %s: %s", document, err)
+	}
+
+	if !strings.Contains(string(document), "\n  \"") {
+		t.Errorf("Expected pretty-printed document, got %s", document)
+	}
+
+	expected := map[string]string{
+		"alpha":   "value:",
+		"beta":    "enum:",
+		"gamma":   "range:",
+		"delta":   "ratio_of:",
+		"epsilon": "cardinality:",
+		"zeta":    "type:",
+	}
+
+	for _, field := range flds {
+		if _, ok := m[field.Name]; !ok {
+			t.Errorf("Expected field %s in the document, got %s", field.Name, document)
+		}
+
+		explanation, ok := explanations[field.Name]
+		if !ok {
+			t.Errorf("Expected explanation for field %s", field.Name)
+			continue
+		}
+
+		if !strings.HasPrefix(explanation, expected[field.Name]) {
+			t.Errorf("Expected explanation for field %s starting with %s, got %s", field.Name, expected[field.Name], explanation)
+		}
+	}
+
+	if !strings.Contains(explanations["epsilon"], "seed:") {
+		t.Errorf("Expected seed in the explanation of epsilon, got %s", explanations["epsilon"])
+	}
+}
@@ -0,0 +1,95 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package genlib
+
+import (
+	"bytes"
+	"math/rand"
+
+	"github.com/Pallinder/go-randomdata"
+)
+
+// newGeneratorRand returns a random generator for a generator of its own, seeded from the global one, so that
+// generators don't interleave their draws
+func newGeneratorRand() *rand.Rand {
+	return rand.New(rand.NewSource(customRand.Int63()))
+}
+
+// useRand replaces the random generator the fields are bound with by r, returning a function restoring the previous one
+func useRand(r *rand.Rand) func() {
+	previous := customRand
+	customRand = r
+	randomdata.CustomRand(r)
+
+	return func() {
+		customRand = previous
+		randomdata.CustomRand(previous)
+	}
+}
+
+// useRand replaces the random generator of state with r, returning a function restoring the previous one
+func (state *genState) useRand(r *rand.Rand) func() {
+	previous := state.rand
+	state.rand = r
+	randomdata.CustomRand(r)
+
+	return func() {
+		state.rand = previous
+		randomdata.CustomRand(previous)
+	}
+}
+
+// useRandomdata points randomdata, that draws from a package global random generator, to the random generator of
+// state: called before generating an event, so that generators don't interleave their randomdata draws
+func (state *genState) useRandomdata() {
+	randomdata.CustomRand(state.rand)
+}
+
+// bindWithSeed calls bind with a random generator of its own, seeded with seed, instead of the global one.
+// The fields bound by bind are wrapped so that they keep using that generator when emitted, instead of the one of
+// the state: their values don't depend on the global seed nor on the other fields.
+func bindWithSeed(seed int64, fieldMap map[string]any, bind func() error) error {
+	fieldRand := rand.New(rand.NewSource(seed))
+
+	bound := make(map[string]struct{}, len(fieldMap))
+	for fieldName := range fieldMap {
+		bound[fieldName] = struct{}{}
+	}
+
+	restore := useRand(fieldRand)
+	err := bind()
+	restore()
+
+	if err != nil {
+		return err
+	}
+
+	for fieldName, f := range fieldMap {
+		if _, ok := bound[fieldName]; ok {
+			continue
+		}
+
+		switch boundF := f.(type) {
+		case emitF:
+			var emitF emitF
+			emitF = func(state *genState) any {
+				defer state.useRand(fieldRand)()
+				return boundF(state)
+			}
+
+			fieldMap[fieldName] = emitF
+		case emitFNotReturn:
+			var emitFNotReturn emitFNotReturn
+			emitFNotReturn = func(state *genState, buf *bytes.Buffer) error {
+				defer state.useRand(fieldRand)()
+				return boundF(state, buf)
+			}
+
+			fieldMap[fieldName] = emitFNotReturn
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,42 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package genlib
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// bindSequence binds a field emitting the values of `sequence` in order, cycling: event i gets sequence[i % len]
+func bindSequence(fieldCfg ConfigField, field Field, fieldMap map[string]any) error {
+	values := make([][]byte, 0, len(fieldCfg.Sequence))
+	for _, v := range fieldCfg.Sequence {
+		vstr, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+
+		values = append(values, vstr)
+	}
+
+	var emitFNotReturn emitFNotReturn
+	emitFNotReturn = func(state *genState, buf *bytes.Buffer) error {
+		buf.Write(values[state.counter%uint64(len(values))])
+		return nil
+	}
+
+	fieldMap[field.Name] = emitFNotReturn
+	return nil
+}
+
+func bindSequenceWithReturn(fieldCfg ConfigField, field Field, fieldMap map[string]any) error {
+	var emitF emitF
+	emitF = func(state *genState) any {
+		return fieldCfg.Sequence[state.counter%uint64(len(fieldCfg.Sequence))]
+	}
+
+	fieldMap[field.Name] = emitF
+	return nil
+}
@@ -0,0 +1,162 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package genlib
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// seriesDimension is a field distinguishing the series of a multi-series corpus, with the values it cycles through
+type seriesDimension struct {
+	name   string
+	values []string
+	// stride is the number of consecutive events with the same value of the dimension
+	stride uint64
+}
+
+// seriesDimensions returns the `dimension` fields: the events cycle through all the combinations of their values,
+// the last dimension changing at every event
+func seriesDimensions(cfg Config, fields Fields) ([]seriesDimension, error) {
+	var dimensions []seriesDimension
+	for _, field := range fields {
+		fieldCfg, _ := cfg.GetField(field.Name)
+		if !fieldCfg.Dimension {
+			continue
+		}
+
+		if len(fieldCfg.Enum) == 0 {
+			return nil, fmt.Errorf("dimension field %s requires an enum of its values", field.Name)
+		}
+
+		dimensions = append(dimensions, seriesDimension{name: field.Name, values: fieldCfg.Enum})
+	}
+
+	stride := uint64(1)
+	for i := len(dimensions) - 1; i >= 0; i-- {
+		dimensions[i].stride = stride
+		stride *= uint64(len(dimensions[i].values))
+	}
+
+	return dimensions, nil
+}
+
+// seriesLength returns the number of series, the combinations of the values of the `dimension` fields, 0 without any
+func seriesLength(cfg Config, fields Fields) uint64 {
+	dimensions, err := seriesDimensions(cfg, fields)
+	if err != nil || len(dimensions) == 0 {
+		return 0
+	}
+
+	return dimensions[0].stride * uint64(len(dimensions[0].values))
+}
+
+// value returns the value of the dimension for the current event
+func (d seriesDimension) value(state *genState) string {
+	return d.values[(state.counter/d.stride)%uint64(len(d.values))]
+}
+
+// alignedStep returns the step of `aligned` dates for the current event: with multiple series all the combinations
+// of the dimension values are emitted at each step, once each
+func (state *genState) alignedStep() uint64 {
+	if state.seriesLength > 1 {
+		return state.counter / state.seriesLength
+	}
+
+	return state.counter
+}
+
+// seriesPrevValue swaps the previous value of the field, kept for its fuzziness, with the one of the series of the
+// current event, so that each series has its own trajectory. It returns a function to call once the value of the
+// event is generated, keeping it for the series.
+func (state *genState) seriesPrevValue(fieldName string) func() {
+	series := state.counter % state.seriesLength
+	if prevs, ok := state.seriesPrevCache[fieldName]; ok {
+		if prev, ok := prevs[series]; ok {
+			state.prevCache[fieldName] = prev
+		} else {
+			delete(state.prevCache, fieldName)
+		}
+	} else {
+		state.seriesPrevCache[fieldName] = make(map[uint64]any)
+		delete(state.prevCache, fieldName)
+	}
+
+	return func() {
+		state.seriesPrevCache[fieldName][series] = state.prevCache[fieldName]
+	}
+}
+
+// bindSeries binds the `dimension` fields, cycling through all the combinations of their values, and wraps the
+// fields with `fuzziness`, so that each series has its own trajectory
+func bindSeries(cfg Config, fields Fields, fieldMap map[string]any, withReturn bool) error {
+	dimensions, err := seriesDimensions(cfg, fields)
+	if err != nil || len(dimensions) == 0 {
+		return err
+	}
+
+	isDimension := make(map[string]struct{}, len(dimensions))
+	for _, dimension := range dimensions {
+		dimension := dimension
+		isDimension[dimension.name] = struct{}{}
+
+		if withReturn {
+			var emitF emitF
+			emitF = func(state *genState) any {
+				return dimension.value(state)
+			}
+
+			fieldMap[dimension.name] = emitF
+			continue
+		}
+
+		var emitFNotReturn emitFNotReturn
+		emitFNotReturn = func(state *genState, buf *bytes.Buffer) error {
+			buf.WriteString(dimension.value(state))
+			return nil
+		}
+
+		fieldMap[dimension.name] = emitFNotReturn
+	}
+
+	for _, field := range fields {
+		fieldCfg, _ := cfg.GetField(field.Name)
+		if _, ok := isDimension[field.Name]; ok || fieldCfg.Fuzziness <= 0 {
+			continue
+		}
+
+		fieldName := field.Name
+		if withReturn {
+			boundF, ok := fieldMap[fieldName].(emitF)
+			if !ok {
+				return fmt.Errorf("cannot bind field %s per series", fieldName)
+			}
+
+			var emitF emitF
+			emitF = func(state *genState) any {
+				defer state.seriesPrevValue(fieldName)()
+				return boundF(state)
+			}
+
+			fieldMap[fieldName] = emitF
+			continue
+		}
+
+		boundF, ok := fieldMap[fieldName].(emitFNotReturn)
+		if !ok {
+			return fmt.Errorf("cannot bind field %s per series", fieldName)
+		}
+
+		var emitFNotReturn emitFNotReturn
+		emitFNotReturn = func(state *genState, buf *bytes.Buffer) error {
+			defer state.seriesPrevValue(fieldName)()
+			return boundF(state, buf)
+		}
+
+		fieldMap[fieldName] = emitFNotReturn
+	}
+
+	return nil
+}
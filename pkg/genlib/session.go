@@ -0,0 +1,66 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package genlib
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+)
+
+// defaultSessionLength is the number of events of a session when `session_length` is not set
+const defaultSessionLength = 10
+
+// session is the generated value of a `session` field
+type session struct {
+	id       string
+	step     int64
+	userName string
+}
+
+func newSession(r *rand.Rand) *session {
+	pool := personNamePools[defaultPersonNameLocale]
+	givenName := pool.givenNames[r.Intn(len(pool.givenNames))]
+	familyName := pool.familyNames[r.Intn(len(pool.familyNames))]
+
+	return &session{
+		id:       randStringFromAlphabet(r, hexAlphabet, 32),
+		userName: fmt.Sprintf("%s.%s%d", strings.ToLower(givenName), strings.ToLower(familyName), r.Intn(100)),
+	}
+}
+
+func sessionLength(fieldCfg ConfigField, field Field) (int64, error) {
+	if fieldCfg.SessionLength < 0 {
+		return 0, fmt.Errorf("field %s has a negative session_length", field.Name)
+	}
+
+	if fieldCfg.SessionLength == 0 {
+		return defaultSessionLength, nil
+	}
+
+	return int64(fieldCfg.SessionLength), nil
+}
+
+// sessionForEvent returns the session of a `session` field for the current event: consecutive events belong to
+// the same session, with the same id and user and increasing step, until length events are emitted and a new
+// session starts.
+func sessionForEvent(fieldName string, length int64, state *genState) session {
+	if value, ok := state.eventValue(fieldName); ok {
+		return value.(session)
+	}
+
+	current, ok := state.prevCache[fieldName].(*session)
+	if !ok || current.step >= length {
+		current = newSession(state.rand)
+		state.prevCache[fieldName] = current
+	}
+
+	current.step += 1
+
+	s := *current
+	state.setEventValue(fieldName, s)
+
+	return s
+}
@@ -0,0 +1,162 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package genlib
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+
+	"github.com/Pallinder/go-randomdata"
+	"github.com/elastic/elastic-integration-corpus-generator-tool/pkg/genlib/config"
+)
+
+// SQL statement types, the keys of `sql_statement_weights`
+const (
+	sqlStatementSelect = "select"
+	sqlStatementInsert = "insert"
+	sqlStatementUpdate = "update"
+	sqlStatementDelete = "delete"
+)
+
+// sqlStatementTypes are the SQL statement types in the order their weights are applied
+var sqlStatementTypes = []string{sqlStatementSelect, sqlStatementInsert, sqlStatementUpdate, sqlStatementDelete}
+
+// defaultSQLTables are the tables statements are generated for when no `sql_tables` is set for a `sql_statement` field
+var defaultSQLTables = []config.SQLTable{
+	{Name: "users", Columns: []string{"id", "username", "email", "country"}},
+	{Name: "orders", Columns: []string{"id", "user_id", "status", "currency"}},
+	{Name: "products", Columns: []string{"id", "name", "category", "sku"}},
+	{Name: "sessions", Columns: []string{"id", "user_id", "token", "user_agent"}},
+}
+
+// defaultSQLStatementWeights are the weights of the SQL statement types when no `sql_statement_weights` is set
+var defaultSQLStatementWeights = map[string]float64{
+	sqlStatementSelect: 0.7,
+	sqlStatementInsert: 0.15,
+	sqlStatementUpdate: 0.1,
+	sqlStatementDelete: 0.05,
+}
+
+// makeSQLStatementFunc returns a function generating SQL statements for the `sql_tables` of the field,
+// with their type chosen according to `sql_statement_weights`
+func makeSQLStatementFunc(fieldCfg ConfigField) (func(r *rand.Rand) string, error) {
+	tables := fieldCfg.SQLTables
+	if len(tables) == 0 {
+		tables = defaultSQLTables
+	}
+
+	for _, table := range tables {
+		if len(table.Name) == 0 || len(table.Columns) == 0 {
+			return nil, fmt.Errorf("sql table must have a name and at least one column: %v", table)
+		}
+	}
+
+	weights := fieldCfg.SQLStatementWeights
+	if len(weights) == 0 {
+		weights = defaultSQLStatementWeights
+	}
+
+	var totWeight float64
+	for statementType, weight := range weights {
+		if _, ok := sqlStatementFormatters[statementType]; !ok {
+			return nil, fmt.Errorf("unknown sql statement type: %s", statementType)
+		}
+
+		if weight < 0 {
+			return nil, fmt.Errorf("negative weight for sql statement type: %s", statementType)
+		}
+
+		totWeight += weight
+	}
+
+	if totWeight <= 0 {
+		return nil, fmt.Errorf("sql statement weights must not be all zero")
+	}
+
+	// fallback for rounding errors when drawing the statement type
+	var lastStatementType string
+	for _, statementType := range sqlStatementTypes {
+		if weights[statementType] > 0 {
+			lastStatementType = statementType
+		}
+	}
+
+	return func(r *rand.Rand) string {
+		table := tables[r.Intn(len(tables))]
+
+		statementType := lastStatementType
+		v := r.Float64() * totWeight
+		for _, t := range sqlStatementTypes {
+			if v < weights[t] {
+				statementType = t
+				break
+			}
+
+			v -= weights[t]
+		}
+
+		return sqlStatementFormatters[statementType](r, table)
+	}, nil
+}
+
+var sqlStatementFormatters = map[string]func(r *rand.Rand, table config.SQLTable) string{
+	sqlStatementSelect: func(r *rand.Rand, table config.SQLTable) string {
+		columns := "*"
+		if r.Intn(2) == 0 {
+			columns = strings.Join(randSQLColumns(r, table), ", ")
+		}
+
+		column := randSQLColumn(r, table)
+		return "SELECT " + columns + " FROM " + table.Name + " WHERE " + column + " = " + randSQLValue(r, column)
+	},
+	sqlStatementInsert: func(r *rand.Rand, table config.SQLTable) string {
+		columns := randSQLColumns(r, table)
+		values := make([]string, 0, len(columns))
+		for _, column := range columns {
+			values = append(values, randSQLValue(r, column))
+		}
+
+		return "INSERT INTO " + table.Name + " (" + strings.Join(columns, ", ") + ") VALUES (" + strings.Join(values, ", ") + ")"
+	},
+	sqlStatementUpdate: func(r *rand.Rand, table config.SQLTable) string {
+		column, whereColumn := randSQLColumn(r, table), randSQLColumn(r, table)
+		return "UPDATE " + table.Name + " SET " + column + " = " + randSQLValue(r, column) + " WHERE " + whereColumn + " = " + randSQLValue(r, whereColumn)
+	},
+	sqlStatementDelete: func(r *rand.Rand, table config.SQLTable) string {
+		column := randSQLColumn(r, table)
+		return "DELETE FROM " + table.Name + " WHERE " + column + " = " + randSQLValue(r, column)
+	},
+}
+
+func randSQLColumn(r *rand.Rand, table config.SQLTable) string {
+	return table.Columns[r.Intn(len(table.Columns))]
+}
+
+// randSQLColumns returns a non-empty subset of the columns of the table, in their order
+func randSQLColumns(r *rand.Rand, table config.SQLTable) []string {
+	columns := make([]string, 0, len(table.Columns))
+	for _, column := range table.Columns {
+		if r.Intn(2) == 0 {
+			columns = append(columns, column)
+		}
+	}
+
+	if len(columns) == 0 {
+		columns = append(columns, randSQLColumn(r, table))
+	}
+
+	return columns
+}
+
+// randSQLValue returns a literal for the column: a number for identifiers, a quoted word otherwise
+func randSQLValue(r *rand.Rand, column string) string {
+	if column == "id" || strings.HasSuffix(column, "_id") {
+		return strconv.Itoa(r.Intn(100000) + 1)
+	}
+
+	return "'" + randomdata.Noun() + "'"
+}
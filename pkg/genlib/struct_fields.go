@@ -0,0 +1,182 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package genlib
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/elastic/elastic-integration-corpus-generator-tool/pkg/genlib/config"
+)
+
+// structFieldsTag is the tag of the struct fields FieldsFromStruct derives the fields and their config from
+const structFieldsTag = "gen"
+
+var notAStruct = errors.New("FieldsFromStruct requires a struct or a pointer to a struct")
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// FieldsFromStruct returns the fields, and their config, derived from the exported fields of the struct v, for quick
+// prototyping. Each field is described by its `gen` tag: the field type, optionally followed by comma separated
+// options (ie: `gen:"keyword,cardinality=10"`):
+//   - `name=<name>`: the dotted path of the field, defaulting to the name in the `json` tag, or the name of the struct field
+//   - `cardinality=<n>`, `fuzziness=<f>`, `min=<f>`, `max=<f>`, `pattern=<regexp>` and `value=<value>`
+//   - `enum=<a|b|c>`: the values of the `enum`, separated by `|`
+//   - `unique`
+//
+// Option values can't contain commas. Without a type, it is inferred from the kind of the struct field: `keyword` for
+// strings, `boolean` for bools, `long` for integers, `unsigned_long` for unsigned integers, `double` for floats and
+// `date` for time.Time. Struct fields of struct type without a type are walked, their fields prefixed by the name of
+// the struct field (ie: `source.ip`).
+// Struct fields tagged `gen:"-"` are skipped.
+func FieldsFromStruct(v interface{}) (Fields, Config, error) {
+	t := reflect.TypeOf(v)
+	if t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, Config{}, notAStruct
+	}
+
+	var flds Fields
+	var fieldsCfg []ConfigField
+	if err := collectStructFields(t, "", &flds, &fieldsCfg); err != nil {
+		return nil, Config{}, err
+	}
+
+	cfg, err := config.NewConfig(fieldsCfg)
+	if err != nil {
+		return nil, Config{}, err
+	}
+
+	return flds, cfg, nil
+}
+
+func collectStructFields(t reflect.Type, prefix string, flds *Fields, fieldsCfg *[]ConfigField) error {
+	for i := 0; i < t.NumField(); i++ {
+		structField := t.Field(i)
+		tag, hasTag := structField.Tag.Lookup(structFieldsTag)
+		if !structField.IsExported() || tag == "-" {
+			continue
+		}
+
+		options := strings.Split(tag, ",")
+		fieldType := options[0]
+
+		name := structField.Name
+		if jsonName, _, _ := strings.Cut(structField.Tag.Get("json"), ","); len(jsonName) > 0 && jsonName != "-" {
+			name = jsonName
+		}
+
+		fieldCfg := ConfigField{}
+		for _, option := range options[1:] {
+			key, value, _ := strings.Cut(option, "=")
+			if key == "name" {
+				name = value
+				continue
+			}
+
+			if err := setStructFieldOption(&fieldCfg, key, value); err != nil {
+				return fmt.Errorf("struct field %s: %w", structField.Name, err)
+			}
+		}
+
+		if len(prefix) > 0 {
+			name = prefix + "." + name
+		}
+
+		goType := structField.Type
+		if goType.Kind() == reflect.Pointer {
+			goType = goType.Elem()
+		}
+
+		if len(fieldType) == 0 && goType.Kind() == reflect.Struct && goType != timeType {
+			if hasTag && len(options) > 1 {
+				return fmt.Errorf("struct field %s: options of a struct require a field type", structField.Name)
+			}
+
+			if err := collectStructFields(goType, name, flds, fieldsCfg); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if len(fieldType) == 0 {
+			fieldType = fieldTypeOfKind(goType)
+			if len(fieldType) == 0 {
+				return fmt.Errorf("struct field %s: cannot infer the field type of %s", structField.Name, goType)
+			}
+		}
+
+		*flds = append(*flds, Field{Name: name, Type: fieldType})
+
+		fieldCfg.Name = name
+		*fieldsCfg = append(*fieldsCfg, fieldCfg)
+	}
+
+	return nil
+}
+
+// fieldTypeOfKind returns the field type of a struct field of type t, empty if it cannot be inferred
+func fieldTypeOfKind(t reflect.Type) string {
+	if t == timeType {
+		return FieldTypeDate
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return FieldTypeKeyword
+	case reflect.Bool:
+		return FieldTypeBool
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return FieldTypeLong
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return FieldTypeUnsignedLong
+	case reflect.Float32, reflect.Float64:
+		return FieldTypeDouble
+	default:
+		return ""
+	}
+}
+
+func setStructFieldOption(fieldCfg *ConfigField, key, value string) error {
+	var err error
+	switch key {
+	case "cardinality":
+		fieldCfg.Cardinality, err = strconv.Atoi(value)
+	case "fuzziness":
+		fieldCfg.Fuzziness, err = strconv.ParseFloat(value, 64)
+	case "min", "max":
+		var bound float64
+		bound, err = strconv.ParseFloat(value, 64)
+		if key == "min" {
+			fieldCfg.Min = &bound
+		} else {
+			fieldCfg.Max = &bound
+		}
+	case "enum":
+		fieldCfg.Enum = strings.Split(value, "|")
+	case "unique":
+		fieldCfg.Unique = true
+	case "pattern":
+		fieldCfg.Pattern = value
+	case "value":
+		fieldCfg.Value = value
+	default:
+		return fmt.Errorf("unknown option %s", key)
+	}
+
+	if err != nil {
+		return fmt.Errorf("option %s: %w", key, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,116 @@
+package genlib
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type structFieldsSource struct {
+	IP   string `json:"ip" gen:"ip,cardinality=10"`
+	Port int    `gen:"long,name=port,min=1024,max=65535"`
+}
+
+type structFieldsEvent struct {
+	Timestamp time.Time `json:"@timestamp"`
+	Action    string    `gen:"keyword,name=event.action,enum=allow|deny"`
+	Bytes     int64     `gen:",fuzziness=0.1,min=0,max=10000"`
+	Packets   uint32
+	Ratio     float64
+	Success   bool
+	ID        string             `gen:"keyword,pattern=eni-[0-9a-f]{17},unique"`
+	Source    structFieldsSource `json:"source"`
+	Ignored   string             `gen:"-"`
+	internal  string
+}
+
+func Test_FieldsFromStruct(t *testing.T) {
+	flds, cfg, err := FieldsFromStruct(&structFieldsEvent{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedFields := Fields{
+		{Name: "@timestamp", Type: FieldTypeDate},
+		{Name: "event.action", Type: FieldTypeKeyword},
+		{Name: "Bytes", Type: FieldTypeLong},
+		{Name: "Packets", Type: FieldTypeUnsignedLong},
+		{Name: "Ratio", Type: FieldTypeDouble},
+		{Name: "Success", Type: FieldTypeBool},
+		{Name: "ID", Type: FieldTypeKeyword},
+		{Name: "source.ip", Type: FieldTypeIP},
+		{Name: "source.port", Type: FieldTypeLong},
+	}
+
+	if !reflect.DeepEqual(expectedFields, flds) {
+		t.Errorf("Expected fields %v, got %v", expectedFields, flds)
+	}
+
+	action, _ := cfg.GetField("event.action")
+	if !reflect.DeepEqual([]string{"allow", "deny"}, action.Enum) {
+		t.Errorf("Expected enum of allow and deny, got %v", action.Enum)
+	}
+
+	bytesCfg, _ := cfg.GetField("Bytes")
+	if bytesCfg.Fuzziness != 0.1 || *bytesCfg.Min != 0 || *bytesCfg.Max != 10000 {
+		t.Errorf("Expected fuzziness 0.1, min 0 and max 10000, got %+v", bytesCfg)
+	}
+
+	id, _ := cfg.GetField("ID")
+	if id.Pattern != "eni-[0-9a-f]{17}" || !id.Unique {
+		t.Errorf("Expected unique pattern, got %+v", id)
+	}
+
+	ip, _ := cfg.GetField("source.ip")
+	if ip.Cardinality != 10 {
+		t.Errorf("Expected cardinality 10, got %d", ip.Cardinality)
+	}
+
+	port, _ := cfg.GetField("source.port")
+	if *port.Min != 1024 || *port.Max != 65535 {
+		t.Errorf("Expected min 1024 and max 65535, got %+v", port)
+	}
+
+	// the derived fields and config generate documents
+	g, err := NewGenerator(cfg, flds, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 10; i++ {
+		var buf bytes.Buffer
+		if err := g.Emit(&buf); err != nil {
+			t.Fatal(err)
+		}
+
+		m := unmarshalJSONT[any](t, buf.Bytes())
+		if value := m["event.action"]; value != "allow" && value != "deny" {
+			t.Errorf("Expected event.action allow or deny, got %v", value)
+		}
+	}
+}
+
+func Test_FieldsFromStructInvalid(t *testing.T) {
+	if _, _, err := FieldsFromStruct("not a struct"); err == nil {
+		t.Errorf("Expected error for a string")
+	}
+
+	if _, _, err := FieldsFromStruct(struct {
+		Alpha string `gen:"keyword,weight=2"`
+	}{}); err == nil {
+		t.Errorf("Expected error for an unknown option")
+	}
+
+	if _, _, err := FieldsFromStruct(struct {
+		Alpha int `gen:"long,cardinality=ten"`
+	}{}); err == nil {
+		t.Errorf("Expected error for a not numeric cardinality")
+	}
+
+	if _, _, err := FieldsFromStruct(struct {
+		Alpha []string
+	}{}); err == nil {
+		t.Errorf("Expected error for a field type that cannot be inferred")
+	}
+}
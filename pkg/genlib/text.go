@@ -0,0 +1,66 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package genlib
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand"
+)
+
+// default number of words of `text` fields when `words` is not set
+const (
+	defaultTextMinWords = 1
+	defaultTextMaxWords = 25
+)
+
+// bounds of the number of words of the sentences of `text` fields with `punctuation`
+const (
+	textSentenceMinWords = 4
+	textSentenceMaxWords = 12
+)
+
+// textWords returns the min and max number of words of the values of a `text` field
+func textWords(fieldCfg ConfigField, field Field) (int, int, error) {
+	if fieldCfg.Words == nil {
+		return defaultTextMinWords, defaultTextMaxWords, nil
+	}
+
+	if fieldCfg.Words.Min < 1 || fieldCfg.Words.Max < fieldCfg.Words.Min {
+		return 0, 0, fmt.Errorf("field %s must have words min of at least 1 and not greater than max", field.Name)
+	}
+
+	return fieldCfg.Words.Min, fieldCfg.Words.Max, nil
+}
+
+// genText writes between minWords and maxWords words separated by a single space. With punctuation the words are
+// grouped in sentences, starting with a capital letter and ending with a full stop.
+func genText(r *rand.Rand, minWords, maxWords int, punctuation bool, buf *bytes.Buffer) {
+	n := minWords + r.Intn(maxWords-minWords+1)
+	if !punctuation {
+		genNounsN(n, buf)
+		return
+	}
+
+	for n > 0 {
+		sentenceWords := textSentenceMinWords + r.Intn(textSentenceMaxWords-textSentenceMinWords+1)
+		if sentenceWords > n {
+			sentenceWords = n
+		}
+
+		start := buf.Len()
+		genNounsN(sentenceWords, buf)
+		if c := buf.Bytes()[start]; c >= 'a' && c <= 'z' {
+			buf.Bytes()[start] = c - 'a' + 'A'
+		}
+
+		buf.WriteByte('.')
+
+		n -= sentenceWords
+		if n > 0 {
+			buf.WriteByte(' ')
+		}
+	}
+}
@@ -0,0 +1,94 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package genlib
+
+import "fmt"
+
+// tlsVersionProtocol is the protocol of the generated TLS versions
+const tlsVersionProtocol = "tls"
+
+// tlsCiphers are, for each TLS version, the IANA names of the cipher suites that can be negotiated with it:
+// TLS 1.3 has its own suites, that cannot be negotiated with older versions, and the other way around
+var tlsCiphers = map[string][]string{
+	"1.0": {
+		"TLS_RSA_WITH_AES_128_CBC_SHA",
+		"TLS_RSA_WITH_AES_256_CBC_SHA",
+		"TLS_ECDHE_RSA_WITH_AES_128_CBC_SHA",
+		"TLS_RSA_WITH_3DES_EDE_CBC_SHA",
+	},
+	"1.1": {
+		"TLS_RSA_WITH_AES_128_CBC_SHA",
+		"TLS_RSA_WITH_AES_256_CBC_SHA",
+		"TLS_ECDHE_RSA_WITH_AES_128_CBC_SHA",
+		"TLS_ECDHE_RSA_WITH_AES_256_CBC_SHA",
+	},
+	"1.2": {
+		"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256",
+		"TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384",
+		"TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256",
+		"TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384",
+		"TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305_SHA256",
+		"TLS_RSA_WITH_AES_128_GCM_SHA256",
+	},
+	"1.3": {
+		"TLS_AES_128_GCM_SHA256",
+		"TLS_AES_256_GCM_SHA384",
+		"TLS_CHACHA20_POLY1305_SHA256",
+	},
+}
+
+// defaultTLSVersions are the TLS versions chosen from when no `enum` is set
+var defaultTLSVersions = []string{"1.2", "1.3"}
+
+// tls is the generated value of a `tls` field
+type tls struct {
+	version    string
+	cipher     string
+	commonName string
+}
+
+func tlsVersions(fieldCfg ConfigField) ([]string, error) {
+	if len(fieldCfg.Enum) == 0 {
+		return defaultTLSVersions, nil
+	}
+
+	for _, version := range fieldCfg.Enum {
+		if _, ok := tlsCiphers[version]; !ok {
+			return nil, fmt.Errorf("unknown TLS version: %s", version)
+		}
+	}
+
+	return fieldCfg.Enum, nil
+}
+
+// tlsForEvent returns the version, cipher and server certificate common name of a `tls` field for the current event,
+// so that the cipher is valid for the version regardless of the order they are emitted.
+func tlsForEvent(fieldName string, versions []string, state *genState) tls {
+	if value, ok := state.eventValue(fieldName); ok {
+		return value.(tls)
+	}
+
+	version := versions[state.rand.Intn(len(versions))]
+	ciphers := tlsCiphers[version]
+	t := tls{
+		version:    version,
+		cipher:     ciphers[state.rand.Intn(len(ciphers))],
+		commonName: randHostname(state.rand),
+	}
+
+	state.setEventValue(fieldName, t)
+
+	return t
+}
+
+// tlsX509Field returns the field the validity dates of the server certificate of a `tls` field are bound as
+func tlsX509Field(field Field) Field {
+	return Field{Name: field.Name + tlsServerX509Suffix, Type: FieldTypeValidity}
+}
+
+// isTLSDateField returns whether fieldName is one of the validity dates of the server certificate of a `tls` field
+func isTLSDateField(field Field, fieldName string) bool {
+	return field.Type == FieldTypeTLS && (fieldName == field.Name+tlsServerX509Suffix+validityNotBeforeSuffix || fieldName == field.Name+tlsServerX509Suffix+validityNotAfterSuffix)
+}
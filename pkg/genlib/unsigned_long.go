@@ -0,0 +1,68 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package genlib
+
+import (
+	"math"
+	"math/rand"
+)
+
+// makeUnsignedLongFunc returns a function generating unsigned_long values: within `range`, up to math.MaxUint64,
+// if any of `min` and `max` is set, otherwise like the values of long fields
+func makeUnsignedLongFunc(fieldCfg ConfigField, field Field) func(r *rand.Rand) uint64 {
+	minValue, errMin := fieldCfg.Range.MinAsUint64()
+	maxValue, errMax := fieldCfg.Range.MaxAsUint64()
+	if errMin != nil && errMax != nil {
+		intFunc := makeIntFunc(fieldCfg, field)
+		return func(r *rand.Rand) uint64 {
+			return uint64(intFunc(r))
+		}
+	}
+
+	return func(r *rand.Rand) uint64 {
+		return minValue + randUint64Inclusive(r, maxValue-minValue)
+	}
+}
+
+// randUint64Inclusive returns a random value in [0, n], so that n can be math.MaxUint64
+func randUint64Inclusive(r *rand.Rand, n uint64) uint64 {
+	if n == math.MaxUint64 {
+		return r.Uint64()
+	}
+
+	n++
+	// the values below 2^64 mod n are rejected, so that the remaining ones are a multiple of n and the modulo is uniform
+	threshold := -n % n
+	for {
+		if v := r.Uint64(); v >= threshold {
+			return v % n
+		}
+	}
+}
+
+// fuzzyUnsignedLong returns a value within `fuzziness` of previous, and within min and max
+func fuzzyUnsignedLong(r *rand.Rand, previous uint64, fuzziness float64, min, max uint64) uint64 {
+	// the delta is at most previous, so that it doesn't overflow when converted back from float64
+	delta := previous
+	if deltaFloat := float64(previous) * fuzziness; deltaFloat < float64(previous) {
+		delta = uint64(deltaFloat)
+	}
+
+	lowerBound := previous - delta
+	if lowerBound < min {
+		lowerBound = min
+	}
+
+	higherBound := max
+	if delta < max-previous {
+		higherBound = previous + delta
+	}
+
+	if lowerBound >= higherBound {
+		return lowerBound
+	}
+
+	return lowerBound + randUint64Inclusive(r, higherBound-lowerBound)
+}
@@ -0,0 +1,98 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package genlib
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// defaultUserPoolSize is the number of distinct users of a `user` field when `user_pool_size` is not set
+const defaultUserPoolSize = 100
+
+// userFirstID is the id of the first user of the pool, like the first regular user of most Linux distributions
+const userFirstID = 1000
+
+// userGroups are the names of the groups the users belong to
+var userGroups = []string{
+	"admins",
+	"developers",
+	"devops",
+	"finance",
+	"hr",
+	"legal",
+	"marketing",
+	"sales",
+	"security",
+	"support",
+}
+
+// user is the generated value of a `user` field
+type user struct {
+	id        string
+	name      string
+	groupName string
+}
+
+// userPoolSize returns the number of distinct users of the field: it can't exceed the number of users whose
+// attributes are remembered, so that a user is never generated anew with a different name or group
+func userPoolSize(fieldCfg ConfigField, field Field) (int, error) {
+	if fieldCfg.UserPoolSize < 0 {
+		return 0, fmt.Errorf("field %s has a negative user_pool_size", field.Name)
+	}
+
+	if fieldCfg.EntityCacheSize < 0 {
+		return 0, fmt.Errorf("field %s has a negative entity_cache_size", field.Name)
+	}
+
+	poolSize := fieldCfg.UserPoolSize
+	if poolSize == 0 {
+		poolSize = defaultUserPoolSize
+	}
+
+	if poolSize > userCacheSize(fieldCfg) {
+		return 0, fmt.Errorf("field %s has a user_pool_size greater than its entity_cache_size", field.Name)
+	}
+
+	return poolSize, nil
+}
+
+func userCacheSize(fieldCfg ConfigField) int {
+	if fieldCfg.EntityCacheSize == 0 {
+		return defaultEntityCacheSize
+	}
+
+	return fieldCfg.EntityCacheSize
+}
+
+// userForEvent returns the id, name and group name of a `user` field for the current event. The name and group are
+// attributes of the user id in the entity attribute store, so that the same id always has the same name and group.
+func userForEvent(fieldName string, fieldCfg ConfigField, poolSize int, state *genState) user {
+	if value, ok := state.eventValue(fieldName); ok {
+		return value.(user)
+	}
+
+	id := strconv.Itoa(userFirstID + state.rand.Intn(poolSize))
+	attributes := state.entityAttributes(fieldName, userCacheSize(fieldCfg), id)
+	if _, ok := attributes[fieldName+userNameSuffix]; !ok {
+		pool := personNamePools[defaultPersonNameLocale]
+		givenName := pool.givenNames[state.rand.Intn(len(pool.givenNames))]
+		familyName := pool.familyNames[state.rand.Intn(len(pool.familyNames))]
+
+		attributes[fieldName+userNameSuffix] = fmt.Sprintf("%s.%s", strings.ToLower(givenName), strings.ToLower(familyName))
+		attributes[fieldName+userGroupNameSuffix] = userGroups[state.rand.Intn(len(userGroups))]
+	}
+
+	u := user{
+		id:        id,
+		name:      attributes[fieldName+userNameSuffix].(string),
+		groupName: attributes[fieldName+userGroupNameSuffix].(string),
+	}
+
+	state.setEventValue(fieldName, u)
+
+	return u
+}
@@ -0,0 +1,39 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package genlib
+
+import (
+	"fmt"
+	"time"
+)
+
+// defaultValidity is the window between the dates of a `validity` field when `validity` is not set
+const defaultValidity = 365 * 24 * time.Hour
+
+// validityWindow returns the configured `validity` of a `validity` field, defaulting to defaultValidity
+func validityWindow(fieldCfg ConfigField, field Field) (time.Duration, error) {
+	if fieldCfg.Validity < 0 {
+		return 0, fmt.Errorf("field %s validity must be positive", field.Name)
+	}
+
+	if fieldCfg.Validity == 0 {
+		return defaultValidity, nil
+	}
+
+	return fieldCfg.Validity, nil
+}
+
+// validityNotBeforeForEvent returns the start of the validity of a `validity` field for the current event,
+// so that `not_before` and `not_after` are consistent regardless of the order they are emitted.
+func validityNotBeforeForEvent(fieldName string, fieldCfg ConfigField, state *genState) time.Time {
+	if value, ok := state.eventValue(fieldName); ok {
+		return value.(time.Time)
+	}
+
+	notBefore := nearTime(fieldCfg, state)
+	state.setEventValue(fieldName, notBefore)
+
+	return notBefore
+}
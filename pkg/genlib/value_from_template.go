@@ -0,0 +1,241 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package genlib
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// valueTemplateReference matches the references to other fields in a `value_from_template` (ie: `{{.url.domain}}`)
+var valueTemplateReference = regexp.MustCompile(`{{\s*\.([^{}\s]+)\s*}}`)
+
+// valueTemplatePart is either a literal or a reference to another field of a `value_from_template`
+type valueTemplatePart struct {
+	literal   string
+	fieldName string
+}
+
+// valueTemplateReferencedField is a field referenced by a `value_from_template`, with the config its values are
+// rendered with in text templates
+type valueTemplateReferencedField struct {
+	field    Field
+	fieldCfg ConfigField
+	layout   string
+}
+
+// parseValueTemplate splits a `value_from_template` in its literals and references to other fields
+func parseValueTemplate(template string) ([]valueTemplatePart, error) {
+	var parts []valueTemplatePart
+	var start int
+	for _, loc := range valueTemplateReference.FindAllStringSubmatchIndex(template, -1) {
+		if loc[0] > start {
+			parts = append(parts, valueTemplatePart{literal: template[start:loc[0]]})
+		}
+
+		parts = append(parts, valueTemplatePart{fieldName: template[loc[2]:loc[3]]})
+		start = loc[1]
+	}
+
+	if start < len(template) {
+		parts = append(parts, valueTemplatePart{literal: template[start:]})
+	}
+
+	for _, part := range parts {
+		if strings.Contains(part.literal, "{{") || strings.Contains(part.literal, "}}") {
+			return nil, fmt.Errorf("only references to other fields (ie: `{{.url.domain}}`) are supported in value_from_template: %s", template)
+		}
+	}
+
+	return parts, nil
+}
+
+// bindValueFromTemplate binds the fields whose value is built from a template referencing other fields in the same
+// event, like ECS `url.full` from `url.scheme`, `url.domain` and `url.path`.
+// The fields are bound after the fields they reference, so that templates can reference other templates, and cycles
+// are reported. The referenced fields are wrapped, so that their value is generated once per event regardless the
+// order the fields are emitted.
+func bindValueFromTemplate(cfg Config, fields Fields, fieldMap map[string]any, withReturn bool) error {
+	fieldsByName := make(map[string]Field, len(fields))
+	templateParts := make(map[string][]valueTemplatePart)
+	var templateFields []string
+	for _, field := range fields {
+		fieldsByName[field.Name] = field
+		fieldCfg, _ := cfg.GetField(field.Name)
+		if len(fieldCfg.ValueFromTemplate) == 0 {
+			continue
+		}
+
+		parts, err := parseValueTemplate(fieldCfg.ValueFromTemplate)
+		if err != nil {
+			return fmt.Errorf("field %s: %w", field.Name, err)
+		}
+
+		for _, part := range parts {
+			if len(part.fieldName) == 0 {
+				continue
+			}
+
+			if _, ok := fieldMap[part.fieldName]; !ok {
+				return fmt.Errorf("field %s value_from_template references field %s that is not defined", field.Name, part.fieldName)
+			}
+		}
+
+		templateParts[field.Name] = parts
+		templateFields = append(templateFields, field.Name)
+	}
+
+	ordered, err := orderValueTemplates(templateFields, templateParts)
+	if err != nil {
+		return err
+	}
+
+	wrapped := make(map[string]struct{})
+	for _, fieldName := range ordered {
+		referencedFields := make(map[string]valueTemplateReferencedField)
+		for _, part := range templateParts[fieldName] {
+			if len(part.fieldName) == 0 {
+				continue
+			}
+
+			referencedFieldCfg, _ := cfg.GetField(part.fieldName)
+			referencedFields[part.fieldName] = valueTemplateReferencedField{
+				field:    fieldsByName[part.fieldName],
+				fieldCfg: referencedFieldCfg,
+				layout:   dateLayout(referencedFieldCfg),
+			}
+
+			if _, ok := wrapped[part.fieldName]; ok {
+				continue
+			}
+
+			if err := wrapEventValue(part.fieldName, fieldMap, withReturn); err != nil {
+				return err
+			}
+
+			wrapped[part.fieldName] = struct{}{}
+		}
+
+		if withReturn {
+			bindValueFromTemplateWithReturn(fieldName, templateParts[fieldName], referencedFields, fieldMap)
+		} else {
+			bindValueFromTemplateNotReturn(fieldName, templateParts[fieldName], fieldMap)
+		}
+	}
+
+	return nil
+}
+
+// orderValueTemplates returns the fields with a `value_from_template` ordered after the ones they reference,
+// or an error if they reference each other in a cycle
+func orderValueTemplates(templateFields []string, templateParts map[string][]valueTemplatePart) ([]string, error) {
+	const (
+		visiting = iota + 1
+		visited
+	)
+
+	ordered := make([]string, 0, len(templateFields))
+	status := make(map[string]int, len(templateFields))
+
+	var visit func(fieldName string, path []string) error
+	visit = func(fieldName string, path []string) error {
+		switch status[fieldName] {
+		case visited:
+			return nil
+		case visiting:
+			return fmt.Errorf("value_from_template cycle: %s", strings.Join(append(path, fieldName), " -> "))
+		}
+
+		status[fieldName] = visiting
+		for _, part := range templateParts[fieldName] {
+			if _, ok := templateParts[part.fieldName]; !ok {
+				continue
+			}
+
+			if err := visit(part.fieldName, append(path, fieldName)); err != nil {
+				return err
+			}
+		}
+
+		status[fieldName] = visited
+		ordered = append(ordered, fieldName)
+		return nil
+	}
+
+	for _, fieldName := range templateFields {
+		if err := visit(fieldName, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	return ordered, nil
+}
+
+func bindValueFromTemplateNotReturn(fieldName string, parts []valueTemplatePart, fieldMap map[string]any) {
+	partFs := make([]emitFNotReturn, len(parts))
+	for i, part := range parts {
+		if len(part.fieldName) > 0 {
+			partFs[i] = fieldMap[part.fieldName].(emitFNotReturn)
+		}
+	}
+
+	var emitFNotReturn emitFNotReturn
+	emitFNotReturn = func(state *genState, buf *bytes.Buffer) error {
+		for i, part := range parts {
+			if partFs[i] == nil {
+				buf.WriteString(part.literal)
+				continue
+			}
+
+			if err := partFs[i](state, buf); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	fieldMap[fieldName] = emitFNotReturn
+}
+
+func bindValueFromTemplateWithReturn(fieldName string, parts []valueTemplatePart, referencedFields map[string]valueTemplateReferencedField, fieldMap map[string]any) {
+	partFs := make([]emitF, len(parts))
+	for i, part := range parts {
+		if len(part.fieldName) > 0 {
+			partFs[i] = fieldMap[part.fieldName].(emitF)
+		}
+	}
+
+	var emitF emitF
+	emitF = func(state *genState) any {
+		var value strings.Builder
+		for i, part := range parts {
+			if partFs[i] == nil {
+				value.WriteString(part.literal)
+				continue
+			}
+
+			referencedField := referencedFields[part.fieldName]
+			switch v := partFs[i](state).(type) {
+			case error:
+				return v
+			case time.Time:
+				value.WriteString(formatDate(v, referencedField.layout))
+			case float64:
+				// doubles are rendered as in placeholder templates, without scientific notation
+				value.Write(appendFieldDouble(make([]byte, 0, 32), referencedField.field, v, referencedField.fieldCfg.OmitIntegerDecimals))
+			default:
+				value.WriteString(fmt.Sprint(v))
+			}
+		}
+
+		return value.String()
+	}
+
+	fieldMap[fieldName] = emitF
+}
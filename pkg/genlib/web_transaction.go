@@ -0,0 +1,134 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package genlib
+
+import (
+	"math"
+	"math/rand"
+	"time"
+)
+
+// webStatus is a response status code with the weight it is chosen with for a request method
+type webStatus struct {
+	code   int
+	weight float64
+}
+
+// webMethod is a request method, with the weight it is chosen with and the response status codes of its requests
+type webMethod struct {
+	name     string
+	weight   float64
+	hasBody  bool
+	statuses []webStatus
+}
+
+// webMethods are the request methods chosen from for a `web_transaction` field
+// NOTE: the weights loosely follow the traffic of a web application serving pages and an API
+var webMethods = []webMethod{
+	{name: "GET", weight: 70, statuses: []webStatus{{200, 80}, {304, 6}, {404, 6}, {301, 3}, {403, 2}, {500, 2}, {503, 1}}},
+	{name: "POST", weight: 18, hasBody: true, statuses: []webStatus{{200, 45}, {201, 35}, {400, 8}, {401, 4}, {204, 4}, {500, 3}, {503, 1}}},
+	{name: "PUT", weight: 5, hasBody: true, statuses: []webStatus{{200, 60}, {204, 30}, {400, 6}, {500, 4}}},
+	{name: "DELETE", weight: 3, statuses: []webStatus{{204, 70}, {200, 15}, {404, 12}, {500, 3}}},
+	{name: "HEAD", weight: 3, statuses: []webStatus{{200, 90}, {404, 10}}},
+	{name: "PATCH", weight: 1, hasBody: true, statuses: []webStatus{{200, 60}, {204, 30}, {400, 10}}},
+}
+
+var webMethodIndex = makeWeightedEnumIndexFunc(webMethodWeights())
+
+// webStatusIndexes choose the index of the response status code of a request, by index of the method in webMethods
+var webStatusIndexes = webStatusIndexFuncs()
+
+func webMethodWeights() []float64 {
+	weights := make([]float64, 0, len(webMethods))
+	for _, method := range webMethods {
+		weights = append(weights, method.weight)
+	}
+
+	return weights
+}
+
+func webStatusIndexFuncs() []func(r *rand.Rand) int {
+	indexFuncs := make([]func(r *rand.Rand) int, 0, len(webMethods))
+	for _, method := range webMethods {
+		weights := make([]float64, 0, len(method.statuses))
+		for _, status := range method.statuses {
+			weights = append(weights, status.weight)
+		}
+
+		indexFuncs = append(indexFuncs, makeWeightedEnumIndexFunc(weights))
+	}
+
+	return indexFuncs
+}
+
+// webTransaction is the generated value of a `web_transaction` field
+type webTransaction struct {
+	method        string
+	requestBytes  int64
+	statusCode    int
+	responseBytes int64
+	duration      int64
+}
+
+// webTransactionEventPrefix returns the prefix of the `event.duration` field of a `web_transaction` field:
+// it is a sibling of the field (ie: `event.duration` for `http`)
+func webTransactionEventPrefix(fieldName string) string {
+	return kubernetesContainerPrefix(fieldName)
+}
+
+// logUniformInt64 returns a random value between min and max, uniformly distributed in logarithmic scale, so that
+// small values are as frequent as big ones by order of magnitude, like payload sizes
+func logUniformInt64(r *rand.Rand, min, max float64) int64 {
+	return int64(math.Exp(math.Log(min) + r.Float64()*(math.Log(max)-math.Log(min))))
+}
+
+// webTransactionForEvent returns the request, response and duration of a `web_transaction` field for the current
+// event, generated jointly so that they are plausible together regardless of the order they are emitted: requests
+// without a body are smaller, `204`, `304` and `HEAD` responses have no body, error responses are small, and the
+// duration grows with the bytes transferred.
+func webTransactionForEvent(fieldName string, state *genState) webTransaction {
+	if value, ok := state.eventValue(fieldName); ok {
+		return value.(webTransaction)
+	}
+
+	methodIdx := webMethodIndex(state.rand)
+	method := webMethods[methodIdx]
+	status := method.statuses[webStatusIndexes[methodIdx](state.rand)]
+
+	// the request line and headers, and the body if any
+	requestBytes := logUniformInt64(state.rand, 200, 1500)
+	if method.hasBody {
+		requestBytes += logUniformInt64(state.rand, 100, 100000)
+	}
+
+	var responseBytes int64
+	switch {
+	case status.code == 204 || status.code == 304 || method.name == "HEAD":
+	case status.code >= 300:
+		responseBytes = logUniformInt64(state.rand, 100, 2000)
+	default:
+		responseBytes = logUniformInt64(state.rand, 500, 500000)
+	}
+
+	// the server processing time, longer for server errors that are often timeouts, and the transfer at about 10MB/s
+	processing := time.Duration(logUniformInt64(state.rand, float64(time.Millisecond), float64(200*time.Millisecond)))
+	if status.code >= 500 {
+		processing += time.Duration(logUniformInt64(state.rand, float64(time.Second), float64(30*time.Second)))
+	}
+
+	transfer := time.Duration(requestBytes+responseBytes) * 100 * time.Nanosecond
+
+	transaction := webTransaction{
+		method:        method.name,
+		requestBytes:  requestBytes,
+		statusCode:    status.code,
+		responseBytes: responseBytes,
+		duration:      int64(processing + transfer),
+	}
+
+	state.setEventValue(fieldName, transaction)
+
+	return transaction
+}
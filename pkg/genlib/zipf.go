@@ -0,0 +1,93 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package genlib
+
+import (
+	"math/rand"
+
+	"github.com/elastic/elastic-integration-corpus-generator-tool/pkg/genlib/config"
+)
+
+// Default parameters of the `zipf` distribution when `zipf_s` and `zipf_v` are not set
+const (
+	defaultZipfS = 1.1
+	defaultZipfV = 1
+)
+
+// makeEnumIndexFunc returns a function choosing the index of the value of the enum of the field,
+// according to the weights of the values, if any, or to the configured `distribution`: with `zipf` the first values
+// of the enum dominate.
+func makeEnumIndexFunc(fieldCfg ConfigField) func(r *rand.Rand) int {
+	if len(fieldCfg.EnumWeights) > 0 {
+		return makeWeightedEnumIndexFunc(fieldCfg.EnumWeights)
+	}
+
+	if fieldCfg.Distribution != config.DistributionZipf {
+		return func(r *rand.Rand) int {
+			return r.Intn(len(fieldCfg.Enum))
+		}
+	}
+
+	s, v := fieldCfg.ZipfS, fieldCfg.ZipfV
+	if s == 0 {
+		s = defaultZipfS
+	}
+
+	if v == 0 {
+		v = defaultZipfV
+	}
+
+	src := &forwardingSource{}
+	zipf := rand.NewZipf(rand.New(src), s, v, uint64(len(fieldCfg.Enum)-1))
+	return func(r *rand.Rand) int {
+		src.r = r
+		return int(zipf.Uint64())
+	}
+}
+
+// forwardingSource is a rand.Source drawing from the random generator it currently points to, so that a rand.Zipf
+// built once at bind time draws from the random generator of the state each time a value is generated
+type forwardingSource struct {
+	r *rand.Rand
+}
+
+func (s *forwardingSource) Int63() int64 {
+	return s.r.Int63()
+}
+
+func (s *forwardingSource) Uint64() uint64 {
+	return s.r.Uint64()
+}
+
+// Seed is a no-op: the random generator the source forwards to is seeded on its own
+func (s *forwardingSource) Seed(int64) {}
+
+// makeWeightedEnumIndexFunc returns a function choosing an index with a probability proportional to its weight
+func makeWeightedEnumIndexFunc(weights []float64) func(r *rand.Rand) int {
+	var totWeight float64
+	for _, weight := range weights {
+		totWeight += weight
+	}
+
+	return func(r *rand.Rand) int {
+		v := r.Float64() * totWeight
+		for i, weight := range weights {
+			if v < weight {
+				return i
+			}
+
+			v -= weight
+		}
+
+		// fallback for rounding errors, to the last value that can be chosen
+		for i := len(weights) - 1; i > 0; i-- {
+			if weights[i] > 0 {
+				return i
+			}
+		}
+
+		return 0
+	}
+}